@@ -44,6 +44,8 @@ var (
 	ErrNoMatch    = errors.New("no key for given address or file")
 	ErrDecrypt    = errors.New("could not decrypt key with given passphrase")
 	ErrChainIdNil = errors.New("Chain ID should not be nil")
+
+	ErrTooManyUnlocked = errors.New("too many unlocked accounts, lock another account first")
 )
 
 // KeyStoreType is the reflect type of a keystore backend.
@@ -62,6 +64,8 @@ type KeyStore struct {
 	changes  chan struct{}                // Channel receiving change notifications from the cache
 	unlocked map[common.Address]*unlocked // Currently unlocked account (decrypted private keys)
 
+	maxUnlocked int // Maximum number of accounts unlocked at the same time (0 = unlimited)
+
 	wallets     []accounts.Wallet       // Wallet wrappers around the individual key files
 	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
 	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
@@ -312,6 +316,14 @@ func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string,
 	return nil, ErrChainIdNil
 }
 
+// SetMaxUnlocked sets the maximum number of accounts which can be unlocked at
+// the same time. Zero means unlimited.
+func (ks *KeyStore) SetMaxUnlocked(max int) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.maxUnlocked = max
+}
+
 // Unlock unlocks the given account indefinitely.
 func (ks *KeyStore) Unlock(a accounts.Account, passphrase string) error {
 	return ks.TimedUnlock(a, passphrase, 0)
@@ -336,6 +348,9 @@ func (ks *KeyStore) Lock(addr common.Address) error {
 // If the account address is already unlocked for a duration, TimedUnlock extends or
 // shortens the active unlock timeout. If the address was previously unlocked
 // indefinitely the timeout is not altered.
+//
+// ErrTooManyUnlocked is returned if the maximum number of unlocked accounts is
+// reached and the account is not unlocked yet.
 func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout time.Duration) error {
 	a, key, err := ks.getDecryptedKey(a, passphrase)
 	if err != nil {
@@ -345,6 +360,10 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 	ks.mu.Lock()
 	defer ks.mu.Unlock()
 	u, found := ks.unlocked[a.Address]
+	if !found && ks.maxUnlocked > 0 && len(ks.unlocked) >= ks.maxUnlocked {
+		zeroKey(key.PrivateKey)
+		return ErrTooManyUnlocked
+	}
 	if found {
 		if u.abort == nil {
 			// The address was unlocked indefinitely, so unlocking
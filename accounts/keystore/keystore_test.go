@@ -117,6 +117,50 @@ func TestSignWithPassphrase(t *testing.T) {
 	}
 }
 
+func TestMaxUnlocked(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	const maxUnlocked = 2
+	ks.SetMaxUnlocked(maxUnlocked)
+
+	pass := "foo"
+	accs := make([]accounts.Account, maxUnlocked+1)
+	for i := range accs {
+		a, err := ks.NewAccount(pass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		accs[i] = a
+	}
+
+	// Unlocking up to the cap succeeds.
+	for i := 0; i < maxUnlocked; i++ {
+		if err := ks.Unlock(accs[i], pass); err != nil {
+			t.Fatalf("failed to unlock account %d: %v", i, err)
+		}
+	}
+	// Unlocking an already unlocked account is not restricted.
+	if err := ks.TimedUnlock(accs[0], pass, time.Minute); err != nil {
+		t.Fatalf("failed to unlock an unlocked account again: %v", err)
+	}
+	// Unlocking beyond the cap fails.
+	if err := ks.Unlock(accs[maxUnlocked], pass); err != ErrTooManyUnlocked {
+		t.Fatalf("unlocking beyond the cap should fail with ErrTooManyUnlocked, got %v", err)
+	}
+	if _, err := ks.SignHash(accounts.Account{Address: accs[maxUnlocked].Address}, testSigData); err != ErrLocked {
+		t.Fatal("Signing should've failed with ErrLocked, got ", err)
+	}
+
+	// Locking another account makes room for the account.
+	if err := ks.Lock(accs[0].Address); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(accs[maxUnlocked], pass); err != nil {
+		t.Fatalf("failed to unlock account after locking another one: %v", err)
+	}
+}
+
 func TestTimedUnlock(t *testing.T) {
 	dir, ks := tmpKeyStore(t, true)
 	defer os.RemoveAll(dir)
@@ -0,0 +1,369 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/crypto"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypedDataDomainType is the type name of the domain of typed data.
+const TypedDataDomainType = "EIP712Domain"
+
+var (
+	errTypedDataNoDomainType = fmt.Errorf("typed data has no %s type", TypedDataDomainType)
+	errTypedDataNoChainId    = fmt.Errorf("typed data has no chainId in the %s", TypedDataDomainType)
+
+	typedDataArrayRegexp = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+	typedDataIntRegexp   = regexp.MustCompile(`^(u?)int(\d*)$`)
+	typedDataBytesRegexp = regexp.MustCompile(`^bytes(\d+)$`)
+)
+
+// TypedDataField is a named and typed field of a struct type of typed data.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDomain is the domain of typed data, which separates the signatures of
+// different dApps and networks. ChainId should be the chain ID of the Klaytn network
+// the signature is used on, so that it cannot be replayed on the other networks.
+type TypedDataDomain struct {
+	Name              string          `json:"name,omitempty"`
+	Version           string          `json:"version,omitempty"`
+	ChainId           *hexutil.Big    `json:"chainId,omitempty"`
+	VerifyingContract *common.Address `json:"verifyingContract,omitempty"`
+	Salt              *common.Hash    `json:"salt,omitempty"`
+}
+
+// UnmarshalJSON accepts the chain ID as a JSON number as well as a hex or decimal string.
+func (domain *TypedDataDomain) UnmarshalJSON(input []byte) error {
+	type typedDataDomain TypedDataDomain
+	var dec struct {
+		typedDataDomain
+		ChainId interface{} `json:"chainId,omitempty"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*domain = TypedDataDomain(dec.typedDataDomain)
+	if dec.ChainId != nil {
+		chainId, err := parseTypedDataInteger(dec.ChainId)
+		if err != nil {
+			return fmt.Errorf("invalid chainId: %v", err)
+		}
+		domain.ChainId = (*hexutil.Big)(chainId)
+	}
+	return nil
+}
+
+// Map returns the fields of the domain as a message of typed data.
+func (domain *TypedDataDomain) Map() map[string]interface{} {
+	m := make(map[string]interface{})
+	if domain.Name != "" {
+		m["name"] = domain.Name
+	}
+	if domain.Version != "" {
+		m["version"] = domain.Version
+	}
+	if domain.ChainId != nil {
+		m["chainId"] = domain.ChainId.ToInt()
+	}
+	if domain.VerifyingContract != nil {
+		m["verifyingContract"] = domain.VerifyingContract.Hex()
+	}
+	if domain.Salt != nil {
+		m["salt"] = domain.Salt.Hex()
+	}
+	return m
+}
+
+// TypedData is structured data to be hashed and signed as defined by EIP-712.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      TypedDataDomain             `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// ValidateChainId returns an error if the domain of the typed data does not have the
+// chain ID, or its chain ID is not the given one. Requiring the chain ID in the domain
+// separator makes a signature valid only on the Klaytn network of the chain ID.
+func (td *TypedData) ValidateChainId(chainId *big.Int) error {
+	declared := false
+	for _, field := range td.Types[TypedDataDomainType] {
+		declared = declared || field.Name == "chainId"
+	}
+	if !declared || td.Domain.ChainId == nil {
+		return errTypedDataNoChainId
+	}
+	if td.Domain.ChainId.ToInt().Cmp(chainId) != 0 {
+		return fmt.Errorf("chain id mismatch: have %v, want %v", td.Domain.ChainId.ToInt(), chainId)
+	}
+	return nil
+}
+
+// Hash returns the hash to be signed for the typed data:
+// keccak256("\x19\x01" || hashStruct(domain) || hashStruct(message)).
+func (td *TypedData) Hash() ([]byte, error) {
+	if _, ok := td.Types[TypedDataDomainType]; !ok {
+		return nil, errTypedDataNoDomainType
+	}
+	domainSeparator, err := td.HashStruct(TypedDataDomainType, td.Domain.Map())
+	if err != nil {
+		return nil, fmt.Errorf("invalid domain: %v", err)
+	}
+	message, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %v", err)
+	}
+	return crypto.Keccak256([]byte("\x19\x01"), domainSeparator, message), nil
+}
+
+// HashStruct returns keccak256(typeHash || encodeData(data)) of the data of the given struct type.
+func (td *TypedData) HashStruct(typeName string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := td.EncodeData(typeName, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(td.TypeHash(typeName), encoded), nil
+}
+
+// TypeHash returns the hash of the encoded type of the given struct type.
+func (td *TypedData) TypeHash(typeName string) []byte {
+	return crypto.Keccak256([]byte(td.EncodeType(typeName)))
+}
+
+// EncodeType returns the encoding of the given struct type followed by the types
+// it references, sorted by name, e.g. "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+func (td *TypedData) EncodeType(typeName string) string {
+	deps := td.dependencies(typeName, map[string]bool{})
+	sort.Strings(deps)
+
+	var buf bytes.Buffer
+	for _, dep := range append([]string{typeName}, deps...) {
+		fields := make([]string, len(td.Types[dep]))
+		for i, field := range td.Types[dep] {
+			fields[i] = field.Type + " " + field.Name
+		}
+		buf.WriteString(dep + "(" + strings.Join(fields, ",") + ")")
+	}
+	return buf.String()
+}
+
+// dependencies returns the struct types referenced by the given struct type, directly
+// or indirectly, except the type itself.
+func (td *TypedData) dependencies(typeName string, found map[string]bool) []string {
+	found[typeName] = true
+
+	var deps []string
+	for _, field := range td.Types[typeName] {
+		dep := typedDataBaseType(field.Type)
+		if _, ok := td.Types[dep]; !ok || found[dep] {
+			continue
+		}
+		deps = append(deps, dep)
+		deps = append(deps, td.dependencies(dep, found)...)
+	}
+	return deps
+}
+
+// EncodeData returns the concatenated 32 byte encodings of the fields of the data
+// of the given struct type.
+func (td *TypedData) EncodeData(typeName string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := td.Types[typeName]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", typeName)
+	}
+	if len(data) > len(fields) {
+		return nil, fmt.Errorf("%s has %d fields, but %d values are given", typeName, len(fields), len(data))
+	}
+	var buf bytes.Buffer
+	for _, field := range fields {
+		encoded, err := td.encodeValue(field.Type, data[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", typeName, field.Name, err)
+		}
+		buf.Write(encoded)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeValue returns the 32 byte encoding of the value of the given type.
+func (td *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if match := typedDataArrayRegexp.FindStringSubmatch(typ); match != nil {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%T is not an array", value)
+		}
+		if match[2] != "" {
+			if length, _ := strconv.Atoi(match[2]); length != len(items) {
+				return nil, fmt.Errorf("array length mismatch: have %d, want %d", len(items), length)
+			}
+		}
+		var buf bytes.Buffer
+		for i, item := range items {
+			encoded, err := td.encodeValue(match[1], item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+			buf.Write(encoded)
+		}
+		return crypto.Keccak256(buf.Bytes()), nil
+	}
+	if _, ok := td.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%T is not a struct", value)
+		}
+		return td.HashStruct(typ, data)
+	}
+	return encodeTypedDataAtomic(typ, value)
+}
+
+// encodeTypedDataAtomic returns the 32 byte encoding of the value of the given atomic type.
+func encodeTypedDataAtomic(typ string, value interface{}) ([]byte, error) {
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a string", value)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+
+	case "bytes":
+		b, err := parseTypedDataBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a bool", value)
+		}
+		if b {
+			return math.PaddedBigBytes(common.Big1, 32), nil
+		}
+		return make([]byte, 32), nil
+
+	case "address":
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %v", value)
+		}
+		return common.LeftPadBytes(common.HexToAddress(s).Bytes(), 32), nil
+	}
+
+	if match := typedDataBytesRegexp.FindStringSubmatch(typ); match != nil {
+		size, _ := strconv.Atoi(match[1])
+		if size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %q", typ)
+		}
+		b, err := parseTypedDataBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != size {
+			return nil, fmt.Errorf("%s has %d bytes", typ, len(b))
+		}
+		return common.RightPadBytes(b, 32), nil
+	}
+
+	if match := typedDataIntRegexp.FindStringSubmatch(typ); match != nil {
+		bits := 256
+		if match[2] != "" {
+			bits, _ = strconv.Atoi(match[2])
+		}
+		if bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("invalid type %q", typ)
+		}
+		n, err := parseTypedDataInteger(value)
+		if err != nil {
+			return nil, err
+		}
+		if match[1] == "u" {
+			if n.Sign() < 0 || n.BitLen() > bits {
+				return nil, fmt.Errorf("%v overflows %s", n, typ)
+			}
+		} else if n.Cmp(math.BigPow(2, int64(bits-1))) >= 0 || n.Cmp(new(big.Int).Neg(math.BigPow(2, int64(bits-1)))) < 0 {
+			return nil, fmt.Errorf("%v overflows %s", n, typ)
+		}
+		return math.PaddedBigBytes(math.U256(new(big.Int).Set(n)), 32), nil
+	}
+	return nil, fmt.Errorf("unknown type %q", typ)
+}
+
+// parseTypedDataBytes parses a hex string into bytes.
+func parseTypedDataBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case hexutil.Bytes:
+		return v, nil
+	case string:
+		return hexutil.Decode(v)
+	}
+	return nil, fmt.Errorf("%T is not bytes", value)
+}
+
+// parseTypedDataInteger parses a JSON number, or a decimal or hex string into an integer.
+func parseTypedDataInteger(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case float64:
+		n, accuracy := big.NewFloat(v).Int(nil)
+		if accuracy != big.Exact {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		return n, nil
+	case string:
+		negative := strings.HasPrefix(v, "-")
+		n, ok := math.ParseBig256(strings.TrimPrefix(v, "-"))
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		if negative {
+			n.Neg(n)
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("%T is not an integer", value)
+}
+
+// typedDataBaseType returns the element type of an array type, or the type itself.
+func typedDataBaseType(typ string) string {
+	for {
+		match := typedDataArrayRegexp.FindStringSubmatch(typ)
+		if match == nil {
+			return typ
+		}
+		typ = match[1]
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"encoding/json"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"math/big"
+	"testing"
+)
+
+// mailTypedData is the example of EIP-712.
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+// groupTypedData has nested struct arrays and the other atomic types.
+const groupTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "salt", "type": "bytes32"}
+		],
+		"Member": [
+			{"name": "wallet", "type": "address"},
+			{"name": "roles", "type": "string[]"},
+			{"name": "weight", "type": "int64"}
+		],
+		"Team": [
+			{"name": "name", "type": "string"},
+			{"name": "members", "type": "Member[]"}
+		],
+		"Group": [
+			{"name": "teams", "type": "Team[2]"},
+			{"name": "active", "type": "bool"},
+			{"name": "data", "type": "bytes"},
+			{"name": "budget", "type": "uint256"}
+		]
+	},
+	"primaryType": "Group",
+	"domain": {
+		"name": "Klaytn Group",
+		"chainId": "0x3e9",
+		"salt": "0x0000000000000000000000000000000000000000000000000000000000000001"
+	},
+	"message": {
+		"teams": [
+			{"name": "core", "members": [
+				{"wallet": "0x0000000000000000000000000000000000000001", "roles": ["admin", "dev"], "weight": -3},
+				{"wallet": "0x0000000000000000000000000000000000000002", "roles": [], "weight": 7}
+			]},
+			{"name": "ops", "members": []}
+		],
+		"active": true,
+		"data": "0xdeadbeef",
+		"budget": "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	}
+}`
+
+func parseTypedData(t *testing.T, input string) *TypedData {
+	var typedData TypedData
+	if err := json.Unmarshal([]byte(input), &typedData); err != nil {
+		t.Fatalf("failed to parse typed data: %v", err)
+	}
+	return &typedData
+}
+
+func TestTypedDataHash(t *testing.T) {
+	typedData := parseTypedData(t, mailTypedData)
+
+	if have, want := typedData.EncodeType("Mail"), "Mail(Person from,Person to,string contents)Person(string name,address wallet)"; have != want {
+		t.Errorf("encoded type mismatch: have %q, want %q", have, want)
+	}
+	domainSeparator, err := typedData.HashStruct(TypedDataDomainType, typedData.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := hexutil.Encode(domainSeparator), "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; have != want {
+		t.Errorf("domain separator mismatch: have %s, want %s", have, want)
+	}
+	message, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := hexutil.Encode(message), "0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"; have != want {
+		t.Errorf("message hash mismatch: have %s, want %s", have, want)
+	}
+	hash, err := typedData.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := hexutil.Encode(hash), "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; have != want {
+		t.Errorf("hash mismatch: have %s, want %s", have, want)
+	}
+}
+
+func TestTypedDataSignAndRecover(t *testing.T) {
+	typedData := parseTypedData(t, groupTypedData)
+	if have, want := typedData.EncodeType("Group"), "Group(Team[2] teams,bool active,bytes data,uint256 budget)Member(address wallet,string[] roles,int64 weight)Team(string name,Member[] members)"; have != want {
+		t.Errorf("encoded type mismatch: have %q, want %q", have, want)
+	}
+
+	key, _ := crypto.GenerateKey()
+	hash, err := typedData.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := crypto.PubkeyToAddress(*pub), crypto.PubkeyToAddress(key.PublicKey); have != want {
+		t.Errorf("recovered address mismatch: have %s, want %s", have.Hex(), want.Hex())
+	}
+
+	// A change of a nested value changes the hash.
+	member := typedData.Message["teams"].([]interface{})[0].(map[string]interface{})["members"].([]interface{})[1].(map[string]interface{})
+	member["wallet"] = common.HexToAddress("0x3").Hex()
+	if changed, err := typedData.Hash(); err != nil || hexutil.Encode(changed) == hexutil.Encode(hash) {
+		t.Errorf("hash should change with a nested value: err %v", err)
+	}
+}
+
+func TestTypedDataInvalid(t *testing.T) {
+	for name, modify := range map[string]func(td *TypedData){
+		"array length": func(td *TypedData) { td.Message["teams"] = td.Message["teams"].([]interface{})[:1] },
+		"uint overflow": func(td *TypedData) {
+			td.Message["budget"] = "0x1" + "0000000000000000000000000000000000000000000000000000000000000000"
+		},
+		"unknown field": func(td *TypedData) { td.Message["unknown"] = true },
+		"bool":          func(td *TypedData) { td.Message["active"] = "true" },
+		"no domain":     func(td *TypedData) { delete(td.Types, TypedDataDomainType) },
+	} {
+		typedData := parseTypedData(t, groupTypedData)
+		modify(typedData)
+		if _, err := typedData.Hash(); err == nil {
+			t.Errorf("%s: invalid typed data should fail to be hashed", name)
+		}
+	}
+}
+
+func TestTypedDataValidateChainId(t *testing.T) {
+	if err := parseTypedData(t, mailTypedData).ValidateChainId(big.NewInt(1)); err != nil {
+		t.Errorf("typed data of the chain should be valid: %v", err)
+	}
+	for name, modify := range map[string]func(td *TypedData){
+		"other chain":      func(td *TypedData) { td.Domain.ChainId = (*hexutil.Big)(big.NewInt(1001)) },
+		"no chainId value": func(td *TypedData) { td.Domain.ChainId = nil },
+		"no chainId field": func(td *TypedData) {
+			td.Types[TypedDataDomainType] = td.Types[TypedDataDomainType][:2]
+		},
+	} {
+		typedData := parseTypedData(t, mailTypedData)
+		modify(typedData)
+		if err := typedData.ValidateChainId(big.NewInt(1)); err == nil {
+			t.Errorf("%s: typed data should be invalid", name)
+		}
+	}
+}
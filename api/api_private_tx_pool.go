@@ -0,0 +1,59 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/params"
+)
+
+// PrivateAdminTxPoolAPI offers an API for managing the transactions of the
+// transaction pool. It is exposed only over the private admin endpoint.
+type PrivateAdminTxPoolAPI struct {
+	b Backend
+}
+
+// NewPrivateAdminTxPoolAPI creates a new tx pool service for managing the transaction pool.
+func NewPrivateAdminTxPoolAPI(b Backend) *PrivateAdminTxPoolAPI {
+	return &PrivateAdminTxPoolAPI{b}
+}
+
+// FillNonceGaps returns unsigned cancel transactions of the given account, one for
+// each nonce missing below its highest queued nonce. Once they are signed and
+// submitted, the queued transactions stuck behind the gaps become executable.
+func (s *PrivateAdminTxPoolAPI) FillNonceGaps(address common.Address, gasPrice hexutil.Big) ([]*types.Transaction, error) {
+	gaps, err := s.b.TxPoolNonceGaps(address)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*types.Transaction, 0, len(gaps))
+	for _, nonce := range gaps {
+		tx, err := types.NewTransactionWithMap(types.TxTypeCancel, map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:    nonce,
+			types.TxValueKeyGasLimit: params.TxGasCancel,
+			types.TxValueKeyGasPrice: gasPrice.ToInt(),
+			types.TxValueKeyFrom:     address,
+		})
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/params"
+	"math/big"
+	"testing"
+)
+
+// nonceGapBackend is a Backend reporting fixed nonce gaps for a single account.
+type nonceGapBackend struct {
+	Backend
+	addr common.Address
+	gaps []uint64
+}
+
+func (b *nonceGapBackend) TxPoolNonceGaps(addr common.Address) ([]uint64, error) {
+	if addr != b.addr {
+		return nil, nil
+	}
+	return b.gaps, nil
+}
+
+func TestFillNonceGaps(t *testing.T) {
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	api := NewPrivateAdminTxPoolAPI(&nonceGapBackend{addr: addr, gaps: []uint64{1, 3, 4}})
+	gasPrice := big.NewInt(25000000000)
+
+	txs, err := api.FillNonceGaps(addr, hexutil.Big(*gasPrice))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{1, 3, 4}
+	if len(txs) != len(want) {
+		t.Fatalf("cancel tx count mismatch: have %d, want %d", len(txs), len(want))
+	}
+	for i, tx := range txs {
+		if tx.Type() != types.TxTypeCancel {
+			t.Errorf("tx %d type mismatch: have %v, want %v", i, tx.Type(), types.TxTypeCancel)
+		}
+		if tx.Nonce() != want[i] {
+			t.Errorf("tx %d nonce mismatch: have %d, want %d", i, tx.Nonce(), want[i])
+		}
+		if tx.Gas() != params.TxGasCancel {
+			t.Errorf("tx %d gas mismatch: have %d, want %d", i, tx.Gas(), params.TxGasCancel)
+		}
+		if tx.GasPrice().Cmp(gasPrice) != 0 {
+			t.Errorf("tx %d gas price mismatch: have %v, want %v", i, tx.GasPrice(), gasPrice)
+		}
+		if from, err := tx.From(); err != nil || from != addr {
+			t.Errorf("tx %d sender mismatch: have %v (%v), want %v", i, from, err, addr)
+		}
+	}
+
+	txs, err = api.FillNonceGaps(common.HexToAddress("0x1000000000000000000000000000000000000002"), hexutil.Big(*gasPrice))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("cancel txs of an account without gaps: have %d, want 0", len(txs))
+	}
+}
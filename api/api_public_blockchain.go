@@ -36,12 +36,22 @@ import (
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/ser/rlp"
 	"math/big"
+	"sort"
 	"time"
 )
 
 const defaultGasPrice = 25 * params.Ston
 const localTxExecutionTime = 5 * time.Second
 
+// maxGasUsedStatsBlocks is the maximum number of blocks that GasUsedStats examines.
+const maxGasUsedStatsBlocks = 1024
+
+// maxHeadersByRange is the maximum number of headers that GetHeadersByRange returns.
+const maxHeadersByRange = 192
+
+// maxAccountsState is the maximum number of accounts that GetAccountsState queries at once.
+const maxAccountsState = 1000
+
 var logger = log.NewModuleLogger(log.API)
 
 // PublicBlockChainAPI provides an API to access the Klaytn blockchain.
@@ -69,6 +79,60 @@ func (s *PublicBlockChainAPI) ChainID() *big.Int {
 	return nil
 }
 
+// maxGenesisAlloc is the maximum number of genesis accounts returned by GetGenesis.
+const maxGenesisAlloc = 1000
+
+// GenesisResult is the result of a klay_getGenesis API call.
+type GenesisResult struct {
+	Hash           common.Hash                     `json:"hash"`
+	Block          map[string]interface{}          `json:"block"`
+	ChainID        *hexutil.Big                    `json:"chainId"`
+	Config         *params.ChainConfig             `json:"config"`
+	Alloc          map[common.Address]*hexutil.Big `json:"alloc,omitempty"`
+	AllocTruncated bool                            `json:"allocTruncated,omitempty"`
+}
+
+// GetGenesis returns the genesis block, its chain ID and the chain config stored
+// with it, so that clients can verify which network the node belongs to.
+// If includeAlloc is true, the balances of at most maxGenesisAlloc genesis
+// accounts are also returned.
+func (s *PublicBlockChainAPI) GetGenesis(ctx context.Context, includeAlloc *bool) (*GenesisResult, error) {
+	db := s.b.ChainDB()
+	hash := db.ReadCanonicalHash(0)
+	block := db.ReadBlock(hash, 0)
+	if block == nil {
+		return nil, fmt.Errorf("genesis block not found")
+	}
+	fields, err := s.rpcOutputBlock(block, false, false)
+	if err != nil {
+		return nil, err
+	}
+	config := db.ReadChainConfig(hash)
+	if config == nil {
+		config = s.b.ChainConfig()
+	}
+	result := &GenesisResult{Hash: hash, Block: fields, Config: config}
+	if config != nil && config.ChainID != nil {
+		result.ChainID = (*hexutil.Big)(config.ChainID)
+	}
+	if includeAlloc != nil && *includeAlloc {
+		state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(0))
+		if err != nil {
+			return nil, err
+		}
+		if state == nil {
+			return nil, fmt.Errorf("genesis state not available")
+		}
+		balances, truncated := state.DumpBalances(maxGenesisAlloc)
+		result.Alloc = make(map[common.Address]*hexutil.Big, len(balances))
+		for addr, balance := range balances {
+			result.Alloc[addr] = (*hexutil.Big)(balance)
+		}
+		result.AllocTruncated = truncated
+	}
+	return result, nil
+}
+
 // IsContractAccount returns true if the account associated with addr has a non-empty codeHash.
 // It returns false otherwise.
 func (s *PublicBlockChainAPI) IsContractAccount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (bool, error) {
@@ -98,6 +162,9 @@ func (s *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockHash co
 	}
 	txs := block.Transactions()
 	if receipts.Len() != txs.Len() {
+		if err := checkReceiptsPruned(s.b, block.NumberU64()); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("the size of transactions and receipts is different in the block (%s)", blockHash.String())
 	}
 	fieldsList := make([]map[string]interface{}, 0, len(receipts))
@@ -108,6 +175,15 @@ func (s *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockHash co
 	return fieldsList, nil
 }
 
+// checkReceiptsPruned returns an error if the receipts of the given block have
+// been deleted by the prune-receipts command.
+func checkReceiptsPruned(b Backend, number uint64) error {
+	if earliest := b.ChainDB().ReadEarliestReceiptsBlockNumber(); number < earliest {
+		return fmt.Errorf("receipts of block %d have been pruned; the earliest available block is %d", number, earliest)
+	}
+	return nil
+}
+
 // GetBalance returns the amount of peb for the given address in the state of the
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
@@ -144,6 +220,78 @@ func (s *PublicBlockChainAPI) GetAccount(ctx context.Context, address common.Add
 	return serAcc, state.Error()
 }
 
+// GetAccountCreationInfo returns the hash, the type and the block of the transaction
+// which created the given account, and whether the account is human-readable. The
+// type tells an account created by an account creation transaction from the ones
+// created implicitly by receiving value or being deployed. It returns nil if no
+// creation info is recorded for the account; the info is recorded only if account
+// creation indexing is enabled.
+func (s *PublicBlockChainAPI) GetAccountCreationInfo(address common.Address) map[string]interface{} {
+	db := s.b.ChainDB()
+	info := db.ReadAccountCreationInfo(address)
+	// Ignore the info recorded by a block which is no longer canonical.
+	if info == nil || db.ReadCanonicalHash(info.BlockNumber) != info.BlockHash {
+		return nil
+	}
+	return map[string]interface{}{
+		"transactionHash": info.TxHash,
+		"type":            info.TxType.String(),
+		"typeInt":         info.TxType,
+		"blockHash":       info.BlockHash,
+		"blockNumber":     hexutil.Uint64(info.BlockNumber),
+		"humanReadable":   info.HumanReadable,
+	}
+}
+
+// GetAccountKeyHistory returns the key changes of the given account made by the
+// account update transactions in the canonical blocks between fromBlock and
+// toBlock inclusive. The history is recorded only if account key history
+// indexing is enabled.
+func (s *PublicBlockChainAPI) GetAccountKeyHistory(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) ([]map[string]interface{}, error) {
+	from, to := s.resolveMetaBlockNumber(fromBlock), s.resolveMetaBlockNumber(toBlock)
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is greater than toBlock %d", from, to)
+	}
+	db := s.b.ChainDB()
+	history, err := db.ReadAccountKeyHistory(address, from, to)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]map[string]interface{}, 0, len(history))
+	for _, change := range history {
+		// Skip the changes made by blocks which are no longer canonical.
+		if db.ReadCanonicalHash(change.BlockNumber) != change.BlockHash {
+			continue
+		}
+		oldKey, newKey := accountkey.NewAccountKeySerializer(), accountkey.NewAccountKeySerializer()
+		if err := rlp.DecodeBytes(change.OldKey, oldKey); err != nil {
+			return nil, err
+		}
+		if err := rlp.DecodeBytes(change.NewKey, newKey); err != nil {
+			return nil, err
+		}
+		changes = append(changes, map[string]interface{}{
+			"transactionHash":  change.TxHash,
+			"transactionIndex": hexutil.Uint64(change.TxIndex),
+			"blockHash":        change.BlockHash,
+			"blockNumber":      hexutil.Uint64(change.BlockNumber),
+			"oldKey":           oldKey,
+			"newKey":           newKey,
+		})
+	}
+	return changes, nil
+}
+
+// resolveMetaBlockNumber returns the number of the given block, resolving the
+// rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block numbers to the
+// current block.
+func (s *PublicBlockChainAPI) resolveMetaBlockNumber(blockNr rpc.BlockNumber) uint64 {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return s.b.CurrentBlock().NumberU64()
+	}
+	return uint64(blockNr)
+}
+
 // GetBlockByNumber returns the requested block. When blockNr is -1 the chain head is returned. When fullTx is true all
 // transactions in the block are returned in full detail, otherwise only the transaction hash is returned.
 func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
@@ -161,6 +309,122 @@ func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.
 	return nil, err
 }
 
+// GetBlockHashByNumber returns the canonical block hash of the given block number.
+// It reads the canonical hash directly instead of retrieving the block. When blockNr is
+// pending, nil is returned since the pending block does not have a canonical hash yet.
+func (s *PublicBlockChainAPI) GetBlockHashByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*common.Hash, error) {
+	switch blockNr {
+	case rpc.PendingBlockNumber:
+		return nil, nil
+	case rpc.LatestBlockNumber:
+		hash := s.b.CurrentBlock().Hash()
+		return &hash, nil
+	}
+	hash := s.b.ChainDB().ReadCanonicalHash(uint64(blockNr))
+	if common.EmptyHash(hash) {
+		return nil, fmt.Errorf("the block does not exist (block number: %d)", blockNr)
+	}
+	return &hash, nil
+}
+
+// GasUsedStats is the distribution of the gas used by a range of blocks.
+type GasUsedStats struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+	Min       hexutil.Uint64 `json:"min"`
+	Max       hexutil.Uint64 `json:"max"`
+	Avg       hexutil.Uint64 `json:"avg"`
+	P25       hexutil.Uint64 `json:"p25"`
+	P50       hexutil.Uint64 `json:"p50"`
+	P75       hexutil.Uint64 `json:"p75"`
+	P90       hexutil.Uint64 `json:"p90"`
+}
+
+// GasUsedStats returns the min, max, average and percentiles of the gas used by the
+// latest given number of blocks. Only block headers are read.
+func (s *PublicBlockChainAPI) GasUsedStats(ctx context.Context, blocks uint64) (*GasUsedStats, error) {
+	if blocks == 0 || blocks > maxGasUsedStatsBlocks {
+		return nil, fmt.Errorf("the number of blocks should be between 1 and %d", maxGasUsedStatsBlocks)
+	}
+	head := s.b.CurrentBlock().NumberU64()
+	if blocks > head+1 {
+		blocks = head + 1
+	}
+	from := head + 1 - blocks
+
+	gasUsed := make([]uint64, 0, blocks)
+	total := new(big.Int)
+	for number := from; number <= head; number++ {
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		gasUsed = append(gasUsed, header.GasUsed)
+		total.Add(total, new(big.Int).SetUint64(header.GasUsed))
+	}
+	sort.Slice(gasUsed, func(i, j int) bool { return gasUsed[i] < gasUsed[j] })
+
+	percentile := func(p int) hexutil.Uint64 {
+		return hexutil.Uint64(gasUsed[(len(gasUsed)-1)*p/100])
+	}
+	return &GasUsedStats{
+		FromBlock: hexutil.Uint64(from),
+		ToBlock:   hexutil.Uint64(head),
+		Min:       hexutil.Uint64(gasUsed[0]),
+		Max:       hexutil.Uint64(gasUsed[len(gasUsed)-1]),
+		Avg:       hexutil.Uint64(total.Div(total, new(big.Int).SetUint64(blocks)).Uint64()),
+		P25:       percentile(25),
+		P50:       percentile(50),
+		P75:       percentile(75),
+		P90:       percentile(90),
+	}, nil
+}
+
+// GetHeadersByRange returns at most count canonical block headers starting from
+// the given block, following the semantics of the p2p block header query: skip
+// headers are skipped between two returned headers and reverse walks towards the
+// genesis block. The result stops early at the chain head or the genesis block.
+func (s *PublicBlockChainAPI) GetHeadersByRange(ctx context.Context, start rpc.BlockNumber, count uint64, skip uint64, reverse bool) ([]map[string]interface{}, error) {
+	if count == 0 || count > maxHeadersByRange {
+		return nil, fmt.Errorf("the number of headers should be between 1 and %d", maxHeadersByRange)
+	}
+	var number uint64
+	switch start {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		number = s.b.CurrentBlock().NumberU64()
+	default:
+		number = uint64(start)
+	}
+
+	db := s.b.ChainDB()
+	headers := make([]map[string]interface{}, 0, count)
+	for uint64(len(headers)) < count {
+		hash := db.ReadCanonicalHash(number)
+		if common.EmptyHash(hash) {
+			break
+		}
+		header := db.ReadHeader(hash, number)
+		if header == nil {
+			break
+		}
+		headers = append(headers, rpcOutputHeader(header))
+
+		if reverse {
+			if number < skip+1 {
+				break
+			}
+			number -= skip + 1
+		} else {
+			next := number + skip + 1
+			if next <= number { // overflow
+				break
+			}
+			number = next
+		}
+	}
+	return headers, nil
+}
+
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
 // detail, otherwise only the transaction hash is returned.
 func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (map[string]interface{}, error) {
@@ -208,10 +472,44 @@ func (s *PublicBlockChainAPI) GetAccountKey(ctx context.Context, address common.
 	return serAccKey, state.Error()
 }
 
+// AccountState is the state of an account returned by GetAccountsState.
+type AccountState struct {
+	Address  common.Address            `json:"address"`
+	Balance  *hexutil.Big              `json:"balance"`
+	Nonce    hexutil.Uint64            `json:"nonce"`
+	CodeHash common.Hash               `json:"codeHash"`
+	KeyType  accountkey.AccountKeyType `json:"keyType"`
+}
+
+// GetAccountsState returns the balance, nonce, code hash and account key type of
+// the given accounts, all read from the state of the given block number. The
+// rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block numbers are also
+// allowed.
+func (s *PublicBlockChainAPI) GetAccountsState(ctx context.Context, addresses []common.Address, blockNr rpc.BlockNumber) ([]*AccountState, error) {
+	if len(addresses) > maxAccountsState {
+		return nil, fmt.Errorf("the number of addresses should not exceed %d", maxAccountsState)
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*AccountState, len(addresses))
+	for i, address := range addresses {
+		accounts[i] = &AccountState{
+			Address:  address,
+			Balance:  (*hexutil.Big)(state.GetBalance(address)),
+			Nonce:    hexutil.Uint64(state.GetNonce(address)),
+			CodeHash: state.GetCodeHash(address),
+			KeyType:  state.GetKey(address).Type(),
+		}
+	}
+	return accounts, state.Error()
+}
+
 // WriteThroughCaching returns if write through caching is enabled or not.
 // If enabled, when data write happens, cache write happens at the same time.
 func (s *PublicBlockChainAPI) WriteThroughCaching() bool {
-	return common.WriteThroughCaching
+	return s.b.ChainDB().IsWriteThroughCaching()
 }
 
 // IsParallelDBWrite returns if parallel write is enabled or not.
@@ -364,6 +662,54 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	return hexutil.Uint64(hi), nil
 }
 
+// FeeSplit is the division of a transaction fee between the sender and the fee payer.
+type FeeSplit struct {
+	SenderFee   *hexutil.Big   `json:"senderFee"`
+	FeePayerFee *hexutil.Big   `json:"feePayerFee"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+}
+
+// EstimateFeeSplit estimates the gas needed to execute the given transaction as a fee-delegated
+// transaction with a fee ratio and returns how its fee is divided between the sender and a fee
+// payer bearing feeRatio percent of it. The gas includes the intrinsic gas of fee delegation with
+// a ratio and the gas validating the signatures of the fee payer, if given, at the latest block.
+// If no gas price is given, the suggested gas price is used.
+func (s *PublicBlockChainAPI) EstimateFeeSplit(ctx context.Context, args CallArgs, feeRatio types.FeeRatio, feePayer *common.Address) (*FeeSplit, error) {
+	if feeRatio > 100 {
+		return nil, fmt.Errorf("fee ratio should be in [0, 100], got %d", feeRatio)
+	}
+	gasUsed, err := s.EstimateGas(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	gasUsed += hexutil.Uint64(params.TxGasFeeDelegatedWithRatio)
+	if feePayer != nil {
+		state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		gasKey, err := state.GetKey(*feePayer).SigValidationGas(header.Number.Uint64(), accountkey.RoleFeePayer)
+		if err != nil {
+			return nil, err
+		}
+		gasUsed += hexutil.Uint64(gasKey)
+	}
+	gasPrice := args.GasPrice.ToInt()
+	if gasPrice.Sign() == 0 {
+		if gasPrice, err = s.b.SuggestPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(uint64(gasUsed)), gasPrice)
+	feePayerFee, senderFee := types.CalcFeeWithRatio(feeRatio, fee)
+
+	return &FeeSplit{
+		SenderFee:   (*hexutil.Big)(senderFee),
+		FeePayerFee: (*hexutil.Big)(feePayerFee),
+		GasUsed:     gasUsed,
+	}, nil
+}
+
 // ExecutionResult groups all structured logs emitted by the EVM
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
@@ -426,26 +772,9 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 }
 
 func RpcOutputBlock(b *types.Block, td *big.Int, inclTx bool, fullTx bool) (map[string]interface{}, error) {
-	head := b.Header() // copies the header once
-	fields := map[string]interface{}{
-		"number":           (*hexutil.Big)(head.Number),
-		"hash":             b.Hash(),
-		"parentHash":       head.ParentHash,
-		"logsBloom":        head.Bloom,
-		"stateRoot":        head.Root,
-		"reward":           head.Rewardbase,
-		"blockscore":       (*hexutil.Big)(head.BlockScore),
-		"totalBlockScore":  (*hexutil.Big)(td),
-		"extraData":        hexutil.Bytes(head.Extra),
-		"governanceData":   hexutil.Bytes(head.Governance),
-		"voteData":         hexutil.Bytes(head.Vote),
-		"size":             hexutil.Uint64(b.Size()),
-		"gasUsed":          hexutil.Uint64(head.GasUsed),
-		"timestamp":        (*hexutil.Big)(head.Time),
-		"timestampFoS":     (hexutil.Uint)(head.TimeFoS),
-		"transactionsRoot": head.TxHash,
-		"receiptsRoot":     head.ReceiptHash,
-	}
+	fields := rpcOutputHeader(b.Header()) // copies the header once
+	fields["totalBlockScore"] = (*hexutil.Big)(td)
+	fields["size"] = hexutil.Uint64(b.Size())
 
 	if inclTx {
 		formatTx := func(tx *types.Transaction) (interface{}, error) {
@@ -472,6 +801,27 @@ func RpcOutputBlock(b *types.Block, td *big.Int, inclTx bool, fullTx bool) (map[
 	return fields, nil
 }
 
+// rpcOutputHeader converts the given header to the header fields of the RPC block output.
+func rpcOutputHeader(head *types.Header) map[string]interface{} {
+	return map[string]interface{}{
+		"number":           (*hexutil.Big)(head.Number),
+		"hash":             head.Hash(),
+		"parentHash":       head.ParentHash,
+		"logsBloom":        head.Bloom,
+		"stateRoot":        head.Root,
+		"reward":           head.Rewardbase,
+		"blockscore":       (*hexutil.Big)(head.BlockScore),
+		"extraData":        hexutil.Bytes(head.Extra),
+		"governanceData":   hexutil.Bytes(head.Governance),
+		"voteData":         hexutil.Bytes(head.Vote),
+		"gasUsed":          hexutil.Uint64(head.GasUsed),
+		"timestamp":        (*hexutil.Big)(head.Time),
+		"timestampFoS":     (hexutil.Uint)(head.TimeFoS),
+		"transactionsRoot": head.TxHash,
+		"receiptsRoot":     head.ReceiptHash,
+	}
+}
+
 // rpcOutputBlock converts the given block to the RPC output which depends on fullTx. If inclTx is true transactions are
 // returned. When fullTx is true the returned block contains full transaction details, otherwise it will only contain
 // transaction hashes.
@@ -0,0 +1,203 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/klaytn/klaytn/accounts/abi"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/networks/rpc"
+)
+
+var errNonCanonicalBlock = errors.New("block hash is not canonical")
+
+// CallByABI executes a call of the given method of the contract at address
+// without creating a transaction. The call data is packed and the returned
+// data is unpacked with the given JSON ABI, so typed calls can be made without
+// precompiled bindings. Arguments are given as JSON values: numbers as JSON
+// numbers or decimal/hex strings, addresses and bytes as hex strings.
+func (s *PublicBlockChainAPI) CallByABI(ctx context.Context, address common.Address, abiJSON string, method string, args []json.RawMessage, blockNrOrHash rpc.BlockNumberOrHash) ([]interface{}, error) {
+	blockNr, err := s.resolveBlockNumber(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return callByABI(abiJSON, method, args, func(data []byte) ([]byte, error) {
+		result, _, _, _, err := s.doCall(ctx, CallArgs{To: &address, Data: data}, blockNr, vm.Config{}, localTxExecutionTime)
+		return result, err
+	})
+}
+
+// resolveBlockNumber returns the number of the canonical block specified by
+// the given argument.
+func (s *PublicBlockChainAPI) resolveBlockNumber(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (rpc.BlockNumber, error) {
+	if blockNr, ok := blockNrOrHash.Number(); ok {
+		return blockNr, nil
+	}
+	hash, _ := blockNrOrHash.Hash()
+	block, err := s.b.GetBlock(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+	if block == nil {
+		return 0, fmt.Errorf("block %x not found", hash)
+	}
+	header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(block.NumberU64()))
+	if err != nil {
+		return 0, err
+	}
+	if header == nil || header.Hash() != hash {
+		return 0, errNonCanonicalBlock
+	}
+	return rpc.BlockNumber(block.NumberU64()), nil
+}
+
+// callByABI packs a call of method with args according to abiJSON, executes
+// it with call and returns the unpacked outputs.
+func callByABI(abiJSON string, method string, args []json.RawMessage, call func(data []byte) ([]byte, error)) ([]interface{}, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %v", err)
+	}
+	m, ok := parsed.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %q not found in ABI", method)
+	}
+	if len(args) != len(m.Inputs) {
+		return nil, fmt.Errorf("method %q takes %d arguments, got %d", method, len(m.Inputs), len(args))
+	}
+	values := make([]interface{}, len(args))
+	for i, input := range m.Inputs {
+		v, err := abiValue(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d (%s): %v", i, input.Type.String(), err)
+		}
+		values[i] = v.Interface()
+	}
+	data, err := parsed.Pack(method, values...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := call(data)
+	if err != nil {
+		return nil, err
+	}
+	return m.Outputs.UnpackValues(output)
+}
+
+// abiValue converts a JSON value into a value of the Go type used by the abi
+// package for t.
+func abiValue(t abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := abiInteger(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.Type == reflect.TypeOf(&big.Int{}) {
+			return reflect.ValueOf(n), nil
+		}
+		if (t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size)) || (t.T == abi.IntTy && !n.IsInt64()) {
+			return reflect.Value{}, fmt.Errorf("%v overflows %s", n, t.String())
+		}
+		v := reflect.New(t.Type).Elem()
+		if t.T == abi.UintTy {
+			v.SetUint(n.Uint64())
+		} else {
+			v.SetInt(n.Int64())
+			if v.Int() != n.Int64() {
+				return reflect.Value{}, fmt.Errorf("%v overflows %s", n, t.String())
+			}
+		}
+		return v, nil
+	case abi.BoolTy:
+		var b bool
+		err := json.Unmarshal(raw, &b)
+		return reflect.ValueOf(b), err
+	case abi.StringTy:
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return reflect.ValueOf(s), err
+	case abi.AddressTy:
+		var addr common.Address
+		err := json.Unmarshal(raw, &addr)
+		return reflect.ValueOf(addr), err
+	case abi.BytesTy:
+		var b hexutil.Bytes
+		err := json.Unmarshal(raw, &b)
+		return reflect.ValueOf([]byte(b)), err
+	case abi.FixedBytesTy:
+		var b hexutil.Bytes
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(t.Type).Elem()
+		reflect.Copy(v, reflect.ValueOf([]byte(b)))
+		return v, nil
+	case abi.SliceTy, abi.ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.Type, len(elems), len(elems))
+		} else {
+			if len(elems) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(elems))
+			}
+			v = reflect.New(t.Type).Elem()
+		}
+		for i, elem := range elems {
+			ev, err := abiValue(*t.Elem, elem)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %v", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t.String())
+}
+
+// abiInteger parses a JSON number or a decimal or 0x-prefixed hex string.
+func abiInteger(raw json.RawMessage) (*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var num json.Number
+		if err := json.Unmarshal(raw, &num); err != nil {
+			return nil, err
+		}
+		s = num.String()
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts/abi"
+	"github.com/klaytn/klaytn/accounts/abi/bind"
+	"github.com/klaytn/klaytn/accounts/abi/bind/backends"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/common"
+	sctoken "github.com/klaytn/klaytn/contracts/servicechain_token"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallByABI(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	auth := bind.NewKeyedTransactor(key)
+	// The token requires its bridge to be a contract.
+	bridgeAddr := common.HexToAddress("0x1000")
+	sim := backends.NewSimulatedBackend(blockchain.GenesisAlloc{
+		auth.From:  {Balance: big.NewInt(params.KLAY)},
+		bridgeAddr: {Code: []byte{0x00}, Balance: common.Big0},
+	})
+
+	tokenAddr, _, token, err := sctoken.DeployServiceChainToken(auth, sim, bridgeAddr)
+	require.NoError(t, err)
+	sim.Commit()
+
+	call := func(data []byte) ([]byte, error) {
+		return sim.CallContract(context.Background(), klaytn.CallMsg{To: &tokenAddr, Data: data}, nil)
+	}
+	arg := func(v interface{}) json.RawMessage {
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		return raw
+	}
+
+	// Compare the results with the ones of the binding.
+	name, err := token.Name(nil)
+	require.NoError(t, err)
+	out, err := callByABI(sctoken.ServiceChainTokenABI, "name", nil, call)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{name}, out)
+
+	balance, err := token.BalanceOf(nil, auth.From)
+	require.NoError(t, err)
+	require.NotZero(t, balance.Sign())
+	out, err = callByABI(sctoken.ServiceChainTokenABI, "balanceOf", []json.RawMessage{arg(auth.From)}, call)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, 0, balance.Cmp(out[0].(*big.Int)))
+
+	// Invalid ABI, method and arguments are rejected.
+	_, err = callByABI("not an abi", "name", nil, call)
+	assert.Error(t, err)
+	_, err = callByABI(sctoken.ServiceChainTokenABI, "balanceOfMine", nil, call)
+	assert.Error(t, err)
+	_, err = callByABI(sctoken.ServiceChainTokenABI, "balanceOf", nil, call)
+	assert.Error(t, err)
+	_, err = callByABI(sctoken.ServiceChainTokenABI, "balanceOf", []json.RawMessage{arg("0x1234")}, call)
+	assert.Error(t, err)
+}
+
+func TestABIValue(t *testing.T) {
+	var parsed []abi.Type
+	for _, typ := range []string{"uint8", "int256", "bytes2", "address[]"} {
+		parsedType, err := abi.NewType(typ)
+		require.NoError(t, err)
+		parsed = append(parsed, parsedType)
+	}
+
+	v, err := abiValue(parsed[0], json.RawMessage(`"0xff"`))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(255), v.Interface())
+	_, err = abiValue(parsed[0], json.RawMessage(`256`))
+	assert.Error(t, err)
+
+	v, err = abiValue(parsed[1], json.RawMessage(`"-12345678901234567890"`))
+	require.NoError(t, err)
+	expected, _ := new(big.Int).SetString("-12345678901234567890", 10)
+	assert.Equal(t, 0, expected.Cmp(v.Interface().(*big.Int)))
+
+	v, err = abiValue(parsed[2], json.RawMessage(`"0xabcd"`))
+	require.NoError(t, err)
+	assert.Equal(t, [2]byte{0xab, 0xcd}, v.Interface())
+	_, err = abiValue(parsed[2], json.RawMessage(`"0xab"`))
+	assert.Error(t, err)
+
+	v, err = abiValue(parsed[3], json.RawMessage(`["0x0000000000000000000000000000000000000001"]`))
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{common.HexToAddress("0x1")}, v.Interface())
+}
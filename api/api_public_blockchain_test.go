@@ -0,0 +1,471 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+// canonicalBackend is a Backend serving a canonical chain of header-only blocks.
+type canonicalBackend struct {
+	Backend
+	db     database.DBManager
+	blocks []*types.Block
+}
+
+func newCanonicalBackend(n int) *canonicalBackend {
+	return newCanonicalBackendWithGasUsed(make([]uint64, n))
+}
+
+// newCanonicalBackendWithGasUsed returns a canonicalBackend whose i-th block used gasUsed[i].
+func newCanonicalBackendWithGasUsed(gasUsed []uint64) *canonicalBackend {
+	b := &canonicalBackend{db: database.NewMemoryDBManager()}
+	parent := common.Hash{}
+	for i := range gasUsed {
+		block := types.NewBlockWithHeader(&types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			BlockScore: big.NewInt(1),
+			Time:       big.NewInt(int64(i)),
+			GasUsed:    gasUsed[i],
+		})
+		b.db.WriteHeader(block.Header())
+		b.db.WriteCanonicalHash(block.Hash(), block.NumberU64())
+		b.blocks = append(b.blocks, block)
+		parent = block.Hash()
+	}
+	return b
+}
+
+func (b *canonicalBackend) ChainDB() database.DBManager { return b.db }
+
+func (b *canonicalBackend) CurrentBlock() *types.Block { return b.blocks[len(b.blocks)-1] }
+
+func (b *canonicalBackend) GetTd(hash common.Hash) *big.Int { return big.NewInt(1) }
+
+func (b *canonicalBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	block, err := b.BlockByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+func (b *canonicalBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return b.CurrentBlock(), nil
+	}
+	return b.blocks[blockNr], nil
+}
+
+func TestGetBlockHashByNumber(t *testing.T) {
+	api := NewPublicBlockChainAPI(newCanonicalBackend(4))
+	ctx := context.Background()
+
+	for _, blockNr := range []rpc.BlockNumber{0, 1, 3, rpc.LatestBlockNumber} {
+		hash, err := api.GetBlockHashByNumber(ctx, blockNr)
+		require.NoError(t, err)
+
+		block, err := api.GetBlockByNumber(ctx, blockNr, false)
+		require.NoError(t, err)
+		assert.Equal(t, block["hash"], *hash, "block number %d", blockNr)
+	}
+
+	// The pending block has no canonical hash.
+	hash, err := api.GetBlockHashByNumber(ctx, rpc.PendingBlockNumber)
+	assert.NoError(t, err)
+	assert.Nil(t, hash)
+
+	// Unknown block numbers return an error.
+	hash, err = api.GetBlockHashByNumber(ctx, 4)
+	assert.Error(t, err)
+	assert.Nil(t, hash)
+}
+
+func TestGetHeadersByRange(t *testing.T) {
+	api := NewPublicBlockChainAPI(newCanonicalBackend(10))
+	ctx := context.Background()
+
+	tests := []struct {
+		start   rpc.BlockNumber
+		count   uint64
+		skip    uint64
+		reverse bool
+		want    []rpc.BlockNumber
+	}{
+		{0, 3, 0, false, []rpc.BlockNumber{0, 1, 2}},
+		{2, 4, 2, false, []rpc.BlockNumber{2, 5, 8}}, // stops at the head
+		{9, 3, 0, true, []rpc.BlockNumber{9, 8, 7}},  // towards the genesis
+		{8, 5, 3, true, []rpc.BlockNumber{8, 4, 0}},  // stops at the genesis
+		{rpc.LatestBlockNumber, 2, 1, true, []rpc.BlockNumber{9, 7}},
+		{10, 1, 0, false, []rpc.BlockNumber{}}, // beyond the head
+	}
+	for i, test := range tests {
+		headers, err := api.GetHeadersByRange(ctx, test.start, test.count, test.skip, test.reverse)
+		require.NoError(t, err, "test %d", i)
+		require.Len(t, headers, len(test.want), "test %d", i)
+
+		for j, blockNr := range test.want {
+			block, err := api.GetBlockByNumber(ctx, blockNr, false)
+			require.NoError(t, err)
+			for field, value := range headers[j] {
+				assert.Equal(t, block[field], value, "test %d, header %d, field %s", i, j, field)
+			}
+		}
+	}
+
+	// The number of headers is bounded.
+	_, err := api.GetHeadersByRange(ctx, 0, 0, 0, false)
+	assert.Error(t, err)
+	_, err = api.GetHeadersByRange(ctx, 0, maxHeadersByRange+1, 0, false)
+	assert.Error(t, err)
+}
+
+func TestGasUsedStats(t *testing.T) {
+	// Blocks from empty to full, in a shuffled order.
+	gasUsed := []uint64{900, 0, 500, 100, 300, 1000, 200, 700, 400, 600, 800}
+	api := NewPublicBlockChainAPI(newCanonicalBackendWithGasUsed(gasUsed))
+	ctx := context.Background()
+
+	// The latest 10 blocks exclude the genesis block.
+	stats, err := api.GasUsedStats(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, &GasUsedStats{
+		FromBlock: 1,
+		ToBlock:   10,
+		Min:       0,
+		Max:       1000,
+		Avg:       460,
+		P25:       200,
+		P50:       400,
+		P75:       600,
+		P90:       800,
+	}, stats)
+
+	// The range is limited by the genesis block.
+	stats, err = api.GasUsedStats(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(0), stats.FromBlock)
+	assert.Equal(t, hexutil.Uint64(500), stats.Avg)
+
+	_, err = api.GasUsedStats(ctx, 0)
+	assert.Error(t, err)
+	_, err = api.GasUsedStats(ctx, maxGasUsedStatsBlocks+1)
+	assert.Error(t, err)
+}
+
+func TestGetAccountCreationInfo(t *testing.T) {
+	b := newCanonicalBackend(2)
+	api := NewPublicBlockChainAPI(b)
+
+	created := common.HexToAddress("0x1")
+	info := &database.AccountCreationInfo{
+		TxHash:        common.HexToHash("0x2"),
+		BlockHash:     b.blocks[1].Hash(),
+		BlockNumber:   1,
+		TxType:        types.TxTypeAccountCreation,
+		HumanReadable: true,
+	}
+	require.NoError(t, b.db.WriteAccountCreationInfo(created, info))
+
+	assert.Equal(t, map[string]interface{}{
+		"transactionHash": info.TxHash,
+		"type":            "TxTypeAccountCreation",
+		"typeInt":         types.TxTypeAccountCreation,
+		"blockHash":       info.BlockHash,
+		"blockNumber":     hexutil.Uint64(1),
+		"humanReadable":   true,
+	}, api.GetAccountCreationInfo(created))
+
+	// Accounts without recorded creation info return nil.
+	assert.Nil(t, api.GetAccountCreationInfo(common.HexToAddress("0x3")))
+
+	// Info recorded by a block which is no longer canonical is ignored.
+	sideCreated := common.HexToAddress("0x4")
+	require.NoError(t, b.db.WriteAccountCreationInfo(sideCreated, &database.AccountCreationInfo{
+		TxHash:      common.HexToHash("0x5"),
+		BlockHash:   common.HexToHash("0x6"),
+		BlockNumber: 1,
+		TxType:      types.TxTypeValueTransfer,
+	}))
+	assert.Nil(t, api.GetAccountCreationInfo(sideCreated))
+}
+
+func TestGetAccountKeyHistory(t *testing.T) {
+	b := newCanonicalBackend(4)
+	api := NewPublicBlockChainAPI(b)
+
+	addr := common.HexToAddress("0x1")
+	prvKey1, _ := crypto.GenerateKey()
+	prvKey2, _ := crypto.GenerateKey()
+	keys := []accountkey.AccountKey{
+		accountkey.NewAccountKeyLegacy(),
+		accountkey.NewAccountKeyPublicWithValue(&prvKey1.PublicKey),
+		accountkey.NewAccountKeyPublicWithValue(&prvKey2.PublicKey),
+	}
+	var encs [][]byte
+	for _, key := range keys {
+		enc, err := rlp.EncodeToBytes(accountkey.NewAccountKeySerializerWithAccountKey(key))
+		require.NoError(t, err)
+		encs = append(encs, enc)
+	}
+	for i := 1; i < len(keys); i++ {
+		require.NoError(t, b.db.WriteAccountKeyChange(addr, &database.AccountKeyChange{
+			TxHash:      common.BigToHash(big.NewInt(int64(i))),
+			BlockHash:   b.blocks[i+1].Hash(),
+			BlockNumber: uint64(i + 1),
+			OldKey:      encs[i-1],
+			NewKey:      encs[i],
+		}))
+	}
+	// Changes of blocks which are no longer canonical are ignored.
+	require.NoError(t, b.db.WriteAccountKeyChange(addr, &database.AccountKeyChange{
+		BlockHash:   common.HexToHash("0xdead"),
+		BlockNumber: 1,
+		OldKey:      encs[0],
+		NewKey:      encs[2],
+	}))
+
+	history, err := api.GetAccountKeyHistory(context.Background(), addr, 0, rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for i, change := range history {
+		assert.Equal(t, hexutil.Uint64(i+2), change["blockNumber"])
+		assert.Equal(t, b.blocks[i+2].Hash(), change["blockHash"])
+		assert.True(t, keys[i].Equal(change["oldKey"].(*accountkey.AccountKeySerializer).GetKey()))
+		assert.True(t, keys[i+1].Equal(change["newKey"].(*accountkey.AccountKeySerializer).GetKey()))
+	}
+
+	history, err = api.GetAccountKeyHistory(context.Background(), addr, 3, 3)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, hexutil.Uint64(3), history[0]["blockNumber"])
+
+	// Accounts without recorded key changes have an empty history.
+	history, err = api.GetAccountKeyHistory(context.Background(), common.HexToAddress("0x2"), 0, rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = api.GetAccountKeyHistory(context.Background(), addr, 3, 2)
+	assert.Error(t, err)
+}
+
+// genesisBackend is a Backend serving a committed genesis block and its state.
+type genesisBackend struct {
+	Backend
+	db      database.DBManager
+	genesis *types.Block
+}
+
+func (b *genesisBackend) ChainDB() database.DBManager { return b.db }
+
+func (b *genesisBackend) GetTd(hash common.Hash) *big.Int { return b.genesis.BlockScore() }
+
+func (b *genesisBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	stateDB, err := state.New(b.genesis.Root(), state.NewDatabase(b.db))
+	return stateDB, b.genesis.Header(), err
+}
+
+func TestGetGenesis(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	config := *params.TestChainConfig
+	config.ChainID = big.NewInt(1234)
+	alloc := blockchain.GenesisAlloc{
+		common.HexToAddress("0x1"): {Balance: big.NewInt(100)},
+		common.HexToAddress("0x2"): {Balance: big.NewInt(200)},
+	}
+	db := database.NewMemoryDBManager()
+	genesis := (&blockchain.Genesis{Config: &config, Alloc: alloc}).MustCommit(db)
+	api := NewPublicBlockChainAPI(&genesisBackend{db: db, genesis: genesis})
+
+	// The genesis hash and the chain ID are returned without the alloc by default.
+	result, err := api.GetGenesis(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, genesis.Hash(), result.Hash)
+	assert.Equal(t, genesis.Hash(), result.Block["hash"])
+	assert.Equal(t, 0, config.ChainID.Cmp(result.ChainID.ToInt()))
+	assert.Nil(t, result.Alloc)
+
+	// The alloc is returned on request.
+	includeAlloc := true
+	result, err = api.GetGenesis(context.Background(), &includeAlloc)
+	require.NoError(t, err)
+	require.Len(t, result.Alloc, len(alloc))
+	for addr, account := range alloc {
+		assert.Equal(t, 0, account.Balance.Cmp(result.Alloc[addr].ToInt()), "balance of %x", addr)
+	}
+	assert.False(t, result.AllocTruncated)
+}
+
+func TestGetAccountsState(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	addrs := []common.Address{
+		common.HexToAddress("0x1"),
+		common.HexToAddress("0x2"),
+		common.HexToAddress("0x3"),
+		common.HexToAddress("0x4"), // not in the genesis alloc
+	}
+	alloc := blockchain.GenesisAlloc{
+		addrs[0]: {Balance: big.NewInt(100)},
+		addrs[1]: {Balance: big.NewInt(200), Nonce: 7},
+		addrs[2]: {Balance: big.NewInt(0), Code: common.FromHex("0x6000")},
+	}
+	db := database.NewMemoryDBManager()
+	genesis := (&blockchain.Genesis{Config: params.TestChainConfig, Alloc: alloc}).MustCommit(db)
+	b := &genesisBackend{db: db, genesis: genesis}
+	api := NewPublicBlockChainAPI(b)
+	txPoolAPI := NewPublicTransactionPoolAPI(b, nil)
+
+	ctx := context.Background()
+	accounts, err := api.GetAccountsState(ctx, addrs, 0)
+	require.NoError(t, err)
+	require.Len(t, accounts, len(addrs))
+
+	// The batch results match the results of the single-account queries.
+	for i, addr := range addrs {
+		assert.Equal(t, addr, accounts[i].Address)
+
+		balance, err := api.GetBalance(ctx, addr, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, balance.Cmp(accounts[i].Balance.ToInt()), "balance of %x", addr)
+
+		nonce, err := txPoolAPI.GetTransactionCount(ctx, addr, 0)
+		require.NoError(t, err)
+		assert.Equal(t, *nonce, accounts[i].Nonce, "nonce of %x", addr)
+
+		code, err := api.GetCode(ctx, addr, 0)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.Keccak256Hash(code), accounts[i].CodeHash, "code hash of %x", addr)
+
+		key, err := api.GetAccountKey(ctx, addr, 0)
+		require.NoError(t, err)
+		if key != nil {
+			assert.Equal(t, key.GetKey().Type(), accounts[i].KeyType, "key type of %x", addr)
+		} else {
+			assert.Equal(t, accountkey.AccountKeyTypeLegacy, accounts[i].KeyType, "key type of %x", addr)
+		}
+	}
+
+	_, err = api.GetAccountsState(ctx, make([]common.Address, maxAccountsState+1), 0)
+	assert.Error(t, err)
+}
+
+// evmBackend is a genesisBackend which can also execute calls on the genesis state.
+type evmBackend struct {
+	*genesisBackend
+	gasPrice *big.Int
+}
+
+func (b *evmBackend) SuggestPrice(ctx context.Context) (*big.Int, error) { return b.gasPrice, nil }
+
+func (b *evmBackend) GetEVM(ctx context.Context, msg blockchain.Message, state *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+	state.SetBalance(msg.ValidatedSender(), math.MaxBig256)
+	context := blockchain.NewEVMContext(msg, header, nil, &common.Address{})
+	return vm.NewEVM(context, state, params.TestChainConfig, &vmCfg), func() error { return nil }, nil
+}
+
+func TestEstimateFeeSplit(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	db := database.NewMemoryDBManager()
+	genesis := (&blockchain.Genesis{Config: params.TestChainConfig}).MustCommit(db)
+	b := &evmBackend{genesisBackend: &genesisBackend{db: db, genesis: genesis}, gasPrice: big.NewInt(25 * params.Ston)}
+	api := NewPublicBlockChainAPI(b)
+
+	to := common.HexToAddress("0x1000")
+	args := CallArgs{From: common.HexToAddress("0x2000"), To: &to, Value: hexutil.Big(*big.NewInt(1))}
+	gas := params.TxGas + params.TxGasFeeDelegatedWithRatio
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), b.gasPrice)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		feeRatio    types.FeeRatio
+		feePayerFee *big.Int
+	}{
+		{0, big.NewInt(0)},
+		{30, new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(30)), common.Big100)},
+		{90, new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(90)), common.Big100)},
+		{100, fee},
+	} {
+		split, err := api.EstimateFeeSplit(ctx, args, tc.feeRatio, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, hexutil.Uint64(gas), split.GasUsed, "ratio %d", tc.feeRatio)
+		assert.Equal(t, 0, tc.feePayerFee.Cmp(split.FeePayerFee.ToInt()), "ratio %d", tc.feeRatio)
+		total := new(big.Int).Add(split.SenderFee.ToInt(), split.FeePayerFee.ToInt())
+		assert.Equal(t, 0, fee.Cmp(total), "ratio %d", tc.feeRatio)
+	}
+
+	// An explicit gas price takes precedence over the suggested one.
+	args.GasPrice = hexutil.Big(*big.NewInt(1))
+	split, err := api.EstimateFeeSplit(ctx, args, 50, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(gas/2), split.FeePayerFee.ToInt().Int64())
+	assert.Equal(t, int64(gas/2), split.SenderFee.ToInt().Int64())
+
+	// The signatures of a fee payer with a multisig key take more gas to validate.
+	feePayer := common.HexToAddress("0x3000")
+	stateDB, err := state.New(genesis.Root(), state.NewDatabase(db))
+	require.NoError(t, err)
+	var keys accountkey.WeightedPublicKeys
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, accountkey.NewWeightedPublicKey(1, (*accountkey.PublicKeySerializable)(&key.PublicKey)))
+	}
+	stateDB.CreateEOA(feePayer, false, accountkey.NewAccountKeyWeightedMultiSigWithValues(2, keys))
+	root, err := stateDB.Commit(false)
+	require.NoError(t, err)
+	require.NoError(t, stateDB.Database().TrieDB().Commit(root, false))
+	b.genesis = types.NewBlockWithHeader(&types.Header{Number: common.Big0, Root: root})
+
+	split, err = api.EstimateFeeSplit(ctx, args, 50, &feePayer)
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(gas+2*params.TxValidationGasPerKey), split.GasUsed)
+
+	_, err = api.EstimateFeeSplit(ctx, args, 101, nil)
+	assert.Error(t, err)
+}
+
+func TestCheckReceiptsPruned(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	b := &genesisBackend{db: db}
+
+	assert.NoError(t, checkReceiptsPruned(b, 0))
+
+	db.WriteEarliestReceiptsBlockNumber(10)
+	assert.Error(t, checkReceiptsPruned(b, 9))
+	assert.NoError(t, checkReceiptsPruned(b, 10))
+}
@@ -22,10 +22,32 @@ package api
 
 import (
 	"context"
+	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/params"
 	"math/big"
 )
 
+// rpcDefaultedTxValueKeys are the value keys filled in by the node when they are
+// not given in a transaction submitted via RPC.
+var rpcDefaultedTxValueKeys = map[types.TxValueKeyType]bool{
+	types.TxValueKeyNonce:    true,
+	types.TxValueKeyGasLimit: true,
+	types.TxValueKeyGasPrice: true,
+}
+
+// TxTypeInfo describes a transaction type and the values composing it.
+type TxTypeInfo struct {
+	Name            string       `json:"name"`
+	Value           hexutil.Uint `json:"value"`
+	RequiredKeys    []string     `json:"requiredKeys"`
+	OptionalKeys    []string     `json:"optionalKeys"`
+	FeeDelegated    bool         `json:"feeDelegated"`
+	FeeRatio        bool         `json:"feeRatio"`
+	AccountCreation bool         `json:"accountCreation"`
+	ContractDeploy  bool         `json:"contractDeploy"`
+}
+
 // PublicKlayAPI provides an API to access Klaytn related information.
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicKlayAPI struct {
@@ -70,3 +92,37 @@ func (s *PublicKlayAPI) Syncing() (interface{}, error) {
 		"knownStates":   hexutil.Uint64(progress.KnownStates),
 	}, nil
 }
+
+// ForkStatus returns the hardforks of the chain config with their activation blocks
+// and whether they are active at the current head block.
+func (s *PublicKlayAPI) ForkStatus() []params.ForkStatus {
+	return params.ForkStatuses(s.b.ChainConfig().Forks(), s.b.CurrentBlock().Number())
+}
+
+// TxTypes returns the supported transaction types with their value keys and properties.
+// Optional keys are the ones filled in by the node if they are omitted in an RPC request.
+func (s *PublicKlayAPI) TxTypes() []TxTypeInfo {
+	txTypes := types.SupportedTxTypes()
+	infos := make([]TxTypeInfo, 0, len(txTypes))
+	for _, txType := range txTypes {
+		info := TxTypeInfo{
+			Name:            txType.String(),
+			Value:           hexutil.Uint(txType),
+			RequiredKeys:    []string{},
+			OptionalKeys:    []string{},
+			FeeDelegated:    txType.IsFeeDelegatedTransaction(),
+			FeeRatio:        txType.IsFeeDelegatedWithRatioTransaction(),
+			AccountCreation: txType.IsAccountCreation(),
+			ContractDeploy:  txType.IsContractDeploy(),
+		}
+		for _, key := range txType.ValueKeys() {
+			if rpcDefaultedTxValueKeys[key] {
+				info.OptionalKeys = append(info.OptionalKeys, key.String())
+			} else {
+				info.RequiredKeys = append(info.RequiredKeys, key.String())
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"testing"
+)
+
+// TestTxTypes checks that klay_txTypes describes all tx types enumerated in
+// tests/tx_validation_test.go with correct flags.
+func TestTxTypes(t *testing.T) {
+	testTxTypes := []struct {
+		txType         types.TxType
+		feeDelegated   bool
+		feeRatio       bool
+		contractDeploy bool
+	}{
+		{types.TxTypeLegacyTransaction, false, false, false},
+		{types.TxTypeValueTransfer, false, false, false},
+		{types.TxTypeValueTransferMemo, false, false, false},
+		{types.TxTypeAccountUpdate, false, false, false},
+		{types.TxTypeSmartContractDeploy, false, false, true},
+		{types.TxTypeSmartContractExecution, false, false, false},
+		{types.TxTypeCancel, false, false, false},
+		{types.TxTypeChainDataAnchoring, false, false, false},
+		{types.TxTypeFeeDelegatedValueTransfer, true, false, false},
+		{types.TxTypeFeeDelegatedValueTransferMemo, true, false, false},
+		{types.TxTypeFeeDelegatedAccountUpdate, true, false, false},
+		{types.TxTypeFeeDelegatedSmartContractDeploy, true, false, true},
+		{types.TxTypeFeeDelegatedSmartContractExecution, true, false, false},
+		{types.TxTypeFeeDelegatedCancel, true, false, false},
+		{types.TxTypeFeeDelegatedValueTransferWithRatio, true, true, false},
+		{types.TxTypeFeeDelegatedValueTransferMemoWithRatio, true, true, false},
+		{types.TxTypeFeeDelegatedAccountUpdateWithRatio, true, true, false},
+		{types.TxTypeFeeDelegatedSmartContractDeployWithRatio, true, true, true},
+		{types.TxTypeFeeDelegatedSmartContractExecutionWithRatio, true, true, false},
+		{types.TxTypeFeeDelegatedCancelWithRatio, true, true, false},
+	}
+
+	infos := make(map[string]TxTypeInfo)
+	for _, info := range NewPublicKlayAPI(nil).TxTypes() {
+		infos[info.Name] = info
+	}
+	if len(infos) != len(testTxTypes) {
+		t.Errorf("tx type count mismatch: have %d, want %d", len(infos), len(testTxTypes))
+	}
+
+	for _, tc := range testTxTypes {
+		info, ok := infos[tc.txType.String()]
+		if !ok {
+			t.Errorf("%v is missing", tc.txType)
+			continue
+		}
+		if uint(info.Value) != uint(tc.txType) {
+			t.Errorf("%v: value mismatch: have %d, want %d", tc.txType, info.Value, tc.txType)
+		}
+		if info.FeeDelegated != tc.feeDelegated || info.FeeRatio != tc.feeRatio ||
+			info.ContractDeploy != tc.contractDeploy || info.AccountCreation {
+			t.Errorf("%v: flags mismatch: %+v", tc.txType, info)
+		}
+		if len(info.RequiredKeys)+len(info.OptionalKeys) != len(tc.txType.ValueKeys()) {
+			t.Errorf("%v: value keys mismatch: %+v", tc.txType, info)
+		}
+		if !containsString(info.OptionalKeys, types.TxValueKeyNonce.String()) {
+			t.Errorf("%v: nonce should be optional: %+v", tc.txType, info)
+		}
+		if containsString(info.RequiredKeys, types.TxValueKeyFeePayer.String()) != tc.feeDelegated {
+			t.Errorf("%v: fee payer key mismatch: %+v", tc.txType, info)
+		}
+		if containsString(info.RequiredKeys, types.TxValueKeyFeeRatioOfFeePayer.String()) != tc.feeRatio {
+			t.Errorf("%v: fee ratio key mismatch: %+v", tc.txType, info)
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"sync"
+	"time"
+)
+
+const (
+	maxSponsorshipTxs = 4096             // Maximum number of txs awaiting a fee payer
+	sponsorshipTxTTL  = 10 * time.Minute // Time a tx awaits a fee payer before it's dropped
+)
+
+var (
+	errNotFeeDelegatedTx     = errors.New("only fee-delegated transactions can be sponsored")
+	errSponsorshipPoolFull   = errors.New("too many transactions are awaiting a fee payer")
+	errSponsorshipTxNotFound = errors.New("transaction awaiting a fee payer is not found")
+)
+
+// sponsorshipPool holds sender-signed fee-delegated txs until fee payers claim them.
+type sponsorshipPool struct {
+	mu  sync.Mutex
+	txs map[common.Hash]*sponsorshipTx // indexed by the sender tx hash
+
+	max int
+	ttl time.Duration
+}
+
+type sponsorshipTx struct {
+	encoded []byte // the tx is decoded again for each claim, as claiming modifies the tx
+	expire  time.Time
+}
+
+func newSponsorshipPool(max int, ttl time.Duration) *sponsorshipPool {
+	return &sponsorshipPool{txs: make(map[common.Hash]*sponsorshipTx), max: max, ttl: ttl}
+}
+
+// add stores the encoded tx under the given sender tx hash.
+func (p *sponsorshipPool) add(senderTxHash common.Hash, encoded []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for hash, tx := range p.txs {
+		if now.After(tx.expire) {
+			delete(p.txs, hash)
+		}
+	}
+	if _, exist := p.txs[senderTxHash]; !exist && len(p.txs) >= p.max {
+		return errSponsorshipPoolFull
+	}
+	p.txs[senderTxHash] = &sponsorshipTx{encoded: encoded, expire: now.Add(p.ttl)}
+	return nil
+}
+
+// get returns a copy of the tx of the given sender tx hash.
+func (p *sponsorshipPool) get(senderTxHash common.Hash) (*types.Transaction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, exist := p.txs[senderTxHash]
+	if !exist || time.Now().After(stored.expire) {
+		delete(p.txs, senderTxHash)
+		return nil, errSponsorshipTxNotFound
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(stored.encoded, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (p *sponsorshipPool) remove(senderTxHash common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.txs, senderTxHash)
+}
+
+// PublicSponsorshipAPI lets senders post fee-delegated transactions signed only by
+// themselves, and lets fee payers managed by this node claim, co-sign and submit them.
+type PublicSponsorshipAPI struct {
+	b    Backend
+	pool *sponsorshipPool
+}
+
+// NewPublicSponsorshipAPI creates a new sponsorship API with an empty holding area.
+func NewPublicSponsorshipAPI(b Backend) *PublicSponsorshipAPI {
+	return &PublicSponsorshipAPI{b, newSponsorshipPool(maxSponsorshipTxs, sponsorshipTxTTL)}
+}
+
+// SubmitForSponsorship stores a fee-delegated transaction signed by its sender until
+// a fee payer claims it. It returns the sender tx hash identifying the transaction.
+func (s *PublicSponsorshipAPI) SubmitForSponsorship(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if !tx.IsFeeDelegatedTransaction() {
+		return common.Hash{}, errNotFeeDelegatedTx
+	}
+	// Reject the tx early if the sender signature is invalid, rather than when it's claimed.
+	statedb, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signer := types.NewEIP155Signer(s.b.ChainConfig().ChainID)
+	if _, err := tx.ValidateSender(signer, statedb, header.Number.Uint64()); err != nil {
+		return common.Hash{}, err
+	}
+
+	senderTxHash := tx.SenderTxHashAll()
+	if err := s.pool.add(senderTxHash, encodedTx); err != nil {
+		return common.Hash{}, err
+	}
+	return senderTxHash, nil
+}
+
+// ClaimSponsorship sets the given fee payer to the transaction awaiting a fee payer,
+// signs it with the fee payer account of this node and submits it to the transaction
+// pool. The fee payer account must be unlocked.
+func (s *PublicSponsorshipAPI) ClaimSponsorship(ctx context.Context, senderTxHash common.Hash, feePayer common.Address) (common.Hash, error) {
+	tx, err := s.pool.get(senderTxHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := tx.SetFeePayer(feePayer); err != nil {
+		return common.Hash{}, err
+	}
+
+	account := accounts.Account{Address: feePayer}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signer := types.NewEIP155Signer(s.b.ChainConfig().ChainID)
+	hash, err := signer.HashFeePayer(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	sig, err := wallet.SignHash(account, hash[:])
+	if err != nil {
+		return common.Hash{}, err
+	}
+	r, ss, v, err := signer.SignatureValues(sig)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := tx.SetFeePayerSignatures(types.TxSignatures{&types.TxSignature{V: v, R: r, S: ss}}); err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, err := submitTransaction(ctx, s.b, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	s.pool.remove(senderTxHash)
+	return txHash, nil
+}
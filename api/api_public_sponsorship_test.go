@@ -0,0 +1,172 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+)
+
+// sponsorshipBackend is a Backend with a state, an account manager and a pool
+// recording the submitted transactions.
+type sponsorshipBackend struct {
+	Backend
+	statedb *state.StateDB
+	am      *accounts.Manager
+	sent    []*types.Transaction
+}
+
+func (b *sponsorshipBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	return b.statedb, &types.Header{Number: big.NewInt(0)}, nil
+}
+
+func (b *sponsorshipBackend) ChainConfig() *params.ChainConfig { return params.TestChainConfig }
+
+func (b *sponsorshipBackend) AccountManager() *accounts.Manager { return b.am }
+
+func (b *sponsorshipBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+// senderSignedTx returns an encoded fee-delegated value transfer signed only by its sender.
+func senderSignedTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey) []byte {
+	tx, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(0),
+		types.TxValueKeyFrom:     crypto.PubkeyToAddress(key.PublicKey),
+		types.TxValueKeyTo:       common.HexToAddress("0xAAAA"),
+		types.TxValueKeyAmount:   big.NewInt(1),
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: big.NewInt(1),
+		types.TxValueKeyFeePayer: common.Address{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestSponsorship(t *testing.T) {
+	keydir, err := ioutil.TempDir("", "klay-sponsorship")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(keydir)
+
+	ks := keystore.NewKeyStore(keydir, keystore.LightScryptN, keystore.LightScryptP)
+	feePayerKey, _ := crypto.GenerateKey()
+	feePayer, err := ks.ImportECDSA(feePayerKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(feePayer, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	senderKey, _ := crypto.GenerateKey()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	statedb.AddBalance(crypto.PubkeyToAddress(senderKey.PublicKey), big.NewInt(1))
+	statedb.AddBalance(feePayer.Address, big.NewInt(1000000))
+
+	b := &sponsorshipBackend{statedb: statedb, am: accounts.NewManager(ks)}
+	api := NewPublicSponsorshipAPI(b)
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	ctx := context.Background()
+
+	senderTxHash, err := api.SubmitForSponsorship(ctx, senderSignedTx(t, signer, senderKey))
+	if err != nil {
+		t.Fatalf("failed to submit for sponsorship: %v", err)
+	}
+	if len(b.sent) != 0 {
+		t.Fatal("a tx awaiting a fee payer should not be submitted")
+	}
+
+	// An account not managed by the node cannot claim the tx.
+	if _, err := api.ClaimSponsorship(ctx, senderTxHash, common.HexToAddress("0xBBBB")); err == nil {
+		t.Error("claiming with an unknown fee payer should fail")
+	}
+
+	txHash, err := api.ClaimSponsorship(ctx, senderTxHash, feePayer.Address)
+	if err != nil {
+		t.Fatalf("failed to claim sponsorship: %v", err)
+	}
+	if len(b.sent) != 1 || b.sent[0].Hash() != txHash {
+		t.Fatalf("the claimed tx is not submitted: %v", b.sent)
+	}
+	tx := b.sent[0]
+	if payer, err := tx.FeePayer(); err != nil || payer != feePayer.Address {
+		t.Errorf("fee payer mismatch: have %v (err %v), want %v", payer, err, feePayer.Address)
+	}
+	if _, err := tx.ValidateSender(signer, statedb, 0); err != nil {
+		t.Errorf("invalid sender signature: %v", err)
+	}
+	if _, err := tx.ValidateFeePayer(signer, statedb, 0); err != nil {
+		t.Errorf("invalid fee payer signature: %v", err)
+	}
+
+	// A claimed tx is removed from the holding area.
+	if _, err := api.ClaimSponsorship(ctx, senderTxHash, feePayer.Address); err != errSponsorshipTxNotFound {
+		t.Errorf("error mismatch: have %v, want %v", err, errSponsorshipTxNotFound)
+	}
+}
+
+func TestSponsorshipPoolLimits(t *testing.T) {
+	pool := newSponsorshipPool(2, time.Hour)
+	for i := byte(1); i <= 2; i++ {
+		if err := pool.add(common.Hash{i}, nil); err != nil {
+			t.Fatalf("failed to add tx %d: %v", i, err)
+		}
+	}
+	if err := pool.add(common.Hash{3}, nil); err != errSponsorshipPoolFull {
+		t.Errorf("error mismatch: have %v, want %v", err, errSponsorshipPoolFull)
+	}
+
+	// Expired txs are dropped to make room for new ones.
+	pool = newSponsorshipPool(1, 0)
+	if err := pool.add(common.Hash{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := pool.get(common.Hash{1}); err != errSponsorshipTxNotFound {
+		t.Errorf("error mismatch: have %v, want %v", err, errSponsorshipTxNotFound)
+	}
+	if err := pool.add(common.Hash{2}, nil); err != nil {
+		t.Errorf("failed to add a tx after the expiry: %v", err)
+	}
+}
@@ -26,12 +26,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/statedb"
 	"math/big"
 )
 
@@ -149,6 +152,80 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 	return nil
 }
 
+// maxTransactionStatuses is the maximum number of hashes of a GetTransactionStatuses call.
+const maxTransactionStatuses = 1000
+
+var errTooManyTransactionStatuses = fmt.Errorf("too many transaction hashes, at most %d allowed", maxTransactionStatuses)
+
+// GetTransactionStatuses returns the status of each of the given transactions:
+// "mined" with its block hash, block number and index, "pending" or "queued" if it
+// is in the pool, "dropped" if it was recently removed from the pool without
+// being mined, or "unknown".
+func (s *PublicTransactionPoolAPI) GetTransactionStatuses(ctx context.Context, hashes []common.Hash) ([]map[string]interface{}, error) {
+	if len(hashes) > maxTransactionStatuses {
+		return nil, errTooManyTransactionStatuses
+	}
+	poolStatuses := s.b.TxPoolStatus(hashes)
+
+	statuses := make([]map[string]interface{}, len(hashes))
+	for i, hash := range hashes {
+		status := map[string]interface{}{"hash": hash}
+		if blockHash, blockNumber, index := s.b.ChainDB().ReadTxLookupEntry(hash); !common.EmptyHash(blockHash) {
+			status["status"] = "mined"
+			status["blockHash"] = blockHash
+			status["blockNumber"] = hexutil.Uint64(blockNumber)
+			status["transactionIndex"] = hexutil.Uint(index)
+		} else {
+			switch poolStatuses[i] {
+			case blockchain.TxStatusPending:
+				status["status"] = "pending"
+			case blockchain.TxStatusQueued:
+				status["status"] = "queued"
+			case blockchain.TxStatusDropped:
+				status["status"] = "dropped"
+			default:
+				status["status"] = "unknown"
+			}
+		}
+		statuses[i] = status
+	}
+	return statuses, nil
+}
+
+var errTransactionProofUnsupported = errors.New("transaction proofs are not supported by the derive sha implementation of the chain")
+
+// GetTransactionProof returns the header of the block including the transaction,
+// the index of the transaction in the block and a merkle proof of the transaction
+// against the transactions root of the header. The proof is the list of RLP encoded
+// trie nodes on the path to the transaction, whose key is the RLP encoded index.
+func (s *PublicTransactionPoolAPI) GetTransactionProof(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	blockHash, _, index := s.b.ChainDB().ReadTxLookupEntry(hash)
+	if common.EmptyHash(blockHash) {
+		return nil, nil
+	}
+	switch s.b.ChainConfig().DeriveShaImpl {
+	case types.ImplDeriveShaSimple, types.ImplDeriveShaConcat:
+		return nil, errTransactionProofUnsupported
+	}
+	block, err := s.b.GetBlock(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := statedb.ProveDerivedSha(block.Transactions(), int(index))
+	if err != nil {
+		return nil, err
+	}
+	proof := make([]hexutil.Bytes, len(nodes))
+	for i, node := range nodes {
+		proof[i] = node
+	}
+	return map[string]interface{}{
+		"header":           block.Header(),
+		"transactionIndex": hexutil.Uint(index),
+		"proof":            proof,
+	}, nil
+}
+
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
 func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	var tx *types.Transaction
@@ -213,7 +290,16 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceiptBySenderTxHash(ctx conte
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
-	return RpcOutputReceipt(s.b.GetTxLookupInfoAndReceipt(ctx, hash)), nil
+	tx, blockHash, blockNumber, index, receipt := s.b.GetTxLookupInfoAndReceipt(ctx, hash)
+	if receipt == nil {
+		// The lookup entry of a transaction outlives the pruned receipt of its block.
+		if lookupBlockHash, lookupBlockNumber, _ := s.b.ChainDB().ReadTxLookupEntry(hash); !common.EmptyHash(lookupBlockHash) {
+			if err := checkReceiptsPruned(s.b, lookupBlockNumber); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return RpcOutputReceipt(tx, blockHash, blockNumber, index, receipt), nil
 }
 
 // GetTransactionReceiptInCache returns the transaction receipt for the given transaction hash.
@@ -480,6 +566,24 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return submitTransaction(ctx, s.b, tx)
 }
 
+// SendRawTransactionConditional will add the signed transaction to the transaction pool
+// to be included in a block only if the given conditions hold at that time. Otherwise
+// the transaction is dropped from the pool rather than executed, unless the block number
+// or the timestamp lower bound is not reached yet. Conditional
+// transactions are not propagated to the peers, so they should be submitted to the
+// nodes which assemble blocks.
+func (s *PublicTransactionPoolAPI) SendRawTransactionConditional(ctx context.Context, encodedTx hexutil.Bytes, conditions types.TxConditions) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := conditions.Validate(); err != nil {
+		return common.Hash{}, err
+	}
+	tx.SetConditions(&conditions)
+	return submitTransaction(ctx, s.b, tx)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Klaytn Signed Message:\n" + len(message) + message).
 //
@@ -505,6 +609,63 @@ func (s *PublicTransactionPoolAPI) Sign(addr common.Address, data hexutil.Bytes)
 	return signature, err
 }
 
+// SignTypedData calculates an ECDSA signature of the typed data as defined by EIP-712:
+// keccak256("\x19\x01" || hashStruct(domain) || hashStruct(message)).
+//
+// The domain must have the chain ID of this network, so that the signature cannot be
+// replayed on the other networks. The V value of the signature will be 27 or 28 like Sign.
+//
+// The account associated with addr must be unlocked.
+func (s *PublicTransactionPoolAPI) SignTypedData(addr common.Address, typedData accounts.TypedData) (hexutil.Bytes, error) {
+	if err := typedData.ValidateChainId(s.b.ChainConfig().ChainID); err != nil {
+		return nil, err
+	}
+	hash, err := typedData.Hash()
+	if err != nil {
+		return nil, err
+	}
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: addr}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := wallet.SignHash(account, hash)
+	if err == nil {
+		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	}
+	return signature, err
+}
+
+// RecoverTypedData returns the address of the account that signed the typed data
+// with SignTypedData. The domain must have the chain ID of this network.
+func (s *PublicTransactionPoolAPI) RecoverTypedData(typedData accounts.TypedData, sig hexutil.Bytes) (common.Address, error) {
+	if err := typedData.ValidateChainId(s.b.ChainConfig().ChainID); err != nil {
+		return common.Address{}, err
+	}
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		return common.Address{}, fmt.Errorf("invalid Klaytn signature (V is not 27 or 28)")
+	}
+	hash, err := typedData.Hash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	// Transform yellow paper V from 27/28 to 0/1 without modifying the given signature
+	rawSig := make([]byte, len(sig))
+	copy(rawSig, sig)
+	rawSig[64] -= 27
+
+	rpk, err := crypto.SigToPub(hash, rawSig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*rpk), nil
+}
+
 // SignTransactionResult represents a RLP encoded signed transaction.
 type SignTransactionResult struct {
 	Raw hexutil.Bytes      `json:"raw"`
@@ -0,0 +1,265 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+)
+
+// txStatusBackend is a Backend with a database of mined transactions, their
+// blocks and fixed pool statuses.
+type txStatusBackend struct {
+	Backend
+	db     database.DBManager
+	pool   map[common.Hash]blockchain.TxStatus
+	blocks map[common.Hash]*types.Block
+}
+
+func (b *txStatusBackend) ChainDB() database.DBManager { return b.db }
+
+func (b *txStatusBackend) ChainConfig() *params.ChainConfig { return params.TestChainConfig }
+
+func (b *txStatusBackend) GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error) {
+	return b.blocks[blockHash], nil
+}
+
+func (b *txStatusBackend) TxPoolStatus(hashes []common.Hash) []blockchain.TxStatus {
+	statuses := make([]blockchain.TxStatus, len(hashes))
+	for i, hash := range hashes {
+		statuses[i] = b.pool[hash]
+	}
+	return statuses
+}
+
+func TestGetTransactionStatuses(t *testing.T) {
+	var (
+		mined   = types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		pending = types.NewTransaction(1, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		queued  = types.NewTransaction(3, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		dropped = types.NewTransaction(4, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		unknown = common.HexToHash("0x1")
+	)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(7)}).WithBody([]*types.Transaction{mined})
+
+	b := &txStatusBackend{db: database.NewMemoryDBManager(), pool: map[common.Hash]blockchain.TxStatus{
+		pending.Hash(): blockchain.TxStatusPending,
+		queued.Hash():  blockchain.TxStatusQueued,
+		dropped.Hash(): blockchain.TxStatusDropped,
+	}}
+	b.db.WriteTxLookupEntries(block)
+	api := NewPublicTransactionPoolAPI(b, new(AddrLocker))
+
+	statuses, err := api.GetTransactionStatuses(context.Background(),
+		[]common.Hash{mined.Hash(), pending.Hash(), queued.Hash(), dropped.Hash(), unknown})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"hash":             mined.Hash(),
+			"status":           "mined",
+			"blockHash":        block.Hash(),
+			"blockNumber":      hexutil.Uint64(7),
+			"transactionIndex": hexutil.Uint(0),
+		},
+		{"hash": pending.Hash(), "status": "pending"},
+		{"hash": queued.Hash(), "status": "queued"},
+		{"hash": dropped.Hash(), "status": "dropped"},
+		{"hash": unknown, "status": "unknown"},
+	}, statuses)
+
+	_, err = api.GetTransactionStatuses(context.Background(), make([]common.Hash, maxTransactionStatuses+1))
+	assert.Equal(t, errTooManyTransactionStatuses, err)
+}
+
+func TestGetTransactionProof(t *testing.T) {
+	types.InitDeriveSha(statedb.DeriveShaOrig{})
+
+	txs := make([]*types.Transaction, 20)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(7)}, txs, nil)
+
+	b := &txStatusBackend{db: database.NewMemoryDBManager(), blocks: map[common.Hash]*types.Block{block.Hash(): block}}
+	b.db.WriteTxLookupEntries(block)
+	api := NewPublicTransactionPoolAPI(b, new(AddrLocker))
+
+	result, err := api.GetTransactionProof(context.Background(), txs[13].Hash())
+	require.NoError(t, err)
+	header := result["header"].(*types.Header)
+	assert.Equal(t, block.Hash(), header.Hash())
+	assert.Equal(t, hexutil.Uint(13), result["transactionIndex"])
+
+	// Verify the proof against the transactions root with only the proof nodes.
+	proofDB := database.NewMemoryDBManager()
+	for _, node := range result["proof"].([]hexutil.Bytes) {
+		proofDB.WriteMerkleProof(crypto.Keccak256(node), node)
+	}
+	key, _ := rlp.EncodeToBytes(uint(13))
+	value, err, _ := statedb.VerifyProof(header.TxHash, key, proofDB)
+	require.NoError(t, err)
+	encoded, _ := rlp.EncodeToBytes(txs[13])
+	assert.Equal(t, encoded, value)
+
+	// Unknown transactions have no proof.
+	result, err = api.GetTransactionProof(context.Background(), common.HexToHash("0x1"))
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestSignTypedData(t *testing.T) {
+	keydir, err := ioutil.TempDir("", "klay-typed-data")
+	require.NoError(t, err)
+	defer os.RemoveAll(keydir)
+
+	ks := keystore.NewKeyStore(keydir, keystore.LightScryptN, keystore.LightScryptP)
+	key, _ := crypto.GenerateKey()
+	account, err := ks.ImportECDSA(key, "")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(account, ""))
+
+	api := NewPublicTransactionPoolAPI(&sponsorshipBackend{am: accounts.NewManager(ks)}, new(AddrLocker))
+
+	var typedData accounts.TypedData
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"types": {
+			"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+			"Order": [{"name": "maker", "type": "address"}, {"name": "items", "type": "Item[]"}],
+			"Item": [{"name": "id", "type": "uint256"}, {"name": "tags", "type": "string[]"}]
+		},
+		"primaryType": "Order",
+		"domain": {"name": "Klaytn Shop", "chainId": 1},
+		"message": {
+			"maker": "0x0000000000000000000000000000000000000001",
+			"items": [{"id": 1, "tags": ["a", "b"]}, {"id": "0x2", "tags": []}]
+		}
+	}`), &typedData))
+
+	sig, err := api.SignTypedData(account.Address, typedData)
+	require.NoError(t, err)
+	signer, err := api.RecoverTypedData(typedData, sig)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address, signer)
+
+	// The signature of the other typed data recovers another address.
+	typedData.Domain.Name = "Other Shop"
+	other, err := api.RecoverTypedData(typedData, sig)
+	require.NoError(t, err)
+	assert.NotEqual(t, account.Address, other)
+
+	// The typed data for another network is neither signed nor recovered.
+	typedData.Domain.ChainId = (*hexutil.Big)(big.NewInt(1001))
+	_, err = api.SignTypedData(account.Address, typedData)
+	assert.Error(t, err)
+	_, err = api.RecoverTypedData(typedData, sig)
+	assert.Error(t, err)
+
+	// The typed data without the chain ID is not signed.
+	typedData.Domain.ChainId = nil
+	typedData.Types[accounts.TypedDataDomainType] = typedData.Types[accounts.TypedDataDomainType][:1]
+	_, err = api.SignTypedData(account.Address, typedData)
+	assert.Error(t, err)
+}
+
+// receiptsBackend is a Backend serving the blocks and the receipts in a database.
+type receiptsBackend struct {
+	Backend
+	db database.DBManager
+}
+
+func (b *receiptsBackend) ChainDB() database.DBManager { return b.db }
+
+func (b *receiptsBackend) GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error) {
+	return b.db.ReadBlockByHash(blockHash), nil
+}
+
+func (b *receiptsBackend) GetBlockReceipts(ctx context.Context, blockHash common.Hash) types.Receipts {
+	return b.db.ReadReceiptsByBlockHash(blockHash)
+}
+
+func (b *receiptsBackend) GetTxLookupInfoAndReceipt(ctx context.Context, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, *types.Receipt) {
+	tx, blockHash, blockNumber, index := b.db.ReadTxAndLookupInfo(hash)
+	if tx == nil {
+		return nil, common.Hash{}, 0, 0, nil
+	}
+	receipt, _, _, _ := b.db.ReadReceipt(hash)
+	if receipt == nil {
+		return nil, common.Hash{}, 0, 0, nil
+	}
+	return tx, blockHash, blockNumber, index, receipt
+}
+
+func TestGetReceiptsPruned(t *testing.T) {
+	b := &receiptsBackend{db: database.NewMemoryDBManager()}
+	key, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	var blocks []*types.Block
+	for i := int64(0); i < 3; i++ {
+		tx, err := types.SignTx(types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		require.NoError(t, err)
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(i), BlockScore: big.NewInt(1), Time: big.NewInt(i)}).WithBody([]*types.Transaction{tx})
+		b.db.WriteBlock(block)
+		b.db.WriteTxLookupEntries(block)
+		b.db.WriteReceipts(block.Hash(), block.NumberU64(), types.Receipts{{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}})
+		blocks = append(blocks, block)
+	}
+	b.db.DeleteReceipts(blocks[0].Hash(), 0)
+	b.db.WriteEarliestReceiptsBlockNumber(1)
+
+	txPoolAPI := NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	blockChainAPI := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+
+	// The receipts of a pruned block are reported as pruned.
+	pruned := blocks[0].Transactions()[0].Hash()
+	_, err := txPoolAPI.GetTransactionReceipt(ctx, pruned)
+	assert.Error(t, err)
+	_, err = txPoolAPI.GetTransactionReceiptBySenderTxHash(ctx, pruned)
+	assert.Error(t, err)
+	_, err = blockChainAPI.GetBlockReceipts(ctx, blocks[0].Hash())
+	assert.Error(t, err)
+
+	// The receipts of the other blocks are served.
+	receipt, err := txPoolAPI.GetTransactionReceipt(ctx, blocks[1].Transactions()[0].Hash())
+	require.NoError(t, err)
+	assert.Equal(t, blocks[1].Hash(), receipt["blockHash"])
+	receipts, err := blockChainAPI.GetBlockReceipts(ctx, blocks[2].Hash())
+	require.NoError(t, err)
+	assert.Len(t, receipts, 1)
+
+	// An unknown transaction has no receipt, without an error.
+	receipt, err = txPoolAPI.GetTransactionReceipt(ctx, common.HexToHash("0x1"))
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+}
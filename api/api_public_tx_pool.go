@@ -23,6 +23,7 @@ package api
 import (
 	"fmt"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 )
 
@@ -63,6 +64,37 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]map[string]
 	return content
 }
 
+// ContentFrom returns the pending and queued transactions of the given account
+// contained within the transaction pool.
+func (s *PublicTxPoolAPI) ContentFrom(address common.Address) map[string]map[string]map[string]interface{} {
+	pending, queue := s.b.TxPoolContentFrom(address)
+
+	// Flatten the pending and queued transactions
+	flatten := func(txs types.Transactions) map[string]map[string]interface{} {
+		dump := make(map[string]map[string]interface{}, len(txs))
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+		}
+		return dump
+	}
+	return map[string]map[string]map[string]interface{}{
+		"pending": flatten(pending),
+		"queued":  flatten(queue),
+	}
+}
+
+// ContentHash returns a deterministic hash of all pending and queued transactions in the pool.
+// Nodes having the same transactions in their pools return the same hash.
+func (s *PublicTxPoolAPI) ContentHash() common.Hash {
+	return s.b.TxPoolContentHash()
+}
+
+// AvailableBalance returns the balance of the given account which is not reserved by
+// its pending transactions, i.e. the amount a new transaction of the account can spend.
+func (s *PublicTxPoolAPI) AvailableBalance(address common.Address) *hexutil.Big {
+	return (*hexutil.Big)(s.b.TxPoolAvailableBalance(address))
+}
+
 // Status returns the number of pending and queued transaction in the pool.
 func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 	pending, queue := s.b.Stats()
@@ -73,6 +73,11 @@ type Backend interface {
 	GetPoolNonce(ctx context.Context, addr common.Address) uint64
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
+	TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions)
+	TxPoolContentHash() common.Hash
+	TxPoolNonceGaps(addr common.Address) ([]uint64, error)
+	TxPoolAvailableBalance(addr common.Address) *big.Int
+	TxPoolStatus(hashes []common.Hash) []blockchain.TxStatus
 	SubscribeNewTxsEvent(chan<- blockchain.NewTxsEvent) event.Subscription
 
 	ChainConfig() *params.ChainConfig
@@ -101,11 +106,21 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicTransactionPoolAPI(apiBackend, nonceLock),
 			Public:    true,
+		}, {
+			Namespace: "klay",
+			Version:   "1.0",
+			Service:   NewPublicSponsorshipAPI(apiBackend),
+			Public:    true,
 		}, {
 			Namespace: "txpool",
 			Version:   "1.0",
 			Service:   NewPublicTxPoolAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminTxPoolAPI(apiBackend),
+			Public:    false,
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -26,6 +26,7 @@ import (
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/consensus"
 	"github.com/klaytn/klaytn/params"
+	"math/big"
 )
 
 // BlockValidator is responsible for validating block headers and
@@ -53,22 +54,69 @@ func NewBlockValidator(config *params.ChainConfig, blockchain *BlockChain, engin
 // validated at this point.
 func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	// Check whether the block's known, and if not, that it's linkable
-	if v.bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
+	if err := v.bc.checkBlockAncestry(block); err != nil {
+		return err
+	}
+	// Header validity is known at this point, check the transactions
+	if err := checkTxRoot(block); err != nil {
+		return err
+	}
+	header := block.Header()
+	if v.config.IsGasPriceFloorForkEnabled(header.Number) {
+		floors, err := v.bc.gasPriceFloors(header.Number)
+		if err != nil {
+			return err
+		}
+		for i, tx := range block.Transactions() {
+			if err := checkGasPriceFloor(floors, tx); err != nil {
+				return fmt.Errorf("transaction %d (%x): %v", i, tx.Hash(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkGasPriceFloor returns ErrGasPriceBelowFloor if the gas price of tx is lower than
+// the floor of its tx type in the given gas price floors.
+func checkGasPriceFloor(floors map[uint64]*big.Int, tx *types.Transaction) error {
+	if floor := floors[uint64(tx.Type())]; floor != nil && tx.GasPrice().Cmp(floor) < 0 {
+		return ErrGasPriceBelowFloor
+	}
+	return nil
+}
+
+// checkTxRoot returns an error if the transactions of the block do not match
+// the transaction root of its header.
+func checkTxRoot(block *types.Block) error {
+	if hash := types.DeriveSha(block.Transactions()); hash != block.Header().TxHash {
+		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, block.Header().TxHash)
+	}
+	return nil
+}
+
+// checkStateRoot returns an error if the processed state does not match the
+// state root of the block header.
+func checkStateRoot(block *types.Block, statedb *state.StateDB) error {
+	if root := statedb.IntermediateRoot(true); block.Root() != root {
+		return fmt.Errorf("invalid merkle root (remote: %x local: %x)", block.Root(), root)
+	}
+	return nil
+}
+
+// checkBlockAncestry checks whether the block is already known, and if not,
+// whether its parent and the parent state are available.
+func (bc *BlockChain) checkBlockAncestry(block *types.Block) error {
+	if bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
 		return ErrKnownBlock
 	}
-	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
-		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
+	if !bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
+		if !bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
 			logger.Error("unknown ancestor (ValidateBody)", "num", block.NumberU64(),
 				"hash", block.Hash(), "parentHash", block.ParentHash())
 			return consensus.ErrUnknownAncestor
 		}
 		return consensus.ErrPrunedAncestor
 	}
-	// Header validity is known at this point, check the transactions
-	header := block.Header()
-	if hash := types.DeriveSha(block.Transactions()); hash != header.TxHash {
-		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
-	}
 	return nil
 }
 
@@ -94,8 +142,5 @@ func (v *BlockValidator) ValidateState(block, parent *types.Block, statedb *stat
 	}
 	// Validate the state root against the received state root and throw
 	// an error if they don't match.
-	if root := statedb.IntermediateRoot(true); header.Root != root {
-		return fmt.Errorf("invalid merkle root (remote: %x local: %x)", header.Root, root)
-	}
-	return nil
+	return checkStateRoot(block, statedb)
 }
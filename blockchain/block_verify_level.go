@@ -0,0 +1,51 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+)
+
+// BlockVerifyLevel determines how thoroughly the blocks received from trusted
+// peers are verified before they are inserted.
+type BlockVerifyLevel string
+
+const (
+	// VerifyLevelFull fully verifies every block regardless of its source.
+	VerifyLevelFull BlockVerifyLevel = "full"
+
+	// VerifyLevelHeadersOnlyTrustedPeers verifies the headers of the blocks
+	// received from trusted peers, but skips the transaction checks (gas price
+	// floor) and the post-execution checks (gas used, bloom and receipt root).
+	// The transaction root and the state root are still checked since the body
+	// and the state are written as they are.
+	//
+	// This trades safety for import speed: a trusted peer relaying a block with
+	// invalid transactions or receipts is not detected on import. Use it only if
+	// every trusted peer is operated by the same party as the node.
+	VerifyLevelHeadersOnlyTrustedPeers BlockVerifyLevel = "headersonly-trustedpeers"
+)
+
+// ParseBlockVerifyLevel returns the block verification level of the given name.
+func ParseBlockVerifyLevel(s string) (BlockVerifyLevel, error) {
+	switch level := BlockVerifyLevel(s); level {
+	case VerifyLevelFull, VerifyLevelHeadersOnlyTrustedPeers:
+		return level, nil
+	default:
+		return "", fmt.Errorf("unknown block verification level %q (want %q or %q)", s, VerifyLevelFull, VerifyLevelHeadersOnlyTrustedPeers)
+	}
+}
@@ -83,6 +83,8 @@ type CacheConfig struct {
 	CacheSize            int  // Size of in-memory cache of a trie (MiB) to flush matured singleton trie nodes to disk
 	BlockInterval        uint // Block interval to flush the trie. Each interval state trie will be flushed into disk.
 	TrieCacheLimit       int  // Memory allowance (MB) to use for caching trie nodes in memory
+	TrieCommitBatchSize  int  // Maximum size (KiB) of a batch written by a trie commit. 0 uses the default size.
+	TrieMaxDirtyBuffer   int  // Memory budget (MiB) of dirty trie nodes forcing an intermediate flush. 0 disables it.
 	SenderTxHashIndexing bool // Enables saving senderTxHash to txHash mapping information to database and cache.
 }
 
@@ -138,7 +140,8 @@ type BlockChain struct {
 	validator Validator // block and state validator interface
 	vmConfig  vm.Config
 
-	badBlocks *lru.Cache // Bad block cache
+	badBlocks      *lru.Cache        // Bad block cache
+	importFailures *importFailureLog // Recent block import failures
 
 	parallelDBWrite bool // TODO-Klaytn-Storage parallelDBWrite will be replaced by number of goroutines when worker pool pattern is introduced.
 
@@ -147,6 +150,18 @@ type BlockChain struct {
 
 	nonceCache   common.Cache
 	balanceCache common.Cache
+
+	diskSpaceGuard *DiskSpaceGuard // rejects new blocks while the free disk space is low, nil if disabled
+
+	verifyLevel BlockVerifyLevel // verification level of the blocks inserted by InsertTrustedChain
+
+	gasPriceFloorReader GasPriceFloorReader // reads the gas price floors in effect at a block, nil to use the chain config
+}
+
+// GasPriceFloorReader reads the gas price floors of the governance in effect at a block.
+type GasPriceFloorReader interface {
+	// GasPriceFloorsAtNumber returns the gas price floors of the tx types in effect at block num.
+	GasPriceFloorsAtNumber(num uint64) (map[uint64]*big.Int, error)
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -186,11 +201,16 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 		engine:          engine,
 		vmConfig:        vmConfig,
 		badBlocks:       badBlocks,
+		importFailures:  newImportFailureLog(maxImportFailures),
 		parallelDBWrite: db.IsParallelDBWrite(),
 		nonceCache:      nonceCache,
 		balanceCache:    balanceCache,
+		verifyLevel:     VerifyLevelFull,
 	}
 
+	bc.stateCache.TrieDB().SetCommitBatchSize(cacheConfig.TrieCommitBatchSize * 1024)
+	bc.stateCache.TrieDB().SetMaxDirtyBufferSize(common.StorageSize(cacheConfig.TrieMaxDirtyBuffer) * 1024 * 1024)
+
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
 
@@ -388,6 +408,36 @@ func (bc *BlockChain) SetValidator(validator Validator) {
 	bc.validator = validator
 }
 
+// SetDiskSpaceGuard sets the guard which halts block insertion while the free disk space is low.
+// It should be called before the blockchain starts to accept blocks.
+func (bc *BlockChain) SetDiskSpaceGuard(guard *DiskSpaceGuard) {
+	bc.diskSpaceGuard = guard
+}
+
+// SetBlockVerifyLevel sets the verification level of the blocks inserted by
+// InsertTrustedChain. It should be called before the blockchain starts to accept blocks.
+func (bc *BlockChain) SetBlockVerifyLevel(level BlockVerifyLevel) {
+	bc.verifyLevel = level
+}
+
+// SetGasPriceFloorReader sets the reader of the gas price floors in effect at a block.
+// It should be called before the blockchain starts to accept blocks.
+func (bc *BlockChain) SetGasPriceFloorReader(reader GasPriceFloorReader) {
+	bc.gasPriceFloorReader = reader
+}
+
+// gasPriceFloors returns the gas price floors of the tx types in effect at block num.
+// Without a GasPriceFloorReader, the floors of the chain config are returned.
+func (bc *BlockChain) gasPriceFloors(num *big.Int) (map[uint64]*big.Int, error) {
+	if !bc.chainConfig.IsGasPriceFloorForkEnabled(num) {
+		return nil, nil
+	}
+	if bc.gasPriceFloorReader != nil {
+		return bc.gasPriceFloorReader.GasPriceFloorsAtNumber(num.Uint64())
+	}
+	return bc.chainConfig.GasPriceFloors(num)
+}
+
 // Validator returns the current validator.
 func (bc *BlockChain) Validator() Validator {
 	bc.procmu.RLock()
@@ -730,6 +780,35 @@ func (bc *BlockChain) TrieNode(hash common.Hash) ([]byte, error) {
 	return bc.stateCache.TrieDB().Node(hash)
 }
 
+// TrieNodeDescendants retrieves the blobs of the trie nodes below the given trie
+// node in pre-order, so that every node is preceded by its parent. Nodes embedded
+// in their parents are skipped. At most maxNodes blobs are returned, stopping
+// once their total size reaches maxBytes. Non-trie data such as contract code
+// has no descendants.
+func (bc *BlockChain) TrieNodeDescendants(hash common.Hash, maxNodes, maxBytes int) [][]byte {
+	trie, err := statedb.NewTrie(hash, bc.stateCache.TrieDB())
+	if err != nil {
+		return nil
+	}
+	var (
+		bytes int
+		data  [][]byte
+	)
+	for it := trie.NodeIterator(nil); it.Next(true) && len(data) < maxNodes && bytes < maxBytes; {
+		node := it.Hash()
+		if node == (common.Hash{}) || node == hash {
+			continue
+		}
+		entry, err := bc.TrieNode(node)
+		if err != nil {
+			break
+		}
+		data = append(data, entry)
+		bytes += len(entry)
+	}
+	return data
+}
+
 // Stop stops the blockchain service. If any imports are currently in progress
 // it will abort them using the procInterrupt.
 func (bc *BlockChain) Stop() {
@@ -873,6 +952,9 @@ func SetReceiptsData(config *params.ChainConfig, block *types.Block, receipts ty
 // InsertReceiptChain attempts to complete an already existing header chain with
 // transaction and receipt data.
 func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain []types.Receipts) (int, error) {
+	if bc.db.IsReadOnly() {
+		return 0, database.ErrReadOnlyDatabase
+	}
 	bc.wg.Add(1)
 	defer bc.wg.Done()
 
@@ -1072,6 +1154,12 @@ func isReorganizationRequired(localTd, externTd *big.Int, currentBlock, block *t
 // If BlockChain.parallelDBWrite is true, it calls writeBlockWithStateParallel.
 // If not, it calls writeBlockWithStateSerial.
 func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.Receipt, stateDB *state.StateDB) (WriteStatus, error) {
+	if bc.db.IsReadOnly() {
+		return NonStatTy, database.ErrReadOnlyDatabase
+	}
+	if bc.diskSpaceGuard.Halted() {
+		return NonStatTy, ErrDiskSpaceLow
+	}
 	var status WriteStatus
 	var err error
 	if bc.parallelDBWrite {
@@ -1324,19 +1412,37 @@ func (bc *BlockChain) GetTxReceiptInCache(txHash common.Hash) *types.Receipt {
 //
 // After insertion is done, all accumulated events will be fired.
 func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
-	n, events, logs, err := bc.insertChain(chain)
+	n, events, logs, err := bc.insertChain(chain, false)
+	bc.recordImportFailure(chain, n, err)
+	bc.PostChainEvents(events, logs)
+	return n, err
+}
+
+// InsertTrustedChain inserts the given batch of blocks received from a trusted
+// peer like InsertChain. If the verification level is VerifyLevelHeadersOnlyTrustedPeers,
+// only the headers, the transaction roots and the state roots of the blocks are verified.
+func (bc *BlockChain) InsertTrustedChain(chain types.Blocks) (int, error) {
+	n, events, logs, err := bc.insertChain(chain, bc.verifyLevel == VerifyLevelHeadersOnlyTrustedPeers)
+	bc.recordImportFailure(chain, n, err)
 	bc.PostChainEvents(events, logs)
 	return n, err
 }
 
 // insertChain will execute the actual chain insertion and event aggregation. The
 // only reason this method exists as a separate one is to make locking cleaner
-// with deferred statements.
-func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*types.Log, error) {
+// with deferred statements. If headersOnly is true, the bodies and the states of
+// the blocks are validated only against the transaction roots and the state roots.
+func (bc *BlockChain) insertChain(chain types.Blocks, headersOnly bool) (int, []interface{}, []*types.Log, error) {
 	// Sanity check that we have something meaningful to import
 	if len(chain) == 0 {
 		return 0, nil, nil, nil
 	}
+	if bc.db.IsReadOnly() {
+		return 0, nil, nil, database.ErrReadOnlyDatabase
+	}
+	if bc.diskSpaceGuard.Halted() {
+		return 0, nil, nil, ErrDiskSpaceLow
+	}
 	// Do a sanity check that the provided chain is actually ordered and linked
 	for i := 1; i < len(chain); i++ {
 		if chain[i].NumberU64() != chain[i-1].NumberU64()+1 || chain[i].ParentHash() != chain[i-1].Hash() {
@@ -1397,7 +1503,14 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 
 		err := <-results
 		if err == nil {
-			err = bc.Validator().ValidateBody(block)
+			if headersOnly {
+				// The body is written as it is, so it should match the header
+				if err = bc.checkBlockAncestry(block); err == nil {
+					err = checkTxRoot(block)
+				}
+			} else {
+				err = bc.Validator().ValidateBody(block)
+			}
 		}
 
 		switch {
@@ -1448,7 +1561,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			}
 			// Import all the pruned blocks to make the state available
 			bc.chainmu.Unlock()
-			_, evs, logs, err := bc.insertChain(winner)
+			_, evs, logs, err := bc.insertChain(winner, headersOnly)
 			bc.chainmu.Lock()
 			events, coalescedLogs = evs, logs
 
@@ -1491,7 +1604,12 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		}
 
 		// Validate the state using the default validator
-		err = bc.Validator().ValidateState(block, parent, stateDB, receipts, usedGas)
+		if headersOnly {
+			// The state is written as it is, so it should match the header
+			err = checkStateRoot(block, stateDB)
+		} else {
+			err = bc.Validator().ValidateState(block, parent, stateDB, receipts, usedGas)
+		}
 		if err != nil {
 
 			bc.reportBlock(block, receipts, err)
@@ -1802,6 +1920,9 @@ Error: %v
 // of the header retrieval mechanisms already need to verify nonces, as well as
 // because nonces can be verified sparsely, not needing to check each.
 func (bc *BlockChain) InsertHeaderChain(chain []*types.Header, checkFreq int) (int, error) {
+	if bc.db.IsReadOnly() {
+		return 0, database.ErrReadOnlyDatabase
+	}
 	start := time.Now()
 	if i, err := bc.hc.ValidateHeaderChain(chain, checkFreq); err != nil {
 		return i, err
@@ -22,8 +22,11 @@ package blockchain
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"math/rand"
+	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -489,6 +492,42 @@ func testBadHashes(t *testing.T, full bool) {
 	}
 }
 
+// Tests that the blocks failed to be imported are recorded with the reasons.
+func TestRecentImportFailures(t *testing.T) {
+	db, blockchain, err := newCanonical(gxhash.NewFaker(), 0, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	blocks := makeBlockChain(blockchain.CurrentBlock(), 3, gxhash.NewFaker(), db, 10)
+	BadHashes[blocks[1].Hash()] = true
+	defer func() { delete(BadHashes, blocks[1].Hash()) }()
+
+	if _, err := blockchain.InsertChain(blocks); err != ErrBlacklistedHash {
+		t.Fatalf("error mismatch: have: %v, want: %v", err, ErrBlacklistedHash)
+	}
+	failures := blockchain.RecentImportFailures()
+	if len(failures) != 1 {
+		t.Fatalf("import failure count mismatch: have %d, want 1", len(failures))
+	}
+	if failures[0].Hash != blocks[1].Hash() || failures[0].Number != blocks[1].NumberU64() {
+		t.Errorf("failed block mismatch: have %x (#%d), want %x (#%d)", failures[0].Hash, failures[0].Number, blocks[1].Hash(), blocks[1].NumberU64())
+	}
+	if failures[0].Error != ErrBlacklistedHash.Error() {
+		t.Errorf("failure reason mismatch: have %q, want %q", failures[0].Error, ErrBlacklistedHash.Error())
+	}
+
+	// Only the most recent failures are kept, from the oldest to the newest.
+	log := newImportFailureLog(2)
+	for _, block := range blocks {
+		log.add(block, ErrBlacklistedHash)
+	}
+	if failures := log.list(); len(failures) != 2 || failures[0].Hash != blocks[1].Hash() || failures[1].Hash != blocks[2].Hash() {
+		t.Errorf("recent import failures mismatch: have %v", failures)
+	}
+}
+
 // Tests that bad hashes are detected on boot, and the chain rolled back to a
 // good state prior to the bad hash.
 func TestReorgBadHeaderHashes(t *testing.T) { testReorgBadHashes(t, false) }
@@ -1414,3 +1453,341 @@ func BenchmarkBlockChain_1x1000Executions(b *testing.B) {
 
 	benchmarkLargeNumberOfValueToNonexisting(b, numTxs, numBlocks, recipientFn, dataFn)
 }
+
+// countingValidator is a Validator counting the body and state validations.
+type countingValidator struct {
+	Validator
+	bodies, states int
+}
+
+func (v *countingValidator) ValidateBody(block *types.Block) error {
+	v.bodies++
+	return v.Validator.ValidateBody(block)
+}
+
+func (v *countingValidator) ValidateState(block, parent *types.Block, statedb *state.StateDB, receipts types.Receipts, usedGas uint64) error {
+	v.states++
+	return v.Validator.ValidateState(block, parent, statedb, receipts, usedGas)
+}
+
+// Tests that the blocks from trusted peers skip the body and state validation only
+// if the headers-only verification level is set, while the other blocks are always
+// fully validated.
+func TestInsertTrustedChainVerifyLevel(t *testing.T) {
+	for _, level := range []BlockVerifyLevel{VerifyLevelFull, VerifyLevelHeadersOnlyTrustedPeers} {
+		var (
+			db      = database.NewMemoryDBManager()
+			genesis = new(Genesis).MustCommit(db)
+		)
+		chain, err := NewBlockChain(db, nil, params.TestChainConfig, gxhash.NewFaker(), vm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain.SetBlockVerifyLevel(level)
+		validator := &countingValidator{Validator: chain.Validator()}
+		chain.SetValidator(validator)
+
+		blocks, _ := GenerateChain(params.TestChainConfig, genesis, gxhash.NewFaker(), db, 4, func(i int, b *BlockGen) {
+			b.SetRewardbase(common.Address{0x01})
+		})
+
+		// Blocks from trusted peers
+		if _, err := chain.InsertTrustedChain(blocks[:2]); err != nil {
+			t.Fatalf("level %s: failed to insert trusted blocks: %v", level, err)
+		}
+		wantValidations := 2
+		if level == VerifyLevelHeadersOnlyTrustedPeers {
+			wantValidations = 0
+		}
+		if validator.bodies != wantValidations || validator.states != wantValidations {
+			t.Errorf("level %s: trusted validations mismatch: have %d bodies and %d states, want %d",
+				level, validator.bodies, validator.states, wantValidations)
+		}
+
+		// Blocks from the other peers
+		validator.bodies, validator.states = 0, 0
+		if _, err := chain.InsertChain(blocks[2:]); err != nil {
+			t.Fatalf("level %s: failed to insert blocks: %v", level, err)
+		}
+		if validator.bodies != 2 || validator.states != 2 {
+			t.Errorf("level %s: validations mismatch: have %d bodies and %d states, want 2",
+				level, validator.bodies, validator.states)
+		}
+		if head := chain.CurrentBlock().NumberU64(); head != 4 {
+			t.Errorf("level %s: head mismatch: have %d, want 4", level, head)
+		}
+		chain.Stop()
+	}
+}
+
+// Tests that the blocks from trusted peers are rejected under the headers-only
+// verification level if their transaction or state roots do not match, so that
+// the bodies and the states of bad blocks are never written.
+func TestInsertTrustedChainBadRoots(t *testing.T) {
+	var (
+		db      = database.NewMemoryDBManager()
+		genesis = new(Genesis).MustCommit(db)
+	)
+	chain, err := NewBlockChain(db, nil, params.TestChainConfig, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	chain.SetBlockVerifyLevel(VerifyLevelHeadersOnlyTrustedPeers)
+
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, gxhash.NewFaker(), db, 1, func(i int, b *BlockGen) {
+		b.SetRewardbase(common.Address{0x01})
+	})
+	for name, corrupt := range map[string]func(header *types.Header){
+		"tx root":    func(header *types.Header) { header.TxHash = common.Hash{0x01} },
+		"state root": func(header *types.Header) { header.Root = common.Hash{0x01} },
+	} {
+		header := blocks[0].Header()
+		corrupt(header)
+		bad := types.NewBlockWithHeader(header).WithBody(blocks[0].Transactions())
+
+		if _, err := chain.InsertTrustedChain(types.Blocks{bad}); err == nil {
+			t.Errorf("%s: inserting a bad trusted block should fail", name)
+		}
+		if chain.HasBlock(bad.Hash(), bad.NumberU64()) {
+			t.Errorf("%s: bad trusted block is written", name)
+		}
+		if head := chain.CurrentBlock().NumberU64(); head != 0 {
+			t.Errorf("%s: head mismatch: have %d, want 0", name, head)
+		}
+	}
+}
+
+func TestParseBlockVerifyLevel(t *testing.T) {
+	for _, level := range []BlockVerifyLevel{VerifyLevelFull, VerifyLevelHeadersOnlyTrustedPeers} {
+		if parsed, err := ParseBlockVerifyLevel(string(level)); err != nil || parsed != level {
+			t.Errorf("failed to parse %q: have %q (%v)", level, parsed, err)
+		}
+	}
+	if _, err := ParseBlockVerifyLevel("light"); err == nil {
+		t.Error("parsing an unknown level should fail")
+	}
+}
+
+// Tests that blocks containing a tx priced below the gas price floor of its type are
+// rejected only after the GasPriceFloor fork.
+func TestInsertChainGasPriceFloor(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		floor   = big.NewInt(25)
+		config  = *params.TestChainConfig
+	)
+	config.GasPriceFloorBlock = big.NewInt(2)
+	config.Governance = &params.GovernanceConfig{
+		Reward:         &params.RewardConfig{},
+		GasPriceFloors: fmt.Sprintf("%d:%s", types.TxTypeLegacyTransaction, floor),
+	}
+
+	gspec := &Genesis{
+		Config: &config,
+		Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+	}
+	signer := types.NewEIP155Signer(config.ChainID)
+
+	// generate returns a chain of the given length whose every block has a tx of the given gas price.
+	generate := func(n int, gasPrice *big.Int) []*types.Block {
+		db := database.NewMemoryDBManager()
+		blocks, _ := GenerateChain(&config, gspec.MustCommit(db), gxhash.NewFaker(), db, n, func(i int, b *BlockGen) {
+			tx, err := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0x01}, big.NewInt(1), params.TxGas, gasPrice, nil), signer, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.AddTx(tx)
+		})
+		return blocks
+	}
+	below := new(big.Int).Sub(floor, common.Big1)
+
+	testCases := []struct {
+		gasPrice *big.Int
+		err      error
+	}{
+		{floor, nil},
+		{below, ErrGasPriceBelowFloor},
+	}
+	for _, tc := range testCases {
+		db := database.NewMemoryDBManager()
+		gspec.MustCommit(db)
+		chain, err := NewBlockChain(db, nil, &config, gxhash.NewFaker(), vm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks := generate(2, tc.gasPrice)
+
+		// The block before the fork is accepted regardless of the floor.
+		if _, err := chain.InsertChain(blocks[:1]); err != nil {
+			t.Fatalf("gas price %v: failed to insert the block before the fork: %v", tc.gasPrice, err)
+		}
+		_, err = chain.InsertChain(blocks[1:])
+		if tc.err == nil && err != nil {
+			t.Errorf("gas price %v: failed to insert the block at the fork: %v", tc.gasPrice, err)
+		}
+		if tc.err != nil && (err == nil || !strings.Contains(err.Error(), tc.err.Error())) {
+			t.Errorf("gas price %v: error mismatch: have %v, want %v", tc.gasPrice, err, tc.err)
+		}
+		chain.Stop()
+	}
+}
+
+// gasPriceFloorsByNumber is a GasPriceFloorReader returning the floors of the
+// governance in effect at each block number.
+type gasPriceFloorsByNumber map[uint64]map[uint64]*big.Int
+
+func (r gasPriceFloorsByNumber) GasPriceFloorsAtNumber(num uint64) (map[uint64]*big.Int, error) {
+	return r[num], nil
+}
+
+// Tests that the gas price floors are read from the governance in effect at the
+// block number rather than from the chain config.
+func TestInsertChainGasPriceFloorAtNumber(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+	)
+	config.GasPriceFloorBlock = big.NewInt(1)
+	config.Governance = &params.GovernanceConfig{
+		Reward:         &params.RewardConfig{},
+		GasPriceFloors: fmt.Sprintf("%d:100", types.TxTypeLegacyTransaction),
+	}
+	gspec := &Genesis{
+		Config: &config,
+		Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+	}
+	signer := types.NewEIP155Signer(config.ChainID)
+
+	db := database.NewMemoryDBManager()
+	blocks, _ := GenerateChain(&config, gspec.MustCommit(db), gxhash.NewFaker(), db, 2, func(i int, b *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(50), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.AddTx(tx)
+	})
+
+	db = database.NewMemoryDBManager()
+	gspec.MustCommit(db)
+	chain, err := NewBlockChain(db, nil, &config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	chain.SetGasPriceFloorReader(gasPriceFloorsByNumber{
+		1: {uint64(types.TxTypeLegacyTransaction): big.NewInt(25)},
+		2: {uint64(types.TxTypeLegacyTransaction): big.NewInt(75)},
+	})
+
+	// The floor of the config is higher than the gas price, but the one in effect at block 1 is not.
+	if _, err := chain.InsertChain(blocks[:1]); err != nil {
+		t.Fatalf("failed to insert block 1: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks[1:]); err == nil || !strings.Contains(err.Error(), ErrGasPriceBelowFloor.Error()) {
+		t.Errorf("error mismatch at block 2: have %v, want %v", err, ErrGasPriceBelowFloor)
+	}
+}
+
+// Tests that TrieNodeDescendants serves the trie nodes below a state trie node
+// with every node preceded by its parent, and honours the given limits.
+func TestTrieNodeDescendants(t *testing.T) {
+	alloc := GenesisAlloc{}
+	for i := 0; i < 100; i++ {
+		alloc[common.BigToAddress(big.NewInt(int64(i+1)))] = GenesisAccount{Balance: big.NewInt(int64(i + 1))}
+	}
+	db := database.NewMemoryDBManager()
+	genesis := (&Genesis{Config: params.TestChainConfig, Alloc: alloc}).MustCommit(db)
+
+	chain, err := NewBlockChain(db, nil, params.TestChainConfig, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	root := genesis.Root()
+	entries := chain.TrieNodeDescendants(root, 1<<20, 1<<30)
+	if len(entries) == 0 {
+		t.Fatal("no descendants served")
+	}
+	// Every entry must be a child of the root or of an entry served before it
+	known := map[common.Hash]bool{root: true}
+	for i, entry := range entries {
+		hash := crypto.Keccak256Hash(entry)
+		if known[hash] {
+			t.Fatalf("entry %d: %x served twice", i, hash)
+		}
+		found := false
+		for parent := range known {
+			blob, _ := chain.TrieNode(parent)
+			if strings.Contains(string(blob), string(hash[:])) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("entry %d: %x served before its parent", i, hash)
+		}
+		known[hash] = true
+	}
+	if limited := chain.TrieNodeDescendants(root, 3, 1<<30); len(limited) != 3 {
+		t.Errorf("node limit mismatch: have %d, want %d", len(limited), 3)
+	}
+	if limited := chain.TrieNodeDescendants(root, 1<<20, 1); len(limited) != 1 {
+		t.Errorf("byte limit mismatch: have %d, want %d", len(limited), 1)
+	}
+	if entries := chain.TrieNodeDescendants(crypto.Keccak256Hash([]byte("missing")), 1<<20, 1<<30); len(entries) != 0 {
+		t.Errorf("unknown node served %d descendants", len(entries))
+	}
+}
+
+// Tests that a block chain on a read-only database serves the stored blocks
+// but rejects new ones.
+func TestReadOnlyBlockChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-blockchain-readonly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbc := &database.DBConfig{Dir: dir, DBType: database.LevelDB, LevelDBCacheSize: 16, OpenFilesLimit: 16}
+	db := database.NewDBManager(dbc)
+	genesis := new(Genesis).MustCommit(db)
+	blocks := makeBlockChain(genesis, 3, gxhash.NewFaker(), db, canonicalSeed)
+
+	chain, err := NewBlockChain(db, nil, params.AllGxhashProtocolChanges, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatal(err)
+	}
+	chain.Stop()
+	db.Close()
+
+	dbc.ReadOnly = true
+	db = database.NewDBManager(dbc)
+	defer db.Close()
+
+	chain, err = NewBlockChain(db, nil, params.AllGxhashProtocolChanges, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	if head := chain.CurrentBlock().NumberU64(); head != 2 {
+		t.Fatalf("head block mismatch: have %d, want 2", head)
+	}
+	if _, err := chain.InsertChain(blocks[2:]); err != database.ErrReadOnlyDatabase {
+		t.Fatalf("error mismatch: have %v, want %v", err, database.ErrReadOnlyDatabase)
+	}
+	if _, err := chain.InsertHeaderChain([]*types.Header{blocks[2].Header()}, 1); err != database.ErrReadOnlyDatabase {
+		t.Fatalf("error mismatch: have %v, want %v", err, database.ErrReadOnlyDatabase)
+	}
+	if head := chain.CurrentBlock().NumberU64(); head != 2 {
+		t.Fatalf("head block mismatch: have %d, want 2", head)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// diskSpaceCheckInterval is the interval at which DiskSpaceGuard checks the free disk space.
+const diskSpaceCheckInterval = 10 * time.Second
+
+// ErrDiskSpaceLow is returned when a block or a transaction is rejected because
+// the free disk space of the data directory is below the configured threshold.
+var ErrDiskSpaceLow = errors.New("free disk space is below the threshold")
+
+// DiskSpaceGuard periodically checks the free space of the volume containing the
+// data directory. While the free space is below the threshold, BlockChain and
+// TxPool reject new blocks and transactions so that the database is not corrupted
+// by running out of disk in the middle of a write.
+type DiskSpaceGuard struct {
+	path         string
+	minFreeSpace uint64 // in bytes
+	freeSpace    func(path string) (uint64, error)
+
+	halted int32 // 1 if writes are halted, must be accessed atomically
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDiskSpaceGuard returns a DiskSpaceGuard halting writes while the free space
+// of the volume containing path is below minFreeSpace bytes.
+func NewDiskSpaceGuard(path string, minFreeSpace uint64) *DiskSpaceGuard {
+	return &DiskSpaceGuard{
+		path:         path,
+		minFreeSpace: minFreeSpace,
+		freeSpace:    getFreeDiskSpace,
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start checks the free disk space and starts the periodic check.
+func (g *DiskSpaceGuard) Start() {
+	g.check()
+
+	g.wg.Add(1)
+	go g.loop()
+}
+
+// Stop terminates the periodic check.
+func (g *DiskSpaceGuard) Stop() {
+	close(g.quit)
+	g.wg.Wait()
+}
+
+// Halted returns true if writes are halted due to low disk space.
+// A nil DiskSpaceGuard never halts writes.
+func (g *DiskSpaceGuard) Halted() bool {
+	return g != nil && atomic.LoadInt32(&g.halted) == 1
+}
+
+func (g *DiskSpaceGuard) loop() {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(diskSpaceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.check()
+		case <-g.quit:
+			return
+		}
+	}
+}
+
+// check updates the halted state with the current free disk space.
+func (g *DiskSpaceGuard) check() {
+	free, err := g.freeSpace(g.path)
+	if err != nil {
+		logger.Warn("Failed to check free disk space", "path", g.path, "err", err)
+		return
+	}
+
+	if free < g.minFreeSpace {
+		if atomic.CompareAndSwapInt32(&g.halted, 0, 1) {
+			logger.Error("Free disk space is low, stopped accepting blocks and transactions",
+				"path", g.path, "free", free, "threshold", g.minFreeSpace)
+		}
+		return
+	}
+	if atomic.CompareAndSwapInt32(&g.halted, 1, 0) {
+		logger.Warn("Free disk space is recovered, resumed accepting blocks and transactions",
+			"path", g.path, "free", free, "threshold", g.minFreeSpace)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"math/big"
+	"sync/atomic"
+	"testing"
+)
+
+// newMockDiskSpaceGuard returns a DiskSpaceGuard reporting the free space stored in free.
+func newMockDiskSpaceGuard(minFreeSpace uint64, free *uint64) *DiskSpaceGuard {
+	guard := NewDiskSpaceGuard("", minFreeSpace)
+	guard.freeSpace = func(string) (uint64, error) {
+		return atomic.LoadUint64(free), nil
+	}
+	return guard
+}
+
+func TestDiskSpaceGuardBlockChain(t *testing.T) {
+	db, bc, err := newCanonical(gxhash.NewFaker(), 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+
+	free := uint64(100)
+	guard := newMockDiskSpaceGuard(1000, &free)
+	bc.SetDiskSpaceGuard(guard)
+
+	blocks := makeBlockChain(bc.CurrentBlock(), 3, gxhash.NewFaker(), db, canonicalSeed)
+
+	// Blocks are rejected while the free space is low.
+	guard.check()
+	if !guard.Halted() {
+		t.Fatal("guard is not halted on low disk space")
+	}
+	if _, err := bc.InsertChain(blocks); err != ErrDiskSpaceLow {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrDiskSpaceLow)
+	}
+	if head := bc.CurrentBlock().NumberU64(); head != 0 {
+		t.Fatalf("head block mismatch: have %d, want 0", head)
+	}
+
+	// Blocks are accepted again once the free space is recovered.
+	atomic.StoreUint64(&free, 2000)
+	guard.check()
+	if guard.Halted() {
+		t.Fatal("guard is still halted after disk space is recovered")
+	}
+	if _, err := bc.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	if head := bc.CurrentBlock().NumberU64(); head != 3 {
+		t.Fatalf("head block mismatch: have %d, want 3", head)
+	}
+}
+
+func TestDiskSpaceGuardTxPool(t *testing.T) {
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	free := uint64(100)
+	guard := newMockDiskSpaceGuard(1000, &free)
+	pool.SetDiskSpaceGuard(guard)
+	guard.check()
+
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != ErrDiskSpaceLow {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrDiskSpaceLow)
+	}
+	if errs := pool.AddRemotes([]*types.Transaction{transaction(0, 100000, key)}); errs[0] != ErrDiskSpaceLow {
+		t.Fatalf("error mismatch: have %v, want %v", errs[0], ErrDiskSpaceLow)
+	}
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Fatalf("transactions are added while disk space is low: pending %d, queued %d", pending, queued)
+	}
+
+	atomic.StoreUint64(&free, 2000)
+	guard.check()
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatch: have %d, want 1", pending)
+	}
+}
+
+func TestDiskSpaceGuardStart(t *testing.T) {
+	free := uint64(100)
+	guard := newMockDiskSpaceGuard(1000, &free)
+	guard.Start()
+	defer guard.Stop()
+
+	if !guard.Halted() {
+		t.Error("guard is not halted on start with low disk space")
+	}
+	var nilGuard *DiskSpaceGuard
+	if nilGuard.Halted() {
+		t.Error("nil guard should not halt writes")
+	}
+}
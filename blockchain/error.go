@@ -89,6 +89,10 @@ var (
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
 
+	// ErrOversizedCode is returned if the init code of a contract deployment tx is
+	// greater than the max code size of the tx pool.
+	ErrOversizedCode = errors.New("oversized contract code")
+
 	// ErrInvlidUnitPrice is returned if gas price of transaction is not equal to UnitPrice
 	ErrInvalidUnitPrice = errors.New("invalid unit price")
 
@@ -108,4 +112,23 @@ var (
 
 	// ErrAccountCreationPrevented is returned if account creation is inserted in the service chain's txpool.
 	ErrAccountCreationPrevented = errors.New("account creation is prevented for the service chain")
+
+	// ErrDeployerNotAllowed is returned if a contract deployment tx is sent from an address not on the deployer allow list.
+	ErrDeployerNotAllowed = errors.New("sender is not allowed to deploy contracts")
+
+	// ErrStaleKeySignature is returned if the transaction is signed by a key which the sender replaced by an account update.
+	ErrStaleKeySignature = errors.New("transaction is signed by a key replaced by an account update")
+
+	// ErrDeployerAllowListDisabled is returned if the deployer allow list is reloaded while it is not configured.
+	ErrDeployerAllowListDisabled = errors.New("deployer allow list is not configured")
+
+	// ErrReplaceCooldown is returned if a tx replaces the same nonce of an account again within the replacement cooldown.
+	ErrReplaceCooldown = errors.New("replacement of the same nonce is too frequent")
+
+	// ErrGasPriceBelowFloor is returned if a tx's gas price is lower than the governance floor of its tx type.
+	ErrGasPriceBelowFloor = errors.New("gas price below the floor of the tx type")
+
+	// ErrTooManyNonceGaps is returned if an account misses more nonces below its highest queued nonce
+	// than the non-executable slots of an account.
+	ErrTooManyNonceGaps = errors.New("too many nonce gaps")
 )
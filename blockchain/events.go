@@ -28,6 +28,9 @@ import (
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
 type NewTxsEvent struct{ Txs []*types.Transaction }
 
+// DroppedTxsEvent is posted when transactions are evicted from the full transaction pool.
+type DroppedTxsEvent struct{ Txs []*types.Transaction }
+
 // PendingLogsEvent is posted pre mining and notifies of pending logs.
 type PendingLogsEvent struct {
 	Logs []*types.Log
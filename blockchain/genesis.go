@@ -61,6 +61,11 @@ type Genesis struct {
 	Number     uint64      `json:"number"`
 	GasUsed    uint64      `json:"gasUsed"`
 	ParentHash common.Hash `json:"parentHash"`
+
+	// StateRoot is the root of a genesis state which is already stored in the
+	// database, such as a state loaded by the import-state command. If it is set,
+	// Alloc is ignored.
+	StateRoot common.Hash `json:"-"`
 }
 
 // GenesisAlloc specifies the initial state that is part of the genesis block.
@@ -242,6 +247,9 @@ func (g *Genesis) ToBlock(db database.DBManager) *types.Block {
 	if db == nil {
 		db = database.NewMemoryDBManager()
 	}
+	if g.StateRoot != (common.Hash{}) {
+		return types.NewBlock(g.header(g.StateRoot), nil, nil)
+	}
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
 	for addr, account := range g.Alloc {
 		if len(account.Code) != 0 {
@@ -254,6 +262,15 @@ func (g *Genesis) ToBlock(db database.DBManager) *types.Block {
 		statedb.SetNonce(addr, account.Nonce)
 	}
 	root := statedb.IntermediateRoot(false)
+	head := g.header(root)
+	statedb.Commit(false)
+	statedb.Database().TrieDB().Commit(root, true)
+
+	return types.NewBlock(head, nil, nil)
+}
+
+// header returns the genesis block header with the given state root.
+func (g *Genesis) header(root common.Hash) *types.Header {
 	head := &types.Header{
 		Number:     new(big.Int).SetUint64(g.Number),
 		Time:       new(big.Int).SetUint64(g.Timestamp),
@@ -268,10 +285,7 @@ func (g *Genesis) ToBlock(db database.DBManager) *types.Block {
 	if g.BlockScore == nil {
 		head.BlockScore = params.GenesisBlockScore
 	}
-	statedb.Commit(false)
-	statedb.Database().TrieDB().Commit(root, true)
-
-	return types.NewBlock(head, nil, nil)
+	return head
 }
 
 // Commit writes the block and state of a genesis specification to the database.
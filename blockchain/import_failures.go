@@ -0,0 +1,89 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"sync"
+	"time"
+)
+
+// maxImportFailures is the number of the recent block import failures kept by BlockChain.
+const maxImportFailures = 64
+
+// ImportFailure represents a block which failed to be imported and the reason.
+type ImportFailure struct {
+	Hash   common.Hash `json:"hash"`
+	Number uint64      `json:"number"`
+	Error  string      `json:"error"`
+	Time   time.Time   `json:"time"`
+}
+
+// importFailureLog is a ring buffer of the recent block import failures.
+type importFailureLog struct {
+	failures []ImportFailure
+	next     int // Index to write the next failure to once the buffer is full
+	mu       sync.Mutex
+}
+
+func newImportFailureLog(size int) *importFailureLog {
+	return &importFailureLog{failures: make([]ImportFailure, 0, size)}
+}
+
+// add records the import failure of the block, overwriting the oldest one if full.
+func (l *importFailureLog) add(block *types.Block, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	failure := ImportFailure{
+		Hash:   block.Hash(),
+		Number: block.NumberU64(),
+		Error:  err.Error(),
+		Time:   time.Now(),
+	}
+	if len(l.failures) < cap(l.failures) {
+		l.failures = append(l.failures, failure)
+		return
+	}
+	l.failures[l.next] = failure
+	l.next = (l.next + 1) % len(l.failures)
+}
+
+// list returns the recorded import failures from the oldest to the newest.
+func (l *importFailureLog) list() []ImportFailure {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	failures := make([]ImportFailure, 0, len(l.failures))
+	failures = append(failures, l.failures[l.next:]...)
+	return append(failures, l.failures[:l.next]...)
+}
+
+// RecentImportFailures returns the recent blocks which failed to be imported by
+// InsertChain or InsertTrustedChain with the reasons, from the oldest to the newest.
+func (bc *BlockChain) RecentImportFailures() []ImportFailure {
+	return bc.importFailures.list()
+}
+
+// recordImportFailure records the import failure of the block at index n of chain, if any.
+func (bc *BlockChain) recordImportFailure(chain types.Blocks, n int, err error) {
+	if err == nil || n < 0 || n >= len(chain) {
+		return
+	}
+	bc.importFailures.add(chain[n], err)
+}
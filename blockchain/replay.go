@@ -0,0 +1,97 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+)
+
+// ReplayBlocks re-executes the canonical blocks from first to last with the
+// processor of the chain, and compares the gas used, the bloom, the receipt root
+// and the state root of each block with its stored header. It is used to check
+// that the current code reproduces the recent blocks before an upgrade.
+//
+// Since the states of only some blocks are stored, the blocks from the latest
+// block with a stored state before first are re-executed as well. The resulting
+// states are kept in memory and never written to the database. It returns the
+// number of the re-executed blocks from first, and the error of the first block
+// which diverges or cannot be re-executed.
+func (bc *BlockChain) ReplayBlocks(first, last uint64) (int, error) {
+	if first == 0 || first > last {
+		return 0, fmt.Errorf("invalid block range [%d, %d]", first, last)
+	}
+	if head := bc.CurrentBlock().NumberU64(); last > head {
+		return 0, fmt.Errorf("block %d is beyond the head block %d", last, head)
+	}
+	stateDB := state.NewDatabase(bc.db)
+
+	// Find the latest block with a stored state to start the re-execution from.
+	base := bc.GetBlockByNumber(first - 1)
+	for {
+		if base == nil {
+			return 0, fmt.Errorf("no block with a stored state before block %d", first)
+		}
+		if _, err := stateDB.OpenTrie(base.Root()); err == nil {
+			break
+		}
+		if base.NumberU64() == 0 {
+			return 0, fmt.Errorf("no block with a stored state before block %d", first)
+		}
+		base = bc.GetBlockByNumber(base.NumberU64() - 1)
+	}
+	if base.NumberU64()+1 < first {
+		logger.Info("Re-executing blocks without stored states", "from", base.NumberU64()+1, "to", first-1)
+	}
+
+	replayed := 0
+	parent := base
+	for number := base.NumberU64() + 1; number <= last; number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return replayed, fmt.Errorf("block %d is not found", number)
+		}
+		if err := bc.replayBlock(stateDB, block, parent); err != nil {
+			return replayed, fmt.Errorf("block %d (%x) diverged: %v", number, block.Hash(), err)
+		}
+		if number >= first {
+			replayed++
+		}
+		parent = block
+	}
+	return replayed, nil
+}
+
+// replayBlock re-executes the block on top of the state of the parent, and validates
+// the result against the header of the block. The resulting state is committed to
+// the given state database for the next block.
+func (bc *BlockChain) replayBlock(stateDB state.Database, block, parent *types.Block) error {
+	statedb, err := state.New(parent.Root(), stateDB)
+	if err != nil {
+		return err
+	}
+	receipts, _, usedGas, err := bc.Processor().Process(block, statedb, bc.vmConfig)
+	if err != nil {
+		return err
+	}
+	if err := bc.Validator().ValidateState(block, parent, statedb, receipts, usedGas); err != nil {
+		return err
+	}
+	_, err = statedb.Commit(true)
+	return err
+}
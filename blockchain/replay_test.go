@@ -0,0 +1,93 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// perturbedProcessor is a Processor changing the state after the given block is processed.
+type perturbedProcessor struct {
+	Processor
+	number uint64
+}
+
+func (p *perturbedProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+	receipts, logs, usedGas, err := p.Processor.Process(block, statedb, cfg)
+	if block.NumberU64() == p.number {
+		statedb.AddBalance(common.Address{0x01}, big.NewInt(1))
+	}
+	return receipts, logs, usedGas, err
+}
+
+func TestReplayBlocks(t *testing.T) {
+	var (
+		db      = database.NewMemoryDBManager()
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(params.KLAY)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 10, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{0x01}, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+
+	// The states of the recent blocks are not stored, so the replay starts from the genesis.
+	if n, err := chain.ReplayBlocks(6, 10); err != nil || n != 5 {
+		t.Fatalf("matching replay failed: replayed %d, err %v", n, err)
+	}
+
+	// A perturbed execution of block 8 is detected.
+	chain.SetProcessor(&perturbedProcessor{Processor: chain.Processor(), number: 8})
+	n, err := chain.ReplayBlocks(6, 10)
+	if err == nil || !strings.Contains(err.Error(), "block 8") || !strings.Contains(err.Error(), "invalid merkle root") {
+		t.Fatalf("perturbed replay should diverge at block 8: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("replayed block count mismatch: have %d, want 2", n)
+	}
+
+	if _, err := chain.ReplayBlocks(6, 11); err == nil {
+		t.Error("replay beyond the head should fail")
+	}
+}
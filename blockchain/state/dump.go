@@ -27,6 +27,7 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/ser/rlp"
 	"github.com/klaytn/klaytn/storage/statedb"
+	"math/big"
 )
 
 var (
@@ -90,6 +91,29 @@ func (self *StateDB) RawDump() Dump {
 	return dump
 }
 
+// DumpBalances returns the balances of at most max accounts, in the order of
+// their hashed addresses, and whether there are more accounts.
+// Accounts whose address preimage is unknown are skipped.
+func (self *StateDB) DumpBalances(max int) (map[common.Address]*big.Int, bool) {
+	balances := make(map[common.Address]*big.Int)
+	it := statedb.NewIterator(self.trie.NodeIterator(nil))
+	for it.Next() {
+		addr := self.trie.GetKey(it.Key)
+		if addr == nil {
+			continue
+		}
+		if len(balances) >= max {
+			return balances, true
+		}
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(it.Value, serializer); err != nil {
+			continue
+		}
+		balances[common.BytesToAddress(addr)] = serializer.GetAccount().GetBalance()
+	}
+	return balances, false
+}
+
 func (self *StateDB) Dump() []byte {
 	json, err := json.MarshalIndent(self.RawDump(), "", "    ")
 	if err != nil {
@@ -0,0 +1,313 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"os"
+	"time"
+)
+
+const (
+	pruneMarking uint8 = iota + 1
+	pruneSweeping
+)
+
+var (
+	pruneStatusKey  = []byte("PruneStatus")
+	pruneMarkPrefix = []byte("m")
+	// pruneMarkPathPrefix marks account trie nodes on the paths to archived accounts.
+	// Unlike pruneMarkPrefix, the subtries of such nodes are not entirely marked.
+	pruneMarkPathPrefix = []byte("p")
+
+	errNoPruneRoot = errors.New("no state root to retain")
+)
+
+// pruneStatus is the progress of a state pruning stored in the journal.
+type pruneStatus struct {
+	Phase uint8
+	Roots []common.Hash
+}
+
+// ArchivedAccounts specifies accounts whose states are retained at historical state
+// roots, in addition to the entire states at the retained roots.
+type ArchivedAccounts struct {
+	Addresses []common.Address
+	Roots     []common.Hash // Historical state roots at which the accounts' states are retained
+}
+
+// PruneState deletes every state trie node and contract code which is not reachable
+// from the given state roots. If archived is not nil, the states of the archived
+// accounts at its roots are retained as well, i.e., the account trie nodes on the
+// paths to the accounts and their storage tries and contract codes.
+//
+// The pruning is done in two phases so that it can be resumed after a crash. Nodes
+// reachable from the roots are marked in a journal database stored at journalPath
+// first, and unmarked nodes are swept from the state trie database afterwards.
+// Marking does not touch the state trie database, so an interrupted marking is simply
+// restarted. If an interrupted sweeping is found in the journal, it is finished with
+// the journaled marks and the given roots are ignored.
+func PruneState(db database.DBManager, roots []common.Hash, archived *ArchivedAccounts, journalPath string) error {
+	journal, err := database.NewLevelDBWithOption(journalPath, database.GetDefaultLevelDBOption())
+	if err != nil {
+		return err
+	}
+
+	status := readPruneStatus(journal)
+	if status == nil || status.Phase != pruneSweeping {
+		if status != nil {
+			logger.Warn("Restarting interrupted state pruning from marking")
+			journal.Close()
+			if err := os.RemoveAll(journalPath); err != nil {
+				return err
+			}
+			if journal, err = database.NewLevelDBWithOption(journalPath, database.GetDefaultLevelDBOption()); err != nil {
+				return err
+			}
+		}
+		if len(roots) == 0 {
+			journal.Close()
+			return errNoPruneRoot
+		}
+		status = &pruneStatus{Phase: pruneMarking, Roots: roots}
+		if err := writePruneStatus(journal, status); err != nil {
+			journal.Close()
+			return err
+		}
+		if err := markStateTries(db, journal, roots, archived); err != nil {
+			journal.Close()
+			return err
+		}
+		status.Phase = pruneSweeping
+		if err := writePruneStatus(journal, status); err != nil {
+			journal.Close()
+			return err
+		}
+	} else {
+		logger.Warn("Resuming interrupted state pruning", "retainedRoots", len(status.Roots))
+	}
+
+	if err := sweepStateTrie(db, journal); err != nil {
+		journal.Close()
+		return err
+	}
+	journal.Close()
+	return os.RemoveAll(journalPath)
+}
+
+func readPruneStatus(journal database.Database) *pruneStatus {
+	enc, err := journal.Get(pruneStatusKey)
+	if err != nil || len(enc) == 0 {
+		return nil
+	}
+	status := new(pruneStatus)
+	if err := rlp.DecodeBytes(enc, status); err != nil {
+		logger.Warn("Invalid state pruning journal", "err", err)
+		return nil
+	}
+	return status
+}
+
+func writePruneStatus(journal database.Database, status *pruneStatus) error {
+	enc, err := rlp.EncodeToBytes(status)
+	if err != nil {
+		return err
+	}
+	return journal.Put(pruneStatusKey, enc)
+}
+
+func pruneMarkKey(hash common.Hash) []byte {
+	return append(append([]byte{}, pruneMarkPrefix...), hash[:]...)
+}
+
+func pruneMarkPathKey(hash common.Hash) []byte {
+	return append(append([]byte{}, pruneMarkPathPrefix...), hash[:]...)
+}
+
+// isPruneMarked returns true if the node of the given hash should not be swept.
+func isPruneMarked(journal database.Database, hash common.Hash) bool {
+	if ok, _ := journal.Has(pruneMarkKey(hash)); ok {
+		return true
+	}
+	ok, _ := journal.Has(pruneMarkPathKey(hash))
+	return ok
+}
+
+// markStateTries marks every trie node and contract code reachable from the given
+// roots in the journal. The states of archived accounts are marked after that, so
+// that the partially marked account trie nodes on their paths are never skipped
+// while marking entire state tries.
+func markStateTries(db database.DBManager, journal database.Database, roots []common.Hash, archived *ArchivedAccounts) error {
+	var (
+		sdb      = NewDatabase(db)
+		batch    = journal.NewBatch()
+		marked   = 0
+		start    = time.Now()
+		logged   = time.Now()
+		isMarked = func(hash common.Hash) bool {
+			ok, _ := journal.Has(pruneMarkKey(hash))
+			return ok
+		}
+		mark = func(hash common.Hash) error {
+			marked++
+			return database.PutAndWriteBatchesOverThreshold(batch, pruneMarkKey(hash), []byte{0x01})
+		}
+	)
+	// markTrie marks the nodes of a trie. Subtries which are already marked are skipped
+	// since they have been fully visited by the same marking.
+	markTrie := func(tr Trie, onLeaf func(blob []byte) error) error {
+		it := tr.NodeIterator(nil)
+		for descend := true; it.Next(descend); {
+			descend = true
+			if hash := it.Hash(); !common.EmptyHash(hash) {
+				if isMarked(hash) {
+					descend = false
+					continue
+				}
+				if err := mark(hash); err != nil {
+					return err
+				}
+			}
+			if it.Leaf() && onLeaf != nil {
+				if err := onLeaf(it.LeafBlob()); err != nil {
+					return err
+				}
+			}
+			if time.Since(logged) > 8*time.Second {
+				logger.Info("Marking reachable state trie nodes", "marked", marked, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+		return it.Error()
+	}
+	onAccount := func(blob []byte) error {
+		serializer := account.NewAccountSerializer()
+		if err := rlp.Decode(bytes.NewReader(blob), serializer); err != nil {
+			return err
+		}
+		pa := account.GetProgramAccount(serializer.GetAccount())
+		if pa == nil {
+			return nil
+		}
+		if codeHash := pa.GetCodeHash(); !bytes.Equal(codeHash, emptyCodeHash) {
+			if err := mark(common.BytesToHash(codeHash)); err != nil {
+				return err
+			}
+		}
+		if root := pa.GetStorageRoot(); root != emptyRoot && !common.EmptyHash(root) && !isMarked(root) {
+			storageTrie, err := sdb.OpenStorageTrie(root)
+			if err != nil {
+				return err
+			}
+			return markTrie(storageTrie, nil)
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		tr, err := sdb.OpenTrie(root)
+		if err != nil {
+			return err
+		}
+		if err := markTrie(tr, onAccount); err != nil {
+			return err
+		}
+	}
+	// Writes the marks so far since isMarked reads them from the journal.
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	batch.Reset()
+
+	if archived != nil {
+		markPath := func(hash common.Hash) error {
+			marked++
+			return database.PutAndWriteBatchesOverThreshold(batch, pruneMarkPathKey(hash), []byte{0x01})
+		}
+		for _, root := range archived.Roots {
+			tr, err := sdb.OpenTrie(root)
+			if err != nil {
+				return err
+			}
+			for _, addr := range archived.Addresses {
+				// Proof nodes are the account trie nodes on the path to the account.
+				proofDB := database.NewMemoryDBManager()
+				if err := tr.Prove(crypto.Keccak256(addr[:]), 0, proofDB); err != nil {
+					return err
+				}
+				if err := proofDB.IterateStateTrieKeys(func(key []byte) error {
+					return markPath(common.BytesToHash(key))
+				}); err != nil {
+					return err
+				}
+				blob, err := tr.TryGet(addr[:])
+				if err != nil {
+					return err
+				}
+				if len(blob) > 0 {
+					if err := onAccount(blob); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	logger.Info("Marked reachable state trie nodes", "roots", len(roots), "marked", marked, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// sweepStateTrie deletes every trie node and contract code which is not marked in
+// the journal. Only hash keys are considered, so preimages and other entries sharing
+// the database are kept.
+func sweepStateTrie(db database.DBManager, journal database.Database) error {
+	var (
+		deleted = 0
+		start   = time.Now()
+		logged  = time.Now()
+	)
+	err := db.IterateStateTrieKeys(func(key []byte) error {
+		if len(key) != common.HashLength {
+			return nil
+		}
+		if isPruneMarked(journal, common.BytesToHash(key)) {
+			return nil
+		}
+		if err := db.DeleteStateTrieNode(key); err != nil {
+			return err
+		}
+		deleted++
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Sweeping unreachable state trie nodes", "deleted", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Swept unreachable state trie nodes", "deleted", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
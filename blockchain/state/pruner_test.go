@@ -0,0 +1,182 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makePruneTestStates commits a sequence of states and returns their roots.
+func makePruneTestStates(t *testing.T, db database.DBManager, n int) []common.Hash {
+	var (
+		sdb      = NewDatabase(db)
+		root     = common.Hash{}
+		roots    []common.Hash
+		eoa      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		contract = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	)
+	for i := 0; i < n; i++ {
+		state, err := New(root, sdb)
+		require.NoError(t, err)
+
+		if i == 0 {
+			state.CreateSmartContractAccount(contract, params.CodeFormatEVM)
+			state.SetCode(contract, []byte{0x60, 0x00, 0x60, 0x00})
+		}
+		state.AddBalance(eoa, big.NewInt(int64(i+1)))
+		state.SetState(contract, common.BigToHash(big.NewInt(int64(i))), common.BigToHash(big.NewInt(int64(i+1))))
+
+		root, err = state.Commit(false)
+		require.NoError(t, err)
+		require.NoError(t, sdb.TrieDB().Commit(root, false))
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// resolveState reads every trie node and contract code reachable from root.
+func resolveState(db database.DBManager, root common.Hash) error {
+	sdb := NewDatabase(db)
+	tr, err := sdb.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if !it.Leaf() {
+			continue
+		}
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(it.LeafBlob(), serializer); err != nil {
+			return err
+		}
+		pa := account.GetProgramAccount(serializer.GetAccount())
+		if pa == nil {
+			continue
+		}
+		if _, err := sdb.ContractCode(common.BytesToHash(pa.GetCodeHash())); err != nil {
+			return err
+		}
+		storageTrie, err := sdb.OpenStorageTrie(pa.GetStorageRoot())
+		if err != nil {
+			return err
+		}
+		storageIt := storageTrie.NodeIterator(nil)
+		for storageIt.Next(true) {
+		}
+		if err := storageIt.Error(); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+func TestPruneState(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	roots := makePruneTestStates(t, db, 6)
+
+	dir, err := ioutil.TempDir("", "klay-prune-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	journalPath := filepath.Join(dir, "journal")
+
+	retained := []common.Hash{roots[0], roots[4], roots[5]}
+	require.NoError(t, PruneState(db, retained, nil, journalPath))
+
+	for _, root := range retained {
+		assert.NoError(t, resolveState(db, root), "retained state %x", root)
+	}
+	for _, root := range roots[1:4] {
+		assert.Error(t, resolveState(db, root), "pruned state %x", root)
+	}
+
+	// The journal is removed after a successful pruning.
+	_, err = os.Stat(journalPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPruneStateResumeSweeping(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	roots := makePruneTestStates(t, db, 3)
+
+	dir, err := ioutil.TempDir("", "klay-prune-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	journalPath := filepath.Join(dir, "journal")
+
+	// Leave a journal which finished marking the latest state only.
+	journal, err := database.NewLevelDBWithOption(journalPath, database.GetDefaultLevelDBOption())
+	require.NoError(t, err)
+	require.NoError(t, markStateTries(db, journal, roots[2:], nil))
+	require.NoError(t, writePruneStatus(journal, &pruneStatus{Phase: pruneSweeping, Roots: roots[2:]}))
+	journal.Close()
+
+	// The journaled roots take precedence over the given ones.
+	require.NoError(t, PruneState(db, roots, nil, journalPath))
+
+	assert.NoError(t, resolveState(db, roots[2]))
+	assert.Error(t, resolveState(db, roots[0]))
+	assert.Error(t, resolveState(db, roots[1]))
+}
+
+func TestPruneStateArchivedAccounts(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	roots := makePruneTestStates(t, db, 6)
+
+	dir, err := ioutil.TempDir("", "klay-prune-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		eoa      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		contract = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		archived = &ArchivedAccounts{Addresses: []common.Address{contract}, Roots: roots}
+	)
+	require.NoError(t, PruneState(db, roots[5:], archived, filepath.Join(dir, "journal")))
+	assert.NoError(t, resolveState(db, roots[5]))
+
+	for i, root := range roots[:5] {
+		// The states of other accounts are pruned.
+		assert.Error(t, resolveState(db, root), "pruned state %x", root)
+
+		statedb, err := New(root, NewDatabase(db))
+		require.NoError(t, err)
+		// The historical storage of the archived contract still resolves.
+		for j := 0; j <= i; j++ {
+			key := common.BigToHash(big.NewInt(int64(j)))
+			assert.Equal(t, common.BigToHash(big.NewInt(int64(j+1))), statedb.GetState(contract, key), "block %d, key %d", i, j)
+		}
+		assert.Equal(t, []byte{0x60, 0x00, 0x60, 0x00}, statedb.GetCode(contract))
+		assert.NoError(t, statedb.Error(), "block %d", i)
+
+		statedb, err = New(root, NewDatabase(db))
+		require.NoError(t, err)
+		statedb.GetBalance(eoa)
+		assert.Error(t, statedb.Error(), "block %d", i)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"io"
+)
+
+// stateExportVersion is the version of the state export format.
+const stateExportVersion = 1
+
+var errStateExportVersion = errors.New("unsupported state export version")
+
+// stateExportHeader is the first item of a state export.
+type stateExportHeader struct {
+	Version uint64
+	Root    common.Hash
+}
+
+// exportedAccount is an account of a state export. The account is kept in its
+// serialized form, so that every account type and its account key are preserved.
+// Keys are the hashed keys of the secure tries, as preimages may not be stored.
+type exportedAccount struct {
+	Hash    common.Hash
+	Account []byte
+	Code    []byte
+	Storage []exportedStorage
+}
+
+type exportedStorage struct {
+	Hash  common.Hash
+	Value []byte
+}
+
+// ExportState writes the state at root to w as a stream of RLP items: a header
+// followed by every account with its code and storage.
+func ExportState(db Database, root common.Hash, w io.Writer) (int, error) {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return 0, err
+	}
+	if err := rlp.Encode(w, &stateExportHeader{Version: stateExportVersion, Root: root}); err != nil {
+		return 0, err
+	}
+
+	accounts := 0
+	it := statedb.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(it.Value, serializer); err != nil {
+			return accounts, err
+		}
+		exported := exportedAccount{Hash: common.BytesToHash(it.Key), Account: it.Value}
+
+		if pa := account.GetProgramAccount(serializer.GetAccount()); pa != nil {
+			if codeHash := common.BytesToHash(pa.GetCodeHash()); codeHash != emptyCode {
+				if exported.Code, err = db.ContractCode(codeHash); err != nil {
+					return accounts, err
+				}
+			}
+			storageTrie, err := db.OpenStorageTrie(pa.GetStorageRoot())
+			if err != nil {
+				return accounts, err
+			}
+			storageIt := statedb.NewIterator(storageTrie.NodeIterator(nil))
+			for storageIt.Next() {
+				exported.Storage = append(exported.Storage, exportedStorage{common.BytesToHash(storageIt.Key), storageIt.Value})
+			}
+			if storageIt.Err != nil {
+				return accounts, storageIt.Err
+			}
+		}
+		if err := rlp.Encode(w, &exported); err != nil {
+			return accounts, err
+		}
+		accounts++
+	}
+	return accounts, it.Err
+}
+
+// ImportState reads a state written by ExportState from r and commits it to the
+// disk database of db. It returns the root of the imported state, which is
+// verified against the root recorded in the export.
+func ImportState(db Database, r io.Reader) (common.Hash, int, error) {
+	stream := rlp.NewStream(r, 0)
+
+	var header stateExportHeader
+	if err := stream.Decode(&header); err != nil {
+		return common.Hash{}, 0, err
+	}
+	if header.Version != stateExportVersion {
+		return common.Hash{}, 0, fmt.Errorf("%v: %d", errStateExportVersion, header.Version)
+	}
+
+	trieDB := db.TrieDB()
+	tr, err := statedb.NewTrie(common.Hash{}, trieDB)
+	if err != nil {
+		return common.Hash{}, 0, err
+	}
+
+	accounts := 0
+	for {
+		var exported exportedAccount
+		if err := stream.Decode(&exported); err == io.EOF {
+			break
+		} else if err != nil {
+			return common.Hash{}, accounts, err
+		}
+		if err := importAccount(trieDB, &exported); err != nil {
+			return common.Hash{}, accounts, fmt.Errorf("account %x: %v", exported.Hash, err)
+		}
+		if err := tr.TryUpdate(exported.Hash[:], exported.Account); err != nil {
+			return common.Hash{}, accounts, err
+		}
+		accounts++
+	}
+
+	root, err := tr.Commit(func(leaf []byte, parent common.Hash) error {
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(leaf, serializer); err != nil {
+			return err
+		}
+		if pa := account.GetProgramAccount(serializer.GetAccount()); pa != nil {
+			if pa.GetStorageRoot() != emptyState {
+				trieDB.Reference(pa.GetStorageRoot(), parent)
+			}
+			if code := common.BytesToHash(pa.GetCodeHash()); code != emptyCode {
+				trieDB.Reference(code, parent)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return common.Hash{}, accounts, err
+	}
+	if root != header.Root {
+		return common.Hash{}, accounts, fmt.Errorf("state root mismatch: have %x, want %x", root, header.Root)
+	}
+	if err := trieDB.Commit(root, false); err != nil {
+		return common.Hash{}, accounts, err
+	}
+	return root, accounts, nil
+}
+
+// importAccount writes the code and the storage trie of an exported account to trieDB.
+func importAccount(trieDB *statedb.Database, exported *exportedAccount) error {
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(exported.Account, serializer); err != nil {
+		return err
+	}
+	pa := account.GetProgramAccount(serializer.GetAccount())
+	if pa == nil {
+		if len(exported.Code) > 0 || len(exported.Storage) > 0 {
+			return errors.New("code or storage is given for a non-program account")
+		}
+		return nil
+	}
+
+	if len(exported.Code) > 0 {
+		trieDB.InsertBlob(common.BytesToHash(pa.GetCodeHash()), exported.Code)
+	}
+	storageTrie, err := statedb.NewTrie(common.Hash{}, trieDB)
+	if err != nil {
+		return err
+	}
+	for _, slot := range exported.Storage {
+		if err := storageTrie.TryUpdate(slot.Hash[:], slot.Value); err != nil {
+			return err
+		}
+	}
+	root, err := storageTrie.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if root != pa.GetStorageRoot() {
+		return fmt.Errorf("storage root mismatch: have %x, want %x", root, pa.GetStorageRoot())
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
+	"testing"
+)
+
+func TestExportImportState(t *testing.T) {
+	src := NewDatabase(database.NewMemoryDBManager())
+	stateDB, _ := New(common.Hash{}, src)
+
+	key, _ := crypto.GenerateKey()
+	var (
+		legacy   = common.Address{0x01}
+		keyed    = common.Address{0x02}
+		contract = common.Address{0x03}
+	)
+	stateDB.AddBalance(legacy, big.NewInt(1))
+	stateDB.CreateEOA(keyed, false, accountkey.NewAccountKeyPublicWithValue(&key.PublicKey))
+	stateDB.SetNonce(keyed, 3)
+	stateDB.CreateSmartContractAccount(contract, params.CodeFormatEVM)
+	stateDB.SetCode(contract, []byte{0x60, 0x01})
+	for i := byte(1); i <= 10; i++ {
+		stateDB.SetState(contract, common.Hash{i}, common.Hash{i, i})
+	}
+	root, err := stateDB.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	exported, err := ExportState(src, root, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 3 {
+		t.Errorf("exported account count mismatch: have %d, want 3", exported)
+	}
+
+	dst := NewDatabase(database.NewMemoryDBManager())
+	imported, count, err := ImportState(dst, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != root || count != exported {
+		t.Fatalf("import mismatch: have root %x with %d accounts, want %x with %d", imported, count, root, exported)
+	}
+
+	importedState, err := New(imported, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !importedState.GetKey(keyed).Equal(stateDB.GetKey(keyed)) {
+		t.Error("account key is not preserved")
+	}
+	if !bytes.Equal(importedState.GetCode(contract), []byte{0x60, 0x01}) {
+		t.Errorf("code mismatch: have %x", importedState.GetCode(contract))
+	}
+	if value := importedState.GetState(contract, common.Hash{5}); value != (common.Hash{5, 5}) {
+		t.Errorf("storage mismatch: have %x", value)
+	}
+}
+
+func TestImportTruncatedState(t *testing.T) {
+	src := NewDatabase(database.NewMemoryDBManager())
+	stateDB, _ := New(common.Hash{}, src)
+	stateDB.AddBalance(common.Address{0x01}, big.NewInt(1))
+	root, _ := stateDB.Commit(false)
+	src.TrieDB().Commit(root, false)
+
+	var buf bytes.Buffer
+	if _, err := ExportState(src, root, &buf); err != nil {
+		t.Fatal(err)
+	}
+	// Drop the last byte of the account, so that the stream ends before the export does.
+	truncated := buf.Bytes()[:buf.Len()-1]
+	if _, _, err := ImportState(NewDatabase(database.NewMemoryDBManager()), bytes.NewReader(truncated)); err == nil {
+		t.Error("importing a truncated export should fail")
+	}
+}
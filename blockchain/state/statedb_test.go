@@ -670,3 +670,53 @@ func TestZeroHashNode(t *testing.T) {
 		t.Fatalf("node should return nil value for zero hash")
 	}
 }
+
+// TestMaxDirtyBufferSize checks that committing a state touching a large number
+// of accounts keeps the dirty trie nodes within the budget by flushing them to
+// disk while committing, and that the flushed state is still complete.
+func TestMaxDirtyBufferSize(t *testing.T) {
+	const (
+		accounts = 5000
+		budget   = common.StorageSize(64 * 1024)
+	)
+	commitAccounts := func(sdb Database) common.Hash {
+		state, _ := New(common.Hash{}, sdb)
+		for i := 0; i < accounts; i++ {
+			state.AddBalance(common.BytesToAddress([]byte{byte(i >> 8), byte(i)}), big.NewInt(int64(i+1)))
+		}
+		root, err := state.Commit(false)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		return root
+	}
+
+	// Without a budget, all the nodes of the block stay in memory.
+	unbounded := NewDatabase(database.NewMemoryDBManager())
+	commitAccounts(unbounded)
+	if size, _ := unbounded.TrieDB().Size(); size <= budget {
+		t.Fatalf("dirty size without budget too small for the test: %v", size)
+	}
+
+	diskDB := database.NewMemoryDBManager()
+	bounded := NewDatabase(diskDB)
+	bounded.TrieDB().SetMaxDirtyBufferSize(budget)
+	root := commitAccounts(bounded)
+	if size, _ := bounded.TrieDB().Size(); size > budget {
+		t.Fatalf("dirty size over budget: have %v, want <= %v", size, budget)
+	}
+	if err := bounded.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+
+	state, err := New(root, NewDatabase(diskDB))
+	if err != nil {
+		t.Fatalf("failed to open committed state: %v", err)
+	}
+	for i := 0; i < accounts; i++ {
+		addr := common.BytesToAddress([]byte{byte(i >> 8), byte(i)})
+		if balance := state.GetBalance(addr); balance.Cmp(big.NewInt(int64(i+1))) != 0 {
+			t.Fatalf("account %d: balance mismatch: have %v, want %v", i, balance, i+1)
+		}
+	}
+}
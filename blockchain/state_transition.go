@@ -30,12 +30,15 @@ import (
 )
 
 var (
-	errInsufficientBalanceForGas         = errors.New("insufficient balance of the sender to pay for gas")
-	errInsufficientBalanceForGasFeePayer = errors.New("insufficient balance of the fee payer to pay for gas")
-	errNotProgramAccount                 = errors.New("not a program account")
-	errAccountAlreadyExists              = errors.New("account already exists")
-	errMsgToNil                          = errors.New("msg.To() is nil")
-	errInvalidCodeFormat                 = errors.New("smart contract code format is invalid")
+	// ErrInsufficientBalanceForGas is returned if the sender cannot afford its share of the gas.
+	ErrInsufficientBalanceForGas = errors.New("insufficient balance of the sender to pay for gas")
+	// ErrInsufficientBalanceForGasFeePayer is returned if the fee payer cannot afford its share of the gas.
+	ErrInsufficientBalanceForGasFeePayer = errors.New("insufficient balance of the fee payer to pay for gas")
+
+	errNotProgramAccount    = errors.New("not a program account")
+	errAccountAlreadyExists = errors.New("account already exists")
+	errMsgToNil             = errors.New("msg.To() is nil")
+	errInvalidCodeFormat    = errors.New("smart contract code format is invalid")
 )
 
 /*
@@ -171,11 +174,11 @@ func (st *StateTransition) buyGas() error {
 		feePayer, feeSender := types.CalcFeeWithRatio(feeRatio, mgval)
 
 		if st.state.GetBalance(validatedFeePayer).Cmp(feePayer) < 0 {
-			return errInsufficientBalanceForGasFeePayer
+			return ErrInsufficientBalanceForGasFeePayer
 		}
 
 		if st.state.GetBalance(validatedSender).Cmp(feeSender) < 0 {
-			return errInsufficientBalanceForGas
+			return ErrInsufficientBalanceForGas
 		}
 
 		st.state.SubBalance(validatedFeePayer, feePayer)
@@ -183,7 +186,7 @@ func (st *StateTransition) buyGas() error {
 	} else {
 		// to make a short circuit, process the special case feeRatio == MaxFeeRatio
 		if st.state.GetBalance(validatedFeePayer).Cmp(mgval) < 0 {
-			return errInsufficientBalanceForGasFeePayer
+			return ErrInsufficientBalanceForGasFeePayer
 		}
 
 		st.state.SubBalance(validatedFeePayer, mgval)
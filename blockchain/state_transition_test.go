@@ -19,8 +19,13 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
 	"testing"
 )
 
@@ -81,3 +86,48 @@ func TestPrintErrorCodeTable(t *testing.T) {
 		}
 	}
 }
+
+// ratioMessage is a Message whose fee is split between the sender and a separate fee payer.
+type ratioMessage struct {
+	*types.Transaction
+	feePayer common.Address
+	feeRatio types.FeeRatio
+}
+
+func (m *ratioMessage) ValidatedFeePayer() common.Address { return m.feePayer }
+
+func (m *ratioMessage) FeeRatio() (types.FeeRatio, bool) { return m.feeRatio, true }
+
+func TestBuyGasInsufficientBalance(t *testing.T) {
+	sender, feePayer := common.HexToAddress("0x1000"), common.HexToAddress("0x2000")
+	gas, gasPrice := uint64(21000), big.NewInt(1)
+
+	tests := []struct {
+		name            string
+		senderBalance   int64
+		feePayerBalance int64
+		feePayerOnly    bool
+		expectedErr     error
+	}{
+		{"sender", 10000, 21000, false, ErrInsufficientBalanceForGas},
+		{"fee payer with ratio", 21000, 10000, false, ErrInsufficientBalanceForGasFeePayer},
+		{"fee payer without ratio", 0, 20999, true, ErrInsufficientBalanceForGasFeePayer},
+		{"enough balance", 10500, 10500, false, nil},
+	}
+	for _, tc := range tests {
+		stateDB, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+		stateDB.SetBalance(sender, big.NewInt(tc.senderBalance))
+		stateDB.SetBalance(feePayer, big.NewInt(tc.feePayerBalance))
+
+		var msg Message = &ratioMessage{types.NewMessage(sender, &sender, 0, common.Big0, gas, gasPrice, nil, false, 0), feePayer, 50}
+		if tc.feePayerOnly {
+			msg = types.NewMessage(feePayer, &sender, 0, common.Big0, gas, gasPrice, nil, false, 0)
+		}
+		evm := vm.NewEVM(vm.Context{}, stateDB, params.TestChainConfig, &vm.Config{})
+
+		err := NewStateTransition(evm, msg).buyGas()
+		if err != tc.expectedErr {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expectedErr, err)
+		}
+	}
+}
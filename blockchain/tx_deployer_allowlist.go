@@ -0,0 +1,86 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+// deployerAllowList is a set of addresses which are allowed to deploy smart contracts.
+// The addresses are loaded from a file which contains one hex address per line.
+// Empty lines and lines starting with '#' are ignored.
+type deployerAllowList struct {
+	path      string
+	deployers map[common.Address]struct{}
+	mu        sync.RWMutex
+}
+
+// newDeployerAllowList creates a deployerAllowList and loads the addresses from the given file.
+func newDeployerAllowList(path string) (*deployerAllowList, error) {
+	list := &deployerAllowList{path: path}
+	if err := list.reload(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// reload reads the allow list file again and replaces the current set of addresses.
+func (list *deployerAllowList) reload() error {
+	deployers, err := loadDeployerAllowList(list.path)
+	if err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	list.deployers = deployers
+	list.mu.Unlock()
+
+	logger.Info("Loaded the deployer allow list", "path", list.path, "deployers", len(deployers))
+	return nil
+}
+
+// isAllowed returns true if the given address is on the allow list.
+func (list *deployerAllowList) isAllowed(addr common.Address) bool {
+	list.mu.RLock()
+	defer list.mu.RUnlock()
+
+	_, ok := list.deployers[addr]
+	return ok
+}
+
+func loadDeployerAllowList(path string) (map[common.Address]struct{}, error) {
+	addrs, err := common.LoadAddressList(path)
+	if err != nil {
+		return nil, err
+	}
+	deployers := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		deployers[addr] = struct{}{}
+	}
+	return deployers, nil
+}
+
+// isContractDeployTx returns true if the given transaction deploys a smart contract.
+func isContractDeployTx(tx *types.Transaction) bool {
+	if tx.IsLegacyTransaction() {
+		return tx.To() == nil
+	}
+	return tx.Type().IsContractDeploy()
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+)
+
+// TxEvictionPolicy decides which transactions make room for a new transaction
+// when the transaction pool is full.
+type TxEvictionPolicy string
+
+const (
+	// TxEvictionNonce only accepts a new transaction filling a nonce gap of its
+	// sender, dropping the queued transaction of the sender with the highest nonce.
+	TxEvictionNonce TxEvictionPolicy = "nonce"
+
+	// TxEvictionPrice accepts a new transaction paying a higher effective gas price
+	// than the cheapest remote transactions in the pool, dropping the cheapest ones.
+	TxEvictionPrice TxEvictionPolicy = "price"
+)
+
+// ParseTxEvictionPolicy returns the tx pool eviction policy of the given name.
+func ParseTxEvictionPolicy(s string) (TxEvictionPolicy, error) {
+	switch policy := TxEvictionPolicy(s); policy {
+	case TxEvictionNonce, TxEvictionPrice:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown tx eviction policy %q (want %q or %q)", s, TxEvictionNonce, TxEvictionPrice)
+	}
+}
@@ -147,12 +147,16 @@ func (journal *txJournal) rotate(all map[common.Address]types.Transactions) erro
 	journaled := 0
 	for _, txs := range all {
 		for _, tx := range txs {
+			// Conditions are not encoded, so conditional txs are not journaled.
+			if tx.Conditions() != nil {
+				continue
+			}
 			if err = rlp.Encode(replacement, tx); err != nil {
 				replacement.Close()
 				return err
 			}
+			journaled++
 		}
-		journaled += len(txs)
 	}
 	replacement.Close()
 
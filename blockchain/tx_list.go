@@ -443,7 +443,7 @@ func (h priceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
 
 func (h priceHeap) Less(i, j int) bool {
 	// Sort primarily by price, returning the cheaper one
-	switch h[i].GasPrice().Cmp(h[j].GasPrice()) {
+	switch types.CompareEffectiveGasPrice(h[i], h[j]) {
 	case -1:
 		return true
 	case 1:
@@ -559,7 +559,7 @@ func (l *txPricedList) Underpriced(tx *types.Transaction, local *accountSet) boo
 		return false
 	}
 	cheapest := []*types.Transaction(*l.items)[0]
-	return cheapest.GasPrice().Cmp(tx.GasPrice()) >= 0
+	return types.CompareEffectiveGasPrice(cheapest, tx) >= 0
 }
 
 // Discard finds a number of most underpriced transactions, removes them from the
@@ -21,6 +21,7 @@
 package blockchain
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/klaytn/klaytn/kerrors"
 	"math"
@@ -28,9 +29,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/golang-lru"
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto/sha3"
 	"github.com/klaytn/klaytn/event"
 	"github.com/klaytn/klaytn/metrics"
 	"github.com/klaytn/klaytn/params"
@@ -46,7 +50,10 @@ const (
 	// txMsgCh is the number of list of transactions can be queued.
 	txMsgChSize = 100
 	// MaxTxDataSize is a heuristic limit of tx data size, and txPool rejects transactions over 32KB to prevent DOS attacks.
+	// It is the default of TxPoolConfig.MaxTxDataSize.
 	MaxTxDataSize = 32 * 1024
+	// maxDroppedTxs is the number of recently removed transaction hashes remembered to report them as dropped.
+	maxDroppedTxs = 16384
 )
 
 var (
@@ -73,8 +80,47 @@ var (
 	invalidTxCounter     = metrics.NewRegisteredCounter("txpool/invalid", nil)
 	underpricedTxCounter = metrics.NewRegisteredCounter("txpool/underpriced", nil)
 	refusedTxCounter     = metrics.NewRegisteredCounter("txpool/refuse", nil)
+
+	replaceCooldownCounter = metrics.NewRegisteredCounter("txpool/replace/cooldown", nil) // Dropped due to the replacement cooldown
 )
 
+// txRejectReasons are the reasons of the rejections counted as txpool/reject/<reason>,
+// and also by tx type if TxPoolConfig.RejectMetrics is set. Other errors are counted as "other".
+var txRejectReasons = map[error]string{
+	ErrInvalidChainId:         "chain_id",
+	ErrInvalidUnitPrice:       "unit_price",
+	ErrGasPriceBelowFloor:     "price_floor",
+	ErrOversizedData:          "oversized_data",
+	ErrOversizedCode:          "oversized_code",
+	ErrNegativeValue:          "negative_value",
+	types.ErrInvalidSigSender: "invalid_sig",
+	kerrors.ErrLegacyTransactionMustBeWithLegacyKey: "legacy_key",
+	ErrStaleKeySignature:                            "stale_key",
+	ErrDeployerNotAllowed:                           "deployer",
+	ErrNonceTooLow:                                  "nonce_too_low",
+	ErrInvalidFeePayer:                              "invalid_fee_payer",
+	kerrors.ErrFeeRatioOutOfRange:                   "fee_ratio",
+	kerrors.ErrSelfFeeDelegation:                    "self_fee_delegation",
+	ErrInsufficientFundsFrom:                        "insufficient_funds",
+	ErrInsufficientFundsFeePayer:                    "insufficient_fee_payer_funds",
+	ErrIntrinsicGas:                                 "intrinsic_gas",
+	kerrors.ErrNotForProgramAccount:                 "program_account",
+}
+
+// txRejectReason returns the reason of the rejection with the given error.
+func txRejectReason(err error) string {
+	if reason, ok := txRejectReasons[err]; ok {
+		return reason
+	}
+	return "other"
+}
+
+// txRejectCounterName returns the name of the counter of the rejections of the
+// given tx type with the given error.
+func txRejectCounterName(txType types.TxType, err error) string {
+	return fmt.Sprintf("klay/txpool/reject/%s/%s", txType, txRejectReason(err))
+}
+
 // TxStatus is the current status of a transaction as seen by the pool.
 type TxStatus uint
 
@@ -84,6 +130,8 @@ const (
 	TxStatusPending
 	// for Les
 	TxStatusIncluded
+	// TxStatusDropped is the status of a transaction recently removed from the pool.
+	TxStatusDropped
 )
 
 // blockChain provides the state of blockchain and current gas limit to do
@@ -118,6 +166,24 @@ type TxPoolConfig struct {
 	Lifetime   time.Duration // Maximum amount of time non-executable transaction are queued
 
 	NoAccountCreation bool // Whether account creation transactions should be disabled
+
+	DeployerAllowList string // File of addresses allowed to deploy contracts (empty = unrestricted)
+
+	AllowZeroGasTypes []types.TxType // Tx types accepted with zero gas price regardless of the unit price
+
+	ReplaceCooldown time.Duration // Minimum interval between replacements of the same nonce of an account (0 = unlimited)
+
+	EvictionPolicy TxEvictionPolicy // Policy deciding which transactions make room for a new one when the pool is full
+
+	MaxCodeSize uint64 // Maximum init code size of contract deployment transactions
+
+	MaxTxDataSize uint64 // Maximum RLP encoded size of transactions (0 = MaxTxDataSize)
+
+	StaleKeys int // Number of accounts whose key replaced by an account update is remembered to detect stale key signatures (0 = disabled)
+
+	RejectMetrics bool // Whether to count rejected transactions by tx type and rejection reason
+
+	RejectSelfFeeDelegation bool // Whether fee-delegated transactions whose fee payer is the sender should be rejected
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -136,6 +202,14 @@ var DefaultTxPoolConfig = TxPoolConfig{
 
 	KeepLocals: false,
 	Lifetime:   5 * time.Minute,
+
+	EvictionPolicy: TxEvictionNonce,
+
+	MaxCodeSize: params.MaxCodeSize,
+
+	MaxTxDataSize: MaxTxDataSize,
+
+	StaleKeys: 1024,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -154,6 +228,17 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		logger.Error("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	if _, err := ParseTxEvictionPolicy(string(conf.EvictionPolicy)); err != nil {
+		logger.Error("Sanitizing invalid txpool eviction policy", "provided", conf.EvictionPolicy, "updated", DefaultTxPoolConfig.EvictionPolicy)
+		conf.EvictionPolicy = DefaultTxPoolConfig.EvictionPolicy
+	}
+	if conf.MaxCodeSize < 1 {
+		logger.Error("Sanitizing invalid txpool max code size", "provided", conf.MaxCodeSize, "updated", DefaultTxPoolConfig.MaxCodeSize)
+		conf.MaxCodeSize = DefaultTxPoolConfig.MaxCodeSize
+	}
+	if conf.MaxTxDataSize == 0 {
+		conf.MaxTxDataSize = MaxTxDataSize
+	}
 	return conf
 }
 
@@ -170,6 +255,7 @@ type TxPool struct {
 	chain        blockChain
 	gasPrice     *big.Int
 	txFeed       event.Feed
+	dropFeed     event.Feed
 	scope        event.SubscriptionScope
 	chainHeadCh  chan ChainHeadEvent
 	chainHeadSub event.Subscription
@@ -177,6 +263,7 @@ type TxPool struct {
 	mu           sync.RWMutex
 
 	currentBlockNumber uint64                    // Current block number
+	gasPriceFloors     map[uint64]*big.Int       // Gas price floors of the tx types for the next block
 	currentState       *state.StateDB            // Current state in the blockchain head
 	pendingNonce       map[common.Address]uint64 // Pending nonce tracking virtual nonces
 
@@ -186,11 +273,16 @@ type TxPool struct {
 	//TODO-Klaytn
 	txMu sync.RWMutex
 
-	pending map[common.Address]*txList         // All currently processable transactions
-	queue   map[common.Address]*txList         // Queued but non-processable transactions
-	beats   map[common.Address]time.Time       // Last heartbeat from each known account
-	all     map[common.Hash]*types.Transaction // All transactions to allow lookups
-	priced  *txPricedList                      // All transactions sorted by price
+	pending  map[common.Address]*txList         // All currently processable transactions
+	queue    map[common.Address]*txList         // Queued but non-processable transactions
+	beats    map[common.Address]time.Time       // Last heartbeat from each known account
+	replaced map[txReplaceKey]time.Time         // Last replacement time of each account nonce, used if ReplaceCooldown is set
+	all      map[common.Hash]*types.Transaction // All transactions to allow lookups
+	dropped  *lru.Cache                         // Hashes of the transactions recently removed from all
+	oldKeys  *lru.Cache                         // Keys replaced by account updates, nil if stale key detection is disabled
+	priced   *txPricedList                      // All transactions sorted by price
+
+	feePaid map[common.Address]map[common.Hash]*types.Transaction // Fee-delegated transactions of all, indexed by the fee payer
 
 	wg sync.WaitGroup // for shutdown sync
 
@@ -198,6 +290,15 @@ type TxPool struct {
 	balanceCache common.Cache
 
 	txMsgCh chan types.Transactions
+
+	deployers *deployerAllowList // Allowed contract deployers, nil if unrestricted
+
+	stateMu sync.Mutex // Serializes the reads of currentState done under the read lock
+
+	contentHashMu sync.Mutex  // Serializes the updates of contentHash done under the read lock
+	contentHash   common.Hash // Cached result of ContentHash, zero if all has changed since
+
+	diskSpaceGuard *DiskSpaceGuard // rejects new transactions while the free disk space is low, nil if disabled
 }
 
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
@@ -215,18 +316,35 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pending:      make(map[common.Address]*txList),
 		queue:        make(map[common.Address]*txList),
 		beats:        make(map[common.Address]time.Time),
+		replaced:     make(map[txReplaceKey]time.Time),
 		all:          make(map[common.Hash]*types.Transaction),
+		feePaid:      make(map[common.Address]map[common.Hash]*types.Transaction),
 		pendingNonce: make(map[common.Address]uint64),
 		chainHeadCh:  make(chan ChainHeadEvent, chainHeadChanSize),
 		// TODO-Klaytn We use ChainConfig.UnitPrice to initialize TxPool.gasPrice,
 		//         later we have to change this rule when governance of UnitPrice is determined.
-		gasPrice:     new(big.Int).SetUint64(chainconfig.UnitPrice),
+		gasPrice:     chainconfig.GetUnitPrice(),
 		nonceCache:   chain.GetNonceCache(),
 		balanceCache: chain.GetBalanceCache(),
 		txMsgCh:      make(chan types.Transactions, txMsgChSize),
 	}
+	pool.dropped, _ = lru.New(maxDroppedTxs)
+	if config.StaleKeys > 0 {
+		pool.oldKeys, _ = lru.New(config.StaleKeys)
+	}
 	pool.locals = newAccountSet(pool.signer)
 	pool.priced = newTxPricedList(&pool.all)
+
+	if config.DeployerAllowList != "" {
+		deployers, err := newDeployerAllowList(config.DeployerAllowList)
+		if err != nil {
+			// Failing open would let anyone deploy contracts, so reject all deployments instead.
+			logger.Error("Failed to load the deployer allow list, all contract deployments will be rejected",
+				"path", config.DeployerAllowList, "err", err)
+			deployers = &deployerAllowList{path: config.DeployerAllowList}
+		}
+		pool.deployers = deployers
+	}
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// If local transactions and journaling is enabled, load from disk
@@ -324,6 +442,12 @@ func (pool *TxPool) loop() {
 					}
 				}
 			}
+			// Forget the replacements whose cooldown is over
+			for key, last := range pool.replaced {
+				if time.Since(last) >= pool.config.ReplaceCooldown {
+					delete(pool.replaced, key)
+				}
+			}
 			pool.mu.Unlock()
 
 			// Handle local transaction journal rotation
@@ -352,9 +476,15 @@ func (pool *TxPool) lockedReset(oldHead, newHead *types.Header) {
 // of the transaction pool is valid with regard to the chain state.
 func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// If we're reorging an old state, reinject all dropped transactions
-	var reinject types.Transactions
-
-	if oldHead != nil && oldHead.Hash() != newHead.ParentHash {
+	var (
+		reinject types.Transactions
+		applied  []*types.Block // Newly applied blocks, used to find the keys replaced by account updates
+	)
+	if oldHead != nil && oldHead.Hash() == newHead.ParentHash {
+		if block := pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64()); block != nil {
+			applied = append(applied, block)
+		}
+	} else if oldHead != nil {
 		// If the reorg is too deep, avoid doing it (will happen during fast sync)
 		oldNum := oldHead.Number.Uint64()
 		newNum := newHead.Number.Uint64()
@@ -378,6 +508,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 			}
 			for add.NumberU64() > rem.NumberU64() {
 				included = append(included, add.Transactions()...)
+				applied = append(applied, add)
 				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
 					logger.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
 					return
@@ -390,6 +521,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 					return
 				}
 				included = append(included, add.Transactions()...)
+				applied = append(applied, add)
 				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
 					logger.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
 					return
@@ -398,6 +530,8 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 			reinject = types.TxDifference(discarded, included)
 		}
 	}
+	pool.rememberOldKeys(applied)
+
 	// Initialize the internal state to the current head
 	if newHead == nil {
 		newHead = pool.chain.CurrentBlock().Header() // Special case during testing
@@ -410,6 +544,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = stateDB
 	pool.pendingNonce = make(map[common.Address]uint64)
 	pool.currentBlockNumber = newHead.Number.Uint64()
+	if pool.gasPriceFloors, err = pool.chainconfig.GasPriceFloors(new(big.Int).SetUint64(pool.currentBlockNumber + 1)); err != nil {
+		logger.Error("Failed to parse the gas price floors", "err", err)
+	}
 
 	// Inject any transactions discarded due to reorgs
 	logger.Debug("Reinjecting stale transactions", "count", len(reinject))
@@ -462,6 +599,12 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeDroppedTxsEvent registers a subscription of DroppedTxsEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDroppedTxsEvent(ch chan<- DroppedTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -470,6 +613,14 @@ func (pool *TxPool) GasPrice() *big.Int {
 	return new(big.Int).Set(pool.gasPrice)
 }
 
+// SetDiskSpaceGuard sets the guard which halts accepting transactions while the free disk space is low.
+func (pool *TxPool) SetDiskSpaceGuard(guard *DiskSpaceGuard) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.diskSpaceGuard = guard
+}
+
 // SetGasPrice updates the gas price of the transaction pool for new transactions, and drops all old transactions.
 func (pool *TxPool) SetGasPrice(price *big.Int) {
 	if pool.gasPrice.Cmp(price) != 0 {
@@ -482,6 +633,8 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 		pool.queue = make(map[common.Address]*txList)
 		pool.beats = make(map[common.Address]time.Time)
 		pool.all = make(map[common.Hash]*types.Transaction)
+		pool.feePaid = make(map[common.Address]map[common.Hash]*types.Transaction)
+		pool.contentHash = common.Hash{}
 		pool.pendingNonce = make(map[common.Address]uint64)
 		pool.locals = newAccountSet(pool.signer)
 		pool.priced = newTxPricedList(&pool.all)
@@ -530,6 +683,131 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool for the given
+// account, returning its pending as well as queued transactions sorted by nonce.
+func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var pending, queued types.Transactions
+	if list, ok := pool.pending[addr]; ok {
+		pending = list.Flatten()
+	}
+	if list, ok := pool.queue[addr]; ok {
+		queued = list.Flatten()
+	}
+	return pending, queued
+}
+
+// NonceGaps returns the nonces missing between the pending nonce of the given
+// account and its highest queued nonce, in ascending order. Queued transactions
+// above a gap can not be executed until the gap is filled. It returns
+// ErrTooManyNonceGaps if more nonces are missing than the non-executable slots
+// of an account.
+func (pool *TxPool) NonceGaps(addr common.Address) ([]uint64, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	list := pool.queue[addr]
+	if list == nil || list.Empty() {
+		return nil, nil
+	}
+	var (
+		gaps []uint64
+		next = pool.getPendingNonce(addr)
+	)
+	for _, tx := range list.Flatten() {
+		if tx.Nonce() < next {
+			continue
+		}
+		if uint64(len(gaps))+tx.Nonce()-next > pool.config.NonExecSlotsAccount {
+			return nil, ErrTooManyNonceGaps
+		}
+		for ; next < tx.Nonce(); next++ {
+			gaps = append(gaps, next)
+		}
+		next = tx.Nonce() + 1
+	}
+	return gaps, nil
+}
+
+// AvailableBalance returns the balance of the given account which is not yet
+// reserved by pending transactions: the state balance minus the maximum amounts
+// the account pays as the sender or the fee payer of the pending transactions.
+// It is zero if the pending transactions reserve the whole balance.
+func (pool *TxPool) AvailableBalance(addr common.Address) *big.Int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	// The state database is not safe for concurrent reads, but the writers hold the write lock.
+	pool.stateMu.Lock()
+	available := new(big.Int).Set(pool.getBalance(addr))
+	pool.stateMu.Unlock()
+
+	if list := pool.pending[addr]; list != nil {
+		for _, tx := range list.Flatten() {
+			bySender, _ := txCosts(tx)
+			available.Sub(available, bySender)
+		}
+	}
+	for hash, tx := range pool.feePaid[addr] {
+		// Queued transactions do not reserve the balance of the fee payer either.
+		list := pool.pending[tx.ValidatedSender()]
+		if list == nil {
+			continue
+		}
+		if pending := list.txs.Get(tx.Nonce()); pending == nil || pending.Hash() != hash {
+			continue
+		}
+		_, byFeePayer := txCosts(tx)
+		available.Sub(available, byFeePayer)
+	}
+	if available.Sign() < 0 {
+		return new(big.Int)
+	}
+	return available
+}
+
+// txCosts returns the maximum amounts the sender and the fee payer of tx pay.
+// The sender pays the value and the part of the fee not delegated to the fee payer.
+func txCosts(tx *types.Transaction) (bySender, byFeePayer *big.Int) {
+	if !tx.IsFeeDelegatedTransaction() {
+		return tx.Cost(), new(big.Int)
+	}
+	if feeRatio, isRatioTx := tx.FeeRatio(); isRatioTx {
+		feeByFeePayer, feeBySender := types.CalcFeeWithRatio(feeRatio, tx.Fee())
+		return new(big.Int).Add(tx.Value(), feeBySender), feeByFeePayer
+	}
+	return new(big.Int).Set(tx.Value()), tx.Fee()
+}
+
+// ContentHash returns a deterministic hash of the hashes of all pending and queued
+// transactions, which can be used to cheaply check whether the pools of two nodes
+// have diverged. The hash is recomputed only if the set of transactions has changed.
+func (pool *TxPool) ContentHash() common.Hash {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	pool.contentHashMu.Lock()
+	defer pool.contentHashMu.Unlock()
+
+	if pool.contentHash != (common.Hash{}) {
+		return pool.contentHash
+	}
+	hashes := make([]common.Hash, 0, len(pool.all))
+	for hash := range pool.all {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+	hasher := sha3.NewKeccak256()
+	for _, hash := range hashes {
+		hasher.Write(hash[:])
+	}
+	hasher.Sum(pool.contentHash[:0])
+
+	return pool.contentHash
+}
+
 // Pending retrieves all currently processable transactions, groupped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -606,7 +884,16 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
-func (pool *TxPool) validateTx(tx *types.Transaction) error {
+func (pool *TxPool) validateTx(tx *types.Transaction) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		metrics.GetOrRegisterCounter("txpool/reject/"+txRejectReason(err), nil).Inc(1)
+		if pool.config.RejectMetrics {
+			metrics.GetOrRegisterCounter(txRejectCounterName(tx.Type(), err), nil).Inc(1)
+		}
+	}()
 	gasFeePayer := uint64(0)
 
 	// Check chain Id first.
@@ -615,16 +902,32 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 	}
 
 	// NOTE-Klaytn Drop transactions with unexpected gasPrice
-	if pool.gasPrice.Cmp(tx.GasPrice()) != 0 {
-		logger.Trace("fail to validate unitprice", "Klaytn unitprice", pool.gasPrice, "tx unitprice", tx.GasPrice())
+	// Zero gas price is allowed only for the tx types configured by AllowZeroGasTypes.
+	if tx.UnitPrice().Sign() == 0 && pool.gasPrice.Sign() != 0 {
+		if !pool.isZeroGasAllowed(tx.Type()) {
+			logger.Trace("fail to validate zero unitprice", "Klaytn unitprice", pool.gasPrice, "txType", tx.Type())
+			return ErrInvalidUnitPrice
+		}
+	} else if pool.gasPrice.Cmp(tx.UnitPrice()) != 0 {
+		logger.Trace("fail to validate unitprice", "Klaytn unitprice", pool.gasPrice, "tx unitprice", tx.UnitPrice())
 		return ErrInvalidUnitPrice
 	}
 
-	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
-	if tx.Size() > MaxTxDataSize {
+	// Drop transactions the next block could not include due to the gas price floor of their tx type.
+	if err := checkGasPriceFloor(pool.gasPriceFloors, tx); err != nil {
+		return err
+	}
+
+	// Heuristic limit, reject transactions over MaxTxDataSize (32KB by default) to prevent DOS attacks
+	if uint64(tx.Size()) > pool.config.MaxTxDataSize {
 		return ErrOversizedData
 	}
 
+	// Reject contract deployments whose init code exceeds the code size limit before execution.
+	if isContractDeployTx(tx) && uint64(len(tx.Data())) > pool.config.MaxCodeSize {
+		return ErrOversizedCode
+	}
+
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -634,10 +937,18 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 	// Make sure the transaction is signed properly
 	gasFrom, err := tx.ValidateSender(pool.signer, pool.currentState, pool.currentBlockNumber)
 	if err != nil {
+		if pool.isStaleKeySignature(tx, err) {
+			return ErrStaleKeySignature
+		}
 		return err
 	}
 	from := tx.ValidatedSender()
 
+	// Only the allowed deployers can deploy contracts if the allow list is configured.
+	if pool.deployers != nil && isContractDeployTx(tx) && !pool.deployers.isAllowed(from) {
+		return ErrDeployerNotAllowed
+	}
+
 	// Ensure the transaction adheres to nonce ordering
 	if pool.getNonce(from) > tx.Nonce() {
 		return ErrNonceTooLow
@@ -653,6 +964,9 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 			return ErrInvalidFeePayer
 		}
 		feePayer := tx.ValidatedFeePayer()
+		if pool.config.RejectSelfFeeDelegation && feePayer == from {
+			return kerrors.ErrSelfFeeDelegation
+		}
 		feePayerBalance := pool.getBalance(feePayer)
 		feeRatio, isRatioTx := tx.FeeRatio()
 		if isRatioTx {
@@ -712,6 +1026,113 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 	return nil
 }
 
+// rememberOldKeys remembers the keys replaced by the account updates of the given
+// blocks, so that transactions still signed by them are reported as such. The
+// replaced keys are read from the states of the parent blocks. The blocks are
+// ordered from the newest, so they are walked backwards to remember the latest
+// replaced key of an account.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) rememberOldKeys(blocks []*types.Block) {
+	if pool.oldKeys == nil {
+		return
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		block := blocks[i]
+		var parentState *state.StateDB
+		for _, tx := range block.Transactions() {
+			if !tx.Type().IsAccountUpdate() {
+				continue
+			}
+			from, err := tx.From()
+			if err != nil {
+				continue
+			}
+			if parentState == nil {
+				parent := pool.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+				if parent == nil {
+					logger.Error("Failed to find the parent of an applied block", "number", block.NumberU64(), "hash", block.Hash())
+					break
+				}
+				if parentState, err = pool.chain.StateAt(parent.Root()); err != nil {
+					logger.Error("Failed to get the parent state of an applied block", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+					break
+				}
+			}
+			pool.oldKeys.Add(from, parentState.GetKey(from).DeepCopy())
+		}
+	}
+}
+
+// isStaleKeySignature returns true if the sender signature of the tx, rejected by
+// ValidateSender with the given error, is valid for a key the sender replaced by
+// an account update.
+func (pool *TxPool) isStaleKeySignature(tx *types.Transaction, err error) bool {
+	if pool.oldKeys == nil || (err != types.ErrInvalidSigSender && err != kerrors.ErrLegacyTransactionMustBeWithLegacyKey) {
+		return false
+	}
+	var from common.Address
+	if tx.IsLegacyTransaction() {
+		if from, err = types.Sender(pool.signer, tx); err != nil {
+			return false
+		}
+	} else if from, err = tx.From(); err != nil {
+		return false
+	}
+	oldKey, ok := pool.oldKeys.Get(from)
+	if !ok {
+		return false
+	}
+	if tx.IsLegacyTransaction() {
+		// The signature of a legacy transaction always recovers its sender.
+		return oldKey.(accountkey.AccountKey).Type().IsLegacyAccountKey()
+	}
+	pubkeys, err := types.SenderPubkey(pool.signer, tx)
+	if err != nil {
+		return false
+	}
+	return accountkey.ValidateAccountKey(from, oldKey.(accountkey.AccountKey), pubkeys, tx.GetRoleTypeForValidation()) == nil
+}
+
+// isZeroGasAllowed returns true if the given tx type can be sent with zero gas price.
+func (pool *TxPool) isZeroGasAllowed(txType types.TxType) bool {
+	for _, t := range pool.config.AllowZeroGasTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
+
+// ReloadDeployerAllowList reloads the deployer allow list from the configured file.
+func (pool *TxPool) ReloadDeployerAllowList() error {
+	if pool.deployers == nil {
+		return ErrDeployerAllowListDisabled
+	}
+	return pool.deployers.reload()
+}
+
+// evictUnderpriced makes room in the full pool for tx by dropping the remote
+// transactions with the lowest effective gas price. It returns ErrUnderpriced if
+// tx is a remote transaction paying no more than the cheapest ones.
+func (pool *TxPool) evictUnderpriced(tx *types.Transaction, local bool) error {
+	if !local && pool.priced.Underpriced(tx, pool.locals) {
+		logger.Trace("Discarding underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
+		underpricedTxCounter.Inc(1)
+		return ErrUnderpriced
+	}
+	drop := pool.priced.Discard(len(pool.all)-int(pool.config.ExecSlotsAll+pool.config.NonExecSlotsAll-1), pool.locals)
+	for _, tx := range drop {
+		logger.Trace("Evicting underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
+		underpricedTxCounter.Inc(1)
+		pool.removeTx(tx.Hash(), false)
+	}
+	if len(drop) > 0 {
+		go pool.dropFeed.Send(DroppedTxsEvent{drop})
+	}
+	return nil
+}
+
 // getMaxTxFromQueueWhenNonceIsMissing finds and returns a trasaction with max nonce in queue when a given Tx has missing nonce.
 // Otherwise it returns a given Tx itself.
 func (pool *TxPool) getMaxTxFromQueueWhenNonceIsMissing(tx *types.Transaction, from *common.Address) *types.Transaction {
@@ -752,50 +1173,62 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		return false, err
 	}
 
-	// If the transaction pool is full and new Tx is valid,
+	// If the transaction pool is full and new Tx is valid, make room for it by the eviction policy.
+	// With TxEvictionPrice, the cheapest remote transactions are dropped for a new Tx paying more.
+	// With TxEvictionNonce,
 	// (1) discard a new Tx if there is no room for the account of the Tx
 	// (2) remove an old Tx with the largest nonce from queue to make a room for a new Tx with missing nonce
 	// (3) discard a new Tx if the new Tx does not have a missing nonce
 	// (4) discard underpriced transactions
 	if uint64(len(pool.all)) >= pool.config.ExecSlotsAll+pool.config.NonExecSlotsAll {
-		// (1) discard a new Tx if there is no room for the account of the Tx
-		from, _ := types.Sender(pool.signer, tx)
-		if pool.queue[from] == nil {
-			logger.Trace("Rejecting a new Tx, because TxPool is full and there is no room for the account", "hash", tx.Hash(), "account", from)
-			refusedTxCounter.Inc(1)
-			return false, fmt.Errorf("txpool is full: %d", uint64(len(pool.all)))
-		}
-
-		maxTx := pool.getMaxTxFromQueueWhenNonceIsMissing(tx, &from)
-		if maxTx != tx {
-			// (2) remove an old Tx with the largest nonce from queue to make a room for a new Tx with missing nonce
-			pool.removeTx(maxTx.Hash(), true)
-			logger.Trace("Removing an old Tx with the max nonce to insert a new Tx with missing nonce, because TxPool is full", "account", from, "new nonce(previously missing)", tx.Nonce(), "removed max nonce", maxTx.Nonce())
-		} else {
-			// (3) discard a new Tx if the new Tx does not have a missing nonce
-			logger.Trace("Rejecting a new Tx, because TxPool is full and a new TX does not have missing nonce", "hash", tx.Hash())
-			refusedTxCounter.Inc(1)
-			return false, fmt.Errorf("txpool is full and the new tx does not have missing nonce: %d", uint64(len(pool.all)))
-		}
+		switch pool.config.EvictionPolicy {
+		case TxEvictionPrice:
+			if err := pool.evictUnderpriced(tx, local); err != nil {
+				return false, err
+			}
+		default:
+			// (1) discard a new Tx if there is no room for the account of the Tx
+			from, _ := types.Sender(pool.signer, tx)
+			if pool.queue[from] == nil {
+				logger.Trace("Rejecting a new Tx, because TxPool is full and there is no room for the account", "hash", tx.Hash(), "account", from)
+				refusedTxCounter.Inc(1)
+				return false, fmt.Errorf("txpool is full: %d", uint64(len(pool.all)))
+			}
 
-		// (4) discard underpriced transactions
-		// If the new transaction is underpriced, don't accept it
-		if !local && pool.priced.Underpriced(tx, pool.locals) {
-			logger.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
-			underpricedTxCounter.Inc(1)
-			return false, ErrUnderpriced
-		}
-		// New transaction is better than our worse ones, make room for it
-		drop := pool.priced.Discard(len(pool.all)-int(pool.config.ExecSlotsAll+pool.config.NonExecSlotsAll-1), pool.locals)
-		for _, tx := range drop {
-			logger.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
-			underpricedTxCounter.Inc(1)
-			pool.removeTx(tx.Hash(), false)
+			maxTx := pool.getMaxTxFromQueueWhenNonceIsMissing(tx, &from)
+			if maxTx != tx {
+				// (2) remove an old Tx with the largest nonce from queue to make a room for a new Tx with missing nonce
+				pool.removeTx(maxTx.Hash(), true)
+				logger.Trace("Removing an old Tx with the max nonce to insert a new Tx with missing nonce, because TxPool is full", "account", from, "new nonce(previously missing)", tx.Nonce(), "removed max nonce", maxTx.Nonce())
+			} else {
+				// (3) discard a new Tx if the new Tx does not have a missing nonce
+				logger.Trace("Rejecting a new Tx, because TxPool is full and a new TX does not have missing nonce", "hash", tx.Hash())
+				refusedTxCounter.Inc(1)
+				return false, fmt.Errorf("txpool is full and the new tx does not have missing nonce: %d", uint64(len(pool.all)))
+			}
+
+			// (4) discard underpriced transactions
+			// If the new transaction is underpriced, don't accept it
+			if !local && pool.priced.Underpriced(tx, pool.locals) {
+				logger.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
+				underpricedTxCounter.Inc(1)
+				return false, ErrUnderpriced
+			}
+			// New transaction is better than our worse ones, make room for it
+			drop := pool.priced.Discard(len(pool.all)-int(pool.config.ExecSlotsAll+pool.config.NonExecSlotsAll-1), pool.locals)
+			for _, tx := range drop {
+				logger.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
+				underpricedTxCounter.Inc(1)
+				pool.removeTx(tx.Hash(), false)
+			}
 		}
 	}
 	// If the transaction is replacing an already pending one, do directly
 	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
+		if err := pool.checkReplaceCooldown(from, tx.Nonce()); err != nil {
+			return false, err
+		}
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
 		if !inserted {
@@ -804,11 +1237,12 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 		// New transaction is better, replace old one
 		if old != nil {
-			delete(pool.all, old.Hash())
+			pool.forgetTx(old.Hash())
 			pool.priced.Removed()
 			pendingReplaceCounter.Inc(1)
+			pool.markReplaced(from, tx.Nonce())
 		}
-		pool.all[tx.Hash()] = tx
+		pool.rememberTx(tx.Hash(), tx)
 		pool.priced.Put(tx)
 		pool.journalTx(from, tx)
 
@@ -843,6 +1277,11 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	if pool.queue[from] == nil {
 		pool.queue[from] = newTxList(false)
 	}
+	if pool.queue[from].Overlaps(tx) {
+		if err := pool.checkReplaceCooldown(from, tx.Nonce()); err != nil {
+			return false, err
+		}
+	}
 	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump)
 	if !inserted {
 		// An older transaction was better, discard this
@@ -851,17 +1290,48 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	}
 	// Discard any previous transaction and mark this
 	if old != nil {
-		delete(pool.all, old.Hash())
+		pool.forgetTx(old.Hash())
 		pool.priced.Removed()
 		queuedReplaceCounter.Inc(1)
+		pool.markReplaced(from, tx.Nonce())
 	}
 	if pool.all[hash] == nil {
-		pool.all[hash] = tx
+		pool.rememberTx(hash, tx)
 		pool.priced.Put(tx)
 	}
 	return old != nil, nil
 }
 
+// txReplaceKey identifies a nonce of an account for the replacement cooldown.
+type txReplaceKey struct {
+	addr  common.Address
+	nonce uint64
+}
+
+// checkReplaceCooldown returns ErrReplaceCooldown if the tx of the given nonce of
+// from has been replaced within the replacement cooldown.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) checkReplaceCooldown(from common.Address, nonce uint64) error {
+	if pool.config.ReplaceCooldown <= 0 {
+		return nil
+	}
+	if last, ok := pool.replaced[txReplaceKey{from, nonce}]; ok && time.Since(last) < pool.config.ReplaceCooldown {
+		replaceCooldownCounter.Inc(1)
+		return ErrReplaceCooldown
+	}
+	return nil
+}
+
+// markReplaced records that the tx of the given nonce of from has been replaced.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) markReplaced(from common.Address, nonce uint64) {
+	if pool.config.ReplaceCooldown > 0 {
+		pool.replaced[txReplaceKey{from, nonce}] = time.Now()
+	}
+}
+
 // journalTx adds the specified transaction to the local disk journal if it is
 // deemed to have been sent from a local account.
 func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
@@ -869,6 +1339,10 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || !pool.locals.contains(from) {
 		return
 	}
+	// Conditions are not encoded, so conditional txs are not journaled.
+	if tx.Conditions() != nil {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		logger.Error("Failed to journal local transaction", "err", err)
 	}
@@ -888,7 +1362,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 		// An older transaction was better, discard this
-		delete(pool.all, hash)
+		pool.forgetTx(hash)
 		pool.priced.Removed()
 
 		pendingDiscardCounter.Inc(1)
@@ -896,14 +1370,14 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
-		delete(pool.all, old.Hash())
+		pool.forgetTx(old.Hash())
 		pool.priced.Removed()
 
 		pendingReplaceCounter.Inc(1)
 	}
 	// Failsafe to work around direct pending inserts (tests)
 	if pool.all[hash] == nil {
-		pool.all[hash] = tx
+		pool.rememberTx(hash, tx)
 		pool.priced.Put(tx)
 	}
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
@@ -970,6 +1444,10 @@ func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
 // If given transactions exceed the capacity of TxPool, it slices the given transactions
 // so it can fit into TxPool's capacity.
 func (pool *TxPool) checkAndAddTxs(txs []*types.Transaction, local bool) []error {
+	if pool.config.EvictionPolicy == TxEvictionPrice {
+		// The cheapest transactions make room for new ones while adding them.
+		return pool.addTxs(txs, local)
+	}
 	poolSize := uint64(len(pool.all))
 	poolCapacity := int(pool.config.ExecSlotsAll + pool.config.NonExecSlotsAll - poolSize)
 	numTxs := len(txs)
@@ -996,6 +1474,9 @@ func (pool *TxPool) addTx(tx *types.Transaction, local bool) error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	if pool.diskSpaceGuard.Halted() {
+		return ErrDiskSpaceLow
+	}
 	// Try to inject the transaction and update any state
 	replace, err := pool.add(tx, local)
 	if err != nil {
@@ -1016,6 +1497,13 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	if pool.diskSpaceGuard.Halted() {
+		errs := make([]error, len(txs))
+		for i := range errs {
+			errs[i] = ErrDiskSpaceLow
+		}
+		return errs
+	}
 	return pool.addTxsLocked(txs, local)
 }
 
@@ -1047,8 +1535,8 @@ func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local bool) []error {
 	return errs
 }
 
-// Status returns the status (unknown/pending/queued) of a batch of transactions
-// identified by their hashes.
+// Status returns the status (unknown/pending/queued/dropped) of a batch of
+// transactions identified by their hashes.
 func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -1062,6 +1550,8 @@ func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 			} else {
 				status[i] = TxStatusQueued
 			}
+		} else if pool.dropped.Contains(hash) {
+			status[i] = TxStatusDropped
 		}
 	}
 	return status
@@ -1076,6 +1566,36 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all[hash]
 }
 
+// rememberTx adds the transaction to the lookup set of all transactions.
+func (pool *TxPool) rememberTx(hash common.Hash, tx *types.Transaction) {
+	pool.all[hash] = tx
+	pool.contentHash = common.Hash{}
+	if tx.IsFeeDelegatedTransaction() {
+		feePayer := tx.ValidatedFeePayer()
+		if pool.feePaid[feePayer] == nil {
+			pool.feePaid[feePayer] = make(map[common.Hash]*types.Transaction)
+		}
+		pool.feePaid[feePayer][hash] = tx
+	}
+}
+
+// forgetTx removes the transaction from the lookup set of all transactions and
+// remembers its hash as recently dropped.
+func (pool *TxPool) forgetTx(hash common.Hash) {
+	if tx := pool.all[hash]; tx != nil && tx.IsFeeDelegatedTransaction() {
+		feePayer := tx.ValidatedFeePayer()
+		if txs := pool.feePaid[feePayer]; txs != nil {
+			delete(txs, hash)
+			if len(txs) == 0 {
+				delete(pool.feePaid, feePayer)
+			}
+		}
+	}
+	delete(pool.all, hash)
+	pool.contentHash = common.Hash{}
+	pool.dropped.Add(hash, struct{}{})
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
@@ -1087,7 +1607,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 	addr, _ := types.Sender(pool.signer, tx) // already validated during insertion
 
 	// Remove it from the list of known transactions
-	delete(pool.all, hash)
+	pool.forgetTx(hash)
 	if outofbound {
 		pool.priced.Removed()
 	}
@@ -1143,7 +1663,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range list.Forward(pool.getNonce(addr)) {
 			hash := tx.Hash()
 			logger.Trace("Removed old queued transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.forgetTx(hash)
 			pool.priced.Removed()
 		}
 		// Drop all transactions that are too costly (low balance)
@@ -1151,7 +1671,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			logger.Trace("Removed unpayable queued transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.forgetTx(hash)
 			pool.priced.Removed()
 			queuedNofundsCounter.Inc(1)
 		}
@@ -1168,7 +1688,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		if !pool.locals.contains(addr) {
 			for _, tx := range list.Cap(int(pool.config.NonExecSlotsAccount)) {
 				hash := tx.Hash()
-				delete(pool.all, hash)
+				pool.forgetTx(hash)
 				pool.priced.Removed()
 				queuedRateLimitCounter.Inc(1)
 				logger.Trace("Removed cap-exceeding queued transaction", "hash", hash)
@@ -1218,7 +1738,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 						for _, tx := range list.Cap(list.Len() - 1) {
 							// Drop the transaction from the global pools too
 							hash := tx.Hash()
-							delete(pool.all, hash)
+							pool.forgetTx(hash)
 							pool.priced.Removed()
 
 							// Update the account nonce to the dropped transaction
@@ -1238,7 +1758,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 					for _, tx := range list.Cap(list.Len() - 1) {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
-						delete(pool.all, hash)
+						pool.forgetTx(hash)
 						pool.priced.Removed()
 
 						// Update the account nonce to the dropped transaction
@@ -1312,7 +1832,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range list.Forward(nonce) {
 			hash := tx.Hash()
 			logger.Trace("Removed old pending transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.forgetTx(hash)
 			pool.priced.Removed()
 		}
 
@@ -1329,7 +1849,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			logger.Trace("Removed unexecutable pending transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.forgetTx(hash)
 			pool.priced.Removed()
 			pendingNofundsCounter.Inc(1)
 		}
@@ -0,0 +1,122 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"io"
+	"os"
+)
+
+// maxTxPoolDumpSize is the maximum size of a tx pool dump file in bytes.
+const maxTxPoolDumpSize = 256 * 1024 * 1024
+
+var errTxPoolDumpTooLarge = fmt.Errorf("tx pool dump exceeds %d bytes", maxTxPoolDumpSize)
+
+// Dump writes all pending and queued transactions of the pool to the file at path
+// as a stream of RLP encoded transactions, ordered by nonce for each sender. Unlike
+// the journal, remote transactions are written too. Conditional transactions are
+// not written. It returns the number of written transactions.
+func (pool *TxPool) Dump(path string) (int, error) {
+	pending, queued := pool.Content()
+
+	// Write to a temporary file first, so a failed dump keeps the previous one.
+	output, err := os.OpenFile(path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	count, size := 0, 0
+	for _, content := range []map[common.Address]types.Transactions{pending, queued} {
+		for _, txs := range content {
+			for _, tx := range txs {
+				// Conditions are not encoded, so conditional txs are not dumped.
+				if tx.Conditions() != nil {
+					continue
+				}
+				enc, err := rlp.EncodeToBytes(tx)
+				if err == nil {
+					if size += len(enc); size > maxTxPoolDumpSize {
+						err = errTxPoolDumpTooLarge
+					} else {
+						_, err = output.Write(enc)
+					}
+				}
+				if err != nil {
+					output.Close()
+					os.Remove(path + ".new")
+					return 0, err
+				}
+				count++
+			}
+		}
+	}
+	if err := output.Close(); err != nil {
+		os.Remove(path + ".new")
+		return 0, err
+	}
+	if err := os.Rename(path+".new", path); err != nil {
+		return 0, err
+	}
+	logger.Info("Dumped transaction pool", "path", path, "transactions", count, "size", size)
+	return count, nil
+}
+
+// Load adds the transactions of the dump file at path to the pool as remote
+// transactions. Each transaction is validated again, and the invalid ones are
+// dropped. It returns the numbers of added and dropped transactions.
+func (pool *TxPool) Load(path string) (int, int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	if info.Size() > maxTxPoolDumpSize {
+		return 0, 0, errTxPoolDumpTooLarge
+	}
+	input, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer input.Close()
+
+	var txs types.Transactions
+	stream := rlp.NewStream(input, uint64(info.Size()))
+	for {
+		tx := new(types.Transaction)
+		if err := stream.Decode(tx); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return 0, 0, err
+		}
+		txs = append(txs, tx)
+	}
+
+	added, dropped := 0, 0
+	for i, err := range pool.AddRemotes(txs) {
+		if err != nil {
+			logger.Debug("Failed to add dumped transaction", "hash", txs[i].Hash(), "err", err)
+			dropped++
+			continue
+		}
+		added++
+	}
+	logger.Info("Loaded transaction pool dump", "path", path, "added", added, "dropped", dropped)
+	return added, dropped, nil
+}
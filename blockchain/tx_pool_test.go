@@ -25,15 +25,20 @@ import (
 	"fmt"
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/kerrors"
+	"github.com/klaytn/klaytn/metrics"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/storage/database"
 	"io/ioutil"
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -81,6 +86,28 @@ func (bc *testBlockChain) GetBalanceCache() common.Cache {
 	return nil
 }
 
+// testBlocksChain is a testBlockChain which also serves the given blocks and the
+// states of the given roots.
+type testBlocksChain struct {
+	*testBlockChain
+	blocks map[common.Hash]*types.Block
+	states map[common.Hash]*state.StateDB
+}
+
+func (bc *testBlocksChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block, ok := bc.blocks[hash]; ok {
+		return block
+	}
+	return bc.testBlockChain.GetBlock(hash, number)
+}
+
+func (bc *testBlocksChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	if statedb, ok := bc.states[root]; ok {
+		return statedb, nil
+	}
+	return bc.testBlockChain.StateAt(root)
+}
+
 func transaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
 	return pricedTransaction(nonce, gaslimit, big.NewInt(1), key)
 }
@@ -114,6 +141,23 @@ func validateTxPoolInternals(pool *TxPool) error {
 	if priced := pool.priced.items.Len() - pool.priced.stales; priced != pending+queued {
 		return fmt.Errorf("total priced transaction count %d != %d pending + %d queued", priced, pending, queued)
 	}
+	// Ensure the fee payer index holds exactly the fee-delegated transactions
+	feeDelegated := 0
+	for _, tx := range pool.all {
+		if tx.IsFeeDelegatedTransaction() {
+			feeDelegated++
+			if pool.feePaid[tx.ValidatedFeePayer()][tx.Hash()] != tx {
+				return fmt.Errorf("fee-delegated transaction %x missing in the fee payer index", tx.Hash())
+			}
+		}
+	}
+	indexed := 0
+	for _, txs := range pool.feePaid {
+		indexed += len(txs)
+	}
+	if indexed != feeDelegated {
+		return fmt.Errorf("fee payer index count %d != %d fee-delegated", indexed, feeDelegated)
+	}
 	// Ensure the next nonce to assign is the correct one
 	for addr, txs := range pool.pending {
 		// Find the last transaction
@@ -277,6 +321,478 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// Tests that only transactions paying exactly the unit price of the chain are accepted.
+func TestUnitPrice(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	unitPrice := params.TestChainConfig.GetUnitPrice()
+	if pool.GasPrice().Cmp(unitPrice) != 0 {
+		t.Fatalf("pool unit price mismatch: have %v, want %v", pool.GasPrice(), unitPrice)
+	}
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(0xffffffffffffff))
+
+	tx := pricedTransaction(0, 100000, new(big.Int).Add(unitPrice, common.Big1), key)
+	if tx.UnitPrice().Cmp(unitPrice) == 0 {
+		t.Fatalf("tx unit price should differ from %v", unitPrice)
+	}
+	if err := pool.AddRemote(tx); err != ErrInvalidUnitPrice {
+		t.Error("expected", ErrInvalidUnitPrice, "got", err)
+	}
+
+	tx = pricedTransaction(0, 100000, unitPrice, key)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Error("expected", nil, "got", err)
+	}
+}
+
+func TestMaxCodeSize(t *testing.T) {
+	t.Parallel()
+
+	if DefaultTxPoolConfig.MaxCodeSize != params.MaxCodeSize {
+		t.Errorf("default max code size mismatch: have %d, want %d", DefaultTxPoolConfig.MaxCodeSize, params.MaxCodeSize)
+	}
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.MaxCodeSize = 100
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(1000000000))
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	deploy := func(nonce uint64, size int) *types.Transaction {
+		tx, err := types.NewTransactionWithMap(types.TxTypeSmartContractDeploy, map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:         nonce,
+			types.TxValueKeyFrom:          from,
+			types.TxValueKeyTo:            (*common.Address)(nil),
+			types.TxValueKeyAmount:        big.NewInt(0),
+			types.TxValueKeyGasLimit:      uint64(500000),
+			types.TxValueKeyGasPrice:      big.NewInt(1),
+			types.TxValueKeyHumanReadable: false,
+			types.TxValueKeyData:          make([]byte, size),
+			types.TxValueKeyCodeFormat:    params.CodeFormatEVM,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	if err := pool.AddRemote(deploy(0, 100)); err != nil {
+		t.Errorf("deployment at the max code size should be accepted: %v", err)
+	}
+	if err := pool.AddRemote(deploy(1, 101)); err != ErrOversizedCode {
+		t.Error("expected", ErrOversizedCode, "got", err)
+	}
+}
+
+// Tests that a tx signed by a key replaced by an account update of an applied block
+// is rejected with ErrStaleKeySignature, and with the generic signature error if the
+// detection is disabled.
+func TestStaleKeySignature(t *testing.T) {
+	t.Parallel()
+
+	for _, staleKeys := range []int{0, 16} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+		blockchain := &testBlocksChain{
+			testBlockChain: &testBlockChain{statedb, 1000000, new(event.Feed)},
+			blocks:         make(map[common.Hash]*types.Block),
+			states:         make(map[common.Hash]*state.StateDB),
+		}
+
+		config := testTxPoolConfig
+		config.StaleKeys = staleKeys
+		pool := NewTxPool(config, params.TestChainConfig, blockchain)
+
+		var (
+			signer      = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+			oldKey, _   = crypto.GenerateKey()
+			newKey, _   = crypto.GenerateKey()
+			otherKey, _ = crypto.GenerateKey()
+			from        = crypto.PubkeyToAddress(oldKey.PublicKey)
+			accKey      = accountkey.NewAccountKeyPublicWithValue(&newKey.PublicKey)
+		)
+		pool.currentState.AddBalance(from, big.NewInt(1000000000))
+
+		newTx := func(txType types.TxType, values map[types.TxValueKeyType]interface{}, key *ecdsa.PrivateKey) *types.Transaction {
+			values[types.TxValueKeyFrom] = from
+			values[types.TxValueKeyGasLimit] = uint64(100000)
+			values[types.TxValueKeyGasPrice] = big.NewInt(1)
+			tx, err := types.NewTransactionWithMap(txType, values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+				t.Fatal(err)
+			}
+			return tx
+		}
+		transfer := func(key *ecdsa.PrivateKey) *types.Transaction {
+			return newTx(types.TxTypeValueTransfer, map[types.TxValueKeyType]interface{}{
+				types.TxValueKeyNonce:  uint64(1),
+				types.TxValueKeyTo:     common.HexToAddress("0xAAAA"),
+				types.TxValueKeyAmount: big.NewInt(1),
+			}, key)
+		}
+
+		update := newTx(types.TxTypeAccountUpdate, map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:      uint64(0),
+			types.TxValueKeyAccountKey: accKey,
+		}, oldKey)
+		if err := pool.AddRemote(update); err != nil {
+			t.Fatalf("failed to add account update: %v", err)
+		}
+		// A pooled account update does not replace the key yet.
+		if pool.oldKeys != nil && pool.oldKeys.Len() != 0 {
+			t.Fatalf("stale keys %d: old key remembered before the update is applied", staleKeys)
+		}
+		// Apply the account update in a block.
+		parent := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Root: common.HexToHash("0x01")})
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), ParentHash: parent.Hash(), Root: common.HexToHash("0x02")}).WithBody(types.Transactions{update})
+		blockchain.blocks[parent.Hash()] = parent
+		blockchain.blocks[block.Hash()] = block
+		blockchain.states[parent.Root()] = pool.currentState.Copy()
+
+		if err := pool.currentState.UpdateKey(from, accKey, 0); err != nil {
+			t.Fatal(err)
+		}
+		pool.currentState.SetNonce(from, 1)
+		pool.lockedReset(parent.Header(), block.Header())
+
+		wantStale := ErrStaleKeySignature
+		if staleKeys == 0 {
+			wantStale = types.ErrInvalidSigSender
+		}
+		if err := pool.AddRemote(transfer(oldKey)); err != wantStale {
+			t.Errorf("stale keys %d: old key signature: have %v, want %v", staleKeys, err, wantStale)
+		}
+		legacy, _ := types.SignTx(types.NewTransaction(1, common.HexToAddress("0xAAAA"), big.NewInt(1), 100000, big.NewInt(1), nil), signer, oldKey)
+		if staleKeys == 0 {
+			wantStale = kerrors.ErrLegacyTransactionMustBeWithLegacyKey
+		}
+		if err := pool.AddRemote(legacy); err != wantStale {
+			t.Errorf("stale keys %d: old key legacy tx: have %v, want %v", staleKeys, err, wantStale)
+		}
+		// A signature of a key the account never had is still a generic invalid signature.
+		if err := pool.AddRemote(transfer(otherKey)); err != types.ErrInvalidSigSender {
+			t.Errorf("stale keys %d: unknown key signature: have %v, want %v", staleKeys, err, types.ErrInvalidSigSender)
+		}
+		if err := pool.AddRemote(transfer(newKey)); err != nil {
+			t.Errorf("stale keys %d: new key signature: %v", staleKeys, err)
+		}
+		pool.Stop()
+	}
+}
+
+// Tests that rejected txs are counted by tx type and rejection reason.
+func TestTxRejectMetrics(t *testing.T) {
+	// Counters are created as no-ops while metrics are disabled.
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.RejectMetrics = true
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	var (
+		signer      = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		key, _      = crypto.GenerateKey()
+		payerKey, _ = crypto.GenerateKey()
+		otherKey, _ = crypto.GenerateKey()
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	pool.currentState.AddBalance(from, big.NewInt(1000000000))
+
+	valueTransfer := func(txType types.TxType, values map[types.TxValueKeyType]interface{}, signKey *ecdsa.PrivateKey) *types.Transaction {
+		values[types.TxValueKeyNonce] = uint64(0)
+		values[types.TxValueKeyFrom] = from
+		values[types.TxValueKeyTo] = common.HexToAddress("0xAAAA")
+		values[types.TxValueKeyAmount] = big.NewInt(1)
+		values[types.TxValueKeyGasLimit] = uint64(100000)
+		values[types.TxValueKeyGasPrice] = big.NewInt(1)
+		tx, err := types.NewTransactionWithMap(txType, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{signKey}); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	feeDelegated := valueTransfer(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyFeePayer: crypto.PubkeyToAddress(payerKey.PublicKey),
+	}, key)
+	if err := feeDelegated.SignFeePayerWithKeys(signer, []*ecdsa.PrivateKey{payerKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tx      *types.Transaction
+		err     error
+		counter string
+	}{
+		{transaction(0, 100000, otherKey), ErrInsufficientFundsFrom, "klay/txpool/reject/TxTypeLegacyTransaction/insufficient_funds"},
+		{pricedTransaction(0, 100000, big.NewInt(2), key), ErrInvalidUnitPrice, "klay/txpool/reject/TxTypeLegacyTransaction/unit_price"},
+		{valueTransfer(types.TxTypeValueTransfer, map[types.TxValueKeyType]interface{}{}, otherKey), types.ErrInvalidSigSender, "klay/txpool/reject/TxTypeValueTransfer/invalid_sig"},
+		{feeDelegated, ErrInsufficientFundsFeePayer, "klay/txpool/reject/TxTypeFeeDelegatedValueTransfer/insufficient_fee_payer_funds"},
+	}
+	for i, tt := range tests {
+		before := metrics.GetOrRegisterCounter(tt.counter, nil).Count()
+		if err := pool.AddRemote(tt.tx); err != tt.err {
+			t.Errorf("tx %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if have := metrics.GetOrRegisterCounter(tt.counter, nil).Count(); have != before+1 {
+			t.Errorf("tx %d: counter %s mismatch: have %d, want %d", i, tt.counter, have, before+1)
+		}
+	}
+}
+
+// Tests that rejected txs are counted by rejection reason regardless of RejectMetrics.
+func TestTxRejectReasonMetrics(t *testing.T) {
+	// Counters are created as no-ops while metrics are disabled.
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	var (
+		signer      = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		payerKey, _ = crypto.GenerateKey()
+		otherKey, _ = crypto.GenerateKey()
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+		program     = common.HexToAddress("0xBBBB")
+	)
+	pool.currentState.AddBalance(from, big.NewInt(1000000000))
+	pool.currentState.SetNonce(from, 1)
+	pool.currentState.CreateSmartContractAccount(program, params.CodeFormatEVM)
+
+	valueTransfer := func(txType types.TxType, to common.Address, values map[types.TxValueKeyType]interface{}) *types.Transaction {
+		values[types.TxValueKeyNonce] = uint64(1)
+		values[types.TxValueKeyFrom] = from
+		values[types.TxValueKeyTo] = to
+		values[types.TxValueKeyAmount] = big.NewInt(1)
+		values[types.TxValueKeyGasLimit] = uint64(100000)
+		values[types.TxValueKeyGasPrice] = big.NewInt(1)
+		tx, err := types.NewTransactionWithMap(txType, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	// The fee payer signature is made by a key other than the one of the fee payer.
+	invalidFeePayer := valueTransfer(types.TxTypeFeeDelegatedValueTransfer, common.HexToAddress("0xAAAA"), map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyFeePayer: crypto.PubkeyToAddress(payerKey.PublicKey),
+	})
+	if err := invalidFeePayer.SignFeePayerWithKeys(signer, []*ecdsa.PrivateKey{otherKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	oversized, err := types.SignTx(types.NewTransaction(1, common.HexToAddress("0xAAAA"), big.NewInt(1), 100000, big.NewInt(1), make([]byte, MaxTxDataSize)), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tx      *types.Transaction
+		err     error
+		counter string
+	}{
+		{transaction(0, 100000, key), ErrNonceTooLow, "txpool/reject/nonce_too_low"},
+		{pricedTransaction(1, 100000, big.NewInt(2), key), ErrInvalidUnitPrice, "txpool/reject/unit_price"},
+		{oversized, ErrOversizedData, "txpool/reject/oversized_data"},
+		{transaction(0, 100000, otherKey), ErrInsufficientFundsFrom, "txpool/reject/insufficient_funds"},
+		{invalidFeePayer, ErrInvalidFeePayer, "txpool/reject/invalid_fee_payer"},
+		{valueTransfer(types.TxTypeValueTransfer, program, map[types.TxValueKeyType]interface{}{}), kerrors.ErrNotForProgramAccount, "txpool/reject/program_account"},
+	}
+	for i, tt := range tests {
+		before := metrics.GetOrRegisterCounter(tt.counter, nil).Count()
+		if err := pool.AddRemote(tt.tx); err != tt.err {
+			t.Errorf("tx %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if have := metrics.GetOrRegisterCounter(tt.counter, nil).Count(); have != before+1 {
+			t.Errorf("tx %d: counter %s mismatch: have %d, want %d", i, tt.counter, have, before+1)
+		}
+	}
+}
+
+func TestDeployerAllowList(t *testing.T) {
+	t.Parallel()
+
+	allowedKey, _ := crypto.GenerateKey()
+	disallowedKey, _ := crypto.GenerateKey()
+	allowed := crypto.PubkeyToAddress(allowedKey.PublicKey)
+	disallowed := crypto.PubkeyToAddress(disallowedKey.PublicKey)
+
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary allow list: %v", err)
+	}
+	defer os.Remove(file.Name())
+	fmt.Fprintf(file, "# allowed deployers\n%s\n", allowed.Hex())
+	file.Close()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.DeployerAllowList = file.Name()
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	pool.currentState.AddBalance(allowed, big.NewInt(1000000000))
+	pool.currentState.AddBalance(disallowed, big.NewInt(1000000000))
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	deploy := func(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(1), []byte{0x60, 0x00}), signer, key)
+		return tx
+	}
+
+	if err := pool.AddRemote(deploy(0, allowedKey)); err != nil {
+		t.Errorf("deployment from an allowed deployer should be accepted: %v", err)
+	}
+	if err := pool.AddRemote(deploy(0, disallowedKey)); err != ErrDeployerNotAllowed {
+		t.Error("expected", ErrDeployerNotAllowed, "got", err)
+	}
+	// Non-deploy transactions are not restricted.
+	if err := pool.AddRemote(transaction(0, 100000, disallowedKey)); err != nil {
+		t.Errorf("value transfer from a disallowed deployer should be accepted: %v", err)
+	}
+
+	// Allow the second deployer and reload the list.
+	if err := ioutil.WriteFile(file.Name(), []byte(allowed.Hex()+"\n"+disallowed.Hex()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.ReloadDeployerAllowList(); err != nil {
+		t.Fatalf("failed to reload the allow list: %v", err)
+	}
+	if err := pool.AddRemote(deploy(1, disallowedKey)); err != nil {
+		t.Errorf("deployment from a newly allowed deployer should be accepted: %v", err)
+	}
+}
+
+func TestAllowZeroGasTypes(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.AllowZeroGasTypes = []types.TxType{types.TxTypeChainDataAnchoring}
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	pool.SetGasPrice(big.NewInt(1))
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(1000000000))
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	anchor, err := types.NewTransactionWithMap(types.TxTypeChainDataAnchoring, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:        uint64(0),
+		types.TxValueKeyFrom:         from,
+		types.TxValueKeyGasLimit:     uint64(100000),
+		types.TxValueKeyGasPrice:     big.NewInt(0),
+		types.TxValueKeyAnchoredData: []byte{0x01, 0x02, 0x03},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := anchor.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.AddRemote(anchor); err != nil {
+		t.Errorf("zero gas price anchoring tx should be accepted: %v", err)
+	}
+
+	// Types not in the list still require the unit price.
+	transfer, _ := types.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(100), 100000, big.NewInt(0), nil), signer, key)
+	if err := pool.AddRemote(transfer); err != ErrInvalidUnitPrice {
+		t.Error("expected", ErrInvalidUnitPrice, "got", err)
+	}
+}
+
+func TestTransactionPoolContentHash(t *testing.T) {
+	t.Parallel()
+
+	pool1, key := setupTxPool()
+	defer pool1.Stop()
+	pool2, _ := setupTxPool()
+	defer pool2.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool1.currentState.AddBalance(from, big.NewInt(1000000000))
+	pool2.currentState.AddBalance(from, big.NewInt(1000000000))
+
+	if pool1.ContentHash() != pool2.ContentHash() {
+		t.Fatal("empty pools have different content hashes")
+	}
+
+	// Add the same pending and queued transactions in different orders.
+	txs := types.Transactions{transaction(0, 100000, key), transaction(1, 100000, key), transaction(3, 100000, key)}
+	for _, tx := range txs {
+		if err := pool1.AddRemote(tx); err != nil {
+			t.Fatalf("failed to add tx: %v", err)
+		}
+	}
+	for i := len(txs) - 1; i >= 0; i-- {
+		if err := pool2.AddRemote(txs[i]); err != nil {
+			t.Fatalf("failed to add tx: %v", err)
+		}
+	}
+	hash := pool1.ContentHash()
+	if hash != pool2.ContentHash() {
+		t.Fatal("identical pools have different content hashes")
+	}
+	if hash != pool1.ContentHash() {
+		t.Fatal("content hash changed without any pool change")
+	}
+
+	// A single added transaction should change the hash.
+	added := transaction(2, 100000, key)
+	if err := pool1.AddRemote(added); err != nil {
+		t.Fatalf("failed to add tx: %v", err)
+	}
+	if pool1.contentHash != (common.Hash{}) {
+		t.Fatal("cached content hash not invalidated after adding a tx")
+	}
+	if pool1.ContentHash() == hash {
+		t.Fatal("content hash not changed after adding a tx")
+	}
+	if pool1.ContentHash() == pool2.ContentHash() {
+		t.Fatal("diverged pools have the same content hash")
+	}
+
+	// Removing the transaction again should restore the hash.
+	pool1.mu.Lock()
+	pool1.removeTx(added.Hash(), true)
+	pool1.mu.Unlock()
+	if pool1.ContentHash() != hash {
+		t.Fatal("content hash not restored after removing the added tx")
+	}
+}
+
 func TestTransactionQueue(t *testing.T) {
 	t.Parallel()
 
@@ -718,6 +1234,263 @@ func TestTransactionGapFilling(t *testing.T) {
 	}
 }
 
+// Tests that the nonces missing below the highest queued nonce of an account are
+// reported as nonce gaps.
+func TestTransactionNonceGaps(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account, _ := deriveSender(transaction(0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	if gaps, err := pool.NonceGaps(account); err != nil || len(gaps) != 0 {
+		t.Fatalf("nonce gaps of an empty account: have %v (%v), want none", gaps, err)
+	}
+	for _, nonce := range []uint64{0, 2, 5, 6} {
+		if err := pool.AddRemote(transaction(nonce, 100000, key)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+	want := []uint64{1, 3, 4}
+	gaps, err := pool.NonceGaps(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != len(want) {
+		t.Fatalf("nonce gaps mismatch: have %v, want %v", gaps, want)
+	}
+	for i := range want {
+		if gaps[i] != want[i] {
+			t.Errorf("nonce gap %d mismatch: have %d, want %d", i, gaps[i], want[i])
+		}
+	}
+	// Filling the first gap promotes the transaction above it
+	if err := pool.AddRemote(transaction(1, 100000, key)); err != nil {
+		t.Fatalf("failed to add gapped transaction: %v", err)
+	}
+	if gaps, err := pool.NonceGaps(account); err != nil || len(gaps) != 2 || gaps[0] != 3 || gaps[1] != 4 {
+		t.Fatalf("nonce gaps mismatch: have %v (%v), want [3 4]", gaps, err)
+	}
+	// A queued transaction far above the pending nonce is not expanded into gaps
+	far := pool.config.NonExecSlotsAccount + 10
+	if err := pool.AddRemote(transaction(far, 100000, key)); err != nil {
+		t.Fatalf("failed to add far transaction: %v", err)
+	}
+	if gaps, err := pool.NonceGaps(account); err != ErrTooManyNonceGaps {
+		t.Fatalf("nonce gaps of a far nonce: have %v (%v), want %v", gaps, err, ErrTooManyNonceGaps)
+	}
+}
+
+// cancelTransaction returns a signed cancel transaction of the given nonce and gas limit.
+func cancelTransaction(t *testing.T, nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, err := types.NewTransactionWithMap(types.TxTypeCancel, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    nonce,
+		types.TxValueKeyFrom:     crypto.PubkeyToAddress(key.PublicKey),
+		types.TxValueKeyGasLimit: gaslimit,
+		types.TxValueKeyGasPrice: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SignWithKeys(types.NewEIP155Signer(params.TestChainConfig.ChainID), []*ecdsa.PrivateKey{key}); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// Tests that the same nonce of an account is replaced at most once per replacement
+// cooldown, both for pending and queued transactions.
+func TestTransactionReplaceCooldown(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.ReplaceCooldown = 500 * time.Millisecond
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	// Nonce 0 is pending and nonce 2 is queued.
+	for _, nonce := range []uint64{0, 2} {
+		if err := pool.AddRemote(transaction(nonce, 100000, key)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+	for _, nonce := range []uint64{0, 2} {
+		// The first replacement is accepted.
+		if err := pool.AddRemote(cancelTransaction(t, nonce, 100000, key)); err != nil {
+			t.Fatalf("nonce %d: failed to replace: %v", nonce, err)
+		}
+		// Replacing again within the cooldown is rejected.
+		if err := pool.AddRemote(cancelTransaction(t, nonce, 100001, key)); err != ErrReplaceCooldown {
+			t.Fatalf("nonce %d: replacement error mismatch: have %v, want %v", nonce, err, ErrReplaceCooldown)
+		}
+	}
+	// Other nonces are not limited by the cooldown.
+	if err := pool.AddRemote(transaction(1, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction 1: %v", err)
+	}
+	if err := pool.AddRemote(cancelTransaction(t, 1, 100000, key)); err != nil {
+		t.Fatalf("failed to replace nonce 1: %v", err)
+	}
+
+	// Replacing after the cooldown is accepted.
+	time.Sleep(config.ReplaceCooldown)
+	for _, nonce := range []uint64{0, 2} {
+		if err := pool.AddRemote(cancelTransaction(t, nonce, 100002, key)); err != nil {
+			t.Fatalf("nonce %d: failed to replace after the cooldown: %v", nonce, err)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the available balance of an account is reduced by the pending
+// transactions it pays for, as the sender or as the fee payer.
+func TestTransactionAvailableBalance(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	feePayerKey, _ := crypto.GenerateKey()
+	var (
+		account  = crypto.PubkeyToAddress(key.PublicKey)
+		feePayer = crypto.PubkeyToAddress(feePayerKey.PublicKey)
+		signer   = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	)
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+	pool.currentState.AddBalance(feePayer, big.NewInt(1000000))
+
+	if balance := pool.AvailableBalance(account); balance.Int64() != 1000000 {
+		t.Fatalf("available balance without pending txs: have %v, want 1000000", balance)
+	}
+
+	// A pending tx of the account costs value 100 + gas 100000 * price 1.
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// A queued tx does not reserve the balance.
+	if err := pool.AddRemote(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add queued transaction: %v", err)
+	}
+	if balance := pool.AvailableBalance(account); balance.Int64() != 1000000-100100 {
+		t.Fatalf("available balance with a pending tx: have %v, want %d", balance, 1000000-100100)
+	}
+
+	// The fee of a fee-delegated tx is paid by the fee payer.
+	feeDelegatedTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:    nonce,
+			types.TxValueKeyFrom:     account,
+			types.TxValueKeyTo:       common.HexToAddress("0xAAAA"),
+			types.TxValueKeyAmount:   big.NewInt(1000),
+			types.TxValueKeyGasLimit: uint64(100000),
+			types.TxValueKeyGasPrice: big.NewInt(1),
+			types.TxValueKeyFeePayer: feePayer,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignFeePayerWithKeys(signer, []*ecdsa.PrivateKey{feePayerKey}); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	// A queued fee-delegated tx does not reserve the balance of the fee payer.
+	if err := pool.AddRemote(feeDelegatedTx(4)); err != nil {
+		t.Fatalf("failed to add queued fee-delegated transaction: %v", err)
+	}
+	if balance := pool.AvailableBalance(feePayer); balance.Int64() != 1000000 {
+		t.Fatalf("available balance of the fee payer with a queued tx: have %v, want 1000000", balance)
+	}
+	if err := pool.AddRemote(feeDelegatedTx(1)); err != nil {
+		t.Fatalf("failed to add fee-delegated transaction: %v", err)
+	}
+	// Nonce 2 is promoted as well.
+	if balance := pool.AvailableBalance(account); balance.Int64() != 1000000-100100-1000-100100 {
+		t.Errorf("available balance of the sender: have %v, want %d", balance, 1000000-100100-1000-100100)
+	}
+	if balance := pool.AvailableBalance(feePayer); balance.Int64() != 1000000-100000 {
+		t.Errorf("available balance of the fee payer: have %v, want %d", balance, 1000000-100000)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the content of an account only contains its own pending and queued
+// transactions, sorted by nonce regardless of the order they were added in.
+func TestTransactionContentFrom(t *testing.T) {
+	t.Parallel()
+
+	pool, key1 := setupTxPool()
+	defer pool.Stop()
+
+	key2, _ := crypto.GenerateKey()
+	var (
+		account1 = crypto.PubkeyToAddress(key1.PublicKey)
+		account2 = crypto.PubkeyToAddress(key2.PublicKey)
+	)
+	pool.currentState.AddBalance(account1, big.NewInt(1000000000))
+	pool.currentState.AddBalance(account2, big.NewInt(1000000000))
+
+	for _, nonce := range []uint64{5, 1, 3, 0} {
+		if err := pool.AddRemote(transaction(nonce, 100000, key1)); err != nil {
+			t.Fatalf("failed to add transaction %d of account1: %v", nonce, err)
+		}
+	}
+	for _, nonce := range []uint64{4, 0, 2} {
+		if err := pool.AddRemote(transaction(nonce, 100000, key2)); err != nil {
+			t.Fatalf("failed to add transaction %d of account2: %v", nonce, err)
+		}
+	}
+
+	nonces := func(txs types.Transactions) []uint64 {
+		list := []uint64{}
+		for _, tx := range txs {
+			list = append(list, tx.Nonce())
+		}
+		return list
+	}
+	tests := []struct {
+		addr    common.Address
+		pending []uint64
+		queued  []uint64
+	}{
+		{account1, []uint64{0, 1}, []uint64{3, 5}},
+		{account2, []uint64{0}, []uint64{2, 4}},
+		{common.HexToAddress("0xAAAA"), []uint64{}, []uint64{}},
+	}
+	for _, tt := range tests {
+		pending, queued := pool.ContentFrom(tt.addr)
+		if have := nonces(pending); !reflect.DeepEqual(have, tt.pending) {
+			t.Errorf("pending nonces of %x: have %v, want %v", tt.addr, have, tt.pending)
+		}
+		if have := nonces(queued); !reflect.DeepEqual(have, tt.queued) {
+			t.Errorf("queued nonces of %x: have %v, want %v", tt.addr, have, tt.queued)
+		}
+		for _, txs := range []types.Transactions{pending, queued} {
+			for _, tx := range txs {
+				if from, _ := types.Sender(pool.signer, tx); from != tt.addr {
+					t.Errorf("transaction %x of %x is sent by %x", tx.Hash(), tt.addr, from)
+				}
+			}
+		}
+	}
+}
+
 // Tests that if the transaction count belonging to a single account goes above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 func TestTransactionQueueAccountLimiting(t *testing.T) {
@@ -972,8 +1745,10 @@ func TestTransactionPendingLimiting(t *testing.T) {
 
 // Tests that the transaction limits are enforced the same way irrelevant whether
 // the transactions are added one by one or in batches.
-func TestTransactionQueueLimitingEquivalency(t *testing.T)   { testTransactionLimitingEquivalency(t, 1) }
-func TestTransactionPendingLimitingEquivalency(t *testing.T) { testTransactionLimitingEquivalency(t, 0) }
+func TestTransactionQueueLimitingEquivalency(t *testing.T) { testTransactionLimitingEquivalency(t, 1) }
+func TestTransactionPendingLimitingEquivalency(t *testing.T) {
+	testTransactionLimitingEquivalency(t, 0)
+}
 
 func testTransactionLimitingEquivalency(t *testing.T, origin uint64) {
 	t.Parallel()
@@ -1774,6 +2549,178 @@ func TestTransactionStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that the price eviction policy drops the transactions with the lowest
+// effective gas price first when the pool is full.
+func TestTransactionPriceEviction(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.ExecSlotsAll = 2
+	config.NonExecSlotsAll = 2
+	config.AllowZeroGasTypes = []types.TxType{types.TxTypeLegacyTransaction}
+	config.EvictionPolicy = TxEvictionPrice
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	events := make(chan DroppedTxsEvent, 4)
+	sub := pool.SubscribeDroppedTxsEvent(events)
+	defer sub.Unsubscribe()
+
+	keys := make([]*ecdsa.PrivateKey, 7)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	// Fill the pool with two zero priced and two unit priced transactions.
+	cheap := types.Transactions{
+		pricedTransaction(0, 100000, big.NewInt(0), keys[0]),
+		pricedTransaction(0, 100000, big.NewInt(0), keys[1]),
+	}
+	expensive := types.Transactions{
+		transaction(0, 100000, keys[2]),
+		transaction(0, 100000, keys[3]),
+	}
+	for _, tx := range append(cheap, expensive...) {
+		if err := pool.AddRemote(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+
+	// New unit priced transactions evict the zero priced ones.
+	errs := pool.AddRemotes(types.Transactions{transaction(0, 100000, keys[4]), transaction(0, 100000, keys[5])})
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d to the full pool: %v", i, err)
+		}
+	}
+	for i := 0; i < len(cheap); i++ {
+		select {
+		case ev := <-events:
+			if len(ev.Txs) != 1 || ev.Txs[0].GasPrice().Sign() != 0 {
+				t.Fatalf("evicted transactions mismatch: have %v, want a zero priced one", ev.Txs)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("dropped transactions event not fired")
+		}
+	}
+	for _, tx := range cheap {
+		if pool.Get(tx.Hash()) != nil {
+			t.Errorf("zero priced transaction %x should be evicted", tx.Hash())
+		}
+	}
+	for _, tx := range expensive {
+		if pool.Get(tx.Hash()) == nil {
+			t.Errorf("unit priced transaction %x should survive", tx.Hash())
+		}
+	}
+
+	// A transaction paying no more than the cheapest one is rejected.
+	if err := pool.AddRemote(transaction(0, 100000, keys[6])); err != ErrUnderpriced {
+		t.Fatalf("adding to the full pool error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the transactions of a dumped pool are added back on load, except
+// the ones which became invalid.
+func TestTransactionPoolDumpLoad(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "txpool-dump")
+	if err != nil {
+		t.Fatalf("failed to create temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "txpool.rlp")
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		statedb.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+
+	txs := types.Transactions{
+		transaction(0, 100000, keys[0]), // Pending
+		transaction(1, 100000, keys[0]),
+		transaction(2, 100000, keys[1]), // Queued
+		transaction(0, 100000, keys[2]), // Becomes invalid
+	}
+	if err := pool.AddLocal(txs[0]); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	for i, err := range pool.AddRemotes(txs[1:]) {
+		if err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", i, err)
+		}
+	}
+	if count, err := pool.Dump(file); err != nil || count != len(txs) {
+		t.Fatalf("dump mismatch: have %d (err %v), want %d", count, err, len(txs))
+	}
+	pool.Stop()
+
+	// The transaction of keys[2] is mined meanwhile.
+	statedb.SetNonce(crypto.PubkeyToAddress(keys[2].PublicKey), 1)
+
+	pool = NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Fatalf("new pool should be empty, have %d pending and %d queued", pending, queued)
+	}
+	added, dropped, err := pool.Load(file)
+	if err != nil {
+		t.Fatalf("failed to load the dump: %v", err)
+	}
+	if added != 3 || dropped != 1 {
+		t.Fatalf("load mismatch: have %d added and %d dropped, want 3 and 1", added, dropped)
+	}
+	for _, tx := range txs[:3] {
+		if pool.Get(tx.Hash()) == nil {
+			t.Errorf("transaction %x should be loaded", tx.Hash())
+		}
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 1 {
+		t.Errorf("pool stats mismatch: have %d pending and %d queued, want 2 and 1", pending, queued)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+
+	if _, _, err := pool.Load(filepath.Join(dir, "missing.rlp")); err == nil {
+		t.Error("loading a missing dump should fail")
+	}
+}
+
+// Tests that transactions removed from the pool are reported as dropped.
+func TestTransactionStatusDropped(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	tx := transaction(0, 100000, key)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	pool.mu.Lock()
+	pool.removeTx(tx.Hash(), true)
+	pool.mu.Unlock()
+
+	if status := pool.Status([]common.Hash{tx.Hash()})[0]; status != TxStatusDropped {
+		t.Fatalf("status mismatch: have %v, want %v", status, TxStatusDropped)
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
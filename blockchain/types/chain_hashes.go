@@ -17,10 +17,14 @@
 package types
 
 import (
+	"errors"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/ser/rlp"
 	"math/big"
 )
 
+var ErrMalformedAnchoredData = errors.New("malformed anchored data")
+
 type ChainHashes struct {
 	BlockHash     common.Hash
 	TxHash        common.Hash
@@ -35,3 +39,18 @@ func NewChainHashes(block *Block) *ChainHashes {
 		block.Header().ParentHash, block.Header().ReceiptHash,
 		block.Header().Root, block.Header().Number}
 }
+
+// DecodeAnchoringData decodes the anchored data of the chain data anchoring
+// transaction into the ChainHashes of the anchored child chain block.
+// If the tx is not a chain data anchoring transaction, it will return error.
+func DecodeAnchoringData(tx *Transaction) (*ChainHashes, error) {
+	data, err := tx.AnchoredData()
+	if err != nil {
+		return nil, err
+	}
+	chainHashes := new(ChainHashes)
+	if err := rlp.DecodeBytes(data, chainHashes); err != nil {
+		return nil, ErrMalformedAnchoredData
+	}
+	return chainHashes, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// newAnchoringTx returns a chain data anchoring transaction anchoring the given data.
+func newAnchoringTx(t *testing.T, anchoredData []byte) *Transaction {
+	tx, err := NewTransactionWithMap(TxTypeChainDataAnchoring, map[TxValueKeyType]interface{}{
+		TxValueKeyNonce:        uint64(1),
+		TxValueKeyFrom:         common.HexToAddress("0xa94f5374Fce5edBC8E2a8697C15331677e6EbF0B"),
+		TxValueKeyGasLimit:     uint64(100000),
+		TxValueKeyGasPrice:     big.NewInt(25),
+		TxValueKeyAnchoredData: anchoredData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestDecodeAnchoringData(t *testing.T) {
+	header := &Header{
+		ParentHash:  common.HexToHash("0x01"),
+		Root:        common.HexToHash("0x02"),
+		TxHash:      common.HexToHash("0x03"),
+		ReceiptHash: common.HexToHash("0x04"),
+		Number:      big.NewInt(1234),
+	}
+	want := NewChainHashes(NewBlockWithHeader(header))
+
+	anchoredData, err := rlp.EncodeToBytes(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainHashes, err := DecodeAnchoringData(newAnchoringTx(t, anchoredData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, chainHashes)
+	assert.Equal(t, header.Hash(), chainHashes.BlockHash)
+	assert.Equal(t, header.Number, chainHashes.BlockNumber)
+}
+
+func TestDecodeAnchoringDataMalformed(t *testing.T) {
+	anchoredData, err := rlp.EncodeToBytes(&ChainHashes{BlockNumber: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{
+		{},
+		{0x01, 0x02, 0x03},
+		anchoredData[:len(anchoredData)-1],
+		append(anchoredData, 0x80),
+	} {
+		_, err := DecodeAnchoringData(newAnchoringTx(t, data))
+		assert.Equal(t, ErrMalformedAnchoredData, err, "anchored data %x", data)
+	}
+}
+
+func TestDecodeAnchoringDataNotAnchoringTx(t *testing.T) {
+	tx := NewTransaction(1, common.HexToAddress("0x01"), big.NewInt(1), 21000, big.NewInt(25), nil)
+
+	_, err := DecodeAnchoringData(tx)
+	assert.Equal(t, ErrInvalidTxTypeForAnchoredData, err)
+}
@@ -73,6 +73,9 @@ type Transaction struct {
 	checkNonce bool
 	// This value is set when the tx is invalidated in block tx validation, and is used to remove pending tx in txPool.
 	markedUnexecutable int32
+	// conditions are the preconditions checked by the miner before executing the tx.
+	// They are set before the tx is submitted to the txPool and are not encoded.
+	conditions *TxConditions
 }
 
 func NewTransactionWithMap(t TxType, values map[TxValueKeyType]interface{}) (*Transaction, error) {
@@ -218,6 +221,13 @@ func (tx *Transaction) ValidatedIntrinsicGas() uint64         { return tx.valida
 func (tx *Transaction) MakeRPCOutput() map[string]interface{} { return tx.data.MakeRPCOutput() }
 func (tx *Transaction) GetTxInternalData() TxInternalData     { return tx.data }
 
+// UnitPrice returns the price per gas paid by the transaction.
+// Since Klaytn uses a fixed unit price, it is the gas price of the transaction, which has to be
+// equal to the unit price of the chain (see params.ChainConfig.GetUnitPrice) to be accepted.
+func (tx *Transaction) UnitPrice() *big.Int {
+	return tx.GasPrice()
+}
+
 func (tx *Transaction) IntrinsicGas(currentBlockNumber uint64) (uint64, error) {
 	return tx.data.IntrinsicGas(currentBlockNumber)
 }
@@ -323,6 +333,48 @@ func (tx *Transaction) FeePayer() (common.Address, error) {
 	return tf.GetFeePayer(), nil
 }
 
+// SenderAndFeePayer returns the sender and the fee payer of the transaction,
+// recovering the public keys of both signatures once. The recovered keys are cached
+// the same as SenderFrom, SenderPubkey and SenderFeePayerPubkey do.
+// It returns ErrInvalidSigSender or ErrInvalidSigFeePayer if a signature can't be
+// recovered. If the transaction is not a fee-delegated transaction, the fee payer
+// is the zero address.
+// NOTE: the recovered keys are not validated against the account keys in the state.
+// In that situation, you should call ValidateSender() and ValidateFeePayer().
+func (tx *Transaction) SenderAndFeePayer(signer Signer) (sender, feePayer common.Address, err error) {
+	if tx.IsLegacyTransaction() {
+		if sender, err = SenderFrom(signer, tx); err != nil {
+			return common.Address{}, common.Address{}, sigRecoveryError(err, ErrInvalidSigSender)
+		}
+		return sender, common.Address{}, nil
+	}
+
+	if _, err := SenderPubkey(signer, tx); err != nil {
+		return common.Address{}, common.Address{}, sigRecoveryError(err, ErrInvalidSigSender)
+	}
+	if sender, err = tx.From(); err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+
+	tf, ok := tx.data.(TxInternalDataFeePayer)
+	if !ok {
+		return sender, common.Address{}, nil
+	}
+	if _, err := SenderFeePayerPubkey(signer, tx); err != nil {
+		return common.Address{}, common.Address{}, sigRecoveryError(err, ErrInvalidSigFeePayer)
+	}
+	return sender, tf.GetFeePayer(), nil
+}
+
+// sigRecoveryError returns sigErr for an error recovering a signature, keeping
+// ErrInvalidChainId which is not about the signature values.
+func sigRecoveryError(err, sigErr error) error {
+	if err == ErrInvalidChainId {
+		return err
+	}
+	return sigErr
+}
+
 // FeeRatio returns the fee ratio of a transaction and a boolean value indicating TxInternalDataFeeRatio implementation.
 // If the transaction does not implement TxInternalDataFeeRatio,
 // it returns MaxFeeRatio which means the fee payer will be paid all tx fee by default.
@@ -488,6 +540,19 @@ func (tx *Transaction) SignFeePayerWithKeys(s Signer, prv []*ecdsa.PrivateKey) e
 	return nil
 }
 
+// SetFeePayer sets the fee payer of a fee-delegated tx. It must be called before the tx
+// is signed by the fee payer and before its hash is computed.
+func (tx *Transaction) SetFeePayer(feePayer common.Address) error {
+	tf, ok := tx.data.(TxInternalDataFeePayer)
+	if !ok {
+		return errNotFeePayer
+	}
+
+	tf.SetFeePayer(feePayer)
+
+	return nil
+}
+
 func (tx *Transaction) SetFeePayerSignatures(s TxSignatures) error {
 	tf, ok := tx.data.(TxInternalDataFeePayer)
 	if !ok {
@@ -515,6 +580,16 @@ func (tx *Transaction) IsMarkedUnexecutable() bool {
 	return atomic.LoadInt32(&tx.markedUnexecutable) == 1
 }
 
+// SetConditions sets the conditions of the tx. It should be called before the tx is submitted to the txPool.
+func (tx *Transaction) SetConditions(conditions *TxConditions) {
+	tx.conditions = conditions
+}
+
+// Conditions returns the conditions of the tx, or nil if the tx is unconditional.
+func (tx *Transaction) Conditions() *TxConditions {
+	return tx.conditions
+}
+
 func (tx *Transaction) RawSignatureValues() TxSignatures {
 	return tx.data.RawSignatureValues()
 }
@@ -643,12 +718,21 @@ func (s TxByNonce) Less(i, j int) bool {
 }
 func (s TxByNonce) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
+// CompareEffectiveGasPrice compares the price per gas a block proposer receives for
+// the transactions a and b, returning -1, 0 or +1. The fee ratio of a partially
+// fee-delegated transaction only splits the fee between the sender and the fee
+// payer, so the effective gas price is the gas price for every tx type. Both the
+// miner's ordering and the tx pool's eviction compare transactions with it.
+func CompareEffectiveGasPrice(a, b *Transaction) int {
+	return a.data.GetPrice().Cmp(b.data.GetPrice())
+}
+
 // TxByPrice implements both the sort and the heap interface, making it useful
 // for all at once sorting as well as individually adding and removing elements.
 type TxByPrice Transactions
 
 func (s TxByPrice) Len() int           { return len(s) }
-func (s TxByPrice) Less(i, j int) bool { return s[i].data.GetPrice().Cmp(s[j].data.GetPrice()) > 0 }
+func (s TxByPrice) Less(i, j int) bool { return CompareEffectiveGasPrice(s[i], s[j]) > 0 }
 func (s TxByPrice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
 func (s *TxByPrice) Push(x interface{}) {
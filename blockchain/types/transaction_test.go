@@ -258,3 +258,62 @@ func TestTransactionJSON(t *testing.T) {
 	}
 }
 */
+
+func TestParseTxType(t *testing.T) {
+	for _, name := range []string{"TxTypeChainDataAnchoring", "ChainDataAnchoring", "chaindataanchoring"} {
+		txType, err := ParseTxType(name)
+		if err != nil || txType != TxTypeChainDataAnchoring {
+			t.Errorf("ParseTxType(%q) = %v, %v; want %v", name, txType, err, TxTypeChainDataAnchoring)
+		}
+	}
+	if _, err := ParseTxType("NoSuchType"); err == nil {
+		t.Error("expected error for an unknown tx type")
+	}
+}
+
+// TestTxTypeValueKeys checks that the value keys of each tx type are exactly
+// the keys required by NewTxInternalDataWithMap.
+func TestTxTypeValueKeys(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	sampleValues := map[TxValueKeyType]interface{}{
+		TxValueKeyNonce:              uint64(1),
+		TxValueKeyTo:                 to,
+		TxValueKeyAmount:             big.NewInt(1),
+		TxValueKeyGasLimit:           uint64(100000),
+		TxValueKeyGasPrice:           big.NewInt(25),
+		TxValueKeyData:               []byte{0x1},
+		TxValueKeyFrom:               common.HexToAddress("0x2"),
+		TxValueKeyAnchoredData:       []byte{0x1},
+		TxValueKeyHumanReadable:      false,
+		TxValueKeyAccountKey:         accountkey.NewAccountKeyLegacy(),
+		TxValueKeyFeePayer:           common.HexToAddress("0x3"),
+		TxValueKeyFeeRatioOfFeePayer: FeeRatio(30),
+		TxValueKeyCodeFormat:         params.CodeFormatEVM,
+	}
+	newValues := func(txType TxType, keys []TxValueKeyType) map[TxValueKeyType]interface{} {
+		values := make(map[TxValueKeyType]interface{}, len(keys))
+		for _, key := range keys {
+			values[key] = sampleValues[key]
+		}
+		if _, ok := values[TxValueKeyTo]; ok && txType.IsContractDeploy() {
+			values[TxValueKeyTo] = (*common.Address)(nil)
+		}
+		return values
+	}
+
+	txTypes := SupportedTxTypes()
+	assert.Equal(t, len(txTypeValueKeys), len(txTypes))
+	for _, txType := range txTypes {
+		keys := txType.ValueKeys()
+		if _, err := NewTxInternalDataWithMap(txType, newValues(txType, keys)); err != nil {
+			t.Errorf("%v: failed to create with its value keys: %v", txType, err)
+		}
+		for i := range keys {
+			partial := append(append([]TxValueKeyType{}, keys[:i]...), keys[i+1:]...)
+			if _, err := NewTxInternalDataWithMap(txType, newValues(txType, partial)); err == nil {
+				t.Errorf("%v: created without %v", txType, keys[i])
+			}
+		}
+	}
+	assert.Nil(t, TxTypeBatch.ValueKeys())
+}
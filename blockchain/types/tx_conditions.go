@@ -0,0 +1,97 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// MaxTxConditions is the maximum number of account and storage slot conditions of a transaction.
+// It bounds the work of the miner checking the conditions when assembling a block.
+const MaxTxConditions = 1000
+
+var (
+	ErrTooManyTxConditions   = fmt.Errorf("too many transaction conditions (max %d)", MaxTxConditions)
+	ErrTxConditionsNotMet    = errors.New("transaction conditions not met")
+	ErrTxConditionsNotMetYet = errors.New("transaction conditions not met yet")
+)
+
+// ConditionState is the state a transaction is checked against its conditions.
+type ConditionState interface {
+	GetNonce(addr common.Address) uint64
+	GetState(addr common.Address, key common.Hash) common.Hash
+}
+
+// AccountConditions are the expected nonce and storage values of an account.
+type AccountConditions struct {
+	Nonce   *hexutil.Uint64             `json:"nonce,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// TxConditions are the preconditions which must hold for a transaction to be included in a block.
+// A transaction whose conditions are not met is skipped by the miner instead of being executed.
+type TxConditions struct {
+	KnownAccounts  map[common.Address]AccountConditions `json:"knownAccounts,omitempty"`
+	BlockNumberMin *hexutil.Big                         `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *hexutil.Big                         `json:"blockNumberMax,omitempty"`
+	TimestampMin   *hexutil.Uint64                      `json:"timestampMin,omitempty"`
+	TimestampMax   *hexutil.Uint64                      `json:"timestampMax,omitempty"`
+}
+
+// Validate checks that the number of the conditions does not exceed MaxTxConditions.
+func (c *TxConditions) Validate() error {
+	count := 0
+	for _, account := range c.KnownAccounts {
+		count += 1 + len(account.Storage)
+	}
+	if count > MaxTxConditions {
+		return ErrTooManyTxConditions
+	}
+	return nil
+}
+
+// Check returns ErrTxConditionsNotMet if the conditions do not hold for the block of
+// the given header on top of the given state. It returns ErrTxConditionsNotMetYet if
+// the block number or the timestamp of the block has not reached the lower bound yet.
+func (c *TxConditions) Check(header *Header, state ConditionState) error {
+	if c.BlockNumberMin != nil && header.Number.Cmp(c.BlockNumberMin.ToInt()) < 0 {
+		return ErrTxConditionsNotMetYet
+	}
+	if c.BlockNumberMax != nil && header.Number.Cmp(c.BlockNumberMax.ToInt()) > 0 {
+		return ErrTxConditionsNotMet
+	}
+	if c.TimestampMin != nil && header.Time.Uint64() < uint64(*c.TimestampMin) {
+		return ErrTxConditionsNotMetYet
+	}
+	if c.TimestampMax != nil && header.Time.Uint64() > uint64(*c.TimestampMax) {
+		return ErrTxConditionsNotMet
+	}
+	for addr, account := range c.KnownAccounts {
+		if account.Nonce != nil && state.GetNonce(addr) != uint64(*account.Nonce) {
+			return ErrTxConditionsNotMet
+		}
+		for key, value := range account.Storage {
+			if state.GetState(addr, key) != value {
+				return ErrTxConditionsNotMet
+			}
+		}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/klaytn/klaytn/params"
 	"math"
 	"math/big"
+	"strings"
 )
 
 // MaxFeeRatio is the maximum value of feeRatio. Since it is represented in percentage,
@@ -181,6 +182,74 @@ func (t TxType) String() string {
 	return "UndefinedTxType"
 }
 
+// txTypeValueKeys lists the value keys required to create each tx type by NewTransactionWithMap.
+var txTypeValueKeys = map[TxType][]TxValueKeyType{
+	TxTypeLegacyTransaction: {TxValueKeyNonce, TxValueKeyTo, TxValueKeyAmount, TxValueKeyData, TxValueKeyGasLimit, TxValueKeyGasPrice},
+
+	TxTypeValueTransfer:                      {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom},
+	TxTypeFeeDelegatedValueTransfer:          {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyFeePayer},
+	TxTypeFeeDelegatedValueTransferWithRatio: {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyFeePayer, TxValueKeyFeeRatioOfFeePayer},
+
+	TxTypeValueTransferMemo:                      {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyData},
+	TxTypeFeeDelegatedValueTransferMemo:          {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyData, TxValueKeyFeePayer},
+	TxTypeFeeDelegatedValueTransferMemoWithRatio: {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyData, TxValueKeyFeePayer, TxValueKeyFeeRatioOfFeePayer},
+
+	TxTypeAccountUpdate:                      {TxValueKeyNonce, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyAccountKey},
+	TxTypeFeeDelegatedAccountUpdate:          {TxValueKeyNonce, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyAccountKey, TxValueKeyFeePayer},
+	TxTypeFeeDelegatedAccountUpdateWithRatio: {TxValueKeyNonce, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyAccountKey, TxValueKeyFeePayer, TxValueKeyFeeRatioOfFeePayer},
+
+	TxTypeSmartContractDeploy:                      {TxValueKeyNonce, TxValueKeyTo, TxValueKeyAmount, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyData, TxValueKeyHumanReadable, TxValueKeyCodeFormat},
+	TxTypeFeeDelegatedSmartContractDeploy:          {TxValueKeyNonce, TxValueKeyTo, TxValueKeyAmount, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyData, TxValueKeyHumanReadable, TxValueKeyFeePayer, TxValueKeyCodeFormat},
+	TxTypeFeeDelegatedSmartContractDeployWithRatio: {TxValueKeyNonce, TxValueKeyTo, TxValueKeyAmount, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyData, TxValueKeyHumanReadable, TxValueKeyFeePayer, TxValueKeyFeeRatioOfFeePayer, TxValueKeyCodeFormat},
+
+	TxTypeSmartContractExecution:                      {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyData},
+	TxTypeFeeDelegatedSmartContractExecution:          {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyData, TxValueKeyFeePayer},
+	TxTypeFeeDelegatedSmartContractExecutionWithRatio: {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyTo, TxValueKeyAmount, TxValueKeyFrom, TxValueKeyData, TxValueKeyFeePayer, TxValueKeyFeeRatioOfFeePayer},
+
+	TxTypeCancel:                      {TxValueKeyNonce, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom},
+	TxTypeFeeDelegatedCancel:          {TxValueKeyNonce, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyFeePayer},
+	TxTypeFeeDelegatedCancelWithRatio: {TxValueKeyNonce, TxValueKeyGasLimit, TxValueKeyGasPrice, TxValueKeyFrom, TxValueKeyFeePayer, TxValueKeyFeeRatioOfFeePayer},
+
+	TxTypeChainDataAnchoring: {TxValueKeyNonce, TxValueKeyGasPrice, TxValueKeyGasLimit, TxValueKeyFrom, TxValueKeyAnchoredData},
+}
+
+// SupportedTxTypes returns all tx types which can be created in ascending order of their values.
+func SupportedTxTypes() []TxType {
+	txTypes := make([]TxType, 0, len(txTypeValueKeys))
+	for t := TxTypeLegacyTransaction; t < TxTypeLast; t++ {
+		if _, ok := txTypeValueKeys[t]; ok {
+			txTypes = append(txTypes, t)
+		}
+	}
+	return txTypes
+}
+
+// ValueKeys returns the value keys required to create a transaction of the tx type
+// with NewTransactionWithMap. It returns nil for an undefined tx type.
+func (t TxType) ValueKeys() []TxValueKeyType {
+	keys, ok := txTypeValueKeys[t]
+	if !ok {
+		return nil
+	}
+	return append([]TxValueKeyType(nil), keys...)
+}
+
+// ParseTxType returns the TxType of the given name. The name is case-insensitive and
+// the "TxType" prefix can be omitted, e.g., "TxTypeChainDataAnchoring" and "chaindataanchoring"
+// are the same.
+func ParseTxType(name string) (TxType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "txtype") {
+		name = "txtype" + name
+	}
+	for t := TxTypeLegacyTransaction; t < TxTypeLast; t++ {
+		if str := t.String(); str != "UndefinedTxType" && strings.ToLower(str) == name {
+			return t, nil
+		}
+	}
+	return 0, errUndefinedTxType
+}
+
 func (t TxType) IsAccountCreation() bool {
 	return t == TxTypeAccountCreation
 }
@@ -304,6 +373,10 @@ type TxInternalDataSerializeForSignToByte interface {
 type TxInternalDataFeePayer interface {
 	GetFeePayer() common.Address
 
+	// SetFeePayer sets the fee payer. It is not signed by the sender, so a fee payer can be
+	// chosen after the sender has signed the tx.
+	SetFeePayer(feePayer common.Address)
+
 	// GetFeePayerRawSignatureValues returns fee payer's signatures as a slice of `*big.Int`.
 	// Due to multi signatures, it is not good to return three values of `*big.Int`.
 	// The format would be something like [["V":v, "R":r, "S":s}, {"V":v, "R":r, "S":s}].
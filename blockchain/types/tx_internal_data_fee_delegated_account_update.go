@@ -278,6 +278,10 @@ func (t *TxInternalDataFeeDelegatedAccountUpdate) GetFeePayer() common.Address {
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedAccountUpdate) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedAccountUpdate) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
@@ -296,6 +296,10 @@ func (t *TxInternalDataFeeDelegatedAccountUpdateWithRatio) GetFeePayer() common.
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedAccountUpdateWithRatio) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedAccountUpdateWithRatio) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
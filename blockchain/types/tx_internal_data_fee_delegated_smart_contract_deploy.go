@@ -231,6 +231,10 @@ func (t *TxInternalDataFeeDelegatedSmartContractDeploy) GetFeePayer() common.Add
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedSmartContractDeploy) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedSmartContractDeploy) GetCodeFormat() params.CodeFormat {
 	return t.CodeFormat
 }
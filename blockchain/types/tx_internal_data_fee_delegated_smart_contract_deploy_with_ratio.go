@@ -245,6 +245,10 @@ func (t *TxInternalDataFeeDelegatedSmartContractDeployWithRatio) GetFeePayer() c
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedSmartContractDeployWithRatio) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedSmartContractDeployWithRatio) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
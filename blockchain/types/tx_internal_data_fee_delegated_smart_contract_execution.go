@@ -215,6 +215,10 @@ func (t *TxInternalDataFeeDelegatedSmartContractExecution) GetFeePayer() common.
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedSmartContractExecution) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedSmartContractExecution) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
@@ -229,6 +229,10 @@ func (t *TxInternalDataFeeDelegatedSmartContractExecutionWithRatio) GetFeePayer(
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedSmartContractExecutionWithRatio) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedSmartContractExecutionWithRatio) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
@@ -202,6 +202,10 @@ func (t *TxInternalDataFeeDelegatedValueTransfer) GetFeePayer() common.Address {
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedValueTransfer) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedValueTransfer) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
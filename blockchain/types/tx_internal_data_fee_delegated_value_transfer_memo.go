@@ -250,6 +250,10 @@ func (t *TxInternalDataFeeDelegatedValueTransferMemo) GetFeePayer() common.Addre
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedValueTransferMemo) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedValueTransferMemo) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
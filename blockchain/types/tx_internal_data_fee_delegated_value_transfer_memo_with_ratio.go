@@ -266,6 +266,10 @@ func (t *TxInternalDataFeeDelegatedValueTransferMemoWithRatio) GetFeePayer() com
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedValueTransferMemoWithRatio) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedValueTransferMemoWithRatio) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
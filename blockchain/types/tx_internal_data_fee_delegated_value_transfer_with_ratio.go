@@ -218,6 +218,10 @@ func (t *TxInternalDataFeeDelegatedValueTransferWithRatio) GetFeePayer() common.
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedValueTransferWithRatio) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedValueTransferWithRatio) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
@@ -156,6 +156,10 @@ func (t *TxInternalDataFeeDelegatedCancel) GetFeePayer() common.Address {
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedCancel) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedCancel) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
@@ -169,6 +169,10 @@ func (t *TxInternalDataFeeDelegatedCancelWithRatio) GetFeePayer() common.Address
 	return t.FeePayer
 }
 
+func (t *TxInternalDataFeeDelegatedCancelWithRatio) SetFeePayer(feePayer common.Address) {
+	t.FeePayer = feePayer
+}
+
 func (t *TxInternalDataFeeDelegatedCancelWithRatio) GetFeePayerRawSignatureValues() TxSignatures {
 	return t.FeePayerSignatures.RawSignatureValues()
 }
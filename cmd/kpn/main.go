@@ -65,6 +65,7 @@ var pnHelpFlagGroups = []utils.FlagGroup{
 			utils.LightKDFFlag,
 			utils.SrvTypeFlag,
 			utils.ExtraDataFlag,
+			utils.TxOrdererFlag,
 			nodecmd.ConfigFileFlag,
 		},
 	},
@@ -73,6 +74,7 @@ var pnHelpFlagGroups = []utils.FlagGroup{
 		Flags: []cli.Flag{
 			utils.UnlockedAccountFlag,
 			utils.PasswordFileFlag,
+			utils.MaxUnlockedAccountsFlag,
 		},
 	},
 	{
@@ -88,6 +90,16 @@ var pnHelpFlagGroups = []utils.FlagGroup{
 			utils.TxPoolNonExecSlotsAccountFlag,
 			utils.TxPoolNonExecSlotsAllFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolDeployerAllowListFlag,
+			utils.TxPoolReplaceCooldownFlag,
+			utils.TxPoolEvictionPolicyFlag,
+			utils.TxPoolMaxCodeSizeFlag,
+			utils.TxPoolMaxTxDataSizeFlag,
+			utils.TxPoolStaleKeysFlag,
+			utils.TxPoolRejectMetricsFlag,
+			utils.TxPoolRejectSelfFeeDelegationFlag,
+			utils.TxPoolBroadcastTargetsFlag,
+			utils.TxPoolAllowZeroGasTypesFlag,
 			utils.TxResendIntervalFlag,
 			utils.TxResendCountFlag,
 			utils.TxResendUseLegacyFlag,
@@ -101,8 +113,14 @@ var pnHelpFlagGroups = []utils.FlagGroup{
 			utils.NumStateTriePartitionsFlag,
 			utils.LevelDBCompressionTypeFlag,
 			utils.LevelDBNoBufferPoolFlag,
+			utils.DBPartitionRatiosFlag,
+			utils.BodyCompressionFlag,
+			utils.DBReadOnlyFlag,
 			utils.NoParallelDBWriteFlag,
+			utils.MinFreeDiskSpaceFlag,
 			utils.SenderTxHashIndexingFlag,
+			utils.AccountCreationIndexingFlag,
+			utils.AccountKeyHistoryFlag,
 		},
 	},
 	{
@@ -123,17 +141,40 @@ var pnHelpFlagGroups = []utils.FlagGroup{
 			utils.CacheWriteThroughFlag,
 			utils.TxPoolStateCacheFlag,
 			utils.TrieCacheLimitFlag,
+			utils.TrieCommitBatchSizeFlag,
+			utils.TrieMaxDirtyBufferFlag,
+			utils.BlockVerifyLevelFlag,
+			utils.MaxFutureDriftFlag,
+			utils.IstanbulSnapshotIntervalFlag,
+			utils.KeyRotationAgeFlag,
 		},
 	},
 	{
 		Name: "NETWORKING",
 		Flags: []cli.Flag{
 			utils.BootnodesFlag,
+			utils.BootnodesURLFlag,
+			utils.BootnodesSignerFlag,
+			utils.BootnodesRefreshFlag,
 			utils.ListenPortFlag,
 			utils.SubListenPortFlag,
 			utils.MultiChannelUseFlag,
 			utils.MaxConnectionsFlag,
 			utils.MaxPendingPeersFlag,
+			utils.MaxConcurrentHandshakesFlag,
+			utils.EvictStaleForkPeersFlag,
+			utils.CatchUpGossipThresholdFlag,
+			utils.CNHandshakeTimeoutFlag,
+			utils.PNHandshakeTimeoutFlag,
+			utils.ENHandshakeTimeoutFlag,
+			utils.PeerQueueTxsFlag,
+			utils.PeerQueuePropsFlag,
+			utils.PeerQueueAnnsFlag,
+			utils.PeerKnownTxsFlag,
+			utils.PeerKnownBlocksFlag,
+			utils.PeerBroadcastRestartsFlag,
+			utils.SyncMaxPeersFlag,
+			utils.SyncMaxWriteLatencyFlag,
 			utils.TargetGasLimitFlag,
 			utils.NATFlag,
 			utils.NoDiscoverFlag,
@@ -178,6 +219,9 @@ var pnHelpFlagGroups = []utils.FlagGroup{
 			utils.WSPortFlag,
 			utils.WSApiFlag,
 			utils.WSAllowedOriginsFlag,
+			utils.WSSubscriptionBufferFlag,
+			utils.WSSubscriptionBufferPolicyFlag,
+			utils.RPCSlowLogFlag,
 			utils.GRPCEnabledFlag,
 			utils.GRPCListenAddrFlag,
 			utils.GRPCPortFlag,
@@ -209,6 +253,23 @@ func init() {
 		// See utils/nodecmd/chaincmd.go:
 		nodecmd.InitCommand,
 
+		// See utils/nodecmd/prunestatecmd.go:
+		nodecmd.PruneStateCommand,
+
+		// See utils/nodecmd/prunereceiptscmd.go:
+		nodecmd.PruneReceiptsCommand,
+
+		// See utils/nodecmd/replaycmd.go:
+		nodecmd.ReplayRecentCommand,
+
+		// See utils/nodecmd/statecmd.go:
+		nodecmd.ExportStateCommand,
+		nodecmd.ImportStateCommand,
+
+		// See utils/nodecmd/receiptcmd.go:
+		nodecmd.ExportReceiptsCommand,
+		nodecmd.DumpBlocksCommand,
+
 		// See utils/nodecmd/accountcmd.go
 		nodecmd.AccountCommand,
 
@@ -27,6 +27,7 @@ import (
 	"github.com/klaytn/klaytn/accounts/keystore"
 	"github.com/klaytn/klaytn/api/debug"
 	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/datasync/dbsyncer"
@@ -37,6 +38,7 @@ import (
 	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/networks/p2p/nat"
 	"github.com/klaytn/klaytn/networks/p2p/netutil"
+	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/node"
 	"github.com/klaytn/klaytn/node/cn"
 	"github.com/klaytn/klaytn/node/sc"
@@ -123,6 +125,25 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	PruneStateRetainFlag = cli.Uint64Flag{
+		Name:  "retain",
+		Usage: "Number of recent blocks whose states are retained by prune-state",
+		Value: 128,
+	}
+	PruneReceiptsKeepFlag = cli.Uint64Flag{
+		Name:  "keep",
+		Usage: "Number of recent blocks whose receipts are kept by prune-receipts",
+		Value: 90000,
+	}
+	ReplayCountFlag = cli.Uint64Flag{
+		Name:  "count",
+		Usage: "Number of the most recent blocks re-executed by replay-recent",
+		Value: 128,
+	}
+	StateArchiveAddressesFlag = cli.StringFlag{
+		Name:  "state.archiveaddresses",
+		Usage: "File of addresses (one per line) whose historical states are retained by prune-state",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -181,6 +202,50 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: cn.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolAllowZeroGasTypesFlag = cli.StringFlag{
+		Name:  "txpool.allowzerogas.types",
+		Usage: "Comma separated list of tx types accepted with zero gas price (e.g. TxTypeChainDataAnchoring)",
+	}
+	TxPoolDeployerAllowListFlag = cli.StringFlag{
+		Name:  "txpool.deployerallowlist",
+		Usage: "File of addresses (one per line) allowed to deploy contracts (default: unrestricted)",
+	}
+	TxPoolReplaceCooldownFlag = cli.DurationFlag{
+		Name:  "txpool.replacecooldown",
+		Usage: "Minimum interval between replacements of the same nonce of an account (0 = unlimited)",
+	}
+	TxPoolEvictionPolicyFlag = cli.StringFlag{
+		Name:  "txpool.evictionpolicy",
+		Usage: `Policy making room for a new tx when the pool is full ("nonce" fills nonce gaps only, "price" evicts the lowest priced txs)`,
+		Value: string(blockchain.DefaultTxPoolConfig.EvictionPolicy),
+	}
+	TxPoolMaxCodeSizeFlag = cli.Uint64Flag{
+		Name:  "txpool.maxcodesize",
+		Usage: "Maximum init code size in bytes of contract deployment txs accepted by the pool",
+		Value: blockchain.DefaultTxPoolConfig.MaxCodeSize,
+	}
+	TxPoolMaxTxDataSizeFlag = cli.Uint64Flag{
+		Name:  "txpool.maxtxdatasize",
+		Usage: "Maximum RLP encoded size in bytes of txs accepted by the pool",
+		Value: blockchain.DefaultTxPoolConfig.MaxTxDataSize,
+	}
+	TxPoolStaleKeysFlag = cli.IntFlag{
+		Name:  "txpool.stalekeys",
+		Usage: "Number of accounts whose key replaced by an account update is remembered to reject txs still signed by it with a specific error (0 = disabled)",
+		Value: blockchain.DefaultTxPoolConfig.StaleKeys,
+	}
+	TxPoolRejectMetricsFlag = cli.BoolFlag{
+		Name:  "txpool.rejectmetrics",
+		Usage: "Count rejected transactions by tx type and rejection reason (klay/txpool/reject/<txtype>/<reason>)",
+	}
+	TxPoolRejectSelfFeeDelegationFlag = cli.BoolFlag{
+		Name:  "txpool.rejectselffeedelegation",
+		Usage: "Reject fee-delegated transactions whose fee payer is the sender",
+	}
+	TxPoolBroadcastTargetsFlag = cli.StringFlag{
+		Name:  "txpool.broadcasttargets",
+		Usage: `Comma separated node types ("cn", "pn", "en") receiving transaction broadcasts (default: all types)`,
+	}
 	// Performance tuning settings
 	StateDBCachingFlag = cli.BoolFlag{
 		Name:  "statedb.use-cache",
@@ -210,10 +275,28 @@ var (
 		Name:  "db.leveldb.no-buffer-pool",
 		Usage: "Disables using buffer pool for LevelDB's block allocation",
 	}
+	DBPartitionRatiosFlag = cli.StringFlag{
+		Name:  "db.partition-ratios",
+		Usage: "Comma-separated percentages of the database cache given to the header, body, receipts, statetrie, txlookup, misc and bridgeservice partitions, summing to 100",
+	}
+	BodyCompressionFlag = cli.StringFlag{
+		Name:  "db.body-compression",
+		Usage: `Compression of newly stored block bodies ("none" or "snappy"). Bodies stored with any compression stay readable`,
+		Value: "none",
+	}
+	DBReadOnlyFlag = cli.BoolFlag{
+		Name:  "db.readonly",
+		Usage: "Opens the chain database read-only to serve queries from a copy of it. The node neither syncs nor mines, and writes to the database fail",
+	}
 	NoParallelDBWriteFlag = cli.BoolFlag{
 		Name:  "db.no-parallel-write",
 		Usage: "Disables parallel writes of block data to persistent database",
 	}
+	MinFreeDiskSpaceFlag = cli.Uint64Flag{
+		Name:  "db.minfreespace",
+		Usage: "Minimum free disk space (MiB) of the data directory to accept new blocks and transactions (0 = disabled)",
+		Value: 0,
+	}
 	TrieMemoryCacheSizeFlag = cli.IntFlag{
 		Name:  "state.cache-size",
 		Usage: "Size of in-memory cache of the global state (in MiB) to flush matured singleton trie nodes to disk",
@@ -235,7 +318,7 @@ var (
 	}
 	CacheUsageLevelFlag = cli.StringFlag{
 		Name:  "cache.level",
-		Usage: "Set the cache usage level ('saving', 'normal', 'extreme')",
+		Usage: "Set the cache usage level ('saving', 'normal', 'extreme'). Scales the trie cache and the block and peer caches",
 	}
 	MemorySizeFlag = cli.IntFlag{
 		Name:  "cache.memory",
@@ -254,11 +337,49 @@ var (
 		Usage: "Memory allowance (MB) to use for caching trie nodes in memory",
 		Value: 4096,
 	}
+	BlockVerifyLevelFlag = cli.StringFlag{
+		Name:  "blockchain.verifylevel",
+		Usage: `Verification level of the blocks from trusted peers ("full", "headersonly-trustedpeers"). "headersonly-trustedpeers" skips the transaction, gas used, bloom and receipt verification of the blocks from trusted peers, checking only their headers, transaction roots and state roots`,
+		Value: string(blockchain.VerifyLevelFull),
+	}
+	MaxFutureDriftFlag = cli.DurationFlag{
+		Name:  "blockchain.maxfuturedrift",
+		Usage: "Max time a block's timestamp may be ahead of the local clock before the block is queued as a future block",
+		Value: cn.DefaultConfig.Istanbul.MaxFutureDrift,
+	}
+	IstanbulSnapshotIntervalFlag = cli.Uint64Flag{
+		Name:  "istanbul.snapshotinterval",
+		Usage: "Number of blocks after which an istanbul vote snapshot is saved to the database. Smaller intervals speed up restart at the cost of more writes",
+		Value: cn.DefaultConfig.Istanbul.SnapshotInterval,
+	}
+	KeyRotationAgeFlag = cli.DurationFlag{
+		Name:  "security.keyrotationage",
+		Usage: "Warn when the node key or the rewardbase has been in use longer than the given duration (0 = disabled)",
+		Value: 0,
+	}
+	TrieCommitBatchSizeFlag = cli.IntFlag{
+		Name:  "state.commitbatchsize",
+		Usage: "Maximum size (KiB) of a batch written by a trie commit. Smaller batches reduce write latency spikes",
+		Value: database.IdealBatchSize / 1024,
+	}
+	TrieMaxDirtyBufferFlag = cli.IntFlag{
+		Name:  "state.maxdirtybuffer",
+		Usage: "Memory budget (MiB) of the dirty trie nodes. A trie commit growing over it flushes the oldest nodes to disk at once (0 = unlimited)",
+		Value: 0,
+	}
 
 	SenderTxHashIndexingFlag = cli.BoolFlag{
 		Name:  "sendertxhashindexing",
 		Usage: "Enables storing mapping information of senderTxHash to txHash",
 	}
+	AccountCreationIndexingFlag = cli.BoolFlag{
+		Name:  "accountcreationindexing",
+		Usage: "Enables storing the creation metadata of accounts created by transactions, explicitly or by receiving value or being deployed",
+	}
+	AccountKeyHistoryFlag = cli.BoolFlag{
+		Name:  "account.keyhistory",
+		Usage: "Enables storing the history of account keys changed by account update transactions",
+	}
 	ChildChainIndexingFlag = cli.BoolFlag{
 		Name:  "childchainindexing",
 		Usage: "Enables storing transaction hash of child chain transaction for fast access to child chain data",
@@ -282,6 +403,10 @@ var (
 		Name:  "extradata",
 		Usage: "Block extra data set by the work (default = client version)",
 	}
+	TxOrdererFlag = cli.StringFlag{
+		Name:  "txorderer",
+		Usage: "Name of the registered tx orderer deciding the tx order of blocks (default = price and nonce order)",
+	}
 
 	TxResendIntervalFlag = cli.Uint64Flag{
 		Name:  "txresend.interval",
@@ -309,6 +434,11 @@ var (
 		Usage: "Password file to use for non-interactive password input",
 		Value: "",
 	}
+	MaxUnlockedAccountsFlag = cli.IntFlag{
+		Name:  "unlock.maxaccounts",
+		Usage: "Maximum number of accounts unlocked at the same time (0 = unlimited)",
+		Value: 0,
+	}
 
 	VMEnableDebugFlag = cli.BoolFlag{
 		Name:  "vmdebug",
@@ -410,6 +540,21 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	WSSubscriptionBufferFlag = cli.IntFlag{
+		Name:  "rpc.ws.subbuffer",
+		Usage: "Maximum number of notifications buffered per WS-RPC subscription (0 = unbuffered)",
+		Value: 0,
+	}
+	WSSubscriptionBufferPolicyFlag = cli.StringFlag{
+		Name:  "rpc.ws.subbuffer.policy",
+		Usage: "Policy applied when a WS-RPC subscription buffer is full (drop-oldest, disconnect)",
+		Value: string(rpc.DropOldestPolicy),
+	}
+	RPCSlowLogFlag = cli.DurationFlag{
+		Name:  "rpc.slowlog",
+		Usage: "Log RPC calls whose handler takes longer than the given duration (0 = disabled)",
+		Value: 0,
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -435,6 +580,74 @@ var (
 		Usage: "Maximum number of pending connection attempts (defaults used if set to 0)",
 		Value: 0,
 	}
+	MaxConcurrentHandshakesFlag = cli.IntFlag{
+		Name:  "p2p.maxconcurrenthandshakes",
+		Usage: "Maximum number of concurrent peer handshakes. Excess handshakes are queued briefly (0 = unlimited)",
+		Value: 0,
+	}
+	EvictStaleForkPeersFlag = cli.BoolFlag{
+		Name:  "p2p.evictstaleforkpeers",
+		Usage: "Disconnect peers whose handshake shows they have not crossed a hardfork this node has crossed",
+	}
+	CatchUpGossipThresholdFlag = cli.Uint64Flag{
+		Name:  "p2p.catchupgossipthreshold",
+		Usage: "Suppress relaying blocks of peers while syncing more than this number of blocks behind the sync target (0 = always relay). Mined blocks are always announced",
+	}
+	CNHandshakeTimeoutFlag = cli.DurationFlag{
+		Name:  "p2p.handshaketimeout.cn",
+		Usage: "Timeout of the protocol handshake with consensus node peers",
+		Value: cn.DefaultConfig.CNHandshakeTimeout,
+	}
+	PNHandshakeTimeoutFlag = cli.DurationFlag{
+		Name:  "p2p.handshaketimeout.pn",
+		Usage: "Timeout of the protocol handshake with proxy node peers",
+		Value: cn.DefaultConfig.PNHandshakeTimeout,
+	}
+	ENHandshakeTimeoutFlag = cli.DurationFlag{
+		Name:  "p2p.handshaketimeout.en",
+		Usage: "Timeout of the protocol handshake with endpoint node peers",
+		Value: cn.DefaultConfig.ENHandshakeTimeout,
+	}
+	PeerQueueTxsFlag = cli.IntFlag{
+		Name:  "p2p.queue.txs",
+		Usage: "Maximum number of transaction lists queued for broadcast to each peer. Larger queues drop fewer broadcasts under bursts at the cost of memory",
+		Value: cn.DefaultConfig.PeerQueueTxs,
+	}
+	PeerQueuePropsFlag = cli.IntFlag{
+		Name:  "p2p.queue.props",
+		Usage: "Maximum number of block propagations queued for broadcast to each peer",
+		Value: cn.DefaultConfig.PeerQueueProps,
+	}
+	PeerQueueAnnsFlag = cli.IntFlag{
+		Name:  "p2p.queue.anns",
+		Usage: "Maximum number of block announcements queued for broadcast to each peer",
+		Value: cn.DefaultConfig.PeerQueueAnns,
+	}
+	PeerKnownTxsFlag = cli.IntFlag{
+		Name:  "p2p.known.txs",
+		Usage: "Number of transaction hashes remembered as known by each peer. Larger caches re-broadcast fewer transactions the peer already has",
+		Value: cn.DefaultConfig.PeerKnownTxs,
+	}
+	PeerKnownBlocksFlag = cli.IntFlag{
+		Name:  "p2p.known.blocks",
+		Usage: "Number of block hashes remembered as known by each peer",
+		Value: cn.DefaultConfig.PeerKnownBlocks,
+	}
+	PeerBroadcastRestartsFlag = cli.IntFlag{
+		Name:  "p2p.broadcastrestarts",
+		Usage: "Maximum number of times a peer's broadcast loop is restarted after a panic before the peer is dropped",
+		Value: cn.DefaultConfig.PeerBroadcastRestarts,
+	}
+	SyncMaxPeersFlag = cli.IntFlag{
+		Name:  "sync.maxpeers",
+		Usage: "Maximum number of peers the downloader fetches headers, bodies, receipts or state from at once (0 = unlimited)",
+		Value: 0,
+	}
+	SyncMaxWriteLatencyFlag = cli.DurationFlag{
+		Name:  "sync.max-write-latency",
+		Usage: "Average database write latency above which the downloader backs off block imports (0 = disabled)",
+		Value: cn.DefaultConfig.SyncMaxWriteLatency,
+	}
 	ListenPortFlag = cli.IntFlag{
 		Name:  "port",
 		Usage: "Network listening port",
@@ -454,6 +667,21 @@ var (
 		Usage: "Comma separated kni URLs for P2P discovery bootstrap",
 		Value: "",
 	}
+	BootnodesURLFlag = cli.StringFlag{
+		Name:  "bootnodes.url",
+		Usage: "HTTPS URL of a signed bootnode list periodically merged into the bootnodes (requires --bootnodes.signer)",
+		Value: "",
+	}
+	BootnodesSignerFlag = cli.StringFlag{
+		Name:  "bootnodes.signer",
+		Usage: "Address of the account which must sign the bootnode list served at --bootnodes.url",
+		Value: "",
+	}
+	BootnodesRefreshFlag = cli.DurationFlag{
+		Name:  "bootnodes.refresh",
+		Usage: "Interval between two fetches of the bootnode list served at --bootnodes.url",
+		Value: p2p.DefaultBootnodesRefreshInterval,
+	}
 	NodeKeyFileFlag = cli.StringFlag{
 		Name:  "nodekey",
 		Usage: "P2P node key file",
@@ -561,7 +789,7 @@ var (
 	}
 	MainChainURLFlag = cli.StringFlag{
 		Name:  "mainchainws",
-		Usage: "mainchain ws url",
+		Usage: "mainchain ws url (comma separated urls are used in turn on failover)",
 		Value: "ws://0.0.0.0:8546",
 	}
 	VTRecoveryFlag = cli.BoolFlag{
@@ -752,6 +980,26 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setBootnodesURL configures the periodic refresh of the bootnodes from a
+// signed bootnode list served over HTTPS.
+func setBootnodesURL(ctx *cli.Context, cfg *p2p.Config) {
+	if !ctx.GlobalIsSet(BootnodesURLFlag.Name) {
+		return
+	}
+	rawurl := ctx.GlobalString(BootnodesURLFlag.Name)
+	if !strings.HasPrefix(rawurl, "https://") {
+		log.Fatalf("Option %q: bootnode list URL must use https", BootnodesURLFlag.Name)
+	}
+	signer := ctx.GlobalString(BootnodesSignerFlag.Name)
+	if !common.IsHexAddress(signer) {
+		log.Fatalf("Option %q: a valid signer address is required with %q", BootnodesSignerFlag.Name, BootnodesURLFlag.Name)
+	}
+	cfg.BootnodesURL = rawurl
+	cfg.BootnodesSigner = common.HexToAddress(signer)
+	cfg.BootnodesRefreshInterval = ctx.GlobalDuration(BootnodesRefreshFlag.Name)
+	logger.Info("Bootnodes are refreshed from a signed list", "url", rawurl, "signer", cfg.BootnodesSigner, "interval", cfg.BootnodesRefreshInterval)
+}
+
 // setListenAddress creates a TCP listening address string from set command
 // line flags.
 func setListenAddress(ctx *cli.Context, cfg *p2p.Config) {
@@ -830,6 +1078,16 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(WSApiFlag.Name) {
 		cfg.WSModules = splitAndTrim(ctx.GlobalString(WSApiFlag.Name))
 	}
+	if ctx.GlobalIsSet(WSSubscriptionBufferFlag.Name) {
+		cfg.WSSubscriptionBuffer = ctx.GlobalInt(WSSubscriptionBufferFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSSubscriptionBufferPolicyFlag.Name) {
+		policy, err := rpc.ParseSubscriptionBufferPolicy(ctx.GlobalString(WSSubscriptionBufferPolicyFlag.Name))
+		if err != nil {
+			log.Fatalf("Option %q: %v", WSSubscriptionBufferPolicyFlag.Name, err)
+		}
+		cfg.WSSubscriptionBufferPolicy = policy
+	}
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -942,6 +1200,7 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 
 	// set bootnodes via this function by check specified parameters
 	setBootstrapNodes(ctx, cfg)
+	setBootnodesURL(ctx, cfg)
 
 	if ctx.GlobalIsSet(MaxConnectionsFlag.Name) {
 		cfg.MaxPhysicalConnections = ctx.GlobalInt(MaxConnectionsFlag.Name)
@@ -1011,6 +1270,10 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setgRPC(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 
+	if ctx.GlobalIsSet(RPCSlowLogFlag.Name) {
+		cfg.RPCSlowLogThreshold = ctx.GlobalDuration(RPCSlowLogFlag.Name)
+	}
+
 	cfg.DBType = ctx.GlobalString(DbTypeFlag.Name)
 	cfg.DataDir = ctx.GlobalString(DataDirFlag.Name)
 
@@ -1020,6 +1283,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(LightKDFFlag.Name) {
 		cfg.UseLightweightKDF = ctx.GlobalBool(LightKDFFlag.Name)
 	}
+	if ctx.GlobalIsSet(MaxUnlockedAccountsFlag.Name) {
+		cfg.MaxUnlockedAccounts = ctx.GlobalInt(MaxUnlockedAccountsFlag.Name)
+	}
 }
 
 func setTxPool(ctx *cli.Context, cfg *blockchain.TxPoolConfig) {
@@ -1056,6 +1322,46 @@ func setTxPool(ctx *cli.Context, cfg *blockchain.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolDeployerAllowListFlag.Name) {
+		cfg.DeployerAllowList = ctx.GlobalString(TxPoolDeployerAllowListFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolReplaceCooldownFlag.Name) {
+		cfg.ReplaceCooldown = ctx.GlobalDuration(TxPoolReplaceCooldownFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolEvictionPolicyFlag.Name) {
+		policy, err := blockchain.ParseTxEvictionPolicy(ctx.GlobalString(TxPoolEvictionPolicyFlag.Name))
+		if err != nil {
+			log.Fatalf("Option %q: %v", TxPoolEvictionPolicyFlag.Name, err)
+		}
+		cfg.EvictionPolicy = policy
+	}
+	if ctx.GlobalIsSet(TxPoolMaxCodeSizeFlag.Name) {
+		cfg.MaxCodeSize = ctx.GlobalUint64(TxPoolMaxCodeSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolMaxTxDataSizeFlag.Name) {
+		cfg.MaxTxDataSize = ctx.GlobalUint64(TxPoolMaxTxDataSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolStaleKeysFlag.Name) {
+		cfg.StaleKeys = ctx.GlobalInt(TxPoolStaleKeysFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolRejectMetricsFlag.Name) {
+		cfg.RejectMetrics = true
+	}
+	if ctx.GlobalIsSet(TxPoolRejectSelfFeeDelegationFlag.Name) {
+		cfg.RejectSelfFeeDelegation = true
+	}
+	if ctx.GlobalIsSet(TxPoolAllowZeroGasTypesFlag.Name) {
+		for _, name := range strings.Split(ctx.GlobalString(TxPoolAllowZeroGasTypesFlag.Name), ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			txType, err := types.ParseTxType(name)
+			if err != nil {
+				log.Fatalf("Option %q: unknown tx type %q", TxPoolAllowZeroGasTypesFlag.Name, name)
+			}
+			cfg.AllowZeroGasTypes = append(cfg.AllowZeroGasTypes, txType)
+		}
+	}
 }
 
 // checkExclusive verifies that only a single instance of the provided flags was
@@ -1096,6 +1402,32 @@ func checkExclusive(ctx *cli.Context, args ...interface{}) {
 	}
 }
 
+// sensitiveFlags are the flags whose values are masked by NonDefaultFlags.
+var sensitiveFlags = map[string]bool{
+	NodeKeyHexFlag.Name: true,
+	DBPasswordFlag.Name: true,
+}
+
+// maskedFlagValue replaces the values of sensitive flags.
+const maskedFlagValue = "<masked>"
+
+// NonDefaultFlags returns the values of the global flags set on the command line,
+// with the values of sensitive flags masked.
+func NonDefaultFlags(ctx *cli.Context) map[string]string {
+	flags := make(map[string]string)
+	for _, name := range ctx.GlobalFlagNames() {
+		if !ctx.GlobalIsSet(name) {
+			continue
+		}
+		if sensitiveFlags[name] {
+			flags[name] = maskedFlagValue
+		} else {
+			flags[name] = fmt.Sprint(ctx.GlobalGeneric(name))
+		}
+	}
+	return flags
+}
+
 // SetKlayConfig applies klay-related command line flags to the config.
 func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	// TODO-Klaytn-Bootnode: better have to check conflicts about network flags when we add Klaytn's `mainnet` parameter
@@ -1105,6 +1437,7 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	setServiceChainSigner(ctx, ks, cfg)
 	setRewardbase(ctx, ks, cfg)
 	setTxPool(ctx, &cfg.TxPool)
+	cfg.Flags = NonDefaultFlags(ctx)
 
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
@@ -1123,7 +1456,21 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 
 	cfg.LevelDBCompression = database.LevelDBCompressionType(ctx.GlobalInt(LevelDBCompressionTypeFlag.Name))
 	cfg.LevelDBBufferPool = !ctx.GlobalIsSet(LevelDBNoBufferPoolFlag.Name)
+	if ctx.GlobalIsSet(DBPartitionRatiosFlag.Name) {
+		ratios, err := database.ParsePartitionRatios(ctx.GlobalString(DBPartitionRatiosFlag.Name))
+		if err != nil {
+			log.Fatalf("Option %q: %v", DBPartitionRatiosFlag.Name, err)
+		}
+		cfg.DBPartitionRatios = ratios
+	}
+	bodyCompression, err := database.ParseBodyCompressionType(ctx.GlobalString(BodyCompressionFlag.Name))
+	if err != nil {
+		log.Fatalf("Option %q: %v", BodyCompressionFlag.Name, err)
+	}
+	cfg.BodyCompression = bodyCompression
+	cfg.DBReadOnly = ctx.GlobalBool(DBReadOnlyFlag.Name)
 	cfg.LevelDBCacheSize = ctx.GlobalInt(LevelDBCacheSizeFlag.Name)
+	cfg.MinFreeDiskSpace = ctx.GlobalUint64(MinFreeDiskSpaceFlag.Name)
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		log.Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1151,14 +1498,6 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	if ctx.GlobalIsSet(CacheScaleFlag.Name) {
 		common.CacheScale = ctx.GlobalInt(CacheScaleFlag.Name)
 	}
-	if ctx.GlobalIsSet(CacheUsageLevelFlag.Name) {
-		cacheUsageLevelFlag := ctx.GlobalString(CacheUsageLevelFlag.Name)
-		if scaleByCacheUsageLevel, err := common.GetScaleByCacheUsageLevel(cacheUsageLevelFlag); err != nil {
-			logger.Crit("Incorrect CacheUsageLevelFlag value", "error", err, "CacheUsageLevelFlag", cacheUsageLevelFlag)
-		} else {
-			common.ScaleByCacheUsageLevel = scaleByCacheUsageLevel
-		}
-	}
 	if ctx.GlobalIsSet(MemorySizeFlag.Name) {
 		physicalMemory := common.TotalPhysicalMemGB
 		common.TotalPhysicalMemGB = ctx.GlobalInt(MemorySizeFlag.Name)
@@ -1166,8 +1505,23 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	} else {
 		logger.Debug("Memory settings", "PhysicalMemory(GB)", common.TotalPhysicalMemGB)
 	}
+	if ctx.GlobalIsSet(CacheUsageLevelFlag.Name) || ctx.GlobalIsSet(MemorySizeFlag.Name) {
+		cacheUsageLevelFlag := ctx.GlobalString(CacheUsageLevelFlag.Name)
+		sizer := common.CacheSizer{UsageLevel: cacheUsageLevelFlag, MemoryGB: common.TotalPhysicalMemGB, TrieCacheSize: cfg.TrieCacheSize}
+		sizes, err := sizer.Sizes()
+		if err != nil {
+			logger.Crit("Incorrect CacheUsageLevelFlag or MemorySizeFlag value", "error", err, "CacheUsageLevelFlag", cacheUsageLevelFlag, "MemorySizeFlag", common.TotalPhysicalMemGB)
+		}
+		common.ScaleByCacheUsageLevel = sizes.ScaleByCacheUsageLevel
+		// An explicitly given trie cache size takes precedence over the usage level.
+		if !ctx.GlobalIsSet(TrieMemoryCacheSizeFlag.Name) {
+			cfg.TrieCacheSize = sizes.TrieCacheSize
+		}
+		logger.Info("Cache sizes are set by the usage level", "level", cacheUsageLevelFlag, "memory(GB)", common.TotalPhysicalMemGB,
+			"cacheScale", sizes.Scale, "trieCacheSize(MiB)", cfg.TrieCacheSize)
+	}
 
-	common.WriteThroughCaching = ctx.GlobalIsSet(CacheWriteThroughFlag.Name)
+	cfg.WriteThroughCaching = ctx.GlobalIsSet(CacheWriteThroughFlag.Name)
 	cfg.TxPoolStateCache = ctx.GlobalIsSet(TxPoolStateCacheFlag.Name)
 
 	if ctx.GlobalIsSet(DocRootFlag.Name) {
@@ -1176,11 +1530,43 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	if ctx.GlobalIsSet(ExtraDataFlag.Name) {
 		cfg.ExtraData = []byte(ctx.GlobalString(ExtraDataFlag.Name))
 	}
+	if ctx.GlobalIsSet(TxOrdererFlag.Name) {
+		cfg.TxOrderer = ctx.GlobalString(TxOrdererFlag.Name)
+	}
 
 	cfg.SenderTxHashIndexing = ctx.GlobalIsSet(SenderTxHashIndexingFlag.Name)
+	cfg.AccountCreationIndexing = ctx.GlobalIsSet(AccountCreationIndexingFlag.Name)
+	cfg.AccountKeyHistoryIndexing = ctx.GlobalIsSet(AccountKeyHistoryFlag.Name)
 	cfg.ParallelDBWrite = !ctx.GlobalIsSet(NoParallelDBWriteFlag.Name)
 	cfg.StateDBCaching = ctx.GlobalIsSet(StateDBCachingFlag.Name)
 	cfg.TrieCacheLimit = ctx.GlobalInt(TrieCacheLimitFlag.Name)
+	cfg.TrieCommitBatchSize = ctx.GlobalInt(TrieCommitBatchSizeFlag.Name)
+	cfg.TrieMaxDirtyBuffer = ctx.GlobalInt(TrieMaxDirtyBufferFlag.Name)
+	cfg.MaxConcurrentHandshakes = ctx.GlobalInt(MaxConcurrentHandshakesFlag.Name)
+	cfg.EvictStaleForkPeers = ctx.GlobalBool(EvictStaleForkPeersFlag.Name)
+	cfg.CatchUpGossipThreshold = ctx.GlobalUint64(CatchUpGossipThresholdFlag.Name)
+	cfg.CNHandshakeTimeout = ctx.GlobalDuration(CNHandshakeTimeoutFlag.Name)
+	cfg.PNHandshakeTimeout = ctx.GlobalDuration(PNHandshakeTimeoutFlag.Name)
+	cfg.ENHandshakeTimeout = ctx.GlobalDuration(ENHandshakeTimeoutFlag.Name)
+	cfg.PeerQueueTxs = ctx.GlobalInt(PeerQueueTxsFlag.Name)
+	cfg.PeerQueueProps = ctx.GlobalInt(PeerQueuePropsFlag.Name)
+	cfg.PeerQueueAnns = ctx.GlobalInt(PeerQueueAnnsFlag.Name)
+	cfg.PeerKnownTxs = ctx.GlobalInt(PeerKnownTxsFlag.Name)
+	cfg.PeerKnownBlocks = ctx.GlobalInt(PeerKnownBlocksFlag.Name)
+	cfg.PeerBroadcastRestarts = ctx.GlobalInt(PeerBroadcastRestartsFlag.Name)
+	cfg.SyncMaxPeers = ctx.GlobalInt(SyncMaxPeersFlag.Name)
+	cfg.SyncMaxWriteLatency = ctx.GlobalDuration(SyncMaxWriteLatencyFlag.Name)
+	verifyLevel, err := blockchain.ParseBlockVerifyLevel(ctx.GlobalString(BlockVerifyLevelFlag.Name))
+	if err != nil {
+		log.Fatalf("Option %q: %v", BlockVerifyLevelFlag.Name, err)
+	}
+	cfg.BlockVerifyLevel = verifyLevel
+	cfg.Istanbul.MaxFutureDrift = ctx.GlobalDuration(MaxFutureDriftFlag.Name)
+	cfg.Istanbul.SnapshotInterval = ctx.GlobalUint64(IstanbulSnapshotIntervalFlag.Name)
+	if cfg.Istanbul.SnapshotInterval == 0 {
+		log.Fatalf("--%s must be greater than 0", IstanbulSnapshotIntervalFlag.Name)
+	}
+	cfg.KeyRotationAge = ctx.GlobalDuration(KeyRotationAgeFlag.Name)
 
 	if ctx.GlobalIsSet(VMEnableDebugFlag.Name) {
 		// TODO(fjl): force-enable this in --dev mode
@@ -1204,6 +1590,7 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	*/
 	// Set the Tx resending related configuration variables
 	setTxResendConfig(ctx, cfg)
+	setTxBroadcastTargets(ctx, cfg)
 }
 
 // RegisterCNService adds a CN client to the stack.
@@ -1328,6 +1715,22 @@ func setTxResendConfig(ctx *cli.Context, cfg *cn.Config) {
 	logger.Debug("TxResend config", "Interval", cfg.TxResendInterval, "TxResendCount", cfg.TxResendCount, "UseLegacy", cfg.TxResendUseLegacy)
 }
 
+// setTxBroadcastTargets sets the node types receiving transaction broadcasts.
+func setTxBroadcastTargets(ctx *cli.Context, cfg *cn.Config) {
+	if !ctx.GlobalIsSet(TxPoolBroadcastTargetsFlag.Name) {
+		return
+	}
+	cfg.TxBroadcastTargets = nil
+	for _, nodetype := range strings.Split(ctx.GlobalString(TxPoolBroadcastTargetsFlag.Name), ",") {
+		target := convertNodeType(strings.TrimSpace(nodetype))
+		if target == node.UNKNOWNNODE {
+			log.Fatalf("Option %q: unknown node type %q", TxPoolBroadcastTargetsFlag.Name, nodetype)
+		}
+		cfg.TxBroadcastTargets = append(cfg.TxBroadcastTargets, target)
+	}
+	logger.Info("Transactions are broadcast to the given node types only", "nodetypes", ctx.GlobalString(TxPoolBroadcastTargetsFlag.Name))
+}
+
 // getNetworkID returns the associated network ID with whether or not the network is private.
 func getNetworkId(ctx *cli.Context) (uint64, bool) {
 	if ctx.GlobalIsSet(BaobabFlag.Name) && ctx.GlobalIsSet(CypressFlag.Name) {
@@ -0,0 +1,46 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestNonDefaultFlags(t *testing.T) {
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{NetworkIdFlag, NodeKeyHexFlag, TxPoolPriceBumpFlag, TxPoolNoLocalsFlag}
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range app.Flags {
+		f.Apply(set)
+	}
+	args := []string{"--networkid", "1001", "--nodekeyhex", "deadbeef", "--txpool.pricebump", "25"}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	ctx := cli.NewContext(app, set, nil)
+
+	assert.Equal(t, map[string]string{
+		NetworkIdFlag.Name:       "1001",
+		NodeKeyHexFlag.Name:      maskedFlagValue,
+		TxPoolPriceBumpFlag.Name: "25",
+	}, NonDefaultFlags(ctx))
+}
@@ -68,6 +68,13 @@ func initGenesis(ctx *cli.Context) error {
 	if len(genesisPath) == 0 {
 		log.Fatalf("Must supply path to genesis JSON file")
 	}
+	writeGenesis(ctx, readGenesis(genesisPath), nil)
+	return nil
+}
+
+// readGenesis reads and checks the given JSON format genesis file. It fails hard
+// if the genesis is invalid.
+func readGenesis(genesisPath string) *blockchain.Genesis {
 	file, err := os.Open(genesisPath)
 	if err != nil {
 		log.Fatalf("Failed to read genesis file: %v", err)
@@ -77,7 +84,6 @@ func initGenesis(ctx *cli.Context) error {
 	genesis := new(blockchain.Genesis)
 	if err := json.NewDecoder(file).Decode(genesis); err != nil {
 		log.Fatalf("invalid genesis file: %v", err)
-		return err
 	}
 
 	genesis = checkGenesisAndFillDefaultIfNeeded(genesis)
@@ -126,6 +132,12 @@ func initGenesis(ctx *cli.Context) error {
 	if genesis.Governance, err = rlp.EncodeToBytes(gbytes); err != nil {
 		logger.Crit("Failed to encode initial settings. Check your genesis.json", "err", err)
 	}
+	return genesis
+}
+
+// writeGenesis writes the genesis block to both full and light databases. If
+// prepare is given, it is called with each database before the genesis is written.
+func writeGenesis(ctx *cli.Context, genesis *blockchain.Genesis, prepare func(database.DBManager) error) {
 	// Open an initialise both full and light databases
 	stack := MakeFullNode(ctx)
 
@@ -140,6 +152,11 @@ func initGenesis(ctx *cli.Context) error {
 		// Initialize DeriveSha implementation
 		blockchain.InitDeriveSha(genesis.Config.DeriveShaImpl)
 
+		if prepare != nil {
+			if err := prepare(chaindb); err != nil {
+				log.Fatalf("Failed to prepare genesis state: %v", err)
+			}
+		}
 		_, hash, err := blockchain.SetupGenesisBlock(chaindb, genesis, params.UnusedNetworkId, false)
 		if err != nil {
 			log.Fatalf("Failed to write genesis block: %v", err)
@@ -148,7 +165,6 @@ func initGenesis(ctx *cli.Context) error {
 
 		chaindb.Close()
 	}
-	return nil
 }
 
 func getGovernanceItemsFromGenesis(genesis *blockchain.Genesis) governance.GovernanceSet {
@@ -131,6 +131,10 @@ func startKlaytnAuxiliaryService(ctx *cli.Context, stack *node.Node) {
 	if err := stack.Service(&cn); err != nil {
 		log.Fatalf("Klaytn service not running: %v", err)
 	}
+	// A node on a read-only database only serves queries.
+	if cn.ChainDB().IsReadOnly() {
+		return
+	}
 
 	// TODO-Klaytn-NodeCmd disable accept tx before finishing sync.
 	if err := cn.StartMining(false); err != nil {
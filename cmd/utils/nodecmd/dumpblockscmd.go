@@ -0,0 +1,75 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"bufio"
+	"github.com/klaytn/klaytn/api"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/log"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"strconv"
+)
+
+var DumpBlocksCommand = cli.Command{
+	Action:    utils.MigrateFlags(dumpBlocks),
+	Name:      "dump-blocks",
+	Usage:     "Dump the blocks and receipts of a block range as JSON",
+	ArgsUsage: "<from> <to>",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.NoPartitionedDBFlag,
+		utils.NumStateTriePartitionsFlag,
+		utils.LevelDBCompressionTypeFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+The dump-blocks command writes the canonical blocks from <from> to <to>, both
+inclusive, to the standard output with one JSON object per line. Each object
+holds the block header, its transactions and its receipts. The receipts are
+formatted as returned by klay_getTransactionReceipt and their logs include the
+block hash, the block number and the transaction index.
+Block numbers without a canonical block are skipped.`,
+}
+
+func dumpBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		log.Fatalf("This command requires two arguments: <from> <to>")
+	}
+	from, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid block number: %v", err)
+	}
+	to, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid block number: %v", err)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	chainDB := openChainDB(stack, cfg)
+	defer chainDB.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	if err := chainDB.ExportBlockRange(from, to, out, api.RpcOutputReceipt); err != nil {
+		log.Fatalf("Failed to dump blocks: %v", err)
+	}
+	if err := out.Flush(); err != nil {
+		log.Fatalf("Failed to dump blocks: %v", err)
+	}
+	return nil
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/klaytn/klaytn/node/cn"
 	"github.com/klaytn/klaytn/node/sc"
 	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
 	"gopkg.in/urfave/cli.v1"
 	"os"
 	"reflect"
@@ -137,6 +138,15 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, klayConfig) {
 	return stack, cfg
 }
 
+// openChainDB opens the chain database of the node configured by cfg for the
+// commands working on the database of a stopped node.
+func openChainDB(stack *node.Node, cfg klayConfig) database.DBManager {
+	dbc := &database.DBConfig{Dir: "chaindata", DBType: database.LevelDB, Partitioned: cfg.CN.PartitionedDB,
+		NumStateTriePartitions: cfg.CN.NumStateTriePartitions, OpenFilesLimit: database.GetOpenFilesLimit(),
+		LevelDBCompression: cfg.CN.LevelDBCompression}
+	return stack.OpenDatabase(dbc)
+}
+
 func makeDBSyncerConfig(ctx *cli.Context) dbsyncer.DBConfig {
 	cfg := dbsyncer.DefaultDBConfig
 
@@ -28,9 +28,13 @@ import (
 // Common flags that configure the node
 var CommonNodeFlags = []cli.Flag{
 	utils.BootnodesFlag,
+	utils.BootnodesURLFlag,
+	utils.BootnodesSignerFlag,
+	utils.BootnodesRefreshFlag,
 	utils.IdentityFlag,
 	utils.UnlockedAccountFlag,
 	utils.PasswordFileFlag,
+	utils.MaxUnlockedAccountsFlag,
 	utils.DbTypeFlag,
 	utils.DataDirFlag,
 	utils.KeyStoreDirFlag,
@@ -44,6 +48,16 @@ var CommonNodeFlags = []cli.Flag{
 	utils.TxPoolNonExecSlotsAccountFlag,
 	utils.TxPoolNonExecSlotsAllFlag,
 	utils.TxPoolLifetimeFlag,
+	utils.TxPoolDeployerAllowListFlag,
+	utils.TxPoolReplaceCooldownFlag,
+	utils.TxPoolEvictionPolicyFlag,
+	utils.TxPoolMaxCodeSizeFlag,
+	utils.TxPoolMaxTxDataSizeFlag,
+	utils.TxPoolStaleKeysFlag,
+	utils.TxPoolRejectMetricsFlag,
+	utils.TxPoolRejectSelfFeeDelegationFlag,
+	utils.TxPoolBroadcastTargetsFlag,
+	utils.TxPoolAllowZeroGasTypesFlag,
 	utils.SyncModeFlag,
 	utils.GCModeFlag,
 	utils.LightKDFFlag,
@@ -52,9 +66,15 @@ var CommonNodeFlags = []cli.Flag{
 	utils.NumStateTriePartitionsFlag,
 	utils.LevelDBCompressionTypeFlag,
 	utils.LevelDBNoBufferPoolFlag,
+	utils.DBPartitionRatiosFlag,
+	utils.BodyCompressionFlag,
+	utils.DBReadOnlyFlag,
 	utils.LevelDBCacheSizeFlag,
 	utils.NoParallelDBWriteFlag,
+	utils.MinFreeDiskSpaceFlag,
 	utils.SenderTxHashIndexingFlag,
+	utils.AccountCreationIndexingFlag,
+	utils.AccountKeyHistoryFlag,
 	utils.TrieMemoryCacheSizeFlag,
 	utils.TrieBlockIntervalFlag,
 	utils.CacheTypeFlag,
@@ -64,11 +84,31 @@ var CommonNodeFlags = []cli.Flag{
 	utils.CacheWriteThroughFlag,
 	utils.TxPoolStateCacheFlag,
 	utils.TrieCacheLimitFlag,
+	utils.TrieCommitBatchSizeFlag,
+	utils.TrieMaxDirtyBufferFlag,
+	utils.BlockVerifyLevelFlag,
+	utils.MaxFutureDriftFlag,
+	utils.IstanbulSnapshotIntervalFlag,
+	utils.KeyRotationAgeFlag,
 	utils.ListenPortFlag,
 	utils.SubListenPortFlag,
 	utils.MultiChannelUseFlag,
 	utils.MaxConnectionsFlag,
 	utils.MaxPendingPeersFlag,
+	utils.MaxConcurrentHandshakesFlag,
+	utils.EvictStaleForkPeersFlag,
+	utils.CatchUpGossipThresholdFlag,
+	utils.CNHandshakeTimeoutFlag,
+	utils.PNHandshakeTimeoutFlag,
+	utils.ENHandshakeTimeoutFlag,
+	utils.PeerQueueTxsFlag,
+	utils.PeerQueuePropsFlag,
+	utils.PeerQueueAnnsFlag,
+	utils.PeerKnownTxsFlag,
+	utils.PeerKnownBlocksFlag,
+	utils.PeerBroadcastRestartsFlag,
+	utils.SyncMaxPeersFlag,
+	utils.SyncMaxWriteLatencyFlag,
 	utils.TargetGasLimitFlag,
 	utils.NATFlag,
 	utils.NoDiscoverFlag,
@@ -86,6 +126,7 @@ var CommonNodeFlags = []cli.Flag{
 	utils.PrometheusExporterFlag,
 	utils.PrometheusExporterPortFlag,
 	utils.ExtraDataFlag,
+	utils.TxOrdererFlag,
 	utils.SrvTypeFlag,
 	ConfigFileFlag,
 }
@@ -104,6 +145,9 @@ var CommonRPCFlags = []cli.Flag{
 	utils.GRPCPortFlag,
 	utils.WSApiFlag,
 	utils.WSAllowedOriginsFlag,
+	utils.WSSubscriptionBufferFlag,
+	utils.WSSubscriptionBufferPolicyFlag,
+	utils.RPCSlowLogFlag,
 	utils.IPCDisabledFlag,
 	utils.IPCPathFlag,
 }
@@ -0,0 +1,114 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/storage/database"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var PruneReceiptsCommand = cli.Command{
+	Action:    utils.MigrateFlags(pruneReceipts),
+	Name:      "prune-receipts",
+	Usage:     "Delete the receipts of the blocks older than the most recent blocks",
+	ArgsUsage: " ",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.GCModeFlag,
+		utils.NoPartitionedDBFlag,
+		utils.NumStateTriePartitionsFlag,
+		utils.LevelDBCompressionTypeFlag,
+		utils.PruneReceiptsKeepFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+The prune-receipts command deletes the receipts of the canonical blocks below the
+head block number minus --keep. The node must be stopped while pruning. Archive
+mode data directories cannot be pruned. The gc mode is the one the node last ran
+with on the data directory. If it is unknown, --gcmode full should be given
+explicitly.
+
+The earliest block whose receipts are available is stored in the database, and
+the receipt APIs return an error for the blocks below it. If the pruning is
+interrupted, running the command again resumes it.`,
+}
+
+// pruneReceiptsChunk is the number of blocks whose receipts are deleted in a
+// batch between the updates of the earliest available receipts block number.
+const pruneReceiptsChunk = 1024
+
+func pruneReceipts(ctx *cli.Context) error {
+	stack, cfg := makeConfigNode(ctx)
+
+	chainDB := openChainDB(stack, cfg)
+	defer chainDB.Close()
+
+	if err := checkArchiveMode(chainDB, cfg.CN.NoPruning, ctx.GlobalIsSet(utils.GCModeFlag.Name)); err != nil {
+		log.Fatalf("Failed to prune receipts: %v", err)
+	}
+
+	if err := pruneReceiptsDB(chainDB, ctx.GlobalUint64(utils.PruneReceiptsKeepFlag.Name)); err != nil {
+		log.Fatalf("Failed to prune receipts: %v", err)
+	}
+	return nil
+}
+
+// pruneReceiptsDB deletes the receipts of the canonical blocks below the head
+// block number minus keep, starting from the earliest available receipts. The
+// earliest available receipts block number is raised before the batch deleting
+// each chunk of blocks is written, so that it never points to a block whose
+// receipts are deleted.
+func pruneReceiptsDB(db database.DBManager, keep uint64) error {
+	headNumber := db.ReadHeaderNumber(db.ReadHeadBlockHash())
+	if headNumber == nil {
+		return errors.New("head block is not found")
+	}
+	earliest := db.ReadEarliestReceiptsBlockNumber()
+	if *headNumber < keep || *headNumber-keep <= earliest {
+		logger.Info("No receipts to prune", "head", *headNumber, "keep", keep, "earliest", earliest)
+		return nil
+	}
+	target := *headNumber - keep
+
+	batch := db.NewBatch(database.ReceiptsDB)
+	for from := earliest; from < target; from += pruneReceiptsChunk {
+		to := from + pruneReceiptsChunk
+		if to > target {
+			to = target
+		}
+		for number := from; number < to; number++ {
+			hash := db.ReadCanonicalHash(number)
+			if hash == (common.Hash{}) {
+				return fmt.Errorf("canonical hash of block %d is not found", number)
+			}
+			db.DeleteReceiptsFromBatch(batch, hash, number)
+		}
+		db.WriteEarliestReceiptsBlockNumber(to)
+		if err := batch.Write(); err != nil {
+			return fmt.Errorf("failed to delete the receipts of blocks %d-%d: %v", from, to-1, err)
+		}
+		batch.Reset()
+		logger.Info("Pruned receipts", "from", from, "to", to-1, "target", target)
+	}
+	logger.Info("Finished pruning receipts", "earliest", target, "head", *headNumber)
+	return nil
+}
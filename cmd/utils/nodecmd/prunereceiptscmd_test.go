@@ -0,0 +1,78 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
+	"testing"
+)
+
+func TestPruneReceiptsDB(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	var (
+		parent  common.Hash
+		headers []*types.Header
+	)
+	for i := 0; i < 10; i++ {
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), BlockScore: big.NewInt(1), Time: big.NewInt(int64(i))}
+		db.WriteHeader(header)
+		db.WriteCanonicalHash(header.Hash(), header.Number.Uint64())
+		db.WriteHeadBlockHash(header.Hash())
+		receipts := types.Receipts{&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: common.BigToHash(big.NewInt(int64(i)))}}
+		db.WriteReceipts(header.Hash(), header.Number.Uint64(), receipts)
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+
+	checkPruned := func(earliest uint64) {
+		if have := db.ReadEarliestReceiptsBlockNumber(); have != earliest {
+			t.Fatalf("earliest receipts block number mismatch: have %d, want %d", have, earliest)
+		}
+		for _, header := range headers {
+			number := header.Number.Uint64()
+			receipts := db.ReadReceipts(header.Hash(), number)
+			if pruned := number < earliest; pruned != (receipts == nil) {
+				t.Errorf("block %d: pruned %v, receipts %v", number, pruned, receipts)
+			}
+		}
+	}
+
+	checkPruned(0)
+
+	if err := pruneReceiptsDB(db, 3); err != nil {
+		t.Fatalf("failed to prune receipts: %v", err)
+	}
+	checkPruned(6)
+
+	// Pruning again with the same or a larger keep does nothing.
+	if err := pruneReceiptsDB(db, 3); err != nil {
+		t.Fatalf("failed to prune receipts: %v", err)
+	}
+	if err := pruneReceiptsDB(db, 20); err != nil {
+		t.Fatalf("failed to prune receipts: %v", err)
+	}
+	checkPruned(6)
+
+	// Pruning resumes from the earliest available receipts.
+	if err := pruneReceiptsDB(db, 1); err != nil {
+		t.Fatalf("failed to prune receipts: %v", err)
+	}
+	checkPruned(8)
+}
@@ -0,0 +1,202 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/storage/database"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var PruneStateCommand = cli.Command{
+	Action:    utils.MigrateFlags(pruneState),
+	Name:      "prune-state",
+	Usage:     "Prune the state trie except the states of recent blocks and the genesis",
+	ArgsUsage: " ",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.GCModeFlag,
+		utils.NoPartitionedDBFlag,
+		utils.NumStateTriePartitionsFlag,
+		utils.LevelDBCompressionTypeFlag,
+		utils.PruneStateRetainFlag,
+		utils.StateArchiveAddressesFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+The prune-state command deletes the state trie nodes which are not reachable from
+the states of the most recent --retain blocks and the genesis block. The node must
+be stopped while pruning. Archive mode data directories cannot be pruned. The gc
+mode is the one the node last ran with on the data directory. If it is unknown,
+--gcmode full should be given explicitly.
+
+The states of the accounts listed in the --state.archiveaddresses file are kept
+at every block whose state is stored, so that their historical storage can still
+be read after pruning.
+
+Reachable nodes are marked in a journal before any deletion. If the pruning is
+interrupted, running the command again resumes it.`,
+}
+
+// pruneStateJournal is the name of the journal database in the instance directory.
+const pruneStateJournal = "prunestate-journal"
+
+var (
+	errArchiveModePruning = errors.New("state of an archive mode node cannot be pruned")
+	errUnknownGCMode      = errors.New("gc mode of the data directory is unknown, run the node once or pass --gcmode full")
+)
+
+// checkArchiveMode returns an error if the data directory of db belongs to an
+// archive mode node. The gc mode stored by the node is used if there is one.
+// Otherwise, the data directory is regarded as a full mode one only if
+// --gcmode full is given explicitly.
+func checkArchiveMode(db database.DBManager, noPruning, gcModeSet bool) error {
+	if archive, stored := db.ReadArchiveMode(); stored {
+		noPruning = archive
+	} else if !gcModeSet {
+		return errUnknownGCMode
+	}
+	if noPruning {
+		return errArchiveModePruning
+	}
+	return nil
+}
+
+func pruneState(ctx *cli.Context) error {
+	stack, cfg := makeConfigNode(ctx)
+
+	chainDB := openChainDB(stack, cfg)
+	defer chainDB.Close()
+
+	if err := checkArchiveMode(chainDB, cfg.CN.NoPruning, ctx.GlobalIsSet(utils.GCModeFlag.Name)); err != nil {
+		log.Fatalf("Failed to prune state: %v", err)
+	}
+
+	var archiveAddrs []common.Address
+	if path := ctx.GlobalString(utils.StateArchiveAddressesFlag.Name); path != "" {
+		addrs, err := common.LoadAddressList(path)
+		if err != nil {
+			log.Fatalf("Failed to load archive addresses: %v", err)
+		}
+		archiveAddrs = addrs
+	}
+
+	retain := ctx.GlobalUint64(utils.PruneStateRetainFlag.Name)
+	if err := pruneStateDB(chainDB, retain, archiveAddrs, stack.ResolvePath(pruneStateJournal)); err != nil {
+		log.Fatalf("Failed to prune state: %v", err)
+	}
+	return nil
+}
+
+// pruneStateDB prunes the state trie of the given database, retaining the states
+// of the most recent retain blocks and the genesis block. The states of archiveAddrs
+// are retained at every block whose state is stored.
+func pruneStateDB(db database.DBManager, retain uint64, archiveAddrs []common.Address, journalPath string) error {
+	roots, err := retainedStateRoots(db, retain)
+	if err != nil {
+		return err
+	}
+	var archived *state.ArchivedAccounts
+	if len(archiveAddrs) > 0 {
+		archived = &state.ArchivedAccounts{Addresses: archiveAddrs, Roots: storedStateRoots(db)}
+		logger.Info("Archiving accounts", "accounts", len(archiveAddrs), "roots", len(archived.Roots))
+	}
+	return state.PruneState(db, roots, archived, journalPath)
+}
+
+// storedStateRoots returns the distinct state roots of the canonical blocks whose
+// states are stored in the database.
+func storedStateRoots(db database.DBManager) []common.Hash {
+	headNumber := db.ReadHeaderNumber(db.ReadHeadBlockHash())
+	if headNumber == nil {
+		return nil
+	}
+	var (
+		roots    []common.Hash
+		included = make(map[common.Hash]bool)
+	)
+	for number := uint64(0); number <= *headNumber; number++ {
+		header := db.ReadHeader(db.ReadCanonicalHash(number), number)
+		if header == nil || included[header.Root] {
+			continue
+		}
+		if ok, _ := db.HasStateTrieNode(header.Root[:]); ok {
+			included[header.Root] = true
+			roots = append(roots, header.Root)
+		}
+	}
+	return roots
+}
+
+// retainedStateRoots returns the state roots of the genesis block and the most
+// recent retain canonical blocks. Recent states which are not stored in the
+// database are skipped, but at least one recent state must exist.
+func retainedStateRoots(db database.DBManager, retain uint64) ([]common.Hash, error) {
+	if retain == 0 {
+		return nil, fmt.Errorf("--%s should be greater than 0", utils.PruneStateRetainFlag.Name)
+	}
+	headHash := db.ReadHeadBlockHash()
+	headNumber := db.ReadHeaderNumber(headHash)
+	if headNumber == nil {
+		return nil, errors.New("head block is not found")
+	}
+
+	var (
+		roots    []common.Hash
+		included = make(map[common.Hash]bool)
+	)
+	addRoot := func(number uint64) bool {
+		header := db.ReadHeader(db.ReadCanonicalHash(number), number)
+		if header == nil {
+			logger.Warn("Skipping a block without header", "number", number)
+			return false
+		}
+		if ok, _ := db.HasStateTrieNode(header.Root[:]); !ok {
+			logger.Warn("Skipping a block without state", "number", number, "root", header.Root)
+			return false
+		}
+		if !included[header.Root] {
+			included[header.Root] = true
+			roots = append(roots, header.Root)
+		}
+		return true
+	}
+
+	if !addRoot(0) {
+		return nil, errors.New("genesis state is not found")
+	}
+	from := uint64(1)
+	if *headNumber >= retain {
+		from = *headNumber - retain + 1
+	}
+	found := false
+	for number := from; number <= *headNumber; number++ {
+		if addRoot(number) {
+			found = true
+		}
+	}
+	if !found && *headNumber > 0 {
+		return nil, errors.New("no recent state is found")
+	}
+	logger.Info("Retaining states", "head", *headNumber, "retain", retain, "roots", len(roots))
+	return roots, nil
+}
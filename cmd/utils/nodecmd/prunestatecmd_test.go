@@ -0,0 +1,161 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makePruneTestChain writes a chain of headers whose states change the balance of
+// addr at every block.
+func makePruneTestChain(t *testing.T, db database.DBManager, n int, addr common.Address) []*types.Header {
+	var (
+		sdb     = state.NewDatabase(db)
+		root    = common.Hash{}
+		parent  = common.Hash{}
+		headers []*types.Header
+	)
+	for i := 0; i < n; i++ {
+		statedb, err := state.New(root, sdb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statedb.AddBalance(addr, big.NewInt(1))
+		if root, err = statedb.Commit(false); err != nil {
+			t.Fatal(err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatal(err)
+		}
+
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), Root: root, BlockScore: big.NewInt(1), Time: big.NewInt(int64(i))}
+		db.WriteHeader(header)
+		db.WriteCanonicalHash(header.Hash(), header.Number.Uint64())
+		db.WriteHeadBlockHash(header.Hash())
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+	return headers
+}
+
+func TestPruneStateDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-prune-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := database.NewMemoryDBManager()
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	headers := makePruneTestChain(t, db, 8, addr)
+
+	if err := pruneStateDB(db, 3, nil, filepath.Join(dir, pruneStateJournal)); err != nil {
+		t.Fatalf("failed to prune state: %v", err)
+	}
+
+	for i, header := range headers {
+		statedb, err := state.New(header.Root, state.NewDatabase(db))
+		retained := i == 0 || i >= len(headers)-3
+		if !retained {
+			if err == nil {
+				t.Errorf("state of block %d should be pruned", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("state of block %d should be retained: %v", i, err)
+			continue
+		}
+		if balance := statedb.GetBalance(addr); balance.Int64() != int64(i+1) {
+			t.Errorf("balance at block %d mismatch: have %v, want %d", i, balance, i+1)
+		}
+	}
+}
+
+func TestRetainedStateRoots(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	headers := makePruneTestChain(t, db, 5, common.HexToAddress("0x1000000000000000000000000000000000000001"))
+
+	if _, err := retainedStateRoots(db, 0); err == nil {
+		t.Error("retaining no state should fail")
+	}
+
+	roots, err := retainedStateRoots(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.Hash{headers[0].Root, headers[3].Root, headers[4].Root}
+	if len(roots) != len(want) {
+		t.Fatalf("retained roots mismatch: have %v, want %v", roots, want)
+	}
+	for i := range want {
+		if roots[i] != want[i] {
+			t.Errorf("retained root %d mismatch: have %x, want %x", i, roots[i], want[i])
+		}
+	}
+}
+
+func TestStoredStateRoots(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	headers := makePruneTestChain(t, db, 4, common.HexToAddress("0x1000000000000000000000000000000000000001"))
+
+	roots := storedStateRoots(db)
+	if len(roots) != len(headers) {
+		t.Fatalf("stored roots count mismatch: have %d, want %d", len(roots), len(headers))
+	}
+	for i, header := range headers {
+		if roots[i] != header.Root {
+			t.Errorf("stored root %d mismatch: have %x, want %x", i, roots[i], header.Root)
+		}
+	}
+}
+
+func TestCheckArchiveMode(t *testing.T) {
+	db := database.NewMemoryDBManager()
+
+	// Without a stored gc mode, only an explicit full mode is allowed.
+	if err := checkArchiveMode(db, false, false); err != errUnknownGCMode {
+		t.Errorf("unknown gc mode: have %v, want %v", err, errUnknownGCMode)
+	}
+	if err := checkArchiveMode(db, false, true); err != nil {
+		t.Errorf("explicit full mode: have %v, want nil", err)
+	}
+	if err := checkArchiveMode(db, true, true); err != errArchiveModePruning {
+		t.Errorf("explicit archive mode: have %v, want %v", err, errArchiveModePruning)
+	}
+
+	// The stored gc mode takes precedence over the flag.
+	db.WriteArchiveMode(true)
+	if err := checkArchiveMode(db, false, false); err != errArchiveModePruning {
+		t.Errorf("stored archive mode: have %v, want %v", err, errArchiveModePruning)
+	}
+	if err := checkArchiveMode(db, false, true); err != errArchiveModePruning {
+		t.Errorf("stored archive mode with --gcmode full: have %v, want %v", err, errArchiveModePruning)
+	}
+	db.WriteArchiveMode(false)
+	if err := checkArchiveMode(db, false, false); err != nil {
+		t.Errorf("stored full mode: have %v, want nil", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	klaytnapi "github.com/klaytn/klaytn/api"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/storage/database"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"strconv"
+)
+
+var ExportReceiptsCommand = cli.Command{
+	Action:    utils.MigrateFlags(exportReceipts),
+	Name:      "export-receipts",
+	Usage:     "Export the receipts of a block range into a file",
+	ArgsUsage: "<from> <to> <filename>",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.NoPartitionedDBFlag,
+		utils.NumStateTriePartitionsFlag,
+		utils.LevelDBCompressionTypeFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+The export-receipts command writes the receipts of the canonical blocks from
+<from> to <to>, both inclusive, into a gzipped file with one JSON object per
+line. Each object is the receipt as returned by klay_getTransactionReceipt,
+including the transaction hash, the block context and the fee delegation
+fields. The receipts are streamed block by block.`,
+}
+
+func exportReceipts(ctx *cli.Context) error {
+	if len(ctx.Args()) != 3 {
+		log.Fatalf("This command requires three arguments: <from> <to> <filename>")
+	}
+	from, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid block number: %v", err)
+	}
+	to, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid block number: %v", err)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	chainDB := openChainDB(stack, cfg)
+	defer chainDB.Close()
+
+	if err := exportReceiptsToFile(chainDB, from, to, ctx.Args().Get(2)); err != nil {
+		log.Fatalf("Failed to export receipts: %v", err)
+	}
+	return nil
+}
+
+// exportReceiptsToFile writes the receipts of the canonical blocks in the given
+// range into a gzipped file, one JSON object per line.
+func exportReceiptsToFile(db database.DBManager, from, to uint64, path string) error {
+	if from > to {
+		return fmt.Errorf("invalid block range: %d > %d", from, to)
+	}
+	if db.ReadCanonicalHash(to) == (common.Hash{}) {
+		return fmt.Errorf("block %d is not found", to)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := gzip.NewWriter(file)
+	if err := db.ExportReceiptRange(from, to, writer, klaytnapi.RpcOutputReceipt); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	logger.Info("Exported receipts", "from", from, "to", to, "file", path)
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"compress/gzip"
+	"crypto/ecdsa"
+	"encoding/json"
+	"github.com/klaytn/klaytn/api"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// readExportedReceipts decodes the receipts of a file written by exportReceiptsToFile.
+func readExportedReceipts(t *testing.T, path string) []map[string]interface{} {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	var receipts []map[string]interface{}
+	decoder := json.NewDecoder(reader)
+	for {
+		var receipt map[string]interface{}
+		if err := decoder.Decode(&receipt); err == io.EOF {
+			return receipts
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		receipts = append(receipts, receipt)
+	}
+}
+
+func TestExportReceiptsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-export-receipts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		key, _      = crypto.GenerateKey()
+		payerKey, _ = crypto.GenerateKey()
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+		payer       = crypto.PubkeyToAddress(payerKey.PublicKey)
+		to          = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		db          = database.NewMemoryDBManager()
+		gspec       = &blockchain.Genesis{Config: params.TestChainConfig, Alloc: blockchain.GenesisAlloc{
+			from:  {Balance: big.NewInt(params.KLAY)},
+			payer: {Balance: big.NewInt(params.KLAY)},
+		}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, big.NewInt(1))
+	)
+	legacyTx, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(1), 100000, big.NewInt(0), nil), signer, key)
+	feeDelegatedTx, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(1),
+		types.TxValueKeyFrom:     from,
+		types.TxValueKeyTo:       to,
+		types.TxValueKeyAmount:   big.NewInt(2),
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: big.NewInt(0),
+		types.TxValueKeyFeePayer: payer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := feeDelegatedTx.SignWithKeys(signer, []*ecdsa.PrivateKey{key}); err != nil {
+		t.Fatal(err)
+	}
+	if err := feeDelegatedTx.SignFeePayerWithKeys(signer, []*ecdsa.PrivateKey{payerKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 3, func(i int, gen *blockchain.BlockGen) {
+		switch i {
+		case 0:
+			gen.AddTx(legacyTx)
+		case 1:
+			gen.AddTx(feeDelegatedTx)
+		}
+	})
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "receipts.json.gz")
+	if err := exportReceiptsToFile(db, 0, 3, path); err != nil {
+		t.Fatalf("failed to export receipts: %v", err)
+	}
+	if err := exportReceiptsToFile(db, 0, 10, path+".missing"); err == nil {
+		t.Error("exporting the receipts of a missing block should fail")
+	}
+
+	receipts := readExportedReceipts(t, path)
+	txs := []*types.Transaction{legacyTx, feeDelegatedTx}
+	if len(receipts) != len(txs) {
+		t.Fatalf("receipt count mismatch: have %d, want %d", len(receipts), len(txs))
+	}
+	for i, tx := range txs {
+		// Each exported receipt matches the result of klay_getTransactionReceipt.
+		enc, err := json.Marshal(api.RpcOutputReceipt(chain.GetTxLookupInfoAndReceipt(tx.Hash())))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want map[string]interface{}
+		if err := json.Unmarshal(enc, &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(receipts[i], want) {
+			t.Errorf("receipt %d mismatch:\nhave %v\nwant %v", i, receipts[i], want)
+		}
+	}
+	if have := receipts[1]["feePayer"]; have != common.ToHex(payer[:]) {
+		t.Errorf("fee payer mismatch: have %v, want %x", have, payer)
+	}
+}
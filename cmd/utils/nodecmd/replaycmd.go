@@ -0,0 +1,114 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"errors"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/cmd/utils"
+	istanbulBackend "github.com/klaytn/klaytn/consensus/istanbul/backend"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var ReplayRecentCommand = cli.Command{
+	Action:    utils.MigrateFlags(replayRecent),
+	Name:      "replay-recent",
+	Usage:     "Re-execute the most recent blocks and compare the results with the stored headers",
+	ArgsUsage: " ",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.NoPartitionedDBFlag,
+		utils.NumStateTriePartitionsFlag,
+		utils.LevelDBCompressionTypeFlag,
+		utils.ReplayCountFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+The replay-recent command re-executes the most recent --count canonical blocks on
+top of their parent states with the current code, and compares the gas used, the
+receipt roots and the state roots with the stored headers. It reports the first
+block which diverges, so that a node upgrade can be checked to reproduce the chain
+before it is deployed. The node must be stopped while replaying.
+
+If the parent states of the blocks are not stored, the blocks since the latest
+stored state are re-executed as well. The re-executed states are not written to
+the database.`,
+}
+
+var errNoReplayCount = errors.New("--count should be greater than 0")
+
+func replayRecent(ctx *cli.Context) error {
+	count := ctx.GlobalUint64(utils.ReplayCountFlag.Name)
+	if count == 0 {
+		log.Fatalf("Failed to replay blocks: %v", errNoReplayCount)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	chainDB := openChainDB(stack, cfg)
+	defer chainDB.Close()
+
+	chainConfig, _, err := blockchain.SetupGenesisBlock(chainDB, nil, cfg.CN.NetworkId, cfg.CN.IsPrivate)
+	if _, ok := err.(*params.ConfigCompatError); err != nil && !ok {
+		log.Fatalf("Failed to load the chain config: %v", err)
+	}
+	if chainConfig.Istanbul != nil {
+		types.EngineType = types.Engine_IBFT
+	}
+	if chainConfig.Governance == nil {
+		chainConfig.Governance = governance.GetDefaultGovernanceConfig(params.UseIstanbul)
+	}
+
+	// Set up the chain as the node does, so that the blocks are processed with the
+	// same consensus engine and governance.
+	gov := governance.NewGovernance(chainConfig, chainDB)
+	engine := istanbulBackend.New(cfg.CN.Rewardbase, &cfg.CN.Istanbul, cfg.Node.NodeKey(), chainDB, gov, node.ENDPOINTNODE)
+	chain, err := blockchain.NewBlockChain(chainDB, nil, chainConfig, engine, vm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to open the chain: %v", err)
+	}
+	defer chain.Stop()
+	gov.SetBlockchain(chain)
+	if chain.Config().Istanbul != nil {
+		chain.Config().Istanbul.ProposerPolicy = gov.ChainConfig.Istanbul.ProposerPolicy
+	}
+	if chain.Config().Governance.Reward != nil {
+		chain.Config().Governance.Reward.UseGiniCoeff = gov.ChainConfig.Governance.Reward.UseGiniCoeff
+	}
+
+	head := chain.CurrentBlock().NumberU64()
+	if head == 0 {
+		log.Fatalf("Failed to replay blocks: no block after the genesis")
+	}
+	first := uint64(1)
+	if head >= count {
+		first = head - count + 1
+	}
+	logger.Info("Replaying recent blocks", "from", first, "to", head)
+
+	replayed, err := chain.ReplayBlocks(first, head)
+	if err != nil {
+		log.Fatalf("Replay diverged after %d matching blocks: %v", replayed, err)
+	}
+	logger.Info("Replayed recent blocks without divergence", "blocks", replayed)
+	return nil
+}
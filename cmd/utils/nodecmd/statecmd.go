@@ -0,0 +1,158 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/storage/database"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"strconv"
+)
+
+var (
+	ExportStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportState),
+		Name:      "export-state",
+		Usage:     "Export the state at a block into a file",
+		ArgsUsage: "<blockNumber> <filename>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.NoPartitionedDBFlag,
+			utils.NumStateTriePartitionsFlag,
+			utils.LevelDBCompressionTypeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-state command writes all accounts and their code and storage at the
+given canonical block into a gzipped RLP file. Accounts are exported in their
+serialized form, so that account types and account keys are preserved.
+The state of the block must be stored in the database.`,
+	}
+
+	ImportStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(importState),
+		Name:      "import-state",
+		Usage:     "Initialize a new genesis block with the state of an export-state file",
+		ArgsUsage: "<genesisPath> <filename>",
+		Flags: []cli.Flag{
+			utils.DbTypeFlag,
+			utils.NoPartitionedDBFlag,
+			utils.NumStateTriePartitionsFlag,
+			utils.LevelDBCompressionTypeFlag,
+			utils.DataDirFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-state command initializes a new genesis block like the init command,
+but the genesis state is loaded from a file written by export-state instead of
+the alloc of the genesis file. The root of the imported state is verified
+against the root recorded in the file.`,
+	}
+)
+
+func exportState(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		log.Fatalf("This command requires two arguments: <blockNumber> <filename>")
+	}
+	number, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid block number: %v", err)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	chainDB := openChainDB(stack, cfg)
+	defer chainDB.Close()
+
+	if err := exportStateToFile(chainDB, number, ctx.Args().Get(1)); err != nil {
+		log.Fatalf("Failed to export state: %v", err)
+	}
+	return nil
+}
+
+// exportStateToFile writes the state at the given canonical block into a gzipped file.
+func exportStateToFile(db database.DBManager, number uint64, path string) error {
+	header := db.ReadHeader(db.ReadCanonicalHash(number), number)
+	if header == nil {
+		return fmt.Errorf("block %d is not found", number)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := gzip.NewWriter(file)
+
+	accounts, err := state.ExportState(state.NewDatabase(db), header.Root, writer)
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	logger.Info("Exported state", "number", number, "root", header.Root, "accounts", accounts, "file", path)
+	return nil
+}
+
+func importState(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		log.Fatalf("This command requires two arguments: <genesisPath> <filename>")
+	}
+	genesis := readGenesis(ctx.Args().Get(0))
+	if len(genesis.Alloc) > 0 {
+		logger.Warn("The alloc of the genesis file is ignored", "accounts", len(genesis.Alloc))
+	}
+
+	path := ctx.Args().Get(1)
+	writeGenesis(ctx, genesis, func(db database.DBManager) error {
+		root, err := importStateFromFile(db, path)
+		if err != nil {
+			return err
+		}
+		genesis.StateRoot = root
+		return nil
+	})
+	return nil
+}
+
+// importStateFromFile loads the state of a gzipped file written by exportStateToFile
+// into the database and returns its root.
+func importStateFromFile(db database.DBManager, path string) (common.Hash, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer reader.Close()
+
+	root, accounts, err := state.ImportState(state.NewDatabase(db), reader)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	logger.Info("Imported state", "root", root, "accounts", accounts, "file", path)
+	return root, nil
+}
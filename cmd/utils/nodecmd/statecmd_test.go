@@ -0,0 +1,72 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportImportStateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-export-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srcDB := database.NewMemoryDBManager()
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	headers := makePruneTestChain(t, srcDB, 4, addr)
+
+	path := filepath.Join(dir, "state.rlp.gz")
+	if err := exportStateToFile(srcDB, 2, path); err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	if err := exportStateToFile(srcDB, 10, path+".missing"); err == nil {
+		t.Error("exporting the state of a missing block should fail")
+	}
+
+	dstDB := database.NewMemoryDBManager()
+	root, err := importStateFromFile(dstDB, path)
+	if err != nil {
+		t.Fatalf("failed to import state: %v", err)
+	}
+	if root != headers[2].Root {
+		t.Fatalf("state root mismatch: have %x, want %x", root, headers[2].Root)
+	}
+
+	// The imported state becomes the state of the genesis block.
+	genesis := &blockchain.Genesis{Config: params.TestChainConfig, StateRoot: root}
+	block := genesis.MustCommit(dstDB)
+	if block.Root() != root {
+		t.Errorf("genesis root mismatch: have %x, want %x", block.Root(), root)
+	}
+	statedb, err := state.New(block.Root(), state.NewDatabase(dstDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := statedb.GetBalance(addr); balance.Uint64() != 3 {
+		t.Errorf("balance mismatch: have %v, want 3", balance)
+	}
+}
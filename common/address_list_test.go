@@ -0,0 +1,56 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAddressList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-address-list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "addresses")
+	content := "# listed accounts\n0x1000000000000000000000000000000000000001\n\n  0x1000000000000000000000000000000000000002  \n"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := LoadAddressList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Address{
+		HexToAddress("0x1000000000000000000000000000000000000001"),
+		HexToAddress("0x1000000000000000000000000000000000000002"),
+	}
+	if len(addrs) != len(want) || addrs[0] != want[0] || addrs[1] != want[1] {
+		t.Errorf("addresses mismatch: have %v, want %v", addrs, want)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("0xinvalid\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAddressList(path); err == nil {
+		t.Error("loading an invalid address should fail")
+	}
+}
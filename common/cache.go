@@ -67,7 +67,10 @@ func getPhysicalMemorySize() int {
 	}
 }
 
-// TODO-Klaytn-Storage WriteThroughCaching flag should be stored to proper place.
+// WriteThroughCaching enables write-through caching of every DBManager.
+//
+// Deprecated: use DBConfig.WriteThroughCaching of the storage/database package,
+// which enables it for a single DBManager.
 var WriteThroughCaching = false
 
 type CacheKey interface {
@@ -78,6 +81,7 @@ type Cache interface {
 	Add(key CacheKey, value interface{}) (evicted bool)
 	Get(key CacheKey) (value interface{}, ok bool)
 	Contains(key CacheKey) bool
+	Remove(key CacheKey)
 	Purge()
 }
 
@@ -182,6 +186,11 @@ func (cache *lruShardCache) Contains(key CacheKey) bool {
 	return cache.shards[shardIndex].Contains(key)
 }
 
+func (cache *lruShardCache) Remove(key CacheKey) {
+	shardIndex := key.getShardIndex(cache.shardIndexMask)
+	cache.shards[shardIndex].Remove(key)
+}
+
 func (cache *lruShardCache) Purge() {
 	for _, shard := range cache.shards {
 		s := shard
@@ -306,8 +315,14 @@ func (c ARCConfig) newCache() (Cache, error) {
 
 // calculateScale returns the scale of the cache.
 // The scale of the cache is obtained by multiplying (MemorySize / minimumMemorySize), (scaleByCacheUsageLevel / 100), and (CacheScale / 100).
+// Like CacheSizes.Scale, it is at least 1 unless CacheScale disables the caches,
+// so that small memory sizes do not make the caches empty.
 func calculateScale() int {
-	return CacheScale * ScaleByCacheUsageLevel * TotalPhysicalMemGB / minimumMemorySize / 100 / 100
+	scale := CacheScale * ScaleByCacheUsageLevel * TotalPhysicalMemGB / minimumMemorySize / 100 / 100
+	if scale < 1 && CacheScale > 0 {
+		return 1
+	}
+	return scale
 }
 
 // GetScaleByCacheUsageLevel returns the scale according to cacheUsageLevel
@@ -324,6 +339,57 @@ func GetScaleByCacheUsageLevel(cacheUsageLevelFlag string) (int, error) {
 	}
 }
 
+// CacheSizer translates a cache usage level and a physical memory size into
+// the sizes of the caches of a node.
+type CacheSizer struct {
+	UsageLevel    string // Cache usage level: "saving", "normal" or "extreme". Empty means "saving"
+	MemoryGB      int    // Physical memory size in GB
+	TrieCacheSize int    // Trie cache size (MiB) for the "saving" level with 16GB of memory
+}
+
+// CacheSizes are the cache sizes recommended by a CacheSizer. Caches grow with
+// the usage level and in proportion to the memory beyond 16GB; for example,
+// the "extreme" level with 32GB of memory makes caches 6 times larger than the
+// "saving" level with 16GB.
+type CacheSizes struct {
+	// ScaleByCacheUsageLevel is the scale (%) of the usage level: 100 for
+	// "saving", 200 for "normal" and 300 for "extreme".
+	ScaleByCacheUsageLevel int
+
+	// Scale is the multiplier NewCache applies to the preset sizes of the
+	// block, header, receipt and peer known caches when CacheScale is 100.
+	// It is at least 1.
+	Scale int
+
+	// TrieCacheSize is the size (MiB) of the trie node cache.
+	TrieCacheSize int
+}
+
+// Sizes returns the cache sizes for the usage level and the memory size of s.
+func (s CacheSizer) Sizes() (CacheSizes, error) {
+	level := s.UsageLevel
+	if level == "" {
+		level = defaultCacheUsageLevel
+	}
+	levelScale, err := GetScaleByCacheUsageLevel(level)
+	if err != nil {
+		return CacheSizes{}, err
+	}
+	if s.MemoryGB <= 0 {
+		return CacheSizes{}, errors.New("memory size should be positive")
+	}
+
+	scale := levelScale * s.MemoryGB / minimumMemorySize / 100
+	if scale < 1 {
+		scale = 1
+	}
+	return CacheSizes{
+		ScaleByCacheUsageLevel: levelScale,
+		Scale:                  scale,
+		TrieCacheSize:          s.TrieCacheSize * levelScale * s.MemoryGB / minimumMemorySize / 100,
+	}, nil
+}
+
 type GovernanceCacheKey string
 
 func (g GovernanceCacheKey) getShardIndex(shardMask int) int {
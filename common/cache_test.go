@@ -0,0 +1,79 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"testing"
+)
+
+func TestCacheSizer(t *testing.T) {
+	tests := []struct {
+		level    string
+		memoryGB int
+		sizes    CacheSizes
+	}{
+		{"", 16, CacheSizes{ScaleByCacheUsageLevel: 100, Scale: 1, TrieCacheSize: 512}},
+		{"saving", 8, CacheSizes{ScaleByCacheUsageLevel: 100, Scale: 1, TrieCacheSize: 256}},
+		{"saving", 16, CacheSizes{ScaleByCacheUsageLevel: 100, Scale: 1, TrieCacheSize: 512}},
+		{"saving", 64, CacheSizes{ScaleByCacheUsageLevel: 100, Scale: 4, TrieCacheSize: 2048}},
+		{"normal", 16, CacheSizes{ScaleByCacheUsageLevel: 200, Scale: 2, TrieCacheSize: 1024}},
+		{"normal", 24, CacheSizes{ScaleByCacheUsageLevel: 200, Scale: 3, TrieCacheSize: 1536}},
+		{"extreme", 16, CacheSizes{ScaleByCacheUsageLevel: 300, Scale: 3, TrieCacheSize: 1536}},
+		{"extreme", 32, CacheSizes{ScaleByCacheUsageLevel: 300, Scale: 6, TrieCacheSize: 3072}},
+		{"extreme", 128, CacheSizes{ScaleByCacheUsageLevel: 300, Scale: 24, TrieCacheSize: 12288}},
+	}
+	for _, tt := range tests {
+		sizes, err := CacheSizer{UsageLevel: tt.level, MemoryGB: tt.memoryGB, TrieCacheSize: 512}.Sizes()
+		if err != nil {
+			t.Errorf("level %q, memory %dGB: unexpected error %v", tt.level, tt.memoryGB, err)
+		}
+		if sizes != tt.sizes {
+			t.Errorf("level %q, memory %dGB: sizes mismatch: have %+v, want %+v", tt.level, tt.memoryGB, sizes, tt.sizes)
+		}
+	}
+
+	if _, err := (CacheSizer{UsageLevel: "huge", MemoryGB: 16}).Sizes(); err == nil {
+		t.Error("unknown usage level should fail")
+	}
+	if _, err := (CacheSizer{UsageLevel: "saving", MemoryGB: 0}).Sizes(); err == nil {
+		t.Error("zero memory size should fail")
+	}
+}
+
+func TestCalculateScaleBySizer(t *testing.T) {
+	defer func(cacheScale, levelScale, memoryGB int) {
+		CacheScale, ScaleByCacheUsageLevel, TotalPhysicalMemGB = cacheScale, levelScale, memoryGB
+	}(CacheScale, ScaleByCacheUsageLevel, TotalPhysicalMemGB)
+
+	CacheScale = 100
+	for _, level := range []string{"saving", "normal", "extreme"} {
+		for _, memoryGB := range []int{4, 8, 16, 24, 64} {
+			sizes, err := CacheSizer{UsageLevel: level, MemoryGB: memoryGB}.Sizes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ScaleByCacheUsageLevel, TotalPhysicalMemGB = sizes.ScaleByCacheUsageLevel, memoryGB
+
+			if scale := calculateScale(); scale != sizes.Scale {
+				t.Errorf("level %q, memory %dGB: scale mismatch: have %d, want %d", level, memoryGB, scale, sizes.Scale)
+			}
+			if _, err := (LRUConfig{CacheSize: 10}).newCache(); err != nil {
+				t.Errorf("level %q, memory %dGB: failed to create a cache: %v", level, memoryGB, err)
+			}
+		}
+	}
+}
@@ -29,7 +29,9 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/consensus"
 	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/governance"
 	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/ser/rlp"
 	"math/big"
 	"reflect"
 )
@@ -128,6 +130,87 @@ func (api *API) Discard(address common.Address) {
 	delete(api.istanbul.candidates, address)
 }
 
+// maxVoteHistoryRange is the maximum number of blocks GetVoteHistory scans in a call.
+const maxVoteHistoryRange = 10000
+
+var (
+	errInvalidVoteHistoryRange  = errors.New("fromBlock should not be larger than toBlock")
+	errVoteHistoryRangeTooLarge = fmt.Errorf("number of requested blocks should not exceed %d", maxVoteHistoryRange)
+)
+
+// VoteHistoryEntry is a governance vote found in a block header.
+type VoteHistoryEntry struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	Proposer    common.Address `json:"proposer"`
+	Validator   common.Address `json:"validator"`
+	Key         string         `json:"key"`
+	Value       interface{}    `json:"value"`
+}
+
+// GetVoteHistory returns the governance votes cast in blocks between fromBlock and toBlock, inclusive.
+func (api *API) GetVoteHistory(fromBlock, toBlock rpc.BlockNumber) ([]VoteHistoryEntry, error) {
+	latest := api.chain.CurrentHeader().Number.Uint64()
+	from, err := api.resolveVoteHistoryNumber(fromBlock, latest)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.resolveVoteHistoryNumber(toBlock, latest)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errInvalidVoteHistoryRange
+	}
+	if to-from >= maxVoteHistoryRange {
+		return nil, errVoteHistoryRangeTooLarge
+	}
+
+	votes := []VoteHistoryEntry{}
+	for num := from; num <= to; num++ {
+		header := api.chain.GetHeaderByNumber(num)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		if len(header.Vote) == 0 {
+			continue
+		}
+		gVote := new(governance.GovernanceVote)
+		if err := rlp.DecodeBytes(header.Vote, gVote); err != nil {
+			logger.Warn("Failed to decode a vote in the header", "number", num, "err", err)
+			continue
+		}
+		if gVote, err = api.istanbul.governance.ParseVoteValue(gVote); err != nil {
+			logger.Warn("Failed to parse a vote value in the header", "number", num, "err", err)
+			continue
+		}
+		proposer, err := api.istanbul.Author(header)
+		if err != nil {
+			return nil, err
+		}
+		votes = append(votes, VoteHistoryEntry{
+			BlockNumber: num,
+			Proposer:    proposer,
+			Validator:   gVote.Validator,
+			Key:         gVote.Key,
+			Value:       gVote.Value,
+		})
+	}
+	return votes, nil
+}
+
+func (api *API) resolveVoteHistoryNumber(number rpc.BlockNumber, latest uint64) (uint64, error) {
+	switch number {
+	case rpc.PendingBlockNumber:
+		return 0, errPendingNotAllowed
+	case rpc.LatestBlockNumber:
+		return latest, nil
+	}
+	if uint64(number) > latest {
+		return 0, errUnknownBlock
+	}
+	return uint64(number), nil
+}
+
 // API extended by Klaytn developers
 type APIExtension struct {
 	chain    consensus.ChainReader
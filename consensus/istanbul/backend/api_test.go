@@ -0,0 +1,135 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"math/big"
+	"testing"
+)
+
+// headerChain serves a fixed list of headers indexed by block number.
+type headerChain struct {
+	consensus.ChainReader
+	config  *params.ChainConfig
+	headers []*types.Header
+}
+
+func (c *headerChain) Config() *params.ChainConfig {
+	return c.config
+}
+
+func (c *headerChain) CurrentHeader() *types.Header {
+	return c.headers[len(c.headers)-1]
+}
+
+func (c *headerChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+func (c *headerChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
+func TestGetVoteHistory(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	target := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+
+	gov := governance.NewGovernance(&params.ChainConfig{Istanbul: &params.IstanbulConfig{}}, nil)
+	sb := New(proposer, istanbul.DefaultConfig, key, nil, gov, p2p.CONSENSUSNODE).(*backend)
+
+	if !gov.AddVote("governance.addvalidator", target) {
+		t.Fatal("failed to add a validator vote")
+	}
+
+	chain := &headerChain{}
+	for i := int64(0); i < 4; i++ {
+		header := &types.Header{Number: big.NewInt(i), BlockScore: big.NewInt(1)}
+		if i == 2 {
+			header.Vote = gov.GetEncodedVote(proposer, uint64(i))
+		}
+		extra, err := prepareExtra(header, []common.Address{proposer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		header.Extra = extra
+		seal, err := sb.Sign(sigHash(header).Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeSeal(header, seal); err != nil {
+			t.Fatal(err)
+		}
+		chain.headers = append(chain.headers, header)
+	}
+	api := &API{chain: chain, istanbul: sb}
+
+	votes, err := api.GetVoteHistory(0, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 1 {
+		t.Fatalf("vote count mismatch: have %d, want 1", len(votes))
+	}
+	vote := votes[0]
+	if vote.BlockNumber != 2 {
+		t.Errorf("block number mismatch: have %d, want 2", vote.BlockNumber)
+	}
+	if vote.Proposer != proposer {
+		t.Errorf("proposer mismatch: have %s, want %s", vote.Proposer.String(), proposer.String())
+	}
+	if vote.Validator != proposer {
+		t.Errorf("voter mismatch: have %s, want %s", vote.Validator.String(), proposer.String())
+	}
+	if vote.Key != "governance.addvalidator" {
+		t.Errorf("key mismatch: have %s, want governance.addvalidator", vote.Key)
+	}
+	if vote.Value != target {
+		t.Errorf("target mismatch: have %v, want %s", vote.Value, target.String())
+	}
+
+	if votes, err := api.GetVoteHistory(3, 3); err != nil || len(votes) != 0 {
+		t.Errorf("expected no votes in block 3, have %v (err: %v)", votes, err)
+	}
+	if _, err := api.GetVoteHistory(3, 1); err != errInvalidVoteHistoryRange {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidVoteHistoryRange)
+	}
+	if _, err := api.GetVoteHistory(0, 4); err != errUnknownBlock {
+		t.Errorf("error mismatch: have %v, want %v", err, errUnknownBlock)
+	}
+	if _, err := api.GetVoteHistory(0, rpc.PendingBlockNumber); err != errPendingNotAllowed {
+		t.Errorf("error mismatch: have %v, want %v", err, errPendingNotAllowed)
+	}
+}
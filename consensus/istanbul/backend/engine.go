@@ -48,12 +48,12 @@ const (
 	//inmemoryPeers      = 40
 	//inmemoryMessages   = 1024
 
-	checkpointInterval = 1024 // Number of blocks after which to save the vote snapshot to the database
+	checkpointInterval = 1024 // Default number of blocks after which to save the vote snapshot to the database
 	inmemorySnapshots  = 496  // Number of recent vote snapshots to keep in memory
 	inmemoryPeers      = 200
 	inmemoryMessages   = 4096
 
-	allowedFutureBlockTime = 1 * time.Second // Max time from current time allowed for blocks, before they're considered future blocks
+	allowedFutureBlockTime = 1 * time.Second // Max time from current time allowed for blocks if MaxFutureDrift is not configured
 )
 
 var (
@@ -109,6 +109,25 @@ func (sb *backend) VerifyHeader(chain consensus.ChainReader, header *types.Heade
 	return sb.verifyHeader(chain, header, nil)
 }
 
+// maxFutureDrift returns the max time a block's timestamp may be ahead of the local
+// clock before the block is considered a future block.
+func (sb *backend) maxFutureDrift() time.Duration {
+	if sb.config.MaxFutureDrift <= 0 {
+		return allowedFutureBlockTime
+	}
+	return sb.config.MaxFutureDrift
+}
+
+// snapshotInterval returns the number of blocks after which a vote snapshot
+// is saved to the database. Checkpoints stored at the default interval are
+// still looked up if it is changed.
+func (sb *backend) snapshotInterval() uint64 {
+	if sb.config.SnapshotInterval == 0 {
+		return checkpointInterval
+	}
+	return sb.config.SnapshotInterval
+}
+
 // verifyHeader checks whether a header conforms to the consensus rules.The
 // caller may optionally pass in a batch of parents (ascending order) to avoid
 // looking those up from the database. This is useful for concurrently verifying
@@ -119,7 +138,7 @@ func (sb *backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 	}
 
 	// Don't waste time checking blocks from the future
-	if header.Time.Cmp(big.NewInt(now().Add(allowedFutureBlockTime).Unix())) > 0 {
+	if header.Time.Cmp(big.NewInt(now().Add(sb.maxFutureDrift()).Unix())) > 0 {
 		return consensus.ErrFutureBlock
 	}
 
@@ -602,8 +621,10 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 			snap = s.(*Snapshot)
 			break
 		}
-		// If an on-disk checkpoint snapshot can be found, use that
-		if number%checkpointInterval == 0 {
+		// If an on-disk checkpoint snapshot can be found, use that. The checkpoints
+		// stored at the default interval are looked up as well, so that they are
+		// still found after the snapshot interval is changed.
+		if number%sb.snapshotInterval() == 0 || number%checkpointInterval == 0 {
 			if s, err := loadSnapshot(sb.db, hash); err == nil {
 				logger.Trace("Loaded voting snapshot form disk", "number", number, "hash", hash)
 				snap = s
@@ -652,11 +673,14 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 		}
 	}
 
-	// If we've generated a new checkpoint snapshot, save to disk
+	// The governance state is written at the default interval regardless of the snapshot interval
 	if snap.Number%checkpointInterval == 0 && len(headers) > 0 {
 		if sb.governance.CanWriteGovernanceState(snap.Number) {
 			sb.governance.WriteGovernanceState(snap.Number, true)
 		}
+	}
+	// If we've generated a new checkpoint snapshot, save to disk
+	if snap.Number%sb.snapshotInterval() == 0 && len(headers) > 0 {
 		if err = snap.store(sb.db); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,195 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestVerifyHeaderMaxFutureDrift(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := *istanbul.DefaultConfig
+	config.MaxFutureDrift = 10 * time.Second
+	gov := governance.NewGovernance(&params.ChainConfig{Istanbul: &params.IstanbulConfig{}}, nil)
+	sb := New(crypto.PubkeyToAddress(key.PublicKey), &config, key, nil, gov, p2p.CONSENSUSNODE).(*backend)
+
+	current := time.Unix(1000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	testCases := []struct {
+		drift  time.Duration
+		future bool
+	}{
+		{0, false},
+		{config.MaxFutureDrift, false},
+		{config.MaxFutureDrift + time.Second, true},
+	}
+	for _, tc := range testCases {
+		header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(current.Add(tc.drift).Unix())}
+		err := sb.verifyHeader(nil, header, nil)
+		if future := err == consensus.ErrFutureBlock; future != tc.future {
+			t.Errorf("drift %v: future block mismatch: have %v (err %v), want %v", tc.drift, future, err, tc.future)
+		}
+	}
+
+	// The default drift is used if it is not configured.
+	config.MaxFutureDrift = 0
+	if drift := sb.maxFutureDrift(); drift != allowedFutureBlockTime {
+		t.Errorf("default drift mismatch: have %v, want %v", drift, allowedFutureBlockTime)
+	}
+}
+
+// newSealedHeaders returns the headers from the genesis to the given block number,
+// proposed and sealed by the given backend.
+func newSealedHeaders(t *testing.T, sb *backend, number int64) []*types.Header {
+	var headers []*types.Header
+	for i := int64(0); i <= number; i++ {
+		header := &types.Header{Number: big.NewInt(i), BlockScore: big.NewInt(1), Time: big.NewInt(i)}
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash()
+		}
+		extra, err := prepareExtra(header, []common.Address{sb.address})
+		if err != nil {
+			t.Fatal(err)
+		}
+		header.Extra = extra
+		seal, err := sb.Sign(sigHash(header).Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeSeal(header, seal); err != nil {
+			t.Fatal(err)
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+func TestSnapshotInterval(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := *istanbul.DefaultConfig
+	config.SnapshotInterval = 4
+	chainConfig := &params.ChainConfig{
+		Istanbul: &params.IstanbulConfig{Epoch: istanbul.DefaultConfig.Epoch},
+		Governance: &params.GovernanceConfig{Reward: &params.RewardConfig{
+			StakingUpdateInterval:  params.StakingUpdateInterval(),
+			ProposerUpdateInterval: params.ProposerUpdateInterval(),
+		}},
+	}
+	db := database.NewMemoryDBManager()
+	sb := New(proposer, &config, key, db, governance.NewGovernance(chainConfig, db), p2p.CONSENSUSNODE).(*backend)
+
+	chain := &headerChain{config: chainConfig, headers: newSealedHeaders(t, sb, 10)}
+
+	// Snapshots are retrieved block by block as the chain grows.
+	var want *Snapshot
+	for _, header := range chain.headers[1:] {
+		if want, err = sb.snapshot(chain, header.Number.Uint64(), header.Hash(), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, header := range chain.headers {
+		_, err := loadSnapshot(db, header.Hash())
+		if stored, checkpoint := err == nil, header.Number.Uint64()%config.SnapshotInterval == 0; stored != checkpoint {
+			t.Errorf("block %d: snapshot stored mismatch: have %v, want %v", header.Number, stored, checkpoint)
+		}
+	}
+
+	// After a restart, the snapshot is recomputed from the nearest checkpoint
+	// without the headers before it.
+	restarted := New(proposer, &config, key, db, governance.NewGovernance(chainConfig, db), p2p.CONSENSUSNODE).(*backend)
+	pruned := &headerChain{config: chainConfig, headers: make([]*types.Header, len(chain.headers))}
+	copy(pruned.headers[8:], chain.headers[8:])
+
+	head := chain.headers[10]
+	snap, err := restarted.snapshot(pruned, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Number != want.Number || snap.Hash != want.Hash {
+		t.Errorf("snapshot mismatch: have %d (%x), want %d (%x)", snap.Number, snap.Hash, want.Number, want.Hash)
+	}
+	if vals := snap.validators(); len(vals) != 1 || vals[0] != proposer {
+		t.Errorf("validators mismatch: have %v, want [%v]", vals, proposer)
+	}
+}
+
+// Tests that the checkpoints stored at the default interval are still used after
+// the snapshot interval is changed.
+func TestSnapshotIntervalChange(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+
+	chainConfig := &params.ChainConfig{
+		Istanbul: &params.IstanbulConfig{Epoch: istanbul.DefaultConfig.Epoch},
+		Governance: &params.GovernanceConfig{Reward: &params.RewardConfig{
+			StakingUpdateInterval:  params.StakingUpdateInterval(),
+			ProposerUpdateInterval: params.ProposerUpdateInterval(),
+		}},
+	}
+	db := database.NewMemoryDBManager()
+	sb := New(proposer, istanbul.DefaultConfig, key, db, governance.NewGovernance(chainConfig, db), p2p.CONSENSUSNODE).(*backend)
+
+	chain := &headerChain{config: chainConfig, headers: newSealedHeaders(t, sb, checkpointInterval+6)}
+	checkpoint := chain.headers[checkpointInterval]
+	if _, err := sb.snapshot(chain, checkpoint.Number.Uint64(), checkpoint.Hash(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSnapshot(db, checkpoint.Hash()); err != nil {
+		t.Fatalf("checkpoint at the default interval not stored: %v", err)
+	}
+
+	// The node restarts with an interval the stored checkpoint is not a multiple of,
+	// and without the headers before the checkpoint.
+	config := *istanbul.DefaultConfig
+	config.SnapshotInterval = 5
+	restarted := New(proposer, &config, key, db, governance.NewGovernance(chainConfig, db), p2p.CONSENSUSNODE).(*backend)
+	pruned := &headerChain{config: chainConfig, headers: make([]*types.Header, len(chain.headers))}
+	copy(pruned.headers[checkpointInterval:], chain.headers[checkpointInterval:])
+
+	head := chain.headers[len(chain.headers)-1]
+	snap, err := restarted.snapshot(pruned, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		t.Fatalf("snapshot not recomputed from the default checkpoint: %v", err)
+	}
+	if snap.Number != head.Number.Uint64() || snap.Hash != head.Hash() {
+		t.Errorf("snapshot mismatch: have %d (%x), want %d (%x)", snap.Number, snap.Hash, head.Number, head.Hash())
+	}
+}
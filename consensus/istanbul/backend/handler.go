@@ -43,10 +43,10 @@ var (
 func (sb *backend) Protocol() consensus.Protocol {
 	return consensus.Protocol{
 		Name:     "istanbul",
-		Versions: []uint{64},
+		Versions: []uint{consensus.Klay66, consensus.Klay65, 64},
 		//Lengths:  []uint64{18},
 		//Lengths:  []uint64{19},  // add PoRMsg
-		Lengths: []uint64{21},
+		Lengths: []uint64{23, 21, 21},
 	}
 }
 
@@ -20,6 +20,8 @@
 
 package istanbul
 
+import "time"
+
 type ProposerPolicy uint64
 
 const (
@@ -29,17 +31,21 @@ const (
 )
 
 type Config struct {
-	RequestTimeout uint64         `toml:",omitempty"` // The timeout for each Istanbul round in milliseconds.
-	BlockPeriod    uint64         `toml:",omitempty"` // Default minimum difference between two consecutive block's timestamps in second
-	ProposerPolicy ProposerPolicy `toml:",omitempty"` // The policy for proposer selection
-	Epoch          uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
-	SubGroupSize   uint64         `toml:",omitempty"`
+	RequestTimeout   uint64         `toml:",omitempty"` // The timeout for each Istanbul round in milliseconds.
+	BlockPeriod      uint64         `toml:",omitempty"` // Default minimum difference between two consecutive block's timestamps in second
+	ProposerPolicy   ProposerPolicy `toml:",omitempty"` // The policy for proposer selection
+	Epoch            uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
+	SubGroupSize     uint64         `toml:",omitempty"`
+	MaxFutureDrift   time.Duration  `toml:",omitempty"` // Max time a block's timestamp may be ahead of the local clock before it's considered a future block
+	SnapshotInterval uint64         `toml:",omitempty"` // Number of blocks after which to save the vote snapshot to the database
 }
 
 var DefaultConfig = &Config{
-	RequestTimeout: 10000,
-	BlockPeriod:    1,
-	ProposerPolicy: RoundRobin,
-	Epoch:          30000,
-	SubGroupSize:   21,
+	RequestTimeout:   10000,
+	BlockPeriod:      1,
+	ProposerPolicy:   RoundRobin,
+	Epoch:            30000,
+	SubGroupSize:     21,
+	MaxFutureDrift:   1 * time.Second,
+	SnapshotInterval: 1024,
 }
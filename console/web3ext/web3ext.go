@@ -154,6 +154,10 @@ web3._extend({
 			name: 'nodeAddress',
 			getter: 'governance_nodeAddress',
 		}),
+		new web3._extend.Property({
+			name: 'rewardConfig',
+			getter: 'governance_rewardConfig',
+		}),
 	]
 });
 `
@@ -182,6 +186,27 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'reloadDeployerAllowList',
+			call: 'admin_reloadDeployerAllowList',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'fillNonceGaps',
+			call: 'admin_fillNonceGaps',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'dumpTxPool',
+			call: 'admin_dumpTxPool',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'loadTxPool',
+			call: 'admin_loadTxPool',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -197,6 +222,11 @@ web3._extend({
 			name: 'stopRPC',
 			call: 'admin_stopRPC'
 		}),
+		new web3._extend.Method({
+			name: 'setNamespaceEnabled',
+			call: 'admin_setNamespaceEnabled',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'startWS',
 			call: 'admin_startWS',
@@ -443,12 +473,49 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionReceiptWithTrace',
+			call: 'debug_getTransactionReceiptWithTrace',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'traceTransaction',
 			call: 'debug_traceTransaction',
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceFilter',
+			call: 'debug_traceFilter',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'recentImportFailures',
+			call: 'debug_recentImportFailures',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'getCachedHeaderNumber',
+			call: 'debug_getCachedHeaderNumber',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'invalidateHeaderNumber',
+			call: 'debug_invalidateHeaderNumber',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'compactDatabase',
+			call: 'debug_compactDatabase',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'effectiveConfig',
+			call: 'debug_effectiveConfig',
+			params: 0,
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -465,6 +532,18 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'getStorageRangeAt',
+			call: 'debug_getStorageRangeAt',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter, web3._extend.formatters.inputAddressFormatter, null, null],
+		}),
+		new web3._extend.Method({
+			name: 'findStorageChange',
+			call: 'debug_findStorageChange',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByNumber',
 			call: 'debug_getModifiedAccountsByNumber',
@@ -499,6 +578,39 @@ web3._extend({
 			name: 'clientVersion',
 			call: 'klay_clientVersion',
 		}),
+		new web3._extend.Method({
+			name: 'gasUsedStats',
+			call: 'klay_gasUsedStats',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'txTypes',
+			call: 'klay_txTypes',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'forkStatus',
+			call: 'klay_forkStatus',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'getBlockHashByNumber',
+			call: 'klay_getBlockHashByNumber',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getHeadersByRange',
+			call: 'klay_getHeadersByRange',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getGenesis',
+			call: 'klay_getGenesis',
+			params: 1,
+			inputFormatter: [null]
+		}),
 		new web3._extend.Method({
 			name: 'getBlockReceipts',
 			call: 'klay_getBlockReceipts',
@@ -511,12 +623,55 @@ web3._extend({
 				return formatted;
 			}
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionTouchedAccounts',
+			call: 'klay_getTransactionTouchedAccounts',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'syncProgress',
+			call: 'klay_syncProgress',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'submitForSponsorship',
+			call: 'klay_submitForSponsorship',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'claimSponsorship',
+			call: 'klay_claimSponsorship',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'sign',
 			call: 'klay_sign',
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'signTypedData',
+			call: 'klay_signTypedData',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'recoverTypedData',
+			call: 'klay_recoverTypedData',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'sendRawTransactionConditional',
+			call: 'klay_sendRawTransactionConditional',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'callByABI',
+			call: 'klay_callByABI',
+			params: 5,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'resend',
 			call: 'klay_resend',
@@ -560,6 +715,13 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter],
 			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
 		}),
+		new web3._extend.Method({
+			name: 'getUnitPrice',
+			call: 'klay_getUnitPrice',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
 		new web3._extend.Method({
 			name: 'accountCreated',
 			call: 'klay_accountCreated'
@@ -607,12 +769,36 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'estimateFeeSplit',
+			call: 'klay_estimateFeeSplit',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'getAccountKey',
 			call: 'klay_getAccountKey',
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getAccountsState',
+			call: 'klay_getAccountsState',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAccountCreationInfo',
+			call: 'klay_getAccountCreationInfo',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAccountKeyHistory',
+			call: 'klay_getAccountKeyHistory',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -638,6 +824,16 @@ web3._extend({
 			call: 'klay_getTransactionBySenderTxHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionStatuses',
+			call: 'klay_getTransactionStatuses',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionProof',
+			call: 'klay_getTransactionProof',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getTransactionReceiptBySenderTxHash',
 			call: 'klay_getTransactionReceiptBySenderTxHash',
@@ -721,6 +917,10 @@ web3._extend({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
 		}),
+		new web3._extend.Method({
+			name: 'pendingBlock',
+			call: 'miner_pendingBlock'
+		}),
 	],
 	properties: []
 });
@@ -828,7 +1028,22 @@ web3._extend({
 const TxPool_JS = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'contentFrom',
+			call: 'txpool_contentFrom',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'availableBalance',
+			call: 'txpool_availableBalance',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -839,6 +1054,10 @@ web3._extend({
 			name: 'inspect',
 			getter: 'txpool_inspect'
 		}),
+		new web3._extend.Property({
+			name: 'contentHash',
+			getter: 'txpool_contentHash'
+		}),
 		new web3._extend.Property({
 			name: 'status',
 			getter: 'txpool_status',
@@ -879,6 +1098,12 @@ web3._extend({
 			call: 'istanbul_getValidatorsAtHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getVoteHistory',
+			call: 'istanbul_getVoteHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'discard',
 			call: 'istanbul_discard',
@@ -1017,6 +1242,10 @@ web3._extend({
 			name: 'sendChainTxslimit',
 			getter: 'subbridge_getSentChainTxsLimit'
 		}),
+		new web3._extend.Property({
+			name: 'mainChainConnectionStatus',
+			getter: 'subbridge_getMainChainConnectionStatus'
+		}),
 		new web3._extend.Property({
 			name: 'mainChainAccountNonce',
 			getter: 'subbridge_getMainChainAccountNonce'
@@ -0,0 +1,100 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package reward
+
+import (
+	"math/big"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+)
+
+// chainHeaderReader provides the current header of the chain.
+type chainHeaderReader interface {
+	CurrentHeader() *types.Header
+}
+
+// PublicRewardAPI provides an API to access the currently effective reward configuration.
+type PublicRewardAPI struct {
+	config     *params.ChainConfig
+	chain      chainHeaderReader
+	rewardbase func() (common.Address, error)
+}
+
+// NewPublicRewardAPI creates a new API definition for the reward configuration.
+// rewardbase is used to retrieve the rewardbase of the node.
+func NewPublicRewardAPI(config *params.ChainConfig, chain chainHeaderReader, rewardbase func() (common.Address, error)) *PublicRewardAPI {
+	return &PublicRewardAPI{config: config, chain: chain, rewardbase: rewardbase}
+}
+
+// RewardConfigResult is the result of a governance_rewardConfig API call.
+type RewardConfigResult struct {
+	BlockNumber   uint64         `json:"blockNumber"`   // The next block number the configuration applies to
+	Rewardbase    common.Address `json:"rewardbase"`    // The rewardbase of this node
+	PoCAddr       common.Address `json:"pocAddr"`       // Empty if the proposer receives the PoC incentive
+	KIRAddr       common.Address `json:"kirAddr"`       // Empty if the proposer receives the KIR incentive
+	Ratio         string         `json:"ratio"`         // The raw CN/PoC/KIR ratio of the governance
+	CNRatio       int64          `json:"cnRatio"`       // The parsed ratio of the CN reward
+	PoCRatio      int64          `json:"pocRatio"`      // The parsed ratio of the PoC incentive
+	KIRRatio      int64          `json:"kirRatio"`      // The parsed ratio of the KIR incentive
+	MintingAmount *big.Int       `json:"mintingAmount"` // The amount of peb minted for each block
+	UseGiniCoeff  bool           `json:"useGiniCoeff"`
+	DeferredTxFee bool           `json:"deferredTxFee"`
+	DistributePoC bool           `json:"distributePoC"` // False if the whole block reward goes to the proposer
+}
+
+// RewardConfig returns the reward recipients and ratios which are used to distribute
+// the block reward of the next block.
+func (api *PublicRewardAPI) RewardConfig() (*RewardConfigResult, error) {
+	header := api.chain.CurrentHeader()
+	result := &RewardConfigResult{BlockNumber: header.Number.Uint64() + 1}
+
+	rewardbase, err := api.rewardbase()
+	if err != nil {
+		return nil, err
+	}
+	result.Rewardbase = rewardbase
+
+	rewardConfig := api.config.Governance.Reward
+	cn, poc, kir, err := parseRewardRatio(rewardConfig.Ratio)
+	if err != nil {
+		// Same as the block reward distribution, the default ratio is used for an invalid ratio.
+		cn, poc, kir = params.DefaultCNRewardRatio, params.DefaultPoCRewardRatio, params.DefaultKIRRewardRatio
+	}
+	result.Ratio = rewardConfig.Ratio
+	result.CNRatio, result.PoCRatio, result.KIRRatio = int64(cn), int64(poc), int64(kir)
+
+	if rewardConfig.MintingAmount == nil {
+		result.MintingAmount = new(big.Int).Set(params.DefaultMintedKLAY)
+	} else {
+		result.MintingAmount = new(big.Int).Set(rewardConfig.MintingAmount)
+	}
+	result.UseGiniCoeff = rewardConfig.UseGiniCoeff
+	result.DeferredTxFee = api.config.Governance.DeferredTxFee()
+
+	// The block reward is distributed to PoC and KIR only with the weighted random proposer policy.
+	// Otherwise, the whole block reward goes to the rewardbase of the proposer.
+	if api.config.Istanbul != nil && api.config.Istanbul.ProposerPolicy == uint64(params.WeightedRandom) {
+		result.DistributePoC = true
+		if stakingInfo := GetStakingInfoFromStakingCache(result.BlockNumber); stakingInfo != nil {
+			result.PoCAddr = stakingInfo.PoCAddr
+			result.KIRAddr = stakingInfo.KIRAddr
+		}
+	}
+	return result, nil
+}
@@ -548,3 +548,53 @@ func TestGiniReflectToExpectedCCO(t *testing.T) {
 		}
 	}
 }
+
+type testHeaderReader struct {
+	header *types.Header
+}
+
+func (r *testHeaderReader) CurrentHeader() *types.Header {
+	return r.header
+}
+
+func TestRewardConfigAPI(t *testing.T) {
+	initStakingCache()
+	defer initStakingCache()
+
+	config := &params.ChainConfig{Istanbul: governance.GetDefaultIstanbulConfig(), Governance: governance.GetDefaultGovernanceConfig(params.UseIstanbul)}
+	config.Istanbul.ProposerPolicy = params.WeightedRandom
+	config.Governance.Reward.Ratio = "50/30/20"
+	config.Governance.Reward.MintingAmount = big.NewInt(1234)
+
+	var (
+		currentBlock = uint64(500000)
+		rewardbase   = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		pocAddr      = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		kirAddr      = common.HexToAddress("0x0000000000000000000000000000000000000003")
+	)
+	stakingCache.add(&StakingInfo{BlockNum: params.CalcStakingBlockNumber(currentBlock + 1), PoCAddr: pocAddr, KIRAddr: kirAddr})
+
+	chain := &testHeaderReader{&types.Header{Number: new(big.Int).SetUint64(currentBlock)}}
+	api := NewPublicRewardAPI(config, chain, func() (common.Address, error) { return rewardbase, nil })
+
+	result, err := api.RewardConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, currentBlock+1, result.BlockNumber)
+	assert.Equal(t, rewardbase, result.Rewardbase)
+	assert.Equal(t, pocAddr, result.PoCAddr)
+	assert.Equal(t, kirAddr, result.KIRAddr)
+	assert.Equal(t, "50/30/20", result.Ratio)
+	assert.Equal(t, int64(50), result.CNRatio)
+	assert.Equal(t, int64(30), result.PoCRatio)
+	assert.Equal(t, int64(20), result.KIRRatio)
+	assert.Equal(t, big.NewInt(1234), result.MintingAmount)
+	assert.True(t, result.DistributePoC)
+
+	// The whole block reward goes to the proposer with other proposer policies.
+	config.Istanbul.ProposerPolicy = params.RoundRobin
+	result, err = api.RewardConfig()
+	assert.NoError(t, err)
+	assert.False(t, result.DistributePoC)
+	assert.Equal(t, common.Address{}, result.PoCAddr)
+	assert.Equal(t, common.Address{}, result.KIRAddr)
+}
@@ -46,6 +46,8 @@ var (
 	MaxReceiptFetch = 256 // Amount of transaction receipts to allow fetching per request
 	MaxStateFetch   = 384 // Amount of node state values to allow fetching per request
 
+	MaxStateRangeFetch = 4 * MaxStateFetch // Amount of node state values to allow serving per range request
+
 	MaxForkAncestry  = 3 * params.EpochDuration // Maximum chain reorganisation
 	rttMinEstimate   = 2 * time.Second          // Minimum round-trip time to target for download requests
 	rttMaxEstimate   = 20 * time.Second         // Maximum round-trip time to target for download requests
@@ -99,13 +101,16 @@ var (
 )
 
 type Downloader struct {
-	mode SyncMode       // Synchronisation mode defining the strategy used (per sync cycle)
-	mux  *event.TypeMux // Event multiplexer to announce sync operation events
+	mode     SyncMode       // Synchronisation mode defining the strategy used (per sync cycle)
+	snapSync bool           // Whether the state of a fast sync is downloaded by ranges (per sync cycle)
+	mux      *event.TypeMux // Event multiplexer to announce sync operation events
 
 	queue   *queue   // Scheduler for selecting the hashes to download
 	peers   *peerSet // Set of active peers from which download can proceed
 	stateDB database.DBManager
 
+	writeThrottle *writeThrottle // Backs off block imports while database writes lag behind
+
 	rttEstimate   uint64 // Round trip time to target for download requests
 	rttConfidence uint64 // Confidence in the estimated RTT (unit: millionths to allow atomic ops)
 
@@ -204,7 +209,11 @@ type BlockChain interface {
 }
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(mode SyncMode, stateDB database.DBManager, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+// At most maxPeers peers are used to fetch the same kind of data concurrently;
+// a non-positive maxPeers means no limit. Block imports are backed off while
+// the average database write latency exceeds maxWriteLatency; a non-positive
+// maxWriteLatency disables the throttling.
+func New(mode SyncMode, stateDB database.DBManager, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn, maxPeers int, maxWriteLatency time.Duration) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
@@ -214,7 +223,8 @@ func New(mode SyncMode, stateDB database.DBManager, mux *event.TypeMux, chain Bl
 		stateDB:        stateDB,
 		mux:            mux,
 		queue:          newQueue(),
-		peers:          newPeerSet(),
+		peers:          newPeerSet(maxPeers),
+		writeThrottle:  newWriteThrottle(stateDB, maxWriteLatency),
 		rttEstimate:    uint64(rttMaxEstimate),
 		rttConfidence:  uint64(1000000),
 		blockchain:     chain,
@@ -390,7 +400,12 @@ func (d *Downloader) synchronise(id string, hash common.Hash, td *big.Int, mode
 
 	defer d.Cancel() // No matter what, we can't leave the cancel channel open
 
-	// Set the requested sync mode, unless it's forbidden
+	// Set the requested sync mode, unless it's forbidden. Snap sync shares the
+	// fast sync pipeline and only differs in how the state trie is downloaded.
+	d.snapSync = mode == SnapSync
+	if d.snapSync {
+		mode = FastSync
+	}
 	d.mode = mode
 
 	// Retrieve the origin peer and initiate the downloading process
@@ -1396,6 +1411,8 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 		logger.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
 		return errInvalidChain
 	}
+	// Give the disk a chance to catch up before taking more blocks
+	d.writeThrottle.wait(d.quitCh)
 	return nil
 }
 
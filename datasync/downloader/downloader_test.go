@@ -68,6 +68,8 @@ type downloadTester struct {
 
 	peerMissingStates map[string]map[common.Hash]bool // State entries that fast sync should not return
 
+	rangeRequests int32 // Number of node data range requests served by the peers
+
 	lock sync.RWMutex
 }
 
@@ -94,7 +96,7 @@ func newTester() *downloadTester {
 	tester.stateDb = database.NewMemoryDBManager()
 	tester.stateDb.GetMemDB().Put(genesis.Root().Bytes(), []byte{0x00})
 
-	tester.downloader = New(FullSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
+	tester.downloader = New(FullSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer, 0, 0)
 
 	return tester
 }
@@ -591,6 +593,48 @@ func (dlp *downloadTesterPeer) RequestNodeData(hashes []common.Hash) error {
 	return nil
 }
 
+// RequestNodeDataRange constructs a getNodeDataRange method associated with a
+// particular peer in the download tester. Besides the requested node state data,
+// the trie nodes below them are delivered in pre-order.
+func (dlp *downloadTesterPeer) RequestNodeDataRange(hashes []common.Hash) error {
+	atomic.AddInt32(&dlp.dl.rangeRequests, 1)
+	dlp.waitDelay()
+
+	dlp.dl.lock.RLock()
+	defer dlp.dl.lock.RUnlock()
+
+	var (
+		triedb  = statedb.NewDatabase(dlp.dl.peerDb)
+		missing = dlp.dl.peerMissingStates[dlp.id]
+		served  []common.Hash
+		results [][]byte
+	)
+	for _, hash := range hashes {
+		if data, err := triedb.Node(hash); err == nil && !missing[hash] {
+			served = append(served, hash)
+			results = append(results, data)
+		}
+	}
+	for _, hash := range served {
+		trie, err := statedb.NewTrie(hash, triedb)
+		if err != nil {
+			continue
+		}
+		for it := trie.NodeIterator(nil); it.Next(true); {
+			node := it.Hash()
+			if node == (common.Hash{}) || node == hash || missing[node] {
+				continue
+			}
+			if data, err := triedb.Node(node); err == nil {
+				results = append(results, data)
+			}
+		}
+	}
+	go dlp.dl.downloader.DeliverNodeData(dlp.id, results)
+
+	return nil
+}
+
 // assertOwnChain checks if the local chain contains the correct number of items
 // of the various chain components.
 func assertOwnChain(t *testing.T, tester *downloadTester, length int) {
@@ -653,6 +697,8 @@ func TestCanonicalSynchronisation63Fast(t *testing.T)  { testCanonicalSynchronis
 func TestCanonicalSynchronisation64Full(t *testing.T)  { testCanonicalSynchronisation(t, 64, FullSync) }
 func TestCanonicalSynchronisation64Fast(t *testing.T)  { testCanonicalSynchronisation(t, 64, FastSync) }
 func TestCanonicalSynchronisation64Light(t *testing.T) { testCanonicalSynchronisation(t, 64, LightSync) }
+func TestCanonicalSynchronisation64Snap(t *testing.T)  { testCanonicalSynchronisation(t, 64, SnapSync) }
+func TestCanonicalSynchronisation65Snap(t *testing.T)  { testCanonicalSynchronisation(t, 65, SnapSync) }
 
 func testCanonicalSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	t.Parallel()
@@ -1006,6 +1052,55 @@ func testMultiSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	assertOwnChain(t, tester, targetBlocks+1)
 }
 
+// Tests that no more than the configured number of peers are fetching the same
+// kind of data at once.
+func TestSyncMaxPeers63Full(t *testing.T) { testSyncMaxPeers(t, 63, FullSync) }
+func TestSyncMaxPeers63Fast(t *testing.T) { testSyncMaxPeers(t, 63, FastSync) }
+
+func testSyncMaxPeers(t *testing.T, protocol int, mode SyncMode) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	maxPeers := 2
+	tester.downloader.peers.maxActive = maxPeers
+
+	// Count the busy peers whenever a new fetch is about to be assigned
+	var exceeded int32
+	checkActive := func(busy func(p *peerConnection) bool) {
+		active := 1 // the peer the fetch is assigned to
+		for _, p := range tester.downloader.peers.AllPeers() {
+			if busy(p) {
+				active++
+			}
+		}
+		if active > maxPeers {
+			atomic.StoreInt32(&exceeded, int32(active))
+		}
+	}
+	tester.downloader.bodyFetchHook = func([]*types.Header) {
+		checkActive(func(p *peerConnection) bool { return atomic.LoadInt32(&p.blockIdle) != 0 })
+	}
+	tester.downloader.receiptFetchHook = func([]*types.Header) {
+		checkActive(func(p *peerConnection) bool { return atomic.LoadInt32(&p.receiptIdle) != 0 })
+	}
+
+	targetBlocks := 4*blockCacheItems - 15
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+	for i := 0; i < 6; i++ {
+		tester.newSlowPeer(fmt.Sprintf("peer #%d", i), protocol, hashes, headers, blocks, receipts, time.Millisecond)
+	}
+	if err := tester.sync("peer #0", nil, mode); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, targetBlocks+1)
+
+	if active := atomic.LoadInt32(&exceeded); active != 0 {
+		t.Errorf("active peers exceeded the limit: have %d, limit %d", active, maxPeers)
+	}
+}
+
 // Tests that synchronisations behave well in multi-version protocol environments
 // and not wreak havoc on other nodes in the network.
 func TestMultiProtoSynchronisation62(t *testing.T)      { testMultiProtoSync(t, 62, FullSync) }
@@ -1014,6 +1109,7 @@ func TestMultiProtoSynchronisation63Fast(t *testing.T)  { testMultiProtoSync(t,
 func TestMultiProtoSynchronisation64Full(t *testing.T)  { testMultiProtoSync(t, 64, FullSync) }
 func TestMultiProtoSynchronisation64Fast(t *testing.T)  { testMultiProtoSync(t, 64, FastSync) }
 func TestMultiProtoSynchronisation64Light(t *testing.T) { testMultiProtoSync(t, 64, LightSync) }
+func TestMultiProtoSynchronisation65Snap(t *testing.T)  { testMultiProtoSync(t, 65, SnapSync) }
 
 func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	t.Parallel()
@@ -1029,6 +1125,7 @@ func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	tester.newPeer("peer 62", 62, hashes, headers, blocks, nil)
 	tester.newPeer("peer 63", 63, hashes, headers, blocks, receipts)
 	tester.newPeer("peer 64", 64, hashes, headers, blocks, receipts)
+	tester.newPeer("peer 65", 65, hashes, headers, blocks, receipts)
 
 	// Synchronise with the requested peer and make sure all blocks were retrieved
 	if err := tester.sync(fmt.Sprintf("peer %d", protocol), nil, mode); err != nil {
@@ -1037,7 +1134,7 @@ func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	assertOwnChain(t, tester, targetBlocks+1)
 
 	// Check that no peers have been dropped off
-	for _, version := range []int{62, 63, 64} {
+	for _, version := range []int{62, 63, 64, 65} {
 		peer := fmt.Sprintf("peer %d", version)
 		if _, ok := tester.peerHashes[peer]; !ok {
 			t.Errorf("%s dropped", peer)
@@ -1045,6 +1142,38 @@ func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	}
 }
 
+// Tests that snap sync requests ranges of state from peers advertising the
+// capability and falls back to node-by-node retrieval for the others.
+func TestSnapSyncRangeRequests(t *testing.T) {
+	tests := []struct {
+		protocol int
+		mode     SyncMode
+		ranges   bool
+	}{
+		{63, SnapSync, false},
+		{64, SnapSync, false},
+		{65, SnapSync, true},
+		{65, FastSync, false},
+	}
+	for _, tt := range tests {
+		tester := newTester()
+
+		targetBlocks := blockCacheItems - 15
+		hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+		tester.newPeer("peer", tt.protocol, hashes, headers, blocks, receipts)
+
+		if err := tester.sync("peer", nil, tt.mode); err != nil {
+			t.Fatalf("klay/%d %v: failed to synchronise blocks: %v", tt.protocol, tt.mode, err)
+		}
+		assertOwnChain(t, tester, targetBlocks+1)
+
+		if ranges := atomic.LoadInt32(&tester.rangeRequests) > 0; ranges != tt.ranges {
+			t.Errorf("klay/%d %v: range requests mismatch: have %v, want %v", tt.protocol, tt.mode, ranges, tt.ranges)
+		}
+		tester.terminate()
+	}
+}
+
 // Tests that if a block is empty (e.g. header only), no body request should be
 // made, and instead the header should be assembled into a whole block in itself.
 func TestEmptyShortCircuit62(t *testing.T)      { testEmptyShortCircuit(t, 62, FullSync) }
@@ -1690,6 +1819,9 @@ func (ftp *floodingTestPeer) RequestReceipts(hashes []common.Hash) error {
 func (ftp *floodingTestPeer) RequestNodeData(hashes []common.Hash) error {
 	return ftp.peer.RequestNodeData(hashes)
 }
+func (ftp *floodingTestPeer) RequestNodeDataRange(hashes []common.Hash) error {
+	return ftp.peer.RequestNodeDataRange(hashes)
+}
 
 func (ftp *floodingTestPeer) RequestHeadersByNumber(from uint64, count, skip int, reverse bool) error {
 	deliveriesDone := make(chan struct{}, 500)
@@ -88,6 +88,7 @@ type Peer interface {
 	RequestBodies([]common.Hash) error
 	RequestReceipts([]common.Hash) error
 	RequestNodeData([]common.Hash) error
+	RequestNodeDataRange([]common.Hash) error
 }
 
 // lightPeerWrapper wraps a LightPeer struct, stubbing out the Peer-only methods.
@@ -111,6 +112,9 @@ func (w *lightPeerWrapper) RequestReceipts([]common.Hash) error {
 func (w *lightPeerWrapper) RequestNodeData([]common.Hash) error {
 	panic("RequestNodeData not supported in light client mode sync")
 }
+func (w *lightPeerWrapper) RequestNodeDataRange([]common.Hash) error {
+	panic("RequestNodeDataRange not supported in light client mode sync")
+}
 
 // newPeerConnection creates a new downloader peer.
 func newPeerConnection(id string, version int, peer Peer, logger log.Logger) *peerConnection {
@@ -222,6 +226,31 @@ func (p *peerConnection) FetchNodeData(hashes []common.Hash) error {
 	return nil
 }
 
+// SupportsNodeDataRange returns whether the remote peer is able to serve node
+// state data retrievals by ranges.
+func (p *peerConnection) SupportsNodeDataRange() bool {
+	return p.version >= 65
+}
+
+// FetchNodeDataRange sends a node state data range retrieval request to the
+// remote peer. The reply carries the requested entries followed by their
+// descendant trie nodes.
+func (p *peerConnection) FetchNodeDataRange(hashes []common.Hash) error {
+	// Sanity check the protocol version
+	if !p.SupportsNodeDataRange() {
+		panic(fmt.Sprintf("node data range fetch [klay/65+] requested on klay/%d", p.version))
+	}
+	// Short circuit if the peer is already fetching
+	if !atomic.CompareAndSwapInt32(&p.stateIdle, 0, 1) {
+		return errAlreadyFetching
+	}
+	p.stateStarted = time.Now()
+
+	go p.peer.RequestNodeDataRange(hashes)
+
+	return nil
+}
+
 // SetHeadersIdle sets the peer to idle, allowing it to execute new header retrieval
 // requests. Its estimated header retrieval throughput is updated with that measured
 // just now.
@@ -353,12 +382,17 @@ type peerSet struct {
 	newPeerFeed  event.Feed
 	peerDropFeed event.Feed
 	lock         sync.RWMutex
+
+	maxActive int // Maximum number of peers fetching the same kind of data at once, 0 for unlimited
 }
 
 // newPeerSet creates a new peer set top track the active download sources.
-func newPeerSet() *peerSet {
+// At most maxActive peers are handed out to fetch the same kind of data
+// concurrently; a non-positive maxActive means no limit.
+func newPeerSet(maxActive int) *peerSet {
 	return &peerSet{
-		peers: make(map[string]*peerConnection),
+		peers:     make(map[string]*peerConnection),
+		maxActive: maxActive,
 	}
 }
 
@@ -477,7 +511,7 @@ func (ps *peerSet) HeaderIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.headerThroughput
 	}
-	return ps.idlePeers(62, 64, idleCheck, throughput)
+	return ps.idlePeers(62, 66, idleCheck, throughput)
 }
 
 // BodyIdlePeers retrieves a flat list of all the currently body-idle peers within
@@ -491,7 +525,7 @@ func (ps *peerSet) BodyIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.blockThroughput
 	}
-	return ps.idlePeers(62, 64, idleCheck, throughput)
+	return ps.idlePeers(62, 66, idleCheck, throughput)
 }
 
 // ReceiptIdlePeers retrieves a flat list of all the currently receipt-idle peers
@@ -505,7 +539,7 @@ func (ps *peerSet) ReceiptIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.receiptThroughput
 	}
-	return ps.idlePeers(63, 64, idleCheck, throughput)
+	return ps.idlePeers(63, 66, idleCheck, throughput)
 }
 
 // NodeDataIdlePeers retrieves a flat list of all the currently node-data-idle
@@ -519,12 +553,15 @@ func (ps *peerSet) NodeDataIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.stateThroughput
 	}
-	return ps.idlePeers(63, 64, idleCheck, throughput)
+	return ps.idlePeers(63, 66, idleCheck, throughput)
 }
 
 // idlePeers retrieves a flat list of all currently idle peers satisfying the
 // protocol version constraints, using the provided function to check idleness.
 // The resulting set of peers are sorted by their measure throughput.
+// If the number of active peers is limited, only the best idle peers which can
+// be activated within the limit are returned and the total number of peers is
+// capped at the limit.
 func (ps *peerSet) idlePeers(minProtocol, maxProtocol int, idleCheck func(*peerConnection) bool, throughput func(*peerConnection) float64) ([]*peerConnection, int) {
 	ps.lock.RLock()
 	defer ps.lock.RUnlock()
@@ -545,6 +582,18 @@ func (ps *peerSet) idlePeers(minProtocol, maxProtocol int, idleCheck func(*peerC
 			}
 		}
 	}
+	if ps.maxActive > 0 {
+		allowed := ps.maxActive - (numTotalPeers - len(idlePeers))
+		if allowed < 0 {
+			allowed = 0
+		}
+		if len(idlePeers) > allowed {
+			idlePeers = idlePeers[:allowed]
+		}
+		if numTotalPeers > ps.maxActive {
+			numTotalPeers = ps.maxActive
+		}
+	}
 	return idlePeers, numTotalPeers
 }
 
@@ -213,7 +213,8 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 // stateSync schedules requests for downloading a particular state trie defined
 // by a given state root.
 type stateSync struct {
-	d *Downloader // Downloader instance to access and manage current peerset
+	d    *Downloader // Downloader instance to access and manage current peerset
+	snap bool        // Whether to request ranges of trie nodes from capable peers
 
 	sched  *statedb.TrieSync          // State trie sync scheduler defining the tasks
 	keccak hash.Hash                  // Keccak256 hasher to verify deliveries with
@@ -240,6 +241,7 @@ type stateTask struct {
 func newStateSync(d *Downloader, root common.Hash) *stateSync {
 	return &stateSync{
 		d:       d,
+		snap:    d.snapSync,
 		sched:   state.NewStateSync(root, d.stateDB),
 		keccak:  sha3.NewKeccak256(),
 		tasks:   make(map[common.Hash]*stateTask),
@@ -358,7 +360,12 @@ func (s *stateSync) assignTasks() {
 			req.peer.logger.Trace("Requesting new batch of data", "type", "state", "count", len(req.items))
 			select {
 			case s.d.trackStateReq <- req:
-				req.peer.FetchNodeData(req.items)
+				// Fall back to node-by-node retrieval if the peer can't serve ranges
+				if s.snap && req.peer.SupportsNodeDataRange() {
+					req.peer.FetchNodeDataRange(req.items)
+				} else {
+					req.peer.FetchNodeData(req.items)
+				}
 			case <-s.cancel:
 			case <-s.d.cancelCh:
 			}
@@ -419,6 +426,9 @@ func (s *stateSync) process(req *stateReq) error {
 			s.numUncommitted++
 			s.bytesUncommitted += len(blob)
 			progress = progress || prog
+
+			// Range replies may fulfil tasks queued for retry or assigned to other peers
+			delete(s.tasks, hash)
 		case statedb.ErrNotRequested:
 			unexpected++
 		case statedb.ErrAlreadyProcessed:
@@ -0,0 +1,66 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"time"
+
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+const writeThrottleBackoff = 100 * time.Millisecond // Time to back off block imports while the disk is lagging behind
+
+// writeThrottle watches the moving average of the latency of the batch writes
+// of the database, which the block imports go through. When the average exceeds
+// the configured maximum, the downloader backs off so that the sync doesn't
+// outrun a slow disk and pile up unwritten data in memory.
+type writeThrottle struct {
+	db         database.DBManager
+	maxLatency time.Duration // Maximum average write latency before backing off (0 = disabled)
+
+	throttled int // Number of times the imports were backed off
+}
+
+// newWriteThrottle creates a write throttle watching the given database.
+// A non-positive maxLatency disables the throttle.
+func newWriteThrottle(db database.DBManager, maxLatency time.Duration) *writeThrottle {
+	return &writeThrottle{
+		db:         db,
+		maxLatency: maxLatency,
+	}
+}
+
+// wait checks the average batch write latency and, if the disk can't keep up,
+// sleeps for a short while before more blocks are requested. It returns early
+// if cancel is closed.
+func (t *writeThrottle) wait(cancel <-chan struct{}) {
+	if t.maxLatency <= 0 {
+		return
+	}
+	avg := t.db.BatchWriteLatency()
+	if avg <= t.maxLatency {
+		return
+	}
+	t.throttled++
+	writeThrottleMeter.Mark(1)
+	logger.Debug("Throttling block import on slow database writes", "latency", avg, "limit", t.maxLatency, "backoff", writeThrottleBackoff)
+
+	select {
+	case <-time.After(writeThrottleBackoff):
+	case <-cancel:
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// slowWriteDB is a database whose average batch write latency is latency.
+type slowWriteDB struct {
+	database.DBManager
+	latency time.Duration
+}
+
+func (db *slowWriteDB) BatchWriteLatency() time.Duration {
+	return db.latency
+}
+
+// Tests that the write throttle only backs off when the average write latency
+// exceeds the limit, and never when it is disabled.
+func TestWriteThrottle(t *testing.T) {
+	tests := []struct {
+		latency    time.Duration
+		maxLatency time.Duration
+		throttled  bool
+	}{
+		{0, time.Second, false},
+		{20 * time.Millisecond, time.Millisecond, true},
+		{20 * time.Millisecond, 0, false},
+	}
+	for i, tt := range tests {
+		db := &slowWriteDB{DBManager: database.NewMemoryDBManager(), latency: tt.latency}
+		throttle := newWriteThrottle(db, tt.maxLatency)
+
+		for j := 0; j < 3; j++ {
+			throttle.wait(nil)
+		}
+		if throttled := throttle.throttled > 0; throttled != tt.throttled {
+			t.Errorf("test %d: throttled mismatch: have %v, want %v", i, throttled, tt.throttled)
+		}
+	}
+}
+
+// Tests that a full sync against a slow database engages the write throttle
+// and still retrieves the whole chain.
+func TestWriteThrottleSlowDatabase(t *testing.T) {
+	tester := newTester()
+	defer tester.terminate()
+
+	slowDB := &slowWriteDB{DBManager: tester.stateDb, latency: 20 * time.Millisecond}
+	tester.downloader.writeThrottle = newWriteThrottle(slowDB, time.Millisecond)
+
+	targetBlocks := 3*maxResultsProcess + 15
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+	tester.newPeer("peer", 63, hashes, headers, blocks, receipts)
+
+	if err := tester.sync("peer", nil, FullSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, targetBlocks+1)
+
+	if tester.downloader.writeThrottle.throttled == 0 {
+		t.Error("write throttle not engaged on a slow database")
+	}
+}
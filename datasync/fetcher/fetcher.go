@@ -70,8 +70,9 @@ type blockHashBroadcasterFn func(block *types.Block)
 // chainHeightFn is a callback type to retrieve the current chain height.
 type chainHeightFn func() uint64
 
-// chainInsertFn is a callback type to insert a batch of blocks into the local chain.
-type chainInsertFn func(types.Blocks) (int, error)
+// chainInsertFn is a callback type to insert a batch of blocks received from the
+// given peer into the local chain.
+type chainInsertFn func(peer string, blocks types.Blocks) (int, error)
 
 // peerDropFn is a callback type for dropping a peer detected as malicious.
 type peerDropFn func(id string)
@@ -701,7 +702,7 @@ func (f *Fetcher) insertWork(peer string, block *types.Block) {
 		return
 	}
 	// Run the actual import and log any issues
-	if _, err := f.insertChain(types.Blocks{block}); err != nil {
+	if _, err := f.insertChain(peer, types.Blocks{block}); err != nil {
 		logger.Debug("Propagated block import failed", "peer", peer, "number", blockNum, "hash", hash, "err", err)
 		return
 	}
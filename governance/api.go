@@ -79,6 +79,12 @@ func (api *GovernanceKlayAPI) GasPriceAt(num *rpc.BlockNumber) (*big.Int, error)
 	}
 }
 
+// GetUnitPrice returns the unit price enforced at the given block.
+// Transactions whose gas price differs from it are rejected.
+func (api *GovernanceKlayAPI) GetUnitPrice(num *rpc.BlockNumber) (*big.Int, error) {
+	return api.GasPriceAt(num)
+}
+
 func (api *GovernanceKlayAPI) GasPrice() *big.Int {
 	ret := api.governance.GetLatestGovernanceItem("governance.unitprice").(uint64)
 	return big.NewInt(0).SetUint64(ret)
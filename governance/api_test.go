@@ -0,0 +1,51 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package governance
+
+import (
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGovernanceKlayAPI_GetUnitPrice(t *testing.T) {
+	config := *getTestConfig()
+	config.UnitPrice = 25000000000
+
+	db := database.NewMemoryDBManager()
+	(&blockchain.Genesis{Config: &config}).MustCommit(db)
+	chain, err := blockchain.NewBlockChain(db, nil, &config, gxhash.NewFaker(), vm.Config{})
+	require.NoError(t, err)
+	defer chain.Stop()
+
+	api := NewGovernanceKlayAPI(NewGovernance(&config, db), chain)
+
+	latest, genesis, future := rpc.LatestBlockNumber, rpc.BlockNumber(0), rpc.BlockNumber(1)
+	for _, num := range []*rpc.BlockNumber{nil, &latest, &genesis} {
+		price, err := api.GetUnitPrice(num)
+		require.NoError(t, err)
+		assert.Equal(t, config.GetUnitPrice(), price)
+	}
+
+	_, err = api.GetUnitPrice(&future)
+	assert.Equal(t, errUnknownBlock, err)
+}
@@ -62,6 +62,7 @@ var (
 		"governance.addvalidator":       params.AddValidator,
 		"governance.removevalidator":    params.RemoveValidator,
 		"param.txgashumanreadable":      params.ConstTxGasHumanReadable,
+		"governance.gaspricefloors":     params.GasPriceFloors,
 	}
 
 	GovernanceForbiddenKeyMap = map[string]int{
@@ -83,6 +84,7 @@ var (
 		"reward.deferredtxfee":      params.DeferredTxFee,
 		"reward.minimumstake":       params.MinimumStake,
 		"param.txgashumanreadable":  params.ConstTxGasHumanReadable,
+		"governance.gaspricefloors": params.GasPriceFloors,
 	}
 
 	GovernanceKeyMapReverse = map[int]string{
@@ -103,6 +105,7 @@ var (
 		params.AddValidator:            "governance.addvalidator",
 		params.RemoveValidator:         "governance.removevalidator",
 		params.ConstTxGasHumanReadable: "param.txgashumanreadable",
+		params.GasPriceFloors:          "governance.gaspricefloors",
 	}
 
 	ProposerPolicyMap = map[string]int{
@@ -307,7 +310,7 @@ func (g *Governance) ParseVoteValue(gVote *GovernanceVote) (*GovernanceVote, err
 	}
 
 	switch k {
-	case params.GovernanceMode, params.MintingAmount, params.MinimumStake, params.Ratio:
+	case params.GovernanceMode, params.MintingAmount, params.MinimumStake, params.Ratio, params.GasPriceFloors:
 		val = string(gVote.Value.([]uint8))
 	case params.GoverningNode, params.AddValidator, params.RemoveValidator:
 		val = common.BytesToAddress(gVote.Value.([]uint8))
@@ -342,7 +345,7 @@ func (gov *Governance) updateChangeSet(vote GovernanceVote) bool {
 	case params.GoverningNode:
 		gov.changeSet[vote.Key] = vote.Value.(common.Address)
 		return true
-	case params.GovernanceMode, params.Ratio:
+	case params.GovernanceMode, params.Ratio, params.GasPriceFloors:
 		gov.changeSet[vote.Key] = vote.Value.(string)
 		return true
 	case params.Epoch, params.StakeUpdateInterval, params.ProposerRefreshInterval, params.CommitteeSize, params.UnitPrice, params.ConstTxGasHumanReadable:
@@ -416,6 +419,7 @@ func CheckGenesisValues(c *params.ChainConfig) error {
 		"reward.minimumstake":           c.Governance.Reward.MinimumStake.String(),
 		"reward.stakingupdateinterval":  c.Governance.Reward.StakingUpdateInterval,
 		"reward.proposerupdateinterval": c.Governance.Reward.ProposerUpdateInterval,
+		"governance.gaspricefloors":     c.Governance.GasPriceFloors,
 	}
 
 	for k, v := range tstMap {
@@ -794,6 +798,7 @@ func getGovernanceItemsFromChainConfig(config *params.ChainConfig) GovernanceSet
 			params.MinimumStake:            governance.Reward.MinimumStake.String(),
 			params.StakeUpdateInterval:     governance.Reward.StakingUpdateInterval,
 			params.ProposerRefreshInterval: governance.Reward.ProposerUpdateInterval,
+			params.GasPriceFloors:          governance.GasPriceFloors,
 		}
 
 		for k, v := range governanceMap {
@@ -17,6 +17,7 @@
 package governance
 
 import (
+	"fmt"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/consensus/istanbul"
@@ -60,6 +61,7 @@ var GovernanceItems = map[int]check{
 	params.Policy:                  {uint64T, checkUint64andBool, updateGovernanceConfig},
 	params.CommitteeSize:           {uint64T, checkUint64andBool, updateGovernanceConfig},
 	params.ConstTxGasHumanReadable: {uint64T, checkUint64andBool, updateParams},
+	params.GasPriceFloors:          {stringT, checkGasPriceFloors, updateGovernanceConfig},
 }
 
 func updateParams(g *Governance, k string, v interface{}) bool {
@@ -105,6 +107,8 @@ func updateGovernanceConfig(g *Governance, k string, v interface{}) bool {
 		g.blockChain.Config().Istanbul.ProposerPolicy = g.ChainConfig.Istanbul.ProposerPolicy
 	case params.CommitteeSize:
 		g.ChainConfig.Istanbul.SubGroupSize = v.(uint64)
+	case params.GasPriceFloors:
+		g.ChainConfig.Governance.GasPriceFloors = v.(string)
 	}
 	return true
 }
@@ -238,6 +242,13 @@ func checkBigInt(k string, v interface{}) bool {
 	return false
 }
 
+func checkGasPriceFloors(k string, v interface{}) bool {
+	if _, err := params.ParseGasPriceFloors(v.(string)); err != nil {
+		return false
+	}
+	return true
+}
+
 func checkAddress(k string, v interface{}) bool {
 	return true
 }
@@ -442,6 +453,22 @@ func (gov *Governance) GetGovernanceItemAtNumber(num uint64, key string) (interf
 	}
 }
 
+// GasPriceFloorsAtNumber returns the gas price floors of the tx types in effect at block num.
+// It returns no floor if the governance in effect at num has no gas price floors.
+func (gov *Governance) GasPriceFloorsAtNumber(num uint64) (map[uint64]*big.Int, error) {
+	item, err := gov.GetGovernanceItemAtNumber(num, GovernanceKeyMapReverse[params.GasPriceFloors])
+	if err == ErrItemNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	floors, ok := item.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid gas price floors %v at block %d", item, num)
+	}
+	return params.ParseGasPriceFloors(floors)
+}
+
 func (gov *Governance) GetLatestGovernanceItem(key string) interface{} {
 	gov.currentSetMu.RLock()
 	defer gov.currentSetMu.RUnlock()
@@ -33,6 +33,7 @@ var (
 	ErrNotProgramAccount          = errors.New("not a program account (e.g., an account having code and storage)")
 	ErrPrecompiledContractAddress = errors.New("the address is reserved for pre-compiled contracts")
 	ErrInvalidCodeFormat          = errors.New("smart contract code format is invalid")
+	ErrSelfFeeDelegation          = errors.New("fee payer is the same as the sender")
 
 	// Error codes related to account keys.
 	ErrAccountAlreadyExists                 = errors.New("account already exists")
@@ -0,0 +1,69 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package grpc
+
+import (
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/event"
+)
+
+// chainHeadChanSize is the size of the channel receiving the chain head events of a stream.
+const chainHeadChanSize = 10
+
+// ChainHeadFeed is the source of the chain head events streamed by the BlockStream service.
+type ChainHeadFeed interface {
+	SubscribeChainHeadEvent(ch chan<- blockchain.ChainHeadEvent) event.Subscription
+}
+
+// blockStreamServer is an implementation of BlockStreamServer.
+type blockStreamServer struct {
+	feed ChainHeadFeed
+}
+
+// Subscribe sends the header of each new chain head block to the client
+// until the client disconnects or the feed is closed.
+func (bss *blockStreamServer) Subscribe(request *SubscribeRequest, stream BlockStream_SubscribeServer) error {
+	headCh := make(chan blockchain.ChainHeadEvent, chainHeadChanSize)
+	sub := bss.feed.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			if err := stream.Send(newBlockHeader(ev.Block.Header())); err != nil {
+				logger.Debug("fail to send the block header", "err", err)
+				return err
+			}
+		case err := <-sub.Err():
+			return err
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// newBlockHeader converts the given header into a BlockHeader message.
+func newBlockHeader(header *types.Header) *BlockHeader {
+	return &BlockHeader{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash().Bytes(),
+		ParentHash: header.ParentHash.Bytes(),
+		Timestamp:  header.Time.Uint64(),
+		GasUsed:    header.GasUsed,
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package grpc
+
+import (
+	"context"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"google.golang.org/grpc"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+type testChainHeadFeed struct {
+	feed event.Feed
+}
+
+func (f *testChainHeadFeed) SubscribeChainHeadEvent(ch chan<- blockchain.ChainHeadEvent) event.Subscription {
+	return f.feed.Subscribe(ch)
+}
+
+// sendUntil sends the event until the feed has the given number of subscribers.
+func (f *testChainHeadFeed) sendUntil(t *testing.T, ev blockchain.ChainHeadEvent, subscribers int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for f.feed.Send(ev) != subscribers {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %d subscribers", subscribers)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBlockStreamSubscribe(t *testing.T) {
+	// Reserve a free port for the listener.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	feed := &testChainHeadFeed{}
+	listener := &Listener{Addr: addr}
+	listener.SetRPCServer(rpc.NewServer())
+	listener.SetChainHeadFeed(feed)
+	go listener.Start()
+	defer listener.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	stream, err := NewBlockStreamClient(conn).Subscribe(streamCtx, &SubscribeRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent := &types.Header{Number: big.NewInt(1), ParentHash: common.HexToHash("0x01"), Time: big.NewInt(100), GasUsed: 21000}
+	child := &types.Header{Number: big.NewInt(2), ParentHash: parent.Hash(), Time: big.NewInt(101), GasUsed: 42000}
+
+	// The first event is sent once the server subscribed to the feed.
+	feed.sendUntil(t, blockchain.ChainHeadEvent{Block: types.NewBlockWithHeader(parent)}, 1)
+	feed.feed.Send(blockchain.ChainHeadEvent{Block: types.NewBlockWithHeader(child)})
+
+	for _, want := range []*types.Header{parent, child} {
+		header, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Number != want.Number.Uint64() {
+			t.Errorf("number mismatch: have %d, want %d", header.Number, want.Number)
+		}
+		if common.BytesToHash(header.Hash) != want.Hash() {
+			t.Errorf("hash mismatch: have %x, want %x", header.Hash, want.Hash())
+		}
+		if common.BytesToHash(header.ParentHash) != want.ParentHash {
+			t.Errorf("parent hash mismatch: have %x, want %x", header.ParentHash, want.ParentHash)
+		}
+		if header.Timestamp != want.Time.Uint64() {
+			t.Errorf("timestamp mismatch: have %d, want %d", header.Timestamp, want.Time)
+		}
+		if header.GasUsed != want.GasUsed {
+			t.Errorf("gas used mismatch: have %d, want %d", header.GasUsed, want.GasUsed)
+		}
+	}
+
+	// The server unsubscribes from the feed when the client disconnects.
+	streamCancel()
+	feed.sendUntil(t, blockchain.ChainHeadEvent{Block: types.NewBlockWithHeader(child)}, 0)
+}
@@ -36,9 +36,10 @@ import (
 var logger = log.NewModuleLogger(log.NetworksGRPC)
 
 type Listener struct {
-	Addr       string
-	handler    *rpc.Server
-	grpcServer *grpc.Server
+	Addr          string
+	handler       *rpc.Server
+	chainHeadFeed ChainHeadFeed
+	grpcServer    *grpc.Server
 }
 
 const maxRequestContentLength = 1024 * 128
@@ -250,6 +251,12 @@ func (gs *Listener) SetRPCServer(handler *rpc.Server) {
 	gs.handler = handler
 }
 
+// SetChainHeadFeed sets the feed of the chain head events streamed by the
+// BlockStream service. The service is not served if the feed is not set.
+func (gs *Listener) SetChainHeadFeed(feed ChainHeadFeed) {
+	gs.chainHeadFeed = feed
+}
+
 func (gs *Listener) Start() {
 	lis, err := net.Listen("tcp", gs.Addr)
 	if err != nil {
@@ -259,6 +266,9 @@ func (gs *Listener) Start() {
 	gs.grpcServer = grpc.NewServer()
 
 	RegisterKlaytnNodeServer(gs.grpcServer, &klaytnServer{handler: gs.handler})
+	if gs.chainHeadFeed != nil {
+		RegisterBlockStreamServer(gs.grpcServer, &blockStreamServer{feed: gs.chainHeadFeed})
+	}
 
 	// Register reflection service on gRPC server.
 	reflection.Register(gs.grpcServer)
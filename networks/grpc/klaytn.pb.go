@@ -147,32 +147,143 @@ func (m *RPCResponse) GetPayload() []byte {
 	return nil
 }
 
+type SubscribeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6d8429895d2d55b, []int{3}
+}
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+type BlockHeader struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	ParentHash           []byte   `protobuf:"bytes,3,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	Timestamp            uint64   `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	GasUsed              uint64   `protobuf:"varint,5,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHeader) Reset()         { *m = BlockHeader{} }
+func (m *BlockHeader) String() string { return proto.CompactTextString(m) }
+func (*BlockHeader) ProtoMessage()    {}
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6d8429895d2d55b, []int{4}
+}
+
+func (m *BlockHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeader.Unmarshal(m, b)
+}
+func (m *BlockHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeader.Marshal(b, m, deterministic)
+}
+func (m *BlockHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeader.Merge(m, src)
+}
+func (m *BlockHeader) XXX_Size() int {
+	return xxx_messageInfo_BlockHeader.Size(m)
+}
+func (m *BlockHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeader proto.InternalMessageInfo
+
+func (m *BlockHeader) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetParentHash() []byte {
+	if m != nil {
+		return m.ParentHash
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "grpc.Empty")
 	proto.RegisterType((*RPCRequest)(nil), "grpc.RPCRequest")
 	proto.RegisterType((*RPCResponse)(nil), "grpc.RPCResponse")
+	proto.RegisterType((*SubscribeRequest)(nil), "grpc.SubscribeRequest")
+	proto.RegisterType((*BlockHeader)(nil), "grpc.BlockHeader")
 }
 
 func init() { proto.RegisterFile("klaytn.proto", fileDescriptor_c6d8429895d2d55b) }
 
 var fileDescriptor_c6d8429895d2d55b = []byte{
-	// 244 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x41, 0x4b, 0xc3, 0x30,
-	0x18, 0x86, 0xfd, 0x66, 0xed, 0xd8, 0x67, 0x61, 0x9a, 0x83, 0x14, 0x4f, 0xa3, 0x17, 0x0b, 0x62,
-	0x19, 0xce, 0x5f, 0xd0, 0xe1, 0x49, 0x90, 0x12, 0xc1, 0x7b, 0xda, 0x7e, 0x68, 0xb1, 0x59, 0x62,
-	0x92, 0x09, 0xfd, 0x3b, 0x1e, 0xfc, 0x9d, 0x92, 0xd8, 0x51, 0x8f, 0x7a, 0x7c, 0xdf, 0x24, 0x4f,
-	0x1e, 0x5e, 0x4c, 0xde, 0x7a, 0x31, 0xb8, 0x5d, 0xa1, 0x8d, 0x72, 0x8a, 0x45, 0x2f, 0x46, 0x37,
-	0xd9, 0x1c, 0x4f, 0xee, 0xa5, 0x76, 0x43, 0xf6, 0x8c, 0xc8, 0xab, 0x2d, 0xa7, 0xf7, 0x3d, 0x59,
-	0xc7, 0x52, 0x9c, 0x5b, 0x32, 0x1f, 0x5d, 0x43, 0x29, 0xac, 0x20, 0x5f, 0xf0, 0x43, 0x64, 0x17,
-	0x18, 0x4b, 0x72, 0xaf, 0xaa, 0x4d, 0x67, 0xe1, 0x60, 0x4c, 0xbe, 0xd7, 0xc2, 0x08, 0x69, 0xd3,
-	0xe3, 0x15, 0xe4, 0x09, 0x1f, 0x53, 0x76, 0x85, 0xa7, 0x81, 0x6b, 0xb5, 0xda, 0x59, 0xf2, 0x60,
-	0x2d, 0x86, 0x5e, 0x89, 0x36, 0x80, 0x13, 0x7e, 0x88, 0xb7, 0x5f, 0x80, 0xf8, 0x10, 0x04, 0x1f,
-	0x55, 0x4b, 0xec, 0x06, 0xa3, 0xad, 0xe8, 0x7b, 0x76, 0x56, 0x78, 0xcf, 0x62, 0x72, 0xbb, 0x3c,
-	0xff, 0xd5, 0xfc, 0x50, 0xb3, 0x23, 0x76, 0x87, 0x8b, 0xa7, 0x7d, 0x6d, 0x1b, 0xd3, 0xd5, 0xf4,
-	0xc7, 0x37, 0x6b, 0x60, 0x1b, 0x8c, 0xcb, 0xee, 0x1f, 0xdf, 0xe4, 0xb0, 0x86, 0xf2, 0x1a, 0x97,
-	0x8d, 0x92, 0xc5, 0x38, 0xa6, 0xbf, 0x54, 0x2e, 0x27, 0xf1, 0xca, 0x8f, 0x5b, 0xc1, 0xe7, 0x2c,
-	0xf2, 0x5d, 0x1d, 0x87, 0xb1, 0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x75, 0x07, 0x2f, 0xb1,
-	0x7c, 0x01, 0x00, 0x00,
+	// 363 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xc1, 0x8a, 0xdb, 0x30,
+	0x10, 0xad, 0x52, 0x27, 0x69, 0x26, 0x86, 0xa4, 0x3a, 0x04, 0xb7, 0x14, 0x1a, 0x7c, 0x69, 0xa0,
+	0xd4, 0x84, 0xa4, 0xa7, 0x1e, 0x1d, 0x0a, 0x29, 0x85, 0x12, 0x1c, 0xba, 0xd7, 0x20, 0xdb, 0x43,
+	0x62, 0x62, 0x59, 0x5a, 0x49, 0x5e, 0xc8, 0x77, 0xec, 0x1f, 0xec, 0x61, 0xbf, 0x73, 0x91, 0xec,
+	0x6c, 0xc2, 0x9e, 0x76, 0x6f, 0x7a, 0x6f, 0x34, 0xef, 0xcd, 0x3c, 0x06, 0xfc, 0x63, 0xc9, 0x4e,
+	0xa6, 0x8a, 0xa4, 0x12, 0x46, 0x50, 0x6f, 0xaf, 0x64, 0x16, 0xf6, 0xa1, 0xfb, 0x9b, 0x4b, 0x73,
+	0x0a, 0x6f, 0x00, 0x92, 0xcd, 0x2a, 0xc1, 0xdb, 0x1a, 0xb5, 0xa1, 0x01, 0xf4, 0x35, 0xaa, 0xbb,
+	0x22, 0xc3, 0x80, 0x4c, 0xc9, 0x6c, 0x90, 0x9c, 0x21, 0x9d, 0x40, 0x8f, 0xa3, 0x39, 0x88, 0x3c,
+	0xe8, 0xb8, 0x42, 0x8b, 0x2c, 0x2f, 0x99, 0x62, 0x5c, 0x07, 0xef, 0xa7, 0x64, 0xe6, 0x27, 0x2d,
+	0x0a, 0xbf, 0xc1, 0xd0, 0xe9, 0x6a, 0x29, 0x2a, 0x8d, 0x56, 0x58, 0xb2, 0x53, 0x29, 0x58, 0xee,
+	0x84, 0xfd, 0xe4, 0x0c, 0x43, 0x0a, 0xe3, 0x6d, 0x9d, 0xea, 0x4c, 0x15, 0x29, 0xb6, 0x63, 0x84,
+	0xf7, 0x04, 0x86, 0x71, 0x29, 0xb2, 0xe3, 0x1a, 0x59, 0x8e, 0xca, 0x9a, 0x54, 0x35, 0x4f, 0x51,
+	0xb9, 0x66, 0x2f, 0x69, 0x11, 0xa5, 0xe0, 0x1d, 0x98, 0x3e, 0xb8, 0x91, 0xfc, 0xc4, 0xbd, 0xe9,
+	0x57, 0x18, 0x4a, 0xa6, 0xb0, 0x32, 0x3b, 0x57, 0x6a, 0xa6, 0x82, 0x86, 0x5a, 0xdb, 0x0f, 0x5f,
+	0x60, 0x60, 0x0a, 0x8e, 0xda, 0x30, 0x2e, 0x03, 0xcf, 0xe9, 0x5d, 0x08, 0xfa, 0x09, 0x3e, 0xec,
+	0x99, 0xde, 0xd5, 0x1a, 0xf3, 0xa0, 0xeb, 0x8a, 0xfd, 0x3d, 0xd3, 0xff, 0x35, 0xe6, 0x8b, 0x47,
+	0x02, 0xf0, 0xd7, 0x45, 0xf9, 0x4f, 0xe4, 0x48, 0x7f, 0x80, 0xb7, 0x62, 0x65, 0x49, 0xc7, 0x91,
+	0x4d, 0x34, 0xba, 0xa4, 0xf8, 0xf9, 0xe3, 0x15, 0xd3, 0xec, 0x1f, 0xbe, 0xa3, 0x3f, 0x61, 0xf0,
+	0xbc, 0xe7, 0x2b, 0x7b, 0xe6, 0x84, 0x2e, 0xa1, 0x17, 0x17, 0x6f, 0xb0, 0x99, 0x91, 0x39, 0x59,
+	0xfc, 0x69, 0xd3, 0xdb, 0x1a, 0x85, 0x8c, 0xd3, 0x5f, 0xd7, 0xce, 0x93, 0xa6, 0xe9, 0x65, 0xe4,
+	0x67, 0xb1, 0xab, 0xd4, 0xad, 0x7f, 0xfc, 0x1d, 0x46, 0x99, 0xe0, 0x51, 0x7b, 0x41, 0xf6, 0x4b,
+	0x3c, 0xba, 0x64, 0xb0, 0xb1, 0x17, 0xb5, 0x21, 0x0f, 0x1d, 0xcf, 0x72, 0x69, 0xcf, 0x5d, 0xd8,
+	0xf2, 0x69, 0x00, 0x81, 0xb0, 0x64, 0xc4, 0x71, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -372,3 +483,94 @@ var _KlaytnNode_serviceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "klaytn.proto",
 }
+
+// BlockStreamClient is the client API for BlockStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BlockStreamClient interface {
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (BlockStream_SubscribeClient, error)
+}
+
+type blockStreamClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBlockStreamClient(cc *grpc.ClientConn) BlockStreamClient {
+	return &blockStreamClient{cc}
+}
+
+func (c *blockStreamClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (BlockStream_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlockStream_serviceDesc.Streams[0], "/grpc.BlockStream/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockStreamSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockStream_SubscribeClient interface {
+	Recv() (*BlockHeader, error)
+	grpc.ClientStream
+}
+
+type blockStreamSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockStreamSubscribeClient) Recv() (*BlockHeader, error) {
+	m := new(BlockHeader)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlockStreamServer is the server API for BlockStream service.
+type BlockStreamServer interface {
+	Subscribe(*SubscribeRequest, BlockStream_SubscribeServer) error
+}
+
+func RegisterBlockStreamServer(s *grpc.Server, srv BlockStreamServer) {
+	s.RegisterService(&_BlockStream_serviceDesc, srv)
+}
+
+func _BlockStream_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockStreamServer).Subscribe(m, &blockStreamSubscribeServer{stream})
+}
+
+type BlockStream_SubscribeServer interface {
+	Send(*BlockHeader) error
+	grpc.ServerStream
+}
+
+type blockStreamSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockStreamSubscribeServer) Send(m *BlockHeader) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BlockStream_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc.BlockStream",
+	HandlerType: (*BlockStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _BlockStream_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "klaytn.proto",
+}
@@ -0,0 +1,223 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+const (
+	// DefaultBootnodesRefreshInterval is the default interval between two
+	// fetches of the bootnode list served at Config.BootnodesURL.
+	DefaultBootnodesRefreshInterval = time.Hour
+
+	bootnodesFetchTimeout = 30 * time.Second
+	maxBootnodesListSize  = 1 << 20
+)
+
+var (
+	errBootnodesNotHTTPS     = errors.New("bootnode list URL must use https")
+	errBootnodesBadSignature = errors.New("bootnode list is not signed by the configured signer")
+)
+
+// SignedBootnodeList is the document served at Config.BootnodesURL. Signature
+// is a secp256k1 signature over the keccak256 hash of the raw List bytes, which
+// hold a JSON encoded BootnodeList.
+type SignedBootnodeList struct {
+	List      json.RawMessage `json:"list"`
+	Signature hexutil.Bytes   `json:"signature"`
+}
+
+// BootnodeList is the signed content of a SignedBootnodeList. Seq must increase
+// whenever the list is updated; lists with a sequence number not greater than
+// the last applied one are ignored.
+type BootnodeList struct {
+	Seq   uint64   `json:"seq"`
+	Nodes []string `json:"nodes"`
+}
+
+// SignBootnodeList encodes and signs the given list with the given key.
+func SignBootnodeList(list *BootnodeList, key *ecdsa.PrivateKey) (*SignedBootnodeList, error) {
+	raw, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(crypto.Keccak256(raw), key)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedBootnodeList{List: raw, Signature: sig}, nil
+}
+
+// Verify checks that the list is signed by signer and returns its content.
+func (s *SignedBootnodeList) Verify(signer common.Address) (*BootnodeList, error) {
+	pub, err := crypto.SigToPub(crypto.Keccak256(s.List), s.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pub) != signer {
+		return nil, errBootnodesBadSignature
+	}
+	list := new(BootnodeList)
+	if err := json.Unmarshal(s.List, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// fetchBootnodeList downloads the bootnode list served at rawurl and verifies
+// that it is signed by signer.
+func fetchBootnodeList(client *http.Client, rawurl string, signer common.Address) (*BootnodeList, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" {
+		return nil, errBootnodesNotHTTPS
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBootnodesListSize))
+	if err != nil {
+		return nil, err
+	}
+	signed := new(SignedBootnodeList)
+	if err := json.Unmarshal(body, signed); err != nil {
+		return nil, err
+	}
+	return signed.Verify(signer)
+}
+
+// parseBootnodes parses the kni URLs of the list, skipping invalid ones.
+// Nodes without a type are treated as bootnodes.
+func (srv *BaseServer) parseBootnodes(urls []string) []*discover.Node {
+	nodes := make([]*discover.Node, 0, len(urls))
+	for _, rawurl := range urls {
+		node, err := discover.ParseNode(rawurl)
+		if err != nil {
+			srv.logger.Warn("Ignoring invalid bootnode in fetched list", "kni", rawurl, "err", err)
+			continue
+		}
+		if node.NType == discover.NodeTypeUnknown {
+			node.NType = discover.NodeTypeBN
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// startBootnodes returns a snapshot of the bootstrap nodes for the discovery
+// table and the dialer of a starting server. Nodes added by AddBootstrapNodes
+// afterwards are handed over to the running dialer.
+func (srv *BaseServer) startBootnodes() []*discover.Node {
+	srv.bootnodesMu.Lock()
+	defer srv.bootnodesMu.Unlock()
+
+	srv.addbootnodes = make(chan []*discover.Node)
+	return append([]*discover.Node(nil), srv.BootstrapNodes...)
+}
+
+// AddBootstrapNodes merges the given nodes into the bootstrap nodes of the
+// server, its discovery table and its dialer. It returns the number of new
+// nodes.
+func (srv *BaseServer) AddBootstrapNodes(nodes []*discover.Node) int {
+	srv.bootnodesMu.Lock()
+	known := make(map[discover.NodeID]bool, len(srv.BootstrapNodes))
+	for _, n := range srv.BootstrapNodes {
+		known[n.ID] = true
+	}
+	var added []*discover.Node
+	for _, n := range nodes {
+		if known[n.ID] {
+			continue
+		}
+		srv.BootstrapNodes = append(srv.BootstrapNodes, n)
+		known[n.ID] = true
+		added = append(added, n)
+	}
+	if srv.ntab != nil {
+		srv.ntab.AddBootstrapNodes(nodes)
+	}
+	addbootnodes, quit := srv.addbootnodes, srv.quit
+	srv.bootnodesMu.Unlock()
+
+	if addbootnodes != nil && len(added) > 0 {
+		select {
+		case addbootnodes <- added:
+		case <-quit:
+		}
+	}
+	return len(added)
+}
+
+// refreshBootnodes fetches the bootnode list once and adopts its nodes if the
+// list is newer than the last applied one.
+func (srv *BaseServer) refreshBootnodes(client *http.Client) error {
+	list, err := fetchBootnodeList(client, srv.BootnodesURL, srv.BootnodesSigner)
+	if err != nil {
+		return err
+	}
+	if srv.bootnodesSeq != 0 && list.Seq <= srv.bootnodesSeq {
+		return nil
+	}
+	srv.bootnodesSeq = list.Seq
+	added := srv.AddBootstrapNodes(srv.parseBootnodes(list.Nodes))
+	srv.logger.Info("Refreshed bootnodes", "url", srv.BootnodesURL, "seq", list.Seq, "nodes", len(list.Nodes), "added", added)
+	return nil
+}
+
+// bootnodesRefreshLoop periodically refreshes the bootnodes from
+// Config.BootnodesURL until the server is stopped.
+func (srv *BaseServer) bootnodesRefreshLoop(quit <-chan struct{}) {
+	interval := srv.BootnodesRefreshInterval
+	if interval <= 0 {
+		interval = DefaultBootnodesRefreshInterval
+	}
+	client := &http.Client{Timeout: bootnodesFetchTimeout}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := srv.refreshBootnodes(client); err != nil {
+			srv.logger.Warn("Failed to refresh bootnodes", "url", srv.BootnodesURL, "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+	}
+}
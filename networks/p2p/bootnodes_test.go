@@ -0,0 +1,130 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+func TestRefreshBootnodes(t *testing.T) {
+	signerKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+
+	initial := discover.MustParseNode("kni://" + discover.PubkeyID(&newkey().PublicKey).String() + "@127.0.0.1:32323")
+	fresh := discover.MustParseNode("kni://" + discover.PubkeyID(&newkey().PublicKey).String() + "@127.0.0.2:32323")
+
+	var (
+		mu     sync.Mutex
+		served *SignedBootnodeList
+	)
+	serve := func(list *BootnodeList, key *ecdsa.PrivateKey) {
+		signed, err := SignBootnodeList(list, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		served = signed
+		mu.Unlock()
+	}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(served)
+	}))
+	defer ts.Close()
+
+	srv := &BaseServer{
+		Config: Config{
+			BootstrapNodes:  []*discover.Node{initial},
+			BootnodesURL:    ts.URL,
+			BootnodesSigner: crypto.PubkeyToAddress(signerKey.PublicKey),
+		},
+		logger: logger.NewWith(),
+	}
+
+	// A list signed by another key must be rejected.
+	serve(&BootnodeList{Seq: 1, Nodes: []string{fresh.String()}}, otherKey)
+	if err := srv.refreshBootnodes(ts.Client()); err != errBootnodesBadSignature {
+		t.Fatalf("unexpected error for a poisoned list: %v", err)
+	}
+	if len(srv.BootstrapNodes) != 1 {
+		t.Fatalf("poisoned list adopted: %v", srv.BootstrapNodes)
+	}
+
+	// An updated list signed by the configured signer is adopted.
+	serve(&BootnodeList{Seq: 1, Nodes: []string{initial.String(), fresh.String()}}, signerKey)
+	if err := srv.refreshBootnodes(ts.Client()); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.BootstrapNodes) != 2 || srv.BootstrapNodes[1].ID != fresh.ID {
+		t.Fatalf("updated list not adopted: %v", srv.BootstrapNodes)
+	}
+	if srv.BootstrapNodes[1].NType != discover.NodeTypeBN {
+		t.Errorf("fetched node type mismatch: have %v, want %v", srv.BootstrapNodes[1].NType, discover.NodeTypeBN)
+	}
+
+	// A stale list is ignored.
+	stale := discover.MustParseNode("kni://" + discover.PubkeyID(&newkey().PublicKey).String() + "@127.0.0.3:32323")
+	serve(&BootnodeList{Seq: 1, Nodes: []string{stale.String()}}, signerKey)
+	if err := srv.refreshBootnodes(ts.Client()); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.BootstrapNodes) != 2 {
+		t.Fatalf("stale list adopted: %v", srv.BootstrapNodes)
+	}
+
+	// Plain http is refused.
+	srv.BootnodesURL = "http://" + ts.Listener.Addr().String()
+	if err := srv.refreshBootnodes(ts.Client()); err != errBootnodesNotHTTPS {
+		t.Fatalf("unexpected error for a plain http URL: %v", err)
+	}
+}
+
+func TestAddBootstrapNodesDialer(t *testing.T) {
+	initial := discover.MustParseNode("kni://" + discover.PubkeyID(&newkey().PublicKey).String() + "@127.0.0.1:32323")
+	fresh := discover.MustParseNode("kni://" + discover.PubkeyID(&newkey().PublicKey).String() + "@127.0.0.2:32323")
+
+	srv := &BaseServer{
+		Config: Config{BootstrapNodes: []*discover.Node{initial}},
+		quit:   make(chan struct{}),
+		logger: logger.NewWith(),
+	}
+	defer close(srv.quit)
+	dialer := newDialState(nil, srv.startBootnodes(), nil, 0, nil, nil, nil)
+
+	// Nodes added after the start must be handed over to the dialer.
+	done := make(chan struct{})
+	go func() {
+		dialer.addBootnodes(<-srv.addbootnodes)
+		close(done)
+	}()
+	if added := srv.AddBootstrapNodes([]*discover.Node{initial, fresh}); added != 1 {
+		t.Fatalf("added nodes mismatch: have %d, want 1", added)
+	}
+	<-done
+	if len(dialer.bootnodes) != 2 || dialer.bootnodes[1].ID != fresh.ID {
+		t.Fatalf("dialer bootnodes not updated: %v", dialer.bootnodes)
+	}
+}
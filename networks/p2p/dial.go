@@ -259,6 +259,21 @@ func (s *dialstate) addTypedStatic(n *discover.Node, dType dialType) {
 	}
 }
 
+// addBootnodes adds the given nodes to the default dials, skipping the ones
+// which are already known.
+func (s *dialstate) addBootnodes(nodes []*discover.Node) {
+	known := make(map[discover.NodeID]bool, len(s.bootnodes))
+	for _, n := range s.bootnodes {
+		known[n.ID] = true
+	}
+	for _, n := range nodes {
+		if !known[n.ID] {
+			s.bootnodes = append(s.bootnodes, n)
+			known[n.ID] = true
+		}
+	}
+}
+
 func (s *dialstate) removeStatic(n *discover.Node) {
 	// This removes a task so future attempts to connect will not be made.
 	delete(s.static, n.ID)
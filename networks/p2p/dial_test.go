@@ -125,6 +125,7 @@ func (t fakeTable) IsAuthorized(id discover.NodeID, ntype discover.NodeType) boo
 func (t fakeTable) GetAuthorizedNodes() []*discover.Node         { return nil }
 func (t fakeTable) PutAuthorizedNodes(nodes []*discover.Node)    {}
 func (t fakeTable) DeleteAuthorizedNodes(nodes []*discover.Node) {}
+func (t fakeTable) AddBootstrapNodes(nodes []*discover.Node) int { return 0 }
 
 // This test checks that dynamic dials are launched from discovery results.
 func TestDialStateDynDial(t *testing.T) {
@@ -963,3 +964,7 @@ func (t *resolveMock) PutAuthorizedNodes(nodes []*discover.Node) {
 func (t *resolveMock) DeleteAuthorizedNodes(nodes []*discover.Node) {
 	panic("implement me")
 }
+
+func (t *resolveMock) AddBootstrapNodes(nodes []*discover.Node) int {
+	panic("implement me")
+}
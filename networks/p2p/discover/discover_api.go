@@ -66,6 +66,12 @@ func (tab *Table) GetReplacements() []*Node {
 	return ret
 }
 
+// AddBootstrapNodes adds the given nodes to the bootstrap nodes used to
+// (re)connect to the network and returns the number of newly added nodes.
+func (tab *Table) AddBootstrapNodes(nodes []*Node) int {
+	return tab.addFallbackNodes(nodes)
+}
+
 // DeleteNodeFromDB deletes node which has id in peer database.
 func (tab *Table) DeleteNodeFromDB(n *Node) error {
 	return tab.db.deleteNode(n.ID)
@@ -77,6 +77,7 @@ type Discovery interface {
 	DeleteNodeFromTable(n *Node) error
 	GetBucketEntries() []*Node
 	GetReplacements() []*Node
+	AddBootstrapNodes(nodes []*Node) int
 
 	GetAuthorizedNodes() []*Node
 	PutAuthorizedNodes(nodes []*Node)
@@ -84,10 +85,11 @@ type Discovery interface {
 }
 
 type Table struct {
-	nursery []*Node     // bootstrap nodes
-	rand    *mrand.Rand // source of randomness, periodically reseeded
-	randMu  sync.Mutex
-	ips     netutil.DistinctNetSet
+	nursery   []*Node // bootstrap nodes
+	nurseryMu sync.RWMutex
+	rand      *mrand.Rand // source of randomness, periodically reseeded
+	randMu    sync.Mutex
+	ips       netutil.DistinctNetSet
 
 	db         *nodeDB // database of known nodes
 	refreshReq chan chan struct{}
@@ -202,6 +204,8 @@ func (tab *Table) setFallbackNodes(nodes []*Node) error {
 			return fmt.Errorf("bad bootstrap/fallback node %q (%v)", n, err)
 		}
 	}
+	tab.nurseryMu.Lock()
+	defer tab.nurseryMu.Unlock()
 	tab.nursery = make([]*Node, 0, len(nodes))
 	for _, n := range nodes {
 		cpy := *n
@@ -213,6 +217,41 @@ func (tab *Table) setFallbackNodes(nodes []*Node) error {
 	return nil
 }
 
+// addFallbackNodes appends the given nodes to the initial points of contact,
+// skipping invalid nodes and nodes already known. It returns the number of
+// nodes added.
+func (tab *Table) addFallbackNodes(nodes []*Node) int {
+	tab.nurseryMu.Lock()
+	defer tab.nurseryMu.Unlock()
+	known := make(map[NodeID]bool, len(tab.nursery))
+	for _, n := range tab.nursery {
+		known[n.ID] = true
+	}
+	added := 0
+	for _, n := range nodes {
+		if known[n.ID] {
+			continue
+		}
+		if err := n.validateComplete(); err != nil {
+			tab.localLogger.Warn("Ignoring bad bootstrap node", "node", n, "err", err)
+			continue
+		}
+		cpy := *n
+		cpy.sha = crypto.Keccak256Hash(n.ID[:])
+		tab.nursery = append(tab.nursery, &cpy)
+		known[n.ID] = true
+		added++
+	}
+	return added
+}
+
+// fallbackNodes returns a copy of the initial points of contact.
+func (tab *Table) fallbackNodes() []*Node {
+	tab.nurseryMu.RLock()
+	defer tab.nurseryMu.RUnlock()
+	return append([]*Node{}, tab.nursery...)
+}
+
 func (tab *Table) findNewNode(seeds *nodesByDistance, targetID NodeID, targetNT NodeType, recursiveFind bool, max int) []*Node {
 	var (
 		asked          = make(map[NodeID]bool)
@@ -508,7 +547,7 @@ func (tab *Table) loadSeedNodes(bond bool) {
 	// TODO-Klaytn-Node Separate logic to storages.
 	seeds := tab.db.querySeeds(seedCount, seedMaxAge)
 	seeds = removeBn(seeds)
-	seeds = append(seeds, tab.nursery...)
+	seeds = append(seeds, tab.fallbackNodes()...)
 	if bond {
 		seeds = tab.bondall(seeds)
 	}
@@ -188,6 +188,11 @@ func (p *Peer) Inbound() bool {
 	return p.rws[ConnDefault].flags&inboundConn != 0
 }
 
+// Trusted returns true if the peer is connected as a trusted peer.
+func (p *Peer) Trusted() bool {
+	return p.rws[ConnDefault].is(trustedConn)
+}
+
 // GetNumberInboundAndOutbound returns the number of
 // inbound and outbound connections connected to the peer.
 func (p *Peer) GetNumberInboundAndOutbound() (int, int) {
@@ -104,6 +104,13 @@ type Config struct {
 	// with the rest of the network.
 	BootstrapNodes []*discover.Node
 
+	// BootnodesURL is an https URL serving a bootnode list signed by
+	// BootnodesSigner. If set, the list is fetched every
+	// BootnodesRefreshInterval and its nodes are added to BootstrapNodes.
+	BootnodesURL             string         `toml:",omitempty"`
+	BootnodesSigner          common.Address `toml:",omitempty"`
+	BootnodesRefreshInterval time.Duration  `toml:",omitempty"`
+
 	//// BootstrapNodesV5 are used to establish connectivity
 	//// with the rest of the network using the V5 discovery
 	//// protocol.
@@ -335,6 +342,8 @@ func (srv *MultiChannelServer) Start() (err error) {
 	srv.peerOpDone = make(chan struct{})
 	srv.discpeer = make(chan discover.NodeID)
 
+	bootnodes := srv.startBootnodes()
+
 	var (
 		conn      *net.UDPConn
 		realaddr  *net.UDPAddr
@@ -369,7 +378,7 @@ func (srv *MultiChannelServer) Start() (err error) {
 			AnnounceAddr: realaddr,
 			NodeDBPath:   srv.NodeDatabase,
 			NetRestrict:  srv.NetRestrict,
-			Bootnodes:    srv.BootstrapNodes,
+			Bootnodes:    bootnodes,
 			Unhandled:    unhandled,
 			Conn:         conn,
 			Addr:         realaddr,
@@ -385,7 +394,7 @@ func (srv *MultiChannelServer) Start() (err error) {
 		srv.ntab = ntab
 	}
 
-	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, srv.maxDialedConns(), srv.NetRestrict, srv.PrivateKey, srv.getTypeStatics())
+	dialer := newDialState(srv.StaticNodes, bootnodes, srv.ntab, srv.maxDialedConns(), srv.NetRestrict, srv.PrivateKey, srv.getTypeStatics())
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name(), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey), Multichannel: true}
@@ -417,6 +426,9 @@ func (srv *MultiChannelServer) Start() (err error) {
 
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
+	if srv.BootnodesURL != "" {
+		go srv.bootnodesRefreshLoop(srv.quit)
+	}
 	srv.running = true
 	srv.logger.Info("Started P2P server", "id", discover.PubkeyID(&srv.PrivateKey.PublicKey), "multichannel", true)
 	return nil
@@ -680,6 +692,11 @@ running:
 			if p, ok := peers[n.ID]; ok {
 				p.Disconnect(DiscRequested)
 			}
+		case nodes := <-srv.addbootnodes:
+			// This channel is used by AddBootstrapNodes to hand
+			// newly fetched bootnodes over to the dialer.
+			srv.logger.Debug("Adding bootnodes", "count", len(nodes))
+			dialstate.addBootnodes(nodes)
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			op(peers)
@@ -914,6 +931,7 @@ type BaseServer struct {
 	quit          chan struct{}
 	addstatic     chan *discover.Node
 	removestatic  chan *discover.Node
+	addbootnodes  chan []*discover.Node
 	posthandshake chan *conn
 	addpeer       chan *conn
 	delpeer       chan peerDrop
@@ -923,6 +941,9 @@ type BaseServer struct {
 	logger        log.Logger
 
 	peerOnParentChain map[*discover.Node]bool
+
+	bootnodesMu  sync.Mutex // protects BootstrapNodes after start
+	bootnodesSeq uint64     // sequence number of the last applied bootnode list
 }
 
 type peerOpFunc func(map[discover.NodeID]*Peer)
@@ -1255,6 +1276,8 @@ func (srv *BaseServer) Start() (err error) {
 	srv.peerOpDone = make(chan struct{})
 	srv.discpeer = make(chan discover.NodeID)
 
+	bootnodes := srv.startBootnodes()
+
 	var (
 		conn      *net.UDPConn
 		realaddr  *net.UDPAddr
@@ -1289,7 +1312,7 @@ func (srv *BaseServer) Start() (err error) {
 			AnnounceAddr: realaddr,
 			NodeDBPath:   srv.NodeDatabase,
 			NetRestrict:  srv.NetRestrict,
-			Bootnodes:    srv.BootstrapNodes,
+			Bootnodes:    bootnodes,
 			Unhandled:    unhandled,
 			Conn:         conn,
 			Addr:         realaddr,
@@ -1310,7 +1333,7 @@ func (srv *BaseServer) Start() (err error) {
 		srv.ntab = ntab
 	}
 
-	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, srv.maxDialedConns(), srv.NetRestrict, srv.PrivateKey, srv.getTypeStatics())
+	dialer := newDialState(srv.StaticNodes, bootnodes, srv.ntab, srv.maxDialedConns(), srv.NetRestrict, srv.PrivateKey, srv.getTypeStatics())
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name(), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey), Multichannel: false}
@@ -1333,6 +1356,9 @@ func (srv *BaseServer) Start() (err error) {
 
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
+	if srv.BootnodesURL != "" {
+		go srv.bootnodesRefreshLoop(srv.quit)
+	}
 	srv.running = true
 	srv.logger.Info("Started P2P server", "id", discover.PubkeyID(&srv.PrivateKey.PublicKey), "multichannel", false)
 	return nil
@@ -1365,6 +1391,7 @@ type dialer interface {
 	taskDone(task, time.Time)
 	addStatic(*discover.Node)
 	removeStatic(*discover.Node)
+	addBootnodes([]*discover.Node)
 }
 
 func (srv *BaseServer) run(dialstate dialer) {
@@ -1437,6 +1464,11 @@ running:
 			if p, ok := peers[n.ID]; ok {
 				p.Disconnect(DiscRequested)
 			}
+		case nodes := <-srv.addbootnodes:
+			// This channel is used by AddBootstrapNodes to hand
+			// newly fetched bootnodes over to the dialer.
+			srv.logger.Debug("Adding bootnodes", "count", len(nodes))
+			dialstate.addBootnodes(nodes)
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			op(peers)
@@ -356,6 +356,8 @@ func (tg taskgen) addStatic(*discover.Node) {
 }
 func (tg taskgen) removeStatic(*discover.Node) {
 }
+func (tg taskgen) addBootnodes([]*discover.Node) {
+}
 
 type testTask struct {
 	index  int
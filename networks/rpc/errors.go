@@ -30,6 +30,15 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("The method %s%s%s does not exist/is not available", e.service, serviceMethodSeparator, e.method)
 }
 
+// request is for a service which has been disabled at runtime
+type namespaceDisabledError struct{ service string }
+
+func (e *namespaceDisabledError) ErrorCode() int { return -32601 }
+
+func (e *namespaceDisabledError) Error() string {
+	return fmt.Sprintf("The %s namespace is disabled on this endpoint", e.service)
+}
+
 // received message isn't a valid request
 type invalidRequestError struct{ message string }
 
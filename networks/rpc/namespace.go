@@ -0,0 +1,49 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import "fmt"
+
+// SetNamespaceEnabled enables or disables every method of the given namespace
+// on this server. Calls to a disabled namespace are rejected with an error
+// until it is enabled again. The namespace must have been registered.
+func (s *Server) SetNamespaceEnabled(namespace string, enabled bool) error {
+	if _, ok := s.services[namespace]; !ok {
+		return fmt.Errorf("namespace %q is not available on this endpoint", namespace)
+	}
+
+	s.disabledMu.Lock()
+	defer s.disabledMu.Unlock()
+
+	if enabled {
+		delete(s.disabled, namespace)
+		return nil
+	}
+	if s.disabled == nil {
+		s.disabled = make(map[string]bool)
+	}
+	s.disabled[namespace] = true
+	return nil
+}
+
+// isNamespaceDisabled returns true if calls to the given namespace are rejected.
+func (s *Server) isNamespaceDisabled(namespace string) bool {
+	s.disabledMu.RLock()
+	defer s.disabledMu.RUnlock()
+
+	return s.disabled[namespace]
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import "testing"
+
+func TestServerSetNamespaceEnabled(t *testing.T) {
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("other", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	if err := server.SetNamespaceEnabled("test", false); err != nil {
+		t.Fatal(err)
+	}
+	var result Result
+	err := client.Call(&result, "test_echo", "x", 1, &Args{"x"})
+	if want := (&namespaceDisabledError{"test"}).Error(); err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+	if err := client.Call(&result, "other_echo", "x", 1, &Args{"x"}); err != nil {
+		t.Fatalf("call to an enabled namespace failed: %v", err)
+	}
+
+	if err := server.SetNamespaceEnabled("test", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&result, "test_echo", "x", 1, &Args{"x"}); err != nil {
+		t.Fatalf("call to a re-enabled namespace failed: %v", err)
+	}
+
+	if err := server.SetNamespaceEnabled("missing", false); err == nil {
+		t.Fatal("expected an error for an unregistered namespace")
+	}
+}
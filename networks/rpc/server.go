@@ -29,6 +29,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const MetadataApi = "rpc"
@@ -66,6 +67,14 @@ func NewServer() *Server {
 	return server
 }
 
+// SetSubscriptionBuffer bounds the number of notifications buffered for each
+// subscription and sets the policy applied to consumers which can't keep up.
+// It must be called before the server starts serving codecs.
+func (s *Server) SetSubscriptionBuffer(size int, policy SubscriptionBufferPolicy) {
+	s.subBufferSize = size
+	s.subBufferPolicy = policy
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
@@ -158,7 +167,7 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 	// to send notification to clients. It is thight to the codec/connection. If the
 	// connection is closed the notifier will stop and cancels all active subscriptions.
 	if options&OptionSubscriptions == OptionSubscriptions {
-		ctx = context.WithValue(ctx, notifierKey{}, newNotifier(codec))
+		ctx = context.WithValue(ctx, notifierKey{}, newNotifier(codec, s.subBufferSize, s.subBufferPolicy))
 	}
 	s.codecsMu.Lock()
 	if atomic.LoadInt32(&s.run) != 1 { // server stopped
@@ -354,7 +363,9 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	//logger.Error("### rpc.server", "#tx", callSendTx, "#receipt", callCount)
 
 	// execute RPC method and return result
+	start := time.Now()
 	reply := req.callb.method.Func.Call(arguments)
+	s.checkSlowCall(ctx, req, time.Since(start))
 	if len(reply) == 0 {
 		return codec.CreateResponse(req.id, nil), nil
 	}
@@ -469,6 +480,11 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 			continue
 		}
 
+		if s.isNamespaceDisabled(r.service) {
+			requests[i] = &serverRequest{id: r.id, err: &namespaceDisabledError{r.service}}
+			continue
+		}
+
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
 				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
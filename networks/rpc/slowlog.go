@@ -0,0 +1,75 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/klaytn/klaytn/metrics"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxSlowLogParamsLen bounds the length of the params summary of a slow call log.
+const maxSlowLogParamsLen = 256
+
+var slowCallCounter = metrics.NewRegisteredCounter("klay/rpc/slow", nil)
+
+// slowCallLogger reports an RPC call which took longer than the slow log threshold.
+// It is a variable so that tests can observe slow calls.
+var slowCallLogger = func(method, params string, elapsed time.Duration, caller string) {
+	logger.Warn("Slow RPC call", "method", method, "params", params, "elapsed", elapsed, "caller", caller)
+}
+
+// SetSlowLogThreshold makes the server log every RPC call whose handler takes
+// longer than d. A zero or negative duration disables the slow call log.
+func (s *Server) SetSlowLogThreshold(d time.Duration) {
+	atomic.StoreInt64(&s.slowLogThreshold, int64(d))
+}
+
+// checkSlowCall logs the given request if it took longer than the slow log threshold.
+func (s *Server) checkSlowCall(ctx context.Context, req *serverRequest, elapsed time.Duration) {
+	threshold := time.Duration(atomic.LoadInt64(&s.slowLogThreshold))
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+	slowCallCounter.Inc(1)
+	method := req.svcname + serviceMethodSeparator + formatName(req.callb.method.Name)
+	slowCallLogger(method, summarizeParams(req), elapsed, callerFromContext(ctx))
+}
+
+// summarizeParams returns a truncated, human readable form of the request arguments.
+func summarizeParams(req *serverRequest) string {
+	args := make([]string, len(req.args))
+	for i, arg := range req.args {
+		args[i] = fmt.Sprintf("%v", arg.Interface())
+	}
+	summary := "[" + strings.Join(args, ", ") + "]"
+	if len(summary) > maxSlowLogParamsLen {
+		summary = summary[:maxSlowLogParamsLen] + "..."
+	}
+	return summary
+}
+
+// callerFromContext returns the remote address of the request if the transport provides it.
+func callerFromContext(ctx context.Context) string {
+	if remote, ok := ctx.Value("remote").(string); ok && remote != "" {
+		return remote
+	}
+	return "local"
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServerSlowLog(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		logged []string
+	)
+	defer func(orig func(string, string, time.Duration, string)) { slowCallLogger = orig }(slowCallLogger)
+	slowCallLogger = func(method, params string, elapsed time.Duration, caller string) {
+		mu.Lock()
+		defer mu.Unlock()
+		logged = append(logged, method)
+	}
+
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	server.SetSlowLogThreshold(50 * time.Millisecond)
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "fast", 1, &Args{"fast"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_sleep", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(logged) != 1 || logged[0] != "test_sleep" {
+		t.Fatalf("expected only test_sleep to be logged as slow, got %v", logged)
+	}
+}
+
+func TestServerSlowLogDisabled(t *testing.T) {
+	var called int32
+	defer func(orig func(string, string, time.Duration, string)) { slowCallLogger = orig }(slowCallLogger)
+	slowCallLogger = func(string, string, time.Duration, string) { atomic.StoreInt32(&called, 1) }
+
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	if err := client.Call(nil, "test_sleep", 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&called) != 0 {
+		t.Fatal("slow call logged while the slow log is disabled")
+	}
+}
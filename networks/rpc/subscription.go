@@ -19,6 +19,8 @@ package rpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/klaytn/klaytn/metrics"
 	"sync"
 )
 
@@ -27,8 +29,36 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrNotificationNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriberTooSlow is returned when the connection of a subscriber is closed
+	// because its notification buffer is full
+	ErrSubscriberTooSlow = errors.New("subscriber too slow, notification buffer full")
 )
 
+var (
+	subscriptionDroppedCounter      = metrics.NewRegisteredCounter("rpc/subscription/dropped", nil)
+	subscriptionDisconnectedCounter = metrics.NewRegisteredCounter("rpc/subscription/disconnected", nil)
+)
+
+// SubscriptionBufferPolicy decides what a notifier does with a new notification
+// when the notification buffer of a subscription is full.
+type SubscriptionBufferPolicy string
+
+const (
+	// DropOldestPolicy discards the oldest buffered notification to make room for the new one.
+	DropOldestPolicy SubscriptionBufferPolicy = "drop-oldest"
+	// DisconnectPolicy closes the connection of the consumer which can't keep up.
+	DisconnectPolicy SubscriptionBufferPolicy = "disconnect"
+)
+
+// ParseSubscriptionBufferPolicy returns the SubscriptionBufferPolicy of the given name.
+func ParseSubscriptionBufferPolicy(name string) (SubscriptionBufferPolicy, error) {
+	switch policy := SubscriptionBufferPolicy(name); policy {
+	case DropOldestPolicy, DisconnectPolicy:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown subscription buffer policy %q (want %q or %q)", name, DropOldestPolicy, DisconnectPolicy)
+}
+
 // ID defines a pseudo random number that is used to identify RPC subscriptions.
 type ID string
 
@@ -38,6 +68,10 @@ type Subscription struct {
 	ID        ID
 	namespace string
 	err       chan error // closed on unsubscribe
+
+	queueMu sync.Mutex    // guards queue
+	queue   []interface{} // notifications waiting to be written, used if the notifier is buffered
+	wakeup  chan struct{} // signals the send loop that queue has notifications
 }
 
 // Err returns a channel that is closed when the client send an unsubscribe request.
@@ -55,15 +89,22 @@ type Notifier struct {
 	subMu    sync.RWMutex // guards active and inactive maps
 	active   map[ID]*Subscription
 	inactive map[ID]*Subscription
+
+	bufferSize   int                      // max number of buffered notifications per subscription, 0 means unbuffered
+	bufferPolicy SubscriptionBufferPolicy // applied when the buffer of a subscription is full
 }
 
 // newNotifier creates a new notifier that can be used to send subscription
-// notifications to the client.
-func newNotifier(codec ServerCodec) *Notifier {
+// notifications to the client. If bufferSize is positive, notifications are
+// written asynchronously through a per-subscription buffer of that size and
+// bufferPolicy is applied when a subscription's buffer overflows.
+func newNotifier(codec ServerCodec, bufferSize int, bufferPolicy SubscriptionBufferPolicy) *Notifier {
 	return &Notifier{
-		codec:    codec,
-		active:   make(map[ID]*Subscription),
-		inactive: make(map[ID]*Subscription),
+		codec:        codec,
+		active:       make(map[ID]*Subscription),
+		inactive:     make(map[ID]*Subscription),
+		bufferSize:   bufferSize,
+		bufferPolicy: bufferPolicy,
 	}
 }
 
@@ -94,6 +135,9 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	sub, active := n.active[id]
 	if active {
 		notification := n.codec.CreateNotification(string(id), sub.namespace, data)
+		if n.bufferSize > 0 {
+			return n.enqueue(sub, notification)
+		}
 		if err := n.codec.Write(notification); err != nil {
 			n.codec.Close()
 			return err
@@ -102,6 +146,62 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	return nil
 }
 
+// enqueue adds the notification to the buffer of the subscription. If the
+// buffer is full, the buffer policy of the notifier is applied.
+func (n *Notifier) enqueue(sub *Subscription, notification interface{}) error {
+	sub.queueMu.Lock()
+	if len(sub.queue) >= n.bufferSize {
+		if n.bufferPolicy == DisconnectPolicy {
+			sub.queueMu.Unlock()
+			subscriptionDisconnectedCounter.Inc(1)
+			logger.Warn("Closing the connection of a slow subscriber", "id", sub.ID, "buffer", n.bufferSize)
+			n.codec.Close()
+			return ErrSubscriberTooSlow
+		}
+		sub.queue[0] = nil
+		sub.queue = sub.queue[1:]
+		subscriptionDroppedCounter.Inc(1)
+	}
+	sub.queue = append(sub.queue, notification)
+	sub.queueMu.Unlock()
+
+	select {
+	case sub.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// sendLoop writes the buffered notifications of the subscription to the client
+// until the subscription is unsubscribed or the connection is closed.
+func (n *Notifier) sendLoop(sub *Subscription) {
+	for {
+		select {
+		case <-sub.wakeup:
+		case <-sub.err:
+			return
+		case <-n.codec.Closed():
+			return
+		}
+		for {
+			sub.queueMu.Lock()
+			if len(sub.queue) == 0 {
+				sub.queueMu.Unlock()
+				break
+			}
+			notification := sub.queue[0]
+			sub.queue[0] = nil
+			sub.queue = sub.queue[1:]
+			sub.queueMu.Unlock()
+
+			if err := n.codec.Write(notification); err != nil {
+				n.codec.Close()
+				return
+			}
+		}
+	}
+}
+
 // Closed returns a channel that is closed when the RPC connection is closed.
 func (n *Notifier) Closed() <-chan interface{} {
 	return n.codec.Closed()
@@ -131,5 +231,9 @@ func (n *Notifier) activate(id ID, namespace string) {
 		sub.namespace = namespace
 		n.active[id] = sub
 		delete(n.inactive, id)
+		if n.bufferSize > 0 {
+			sub.wakeup = make(chan struct{}, 1)
+			go n.sendLoop(sub)
+		}
 	}
 }
@@ -332,3 +332,78 @@ func TestSubscriptionMultipleNamespaces(t *testing.T) {
 		}
 	}
 }
+
+// newSlowNotifier returns a notifier whose client never reads, so every
+// write to the connection blocks like a consumer which can't keep up.
+func newSlowNotifier(bufferSize int, policy SubscriptionBufferPolicy) (*Notifier, *Subscription, func()) {
+	clientConn, serverConn := net.Pipe()
+	codec := NewJSONCodec(serverConn)
+	notifier := newNotifier(codec, bufferSize, policy)
+	sub := notifier.CreateSubscription()
+	notifier.activate(sub.ID, "klay")
+	return notifier, sub, func() {
+		codec.Close()
+		clientConn.Close()
+	}
+}
+
+func TestNotifierDropOldestPolicy(t *testing.T) {
+	bufferSize := 3
+	notifier, sub, closeFn := newSlowNotifier(bufferSize, DropOldestPolicy)
+	defer closeFn()
+
+	for i := 0; i < 100; i++ {
+		if err := notifier.Notify(sub.ID, i); err != nil {
+			t.Fatalf("unexpected error on notification %d: %v", i, err)
+		}
+	}
+
+	sub.queueMu.Lock()
+	queue := append([]interface{}(nil), sub.queue...)
+	sub.queueMu.Unlock()
+	if len(queue) != bufferSize {
+		t.Fatalf("buffer length mismatch: have %d, want %d", len(queue), bufferSize)
+	}
+	// Only the newest notifications are kept in the buffer.
+	for i, notification := range queue {
+		msg := notification.(*jsonNotification)
+		if have, want := msg.Params.Result, 100-bufferSize+i; have != want {
+			t.Errorf("buffered notification %d mismatch: have %v, want %d", i, have, want)
+		}
+	}
+	select {
+	case <-notifier.Closed():
+		t.Error("connection closed on drop-oldest policy")
+	default:
+	}
+}
+
+func TestNotifierDisconnectPolicy(t *testing.T) {
+	bufferSize := 3
+	notifier, sub, closeFn := newSlowNotifier(bufferSize, DisconnectPolicy)
+	defer closeFn()
+
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		err = notifier.Notify(sub.ID, i)
+	}
+	if err != ErrSubscriberTooSlow {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrSubscriberTooSlow)
+	}
+	select {
+	case <-notifier.Closed():
+	case <-time.After(time.Second):
+		t.Error("connection of the slow consumer is not closed")
+	}
+}
+
+func TestParseSubscriptionBufferPolicy(t *testing.T) {
+	for _, policy := range []SubscriptionBufferPolicy{DropOldestPolicy, DisconnectPolicy} {
+		if have, err := ParseSubscriptionBufferPolicy(string(policy)); err != nil || have != policy {
+			t.Errorf("policy mismatch: have %q (err: %v), want %q", have, err, policy)
+		}
+	}
+	if _, err := ParseSubscriptionBufferPolicy("unknown"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
@@ -21,10 +21,12 @@
 package rpc
 
 import (
+	"encoding/json"
 	"reflect"
 	"sync"
 
 	"fmt"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 	"gopkg.in/fatih/set.v0"
 	"math"
@@ -78,6 +80,14 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   *set.Set
+
+	subBufferSize   int                      // per-subscription notification buffer size, 0 means unbuffered
+	subBufferPolicy SubscriptionBufferPolicy // applied when a subscription buffer is full
+
+	slowLogThreshold int64 // calls taking longer than this duration (in nanoseconds) are logged, 0 disables it
+
+	disabledMu sync.RWMutex
+	disabled   map[string]bool // namespaces whose calls are rejected at runtime
 }
 
 // rpcRequest represents a raw incoming RPC request
@@ -167,3 +177,75 @@ func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
 func (bn BlockNumber) Int64() int64 {
 	return (int64)(bn)
 }
+
+// BlockNumberOrHash is an argument which accepts either a block number (including
+// the "latest", "earliest" and "pending" tags) or a block hash.
+type BlockNumberOrHash struct {
+	BlockNumber *BlockNumber `json:"blockNumber,omitempty"`
+	BlockHash   *common.Hash `json:"blockHash,omitempty"`
+}
+
+// UnmarshalJSON parses the given JSON fragment into a BlockNumberOrHash. It supports:
+// - "latest", "earliest" or "pending" as string arguments
+// - the block number as a hex string
+// - the block hash as a 32 byte hex string
+// - an object with either a "blockNumber" or a "blockHash" field
+func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
+	type object BlockNumberOrHash
+	var obj object
+	if err := json.Unmarshal(data, &obj); err == nil {
+		if obj.BlockNumber != nil && obj.BlockHash != nil {
+			return fmt.Errorf("cannot specify both BlockHash and BlockNumber, choose one or the other")
+		}
+		if obj.BlockNumber == nil && obj.BlockHash == nil {
+			return fmt.Errorf("either BlockHash or BlockNumber must be specified")
+		}
+		*bnh = BlockNumberOrHash(obj)
+		return nil
+	}
+
+	var input string
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	if len(input) == 2*common.HashLength+2 {
+		hash := common.Hash{}
+		if err := hash.UnmarshalText([]byte(input)); err != nil {
+			return err
+		}
+		bnh.BlockHash = &hash
+		return nil
+	}
+	bn := new(BlockNumber)
+	if err := bn.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	bnh.BlockNumber = bn
+	return nil
+}
+
+// Number returns the block number, if the argument was given as a block number.
+func (bnh *BlockNumberOrHash) Number() (BlockNumber, bool) {
+	if bnh.BlockNumber != nil {
+		return *bnh.BlockNumber, true
+	}
+	return BlockNumber(0), false
+}
+
+// Hash returns the block hash, if the argument was given as a block hash.
+func (bnh *BlockNumberOrHash) Hash() (common.Hash, bool) {
+	if bnh.BlockHash != nil {
+		return *bnh.BlockHash, true
+	}
+	return common.Hash{}, false
+}
+
+// BlockNumberOrHashWithNumber returns a BlockNumberOrHash which refers to the given block number.
+func BlockNumberOrHashWithNumber(blockNr BlockNumber) BlockNumberOrHash {
+	return BlockNumberOrHash{BlockNumber: &blockNr}
+}
+
+// BlockNumberOrHashWithHash returns a BlockNumberOrHash which refers to the given block hash.
+func BlockNumberOrHashWithHash(hash common.Hash) BlockNumberOrHash {
+	return BlockNumberOrHash{BlockHash: &hash}
+}
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/math"
 )
 
@@ -68,3 +69,48 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockNumberOrHashJSONUnmarshal(t *testing.T) {
+	hash := common.HexToHash("0x3f1d5e0f3f1d5e0f3f1d5e0f3f1d5e0f3f1d5e0f3f1d5e0f3f1d5e0f3f1d5e0f")
+	tests := []struct {
+		input    string
+		mustFail bool
+		expected BlockNumberOrHash
+	}{
+		0: {`"0x1"`, false, BlockNumberOrHashWithNumber(1)},
+		1: {`"latest"`, false, BlockNumberOrHashWithNumber(LatestBlockNumber)},
+		2: {`"pending"`, false, BlockNumberOrHashWithNumber(PendingBlockNumber)},
+		3: {`"` + hash.Hex() + `"`, false, BlockNumberOrHashWithHash(hash)},
+		4: {`{"blockNumber":"0x2"}`, false, BlockNumberOrHashWithNumber(2)},
+		5: {`{"blockHash":"` + hash.Hex() + `"}`, false, BlockNumberOrHashWithHash(hash)},
+		6: {`{"blockNumber":"0x2","blockHash":"` + hash.Hex() + `"}`, true, BlockNumberOrHash{}},
+		7: {`{}`, true, BlockNumberOrHash{}},
+		8: {`"ff"`, true, BlockNumberOrHash{}},
+	}
+
+	for i, test := range tests {
+		var bnh BlockNumberOrHash
+		err := json.Unmarshal([]byte(test.input), &bnh)
+		if test.mustFail && err == nil {
+			t.Errorf("Test %d should fail", i)
+			continue
+		}
+		if !test.mustFail && err != nil {
+			t.Errorf("Test %d should pass but got err: %v", i, err)
+			continue
+		}
+		if test.mustFail {
+			continue
+		}
+		if num, ok := bnh.Number(); ok {
+			if expected, _ := test.expected.Number(); num != expected {
+				t.Errorf("Test %d got unexpected number, want %d, got %d", i, expected, num)
+			}
+		}
+		if h, ok := bnh.Hash(); ok {
+			if expected, _ := test.expected.Hash(); h != expected {
+				t.Errorf("Test %d got unexpected hash, want %x, got %x", i, expected, h)
+			}
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	klaytnapi "github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
@@ -39,6 +40,7 @@ import (
 	"math/big"
 	"os"
 	"strings"
+	"time"
 )
 
 // PublicKlayAPI provides an API to access Klaytn CN-related
@@ -205,6 +207,17 @@ func (api *PrivateMinerAPI) GetHashrate() uint64 {
 	return uint64(api.e.miner.HashRate())
 }
 
+// PendingBlock returns the block which the miner is currently assembling for
+// sealing with the hashes of the transactions selected so far.
+// It returns nil if the node is not mining.
+func (api *PrivateMinerAPI) PendingBlock() (map[string]interface{}, error) {
+	block := api.e.Miner().MiningBlock()
+	if block == nil {
+		return nil, nil
+	}
+	return klaytnapi.RpcOutputBlock(block, nil, true, false)
+}
+
 // PrivateAdminAPI is the collection of CN full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -298,6 +311,32 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// ReloadDeployerAllowList reloads the contract deployer allow list of the tx pool
+// from the file given by --txpool.deployerallowlist.
+func (api *PrivateAdminAPI) ReloadDeployerAllowList() (bool, error) {
+	if err := api.cn.TxPool().ReloadDeployerAllowList(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DumpTxPool writes all pending and queued transactions of the tx pool, local
+// and remote ones, to the given file and returns the number of written transactions.
+func (api *PrivateAdminAPI) DumpTxPool(file string) (int, error) {
+	return api.cn.TxPool().Dump(file)
+}
+
+// LoadTxPool adds the transactions of a file written by DumpTxPool to the tx pool
+// as remote transactions, validating each of them again. It returns the numbers
+// of added and dropped transactions.
+func (api *PrivateAdminAPI) LoadTxPool(file string) (map[string]int, error) {
+	added, dropped, err := api.cn.TxPool().Load(file)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{"added": added, "dropped": dropped}, nil
+}
+
 // PublicDebugAPI is the collection of Klaytn full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
@@ -356,12 +395,82 @@ func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hex
 	return nil, errors.New("unknown preimage")
 }
 
+// EffectiveConfig returns the resolved configuration of the node for support
+// purposes: the chain, sync, database, cache, tx pool and RPC settings and the
+// global flags set on the command line, with sensitive values masked.
+func (api *PrivateDebugAPI) EffectiveConfig() map[string]interface{} {
+	cfg := api.cn.config
+	return map[string]interface{}{
+		"chainId":   api.config.ChainID,
+		"networkId": cfg.NetworkId,
+		"syncMode":  cfg.SyncMode.String(),
+		"noPruning": cfg.NoPruning,
+		"database": map[string]interface{}{
+			"partitioned":            cfg.PartitionedDB,
+			"numStateTriePartitions": cfg.NumStateTriePartitions,
+			"levelDBCompression":     cfg.LevelDBCompression,
+			"levelDBBufferPool":      cfg.LevelDBBufferPool,
+			"bodyCompression":        cfg.BodyCompression.String(),
+			"readOnly":               cfg.DBReadOnly,
+			"parallelDBWrite":        cfg.ParallelDBWrite,
+		},
+		"cache": map[string]interface{}{
+			"levelDBCacheSize": cfg.LevelDBCacheSize,
+			"trieCacheSize":    cfg.TrieCacheSize,
+			"trieCacheLimit":   cfg.TrieCacheLimit,
+			"stateDBCaching":   cfg.StateDBCaching,
+			"txPoolStateCache": cfg.TxPoolStateCache,
+			"writeThrough":     cfg.WriteThroughCaching,
+		},
+		"txPool": cfg.TxPool,
+		"node":   api.cn.nodeConfig,
+		"flags":  cfg.Flags,
+	}
+}
+
 // GetBadBLocks returns a list of the last 'bad blocks' that the client has seen on the network
 // and returns them as a JSON list of block-hashes
 func (api *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]blockchain.BadBlockArgs, error) {
 	return api.cn.BlockChain().BadBlocks()
 }
 
+// RecentImportFailures returns the recent blocks which failed to be imported with
+// the reasons, from the oldest to the newest.
+func (api *PrivateDebugAPI) RecentImportFailures() []blockchain.ImportFailure {
+	return api.cn.BlockChain().RecentImportFailures()
+}
+
+// GetCachedHeaderNumber returns the block number cached for the given block
+// hash, or nil if the hash is not cached. The database is not read.
+func (api *PrivateDebugAPI) GetCachedHeaderNumber(hash common.Hash) *hexutil.Uint64 {
+	number := api.cn.ChainDB().ReadCachedHeaderNumber(hash)
+	if number == nil {
+		return nil
+	}
+	return (*hexutil.Uint64)(number)
+}
+
+// InvalidateHeaderNumber evicts the block number cached for the given block
+// hash, so that it is read again from the database. It is meant to recover
+// from stale cache entries after the database was modified manually.
+func (api *PrivateDebugAPI) InvalidateHeaderNumber(hash common.Hash) {
+	api.cn.ChainDB().InvalidateHeaderNumberCache(hash)
+	logger.Info("Invalidated cached header number", "hash", hash)
+}
+
+// CompactDatabase compacts the whole chain database to reclaim the space of
+// deleted entries, e.g. after pruning. It may take a long time on a large database.
+func (api *PrivateDebugAPI) CompactDatabase() error {
+	logger.Info("Compacting chain database")
+	start := time.Now()
+	if err := api.cn.ChainDB().Compact(nil, nil); err != nil {
+		logger.Error("Failed to compact chain database", "err", err)
+		return err
+	}
+	logger.Info("Compacted chain database", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 // StorageRangeResult is the result of a debug_storageRangeAt API call.
 type StorageRangeResult struct {
 	Storage storageMap   `json:"storage"`
@@ -411,6 +520,70 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+// maxStorageRangeResults is the upper bound of storage slots returned by a
+// single debug_getStorageRangeAt call.
+const maxStorageRangeResults = 1024
+
+// AccountStorageRangeResult is the result of a debug_getStorageRangeAt API call.
+type AccountStorageRangeResult struct {
+	StorageRoot common.Hash  `json:"storageRoot"`
+	Storage     storageMap   `json:"storage"`
+	NextKey     *common.Hash `json:"nextKey"` // nil if Storage includes the last key of the account.
+}
+
+// GetStorageRangeAt returns the storage slots of the given account at the given block,
+// starting from startKey (a hashed storage key). At most maxResults slots are returned
+// and NextKey can be used as startKey of the next call to continue the iteration.
+func (api *PrivateDebugAPI) GetStorageRangeAt(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, address common.Address, startKey hexutil.Bytes, maxResults int) (AccountStorageRangeResult, error) {
+	if maxResults <= 0 || maxResults > maxStorageRangeResults {
+		maxResults = maxStorageRangeResults
+	}
+	stateDB, err := api.stateAtBlockNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return AccountStorageRangeResult{}, err
+	}
+	return accountStorageRangeAt(stateDB, address, startKey, maxResults)
+}
+
+// stateAtBlockNumberOrHash returns the state of the block specified by the given argument.
+func (api *PrivateDebugAPI) stateAtBlockNumberOrHash(blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, error) {
+	var block *types.Block
+	if blockNr, ok := blockNrOrHash.Number(); ok {
+		switch blockNr {
+		case rpc.PendingBlockNumber:
+			_, stateDB := api.cn.miner.Pending()
+			return stateDB, nil
+		case rpc.LatestBlockNumber:
+			block = api.cn.blockchain.CurrentBlock()
+		default:
+			block = api.cn.blockchain.GetBlockByNumber(uint64(blockNr))
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", blockNr)
+		}
+	} else if hash, ok := blockNrOrHash.Hash(); ok {
+		block = api.cn.blockchain.GetBlockByHash(hash)
+		if block == nil {
+			return nil, fmt.Errorf("block %x not found", hash)
+		}
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	return api.cn.BlockChain().StateAt(block.Root())
+}
+
+func accountStorageRangeAt(stateDB *state.StateDB, address common.Address, start []byte, maxResult int) (AccountStorageRangeResult, error) {
+	st := stateDB.StorageTrie(address)
+	if st == nil {
+		return AccountStorageRangeResult{}, fmt.Errorf("account %x doesn't exist", address)
+	}
+	result, err := storageRangeAt(st, start, maxResult)
+	if err != nil {
+		return AccountStorageRangeResult{}, err
+	}
+	return AccountStorageRangeResult{StorageRoot: st.Hash(), Storage: result.Storage, NextKey: result.NextKey}, nil
+}
+
 // GetModifiedAccountsByumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
@@ -495,3 +668,94 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 	}
 	return dirty, nil
 }
+
+// maxStorageChangeProbes is the upper bound of the historical states read by a
+// single debug_findStorageChange call.
+const maxStorageChangeProbes = 40
+
+// StorageChangeResult is the result of a debug_findStorageChange API call.
+type StorageChangeResult struct {
+	BlockNumber   hexutil.Uint64 `json:"blockNumber"`
+	BlockHash     common.Hash    `json:"blockHash"`
+	PreviousValue common.Hash    `json:"previousValue"`
+	Value         common.Hash    `json:"value"`
+	Probes        int            `json:"probes"`
+}
+
+// FindStorageChange returns the block, at or before beforeBlock, which set the given
+// storage slot of the given account to its value at beforeBlock. It binary-searches
+// the historical states, so if the slot was set to the same value more than once,
+// any of those blocks may be returned. It returns nil if the slot has kept the
+// value since the genesis block.
+func (api *PrivateDebugAPI) FindStorageChange(address common.Address, slot common.Hash, beforeBlock rpc.BlockNumber) (*StorageChangeResult, error) {
+	var before uint64
+	switch beforeBlock {
+	case rpc.PendingBlockNumber:
+		return nil, errors.New("pending block is not supported")
+	case rpc.LatestBlockNumber:
+		before = api.cn.blockchain.CurrentBlock().NumberU64()
+	default:
+		before = uint64(beforeBlock)
+	}
+	valueAt := func(number uint64) (common.Hash, error) {
+		header := api.cn.blockchain.GetHeaderByNumber(number)
+		if header == nil {
+			return common.Hash{}, fmt.Errorf("block #%d not found", number)
+		}
+		stateDB, err := api.cn.blockchain.StateAt(header.Root)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("state of block #%d not available: %v", number, err)
+		}
+		return stateDB.GetState(address, slot), nil
+	}
+	result, err := findStorageChange(valueAt, before)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if header := api.cn.blockchain.GetHeaderByNumber(uint64(result.BlockNumber)); header != nil {
+		result.BlockHash = header.Hash()
+	}
+	return result, nil
+}
+
+// findStorageChange binary-searches the blocks from the genesis to before for the
+// block which changed the value returned by valueAt to its value at before.
+func findStorageChange(valueAt func(number uint64) (common.Hash, error), before uint64) (*StorageChangeResult, error) {
+	probes := 0
+	probe := func(number uint64) (common.Hash, error) {
+		if probes >= maxStorageChangeProbes {
+			return common.Hash{}, fmt.Errorf("storage change not found within %d probes", maxStorageChangeProbes)
+		}
+		probes++
+		return valueAt(number)
+	}
+	value, err := probe(before)
+	if err != nil {
+		return nil, err
+	}
+	if before == 0 {
+		return nil, nil
+	}
+	genesisValue, err := probe(0)
+	if err != nil {
+		return nil, err
+	}
+	if genesisValue == value {
+		return nil, nil
+	}
+	// The slot differs from value at lo and equals value at hi.
+	lo, hi, previous := uint64(0), before, genesisValue
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		midValue, err := probe(mid)
+		if err != nil {
+			return nil, err
+		}
+		if midValue == value {
+			hi = mid
+		} else {
+			lo, previous = mid, midValue
+		}
+	}
+	return &StorageChangeResult{BlockNumber: hexutil.Uint64(hi), PreviousValue: previous, Value: value, Probes: probes}, nil
+}
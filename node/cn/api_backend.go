@@ -220,6 +220,26 @@ func (b *CNAPIBackend) TxPoolContent() (map[common.Address]types.Transactions, m
 	return b.cn.TxPool().Content()
 }
 
+func (b *CNAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.cn.TxPool().ContentFrom(addr)
+}
+
+func (b *CNAPIBackend) TxPoolContentHash() common.Hash {
+	return b.cn.TxPool().ContentHash()
+}
+
+func (b *CNAPIBackend) TxPoolNonceGaps(addr common.Address) ([]uint64, error) {
+	return b.cn.TxPool().NonceGaps(addr)
+}
+
+func (b *CNAPIBackend) TxPoolAvailableBalance(addr common.Address) *big.Int {
+	return b.cn.TxPool().AvailableBalance(addr)
+}
+
+func (b *CNAPIBackend) TxPoolStatus(hashes []common.Hash) []blockchain.TxStatus {
+	return b.cn.TxPool().Status(hashes)
+}
+
 func (b *CNAPIBackend) SubscribeNewTxsEvent(ch chan<- blockchain.NewTxsEvent) event.Subscription {
 	return b.cn.TxPool().SubscribeNewTxsEvent(ch)
 }
@@ -0,0 +1,83 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"context"
+	"runtime"
+
+	klaytnapi "github.com/klaytn/klaytn/api"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+)
+
+// PrivateTraceAPI provides the APIs returning transaction traces together with
+// other transaction data, so that clients such as block explorers save round trips.
+// Like the other tracing APIs, it is only exposed in the private debug namespace.
+type PrivateTraceAPI struct {
+	debug    *PrivateDebugAPI
+	traceSem chan struct{} // Bounds the number of transactions traced concurrently
+}
+
+// NewPrivateTraceAPI creates a new API definition for the trace-related APIs.
+func NewPrivateTraceAPI(config *params.ChainConfig, cn *CN) *PrivateTraceAPI {
+	return &PrivateTraceAPI{
+		debug:    NewPrivateDebugAPI(config, cn),
+		traceSem: make(chan struct{}, runtime.NumCPU()),
+	}
+}
+
+// ReceiptWithTrace is the result of a debug_getTransactionReceiptWithTrace API call.
+type ReceiptWithTrace struct {
+	Receipt map[string]interface{} `json:"receipt"`
+	Trace   interface{}            `json:"trace"`
+}
+
+// GetTransactionReceiptWithTrace returns the receipt of the given transaction with
+// its trace produced by the given tracer, the call tracer by default. The
+// transaction is re-executed once for the trace. It returns nil if the transaction
+// is not found.
+func (api *PrivateTraceAPI) GetTransactionReceiptWithTrace(ctx context.Context, hash common.Hash, tracer *string) (*ReceiptWithTrace, error) {
+	tx, blockHash, blockNumber, index, receipt := api.debug.cn.blockchain.GetTxLookupInfoAndReceipt(hash)
+	if tx == nil || receipt == nil {
+		return nil, nil
+	}
+	config := &TraceConfig{Tracer: &callTracerName}
+	if tracer != nil && *tracer != "" {
+		config.Tracer = tracer
+	}
+
+	select {
+	case api.traceSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-api.traceSem }()
+
+	msg, vmctx, statedb, err := api.debug.computeTxEnv(blockHash, int(index), defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	trace, err := api.debug.traceTx(ctx, msg, vmctx, statedb, config)
+	if err != nil {
+		return nil, err
+	}
+	return &ReceiptWithTrace{
+		Receipt: klaytnapi.RpcOutputReceipt(tx, blockHash, blockNumber, index, receipt),
+		Trace:   trace,
+	}, nil
+}
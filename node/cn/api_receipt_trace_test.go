@@ -0,0 +1,114 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// removeTraceTime removes the execution time, which differs between runs, from
+// a call tracer result.
+func removeTraceTime(t *testing.T, trace interface{}) map[string]interface{} {
+	enc, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(enc, &result); err != nil {
+		t.Fatal(err)
+	}
+	delete(result, "time")
+	return result
+}
+
+func TestGetTransactionReceiptWithTrace(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		to     = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		db     = database.NewMemoryDBManager()
+		gspec  = &blockchain.Genesis{Config: params.TestChainConfig, Alloc: blockchain.GenesisAlloc{
+			from: {Balance: big.NewInt(params.KLAY)},
+			// Returns 1 + 1 as a 32 byte word.
+			to: {Code: common.FromHex("0x600160010160005260206000f3"), Balance: common.Big0},
+		}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, big.NewInt(1))
+		tx, _   = types.SignTx(types.NewTransaction(0, to, big.NewInt(0), 100000, nil, nil), signer, key)
+	)
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 1, func(i int, gen *blockchain.BlockGen) {
+		gen.AddTx(tx)
+	})
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	cn := &CN{chainConfig: gspec.Config, blockchain: chain, chainDB: db}
+	api := NewPrivateTraceAPI(gspec.Config, cn)
+
+	result, err := api.GetTransactionReceiptWithTrace(context.Background(), tx.Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil {
+		t.Fatal("receipt not found")
+	}
+	if hash := result.Receipt["transactionHash"]; hash != tx.Hash() {
+		t.Errorf("receipt transaction hash mismatch: have %v, want %x", hash, tx.Hash())
+	}
+	if status := result.Receipt["status"]; status != hexutil.Uint(types.ReceiptStatusSuccessful) {
+		t.Errorf("receipt status mismatch: have %v, want %v", status, types.ReceiptStatusSuccessful)
+	}
+
+	// The trace must match the one of debug_traceTransaction with the same tracer.
+	trace, err := NewPrivateDebugAPI(gspec.Config, cn).TraceTransaction(context.Background(), tx.Hash(), &TraceConfig{Tracer: &callTracerName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, want := removeTraceTime(t, result.Trace), removeTraceTime(t, trace)
+	haveEnc, _ := json.Marshal(have)
+	wantEnc, _ := json.Marshal(want)
+	if string(haveEnc) != string(wantEnc) {
+		t.Errorf("trace mismatch:\nhave %s\nwant %s", haveEnc, wantEnc)
+	}
+	if have["type"] != "CALL" {
+		t.Errorf("trace type mismatch: have %v, want CALL", have["type"])
+	}
+
+	// Unknown transactions have no receipt.
+	if result, err := api.GetTransactionReceiptWithTrace(context.Background(), common.Hash{0x01}, nil); err != nil || result != nil {
+		t.Errorf("unknown transaction: have %v (%v), want nil", result, err)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	klaytnapi "github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
@@ -191,6 +192,17 @@ func (api *PrivateServiceChainMinerAPI) GetHashrate() uint64 {
 	return uint64(api.e.miner.HashRate())
 }
 
+// PendingBlock returns the block which the miner is currently assembling for
+// sealing with the hashes of the transactions selected so far.
+// It returns nil if the node is not mining.
+func (api *PrivateServiceChainMinerAPI) PendingBlock() (map[string]interface{}, error) {
+	block := api.e.Miner().MiningBlock()
+	if block == nil {
+		return nil, nil
+	}
+	return klaytnapi.RpcOutputBlock(block, nil, true, false)
+}
+
 // PrivateAdminAPI is the collection of sc full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateServiceChainAdminAPI struct {
@@ -274,6 +286,23 @@ func (api *PrivateServiceChainAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// DumpTxPool writes all pending and queued transactions of the tx pool, local
+// and remote ones, to the given file and returns the number of written transactions.
+func (api *PrivateServiceChainAdminAPI) DumpTxPool(file string) (int, error) {
+	return api.sc.TxPool().Dump(file)
+}
+
+// LoadTxPool adds the transactions of a file written by DumpTxPool to the tx pool
+// as remote transactions, validating each of them again. It returns the numbers
+// of added and dropped transactions.
+func (api *PrivateServiceChainAdminAPI) LoadTxPool(file string) (map[string]int, error) {
+	added, dropped, err := api.sc.TxPool().Load(file)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{"added": added, "dropped": dropped}, nil
+}
+
 // PublicDebugAPI is the collection of Klaytn full node APIs exposed
 // over the public debugging endpoint.
 type PublicServiceChainDebugAPI struct {
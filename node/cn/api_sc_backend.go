@@ -220,6 +220,26 @@ func (b *ServiceChainAPIBackend) TxPoolContent() (map[common.Address]types.Trans
 	return b.sc.TxPool().Content()
 }
 
+func (b *ServiceChainAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.sc.TxPool().ContentFrom(addr)
+}
+
+func (b *ServiceChainAPIBackend) TxPoolContentHash() common.Hash {
+	return b.sc.TxPool().ContentHash()
+}
+
+func (b *ServiceChainAPIBackend) TxPoolNonceGaps(addr common.Address) ([]uint64, error) {
+	return b.sc.TxPool().NonceGaps(addr)
+}
+
+func (b *ServiceChainAPIBackend) TxPoolAvailableBalance(addr common.Address) *big.Int {
+	return b.sc.TxPool().AvailableBalance(addr)
+}
+
+func (b *ServiceChainAPIBackend) TxPoolStatus(hashes []common.Hash) []blockchain.TxStatus {
+	return b.sc.TxPool().Status(hashes)
+}
+
 func (b *ServiceChainAPIBackend) SubscribeNewTxsEvent(ch chan<- blockchain.NewTxsEvent) event.Subscription {
 	return b.sc.TxPool().SubscribeNewTxsEvent(ch)
 }
@@ -20,12 +20,14 @@
 package cn
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/storage/database"
 )
 
@@ -91,3 +93,129 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestAccountStorageRangeAtPaging(t *testing.T) {
+	var (
+		stateDB, _ = state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+		addr       = common.Address{0x01}
+		numSlots   = 10
+		pageSize   = 3
+	)
+	for i := 1; i <= numSlots; i++ {
+		stateDB.SetState(addr, common.BigToHash(big.NewInt(int64(i))), common.BigToHash(big.NewInt(int64(i*100))))
+	}
+	root, err := stateDB.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateDB, _ = state.New(root, stateDB.Database())
+
+	collected := storageMap{}
+	var start []byte
+	for pages := 0; ; pages++ {
+		if pages > numSlots {
+			t.Fatal("too many pages")
+		}
+		result, err := accountStorageRangeAt(stateDB, addr, start, pageSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Storage) > pageSize {
+			t.Fatalf("page too large: have %d, want at most %d", len(result.Storage), pageSize)
+		}
+		if result.StorageRoot != stateDB.StorageTrie(addr).Hash() {
+			t.Fatalf("storage root mismatch: have %x, want %x", result.StorageRoot, stateDB.StorageTrie(addr).Hash())
+		}
+		for hashedKey, entry := range result.Storage {
+			collected[hashedKey] = entry
+		}
+		if result.NextKey == nil {
+			break
+		}
+		start = result.NextKey.Bytes()
+	}
+
+	if len(collected) != numSlots {
+		t.Fatalf("wrong number of slots: have %d, want %d", len(collected), numSlots)
+	}
+	for _, entry := range collected {
+		if entry.Key == nil {
+			t.Fatal("missing preimage of storage key")
+		}
+		if want := stateDB.GetState(addr, *entry.Key); entry.Value != want {
+			t.Errorf("wrong value for key %x: have %x, want %x", *entry.Key, entry.Value, want)
+		}
+	}
+
+	if _, err := accountStorageRangeAt(stateDB, common.Address{0x02}, nil, pageSize); err == nil {
+		t.Error("expected an error for a non-existent account")
+	}
+}
+
+func TestFindStorageChange(t *testing.T) {
+	var (
+		sdb      = state.NewDatabase(database.NewMemoryDBManager())
+		addr     = common.Address{0x01}
+		slot     = common.Hash{0x02}
+		roots    []common.Hash
+		root     common.Hash
+		changes  = map[uint64]common.Hash{3: {0x03}, 7: {0x07}}
+		numBlock = uint64(20)
+	)
+	// Build the states of blocks changing the slot at the blocks of changes.
+	for number := uint64(0); number < numBlock; number++ {
+		stateDB, _ := state.New(root, sdb)
+		if number == 0 {
+			stateDB.CreateSmartContractAccount(addr, params.CodeFormatEVM)
+		}
+		stateDB.AddBalance(addr, big.NewInt(1))
+		if value, ok := changes[number]; ok {
+			stateDB.SetState(addr, slot, value)
+		}
+		var err error
+		if root, err = stateDB.Commit(false); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+	valueAt := func(number uint64) (common.Hash, error) {
+		stateDB, err := state.New(roots[number], sdb)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return stateDB.GetState(addr, slot), nil
+	}
+
+	tests := []struct {
+		before   uint64
+		want     uint64
+		previous common.Hash
+	}{
+		{before: numBlock - 1, want: 7, previous: common.Hash{0x03}},
+		{before: 7, want: 7, previous: common.Hash{0x03}},
+		{before: 6, want: 3, previous: common.Hash{}},
+	}
+	for _, test := range tests {
+		result, err := findStorageChange(valueAt, test.before)
+		if err != nil {
+			t.Fatalf("before %d: %v", test.before, err)
+		}
+		if result == nil {
+			t.Fatalf("before %d: change not found", test.before)
+		}
+		if uint64(result.BlockNumber) != test.want {
+			t.Errorf("before %d: block mismatch: have %d, want %d", test.before, result.BlockNumber, test.want)
+		}
+		if result.PreviousValue != test.previous {
+			t.Errorf("before %d: previous value mismatch: have %x, want %x", test.before, result.PreviousValue, test.previous)
+		}
+		if result.Value != changes[test.want] {
+			t.Errorf("before %d: value mismatch: have %x, want %x", test.before, result.Value, changes[test.want])
+		}
+	}
+
+	// The slot is empty since the genesis until block 3.
+	if result, err := findStorageChange(valueAt, 2); err != nil || result != nil {
+		t.Errorf("unchanged slot: have %v (%v), want nil", result, err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+)
+
+// touchedAccountsTracer is a vm.Tracer collecting the addresses of the accounts
+// read or written during the execution of a transaction.
+type touchedAccountsTracer struct {
+	touched map[common.Address]struct{}
+}
+
+func newTouchedAccountsTracer() *touchedAccountsTracer {
+	return &touchedAccountsTracer{touched: make(map[common.Address]struct{})}
+}
+
+func (t *touchedAccountsTracer) touch(addr common.Address) {
+	t.touched[addr] = struct{}{}
+}
+
+// CaptureStart records the sender and the recipient or the created contract.
+func (t *touchedAccountsTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.touch(from)
+	t.touch(to)
+	return nil
+}
+
+// CaptureState records the accounts accessed by the opcode about to be executed.
+func (t *touchedAccountsTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil {
+		return nil
+	}
+	switch op {
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH:
+		if len(stack.Data()) >= 1 {
+			t.touch(common.BigToAddress(stack.Back(0)))
+		}
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if len(stack.Data()) >= 2 {
+			t.touch(common.BigToAddress(stack.Back(1)))
+		}
+	case vm.CREATE:
+		caller := contract.Address()
+		t.touch(crypto.CreateAddress(caller, env.StateDB.GetNonce(caller)))
+	case vm.CREATE2:
+		if len(stack.Data()) >= 4 {
+			offset, size := stack.Back(1).Int64(), stack.Back(2).Int64()
+			code := memory.Get(offset, size)
+			t.touch(crypto.CreateAddress2(contract.Address(), common.BigToHash(stack.Back(3)), crypto.Keccak256(code)))
+		}
+	case vm.SELFDESTRUCT:
+		t.touch(contract.Address())
+		if len(stack.Data()) >= 1 {
+			t.touch(common.BigToAddress(stack.Back(0)))
+		}
+	}
+	return nil
+}
+
+func (t *touchedAccountsTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *touchedAccountsTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// addresses returns the collected addresses in ascending order.
+func (t *touchedAccountsTracer) addresses() []common.Address {
+	addrs := make([]common.Address, 0, len(t.touched))
+	for addr := range t.touched {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return addrs
+}
+
+// PublicTouchedAccountsAPI provides the API returning the accounts touched by a
+// transaction. Unlike the tracing APIs, it runs no tracer given by the caller, so
+// it is exposed in the public klay namespace. The re-executions are bounded by the
+// semaphore of the trace APIs.
+type PublicTouchedAccountsAPI struct {
+	trace *PrivateTraceAPI
+}
+
+// NewPublicTouchedAccountsAPI creates a new API definition for the touched accounts
+// API, sharing the re-execution bound of the given trace API.
+func NewPublicTouchedAccountsAPI(trace *PrivateTraceAPI) *PublicTouchedAccountsAPI {
+	return &PublicTouchedAccountsAPI{trace: trace}
+}
+
+// GetTransactionTouchedAccounts returns the addresses of the accounts read or
+// written by the given transaction, including the sender, the fee payer and the
+// contracts created or self-destructed during its execution. The transaction is
+// re-executed once, which is cheaper than a full trace when only the address set
+// is needed. It returns nil if the transaction is not found.
+func (api *PublicTouchedAccountsAPI) GetTransactionTouchedAccounts(ctx context.Context, hash common.Hash) ([]common.Address, error) {
+	debug := api.trace.debug
+	tx, blockHash, _, index := debug.cn.blockchain.GetTxAndLookupInfo(hash)
+	if tx == nil {
+		return nil, nil
+	}
+
+	select {
+	case api.trace.traceSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-api.trace.traceSem }()
+
+	msg, vmctx, statedb, err := debug.computeTxEnv(blockHash, int(index), defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	tracer := newTouchedAccountsTracer()
+	tracer.touch(msg.ValidatedSender())
+	tracer.touch(msg.ValidatedFeePayer())
+	if to := msg.To(); to != nil {
+		tracer.touch(*to)
+	}
+
+	vmenv := vm.NewEVM(vmctx, statedb, debug.config, &vm.Config{Debug: true, Tracer: tracer})
+	if _, _, kerr := blockchain.ApplyMessage(vmenv, msg); kerr.ErrTxInvalid != nil {
+		return nil, fmt.Errorf("tracing failed: %v", kerr.ErrTxInvalid)
+	}
+	return tracer.addresses(), nil
+}
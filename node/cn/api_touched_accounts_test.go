@@ -0,0 +1,113 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+func TestGetTransactionTouchedAccounts(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+		contract    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		queried     = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		callee      = common.HexToAddress("0x3000000000000000000000000000000000000003")
+		beneficiary = common.HexToAddress("0x4000000000000000000000000000000000000004")
+		created     = crypto.CreateAddress(contract, 0)
+	)
+	// The contract reads the balance of queried, calls callee, creates an empty
+	// contract and self-destructs in favour of beneficiary.
+	code := common.FromHex("0x73" + common.Bytes2Hex(queried[:]) + "3150" +
+		"60006000600060006000" + "73" + common.Bytes2Hex(callee[:]) + "61fffff150" +
+		"600060006000f050" +
+		"73" + common.Bytes2Hex(beneficiary[:]) + "ff")
+
+	var (
+		db    = database.NewMemoryDBManager()
+		gspec = &blockchain.Genesis{Config: params.TestChainConfig, Alloc: blockchain.GenesisAlloc{
+			from:     {Balance: big.NewInt(params.KLAY)},
+			contract: {Code: code, Balance: common.Big0},
+		}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, big.NewInt(1))
+		tx, _   = types.SignTx(types.NewTransaction(0, contract, big.NewInt(0), 1000000, nil, nil), signer, key)
+	)
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 1, func(i int, gen *blockchain.BlockGen) {
+		gen.AddTx(tx)
+	})
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	if receipt := chain.GetReceiptByTxHash(tx.Hash()); receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction failed: %v", receipt)
+	}
+
+	cn := &CN{chainConfig: gspec.Config, blockchain: chain, chainDB: db}
+	api := NewPublicTouchedAccountsAPI(NewPrivateTraceAPI(gspec.Config, cn))
+
+	have, err := api.GetTransactionTouchedAccounts(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.Address{from, contract, queried, callee, created, beneficiary}
+	sort.Slice(want, func(i, j int) bool { return bytes.Compare(want[i][:], want[j][:]) < 0 })
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("touched accounts mismatch:\nhave %x\nwant %x", have, want)
+	}
+
+	// The API is served in the public klay namespace.
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("klay", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+	var served []common.Address
+	if err := client.Call(&served, "klay_getTransactionTouchedAccounts", tx.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(served, want) {
+		t.Errorf("served touched accounts mismatch:\nhave %x\nwant %x", served, want)
+	}
+
+	// Unknown transactions have no touched accounts.
+	if have, err := api.GetTransactionTouchedAccounts(context.Background(), common.Hash{0x01}); err != nil || have != nil {
+		t.Errorf("unknown transaction: have %v (%v), want nil", have, err)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/networks/rpc"
+)
+
+const (
+	// maxTraceFilterBlockRange is the maximum number of blocks re-executed by a single
+	// debug_traceFilter call.
+	maxTraceFilterBlockRange = 100
+
+	// maxTraceFilterResults is the maximum number of calls returned by a single
+	// debug_traceFilter call.
+	maxTraceFilterResults = 1000
+)
+
+var (
+	errTraceFilterInvalidRange = errors.New("fromBlock must not be greater than toBlock")
+	errTraceFilterTooManyCalls = fmt.Errorf("too many matching calls, narrow the filter (max %d)", maxTraceFilterResults)
+)
+
+// callTracerName is the name of the built-in JavaScript call tracer.
+var callTracerName = "callTracer"
+
+// TraceFilterArgs represents the arguments of debug_traceFilter.
+// A call matches if its sender is one of FromAddress and its recipient is one of ToAddress.
+// An empty address list matches any address.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	Timeout     *string          `json:"timeout"`
+	Reexec      *uint64          `json:"reexec"`
+}
+
+// traceFilterResult is a single call returned by debug_traceFilter.
+type traceFilterResult struct {
+	BlockNumber         hexutil.Uint64  `json:"blockNumber"`
+	BlockHash           common.Hash     `json:"blockHash"`
+	TransactionHash     common.Hash     `json:"transactionHash"`
+	TransactionPosition hexutil.Uint    `json:"transactionPosition"`
+	TraceAddress        []int           `json:"traceAddress"` // Position of the call in the call tree of the transaction
+	Type                string          `json:"type"`
+	From                common.Address  `json:"from"`
+	To                  common.Address  `json:"to"`
+	Value               *hexutil.Big    `json:"value,omitempty"`
+	Gas                 *hexutil.Uint64 `json:"gas,omitempty"`
+	GasUsed             *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	Input               hexutil.Bytes   `json:"input,omitempty"`
+	Output              hexutil.Bytes   `json:"output,omitempty"`
+	Error               string          `json:"error,omitempty"`
+}
+
+// callFrame is a call decoded from the result of the call tracer.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      common.Address  `json:"to"`
+	Value   *hexutil.Big    `json:"value"`
+	Gas     *hexutil.Uint64 `json:"gas"`
+	GasUsed *hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output"`
+	Error   string          `json:"error"`
+	Calls   []*callFrame    `json:"calls"`
+}
+
+// traceFilter holds the address sets of TraceFilterArgs.
+type traceFilter struct {
+	from map[common.Address]struct{}
+	to   map[common.Address]struct{}
+}
+
+func newTraceFilter(args TraceFilterArgs) *traceFilter {
+	f := &traceFilter{from: make(map[common.Address]struct{}), to: make(map[common.Address]struct{})}
+	for _, addr := range args.FromAddress {
+		f.from[addr] = struct{}{}
+	}
+	for _, addr := range args.ToAddress {
+		f.to[addr] = struct{}{}
+	}
+	return f
+}
+
+func (f *traceFilter) match(call *callFrame) bool {
+	if len(f.from) > 0 {
+		if _, ok := f.from[call.From]; !ok {
+			return false
+		}
+	}
+	if len(f.to) > 0 {
+		if _, ok := f.to[call.To]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// collect appends the calls in the call tree rooted at call which match the filter.
+// It returns errTraceFilterTooManyCalls if the number of results exceeds limit.
+func (f *traceFilter) collect(results []*traceFilterResult, call *callFrame, traceAddress []int, base traceFilterResult, limit int) ([]*traceFilterResult, error) {
+	if f.match(call) {
+		if len(results) >= limit {
+			return nil, errTraceFilterTooManyCalls
+		}
+		result := base
+		result.TraceAddress = append([]int{}, traceAddress...)
+		result.Type, result.From, result.To = call.Type, call.From, call.To
+		result.Value, result.Gas, result.GasUsed = call.Value, call.Gas, call.GasUsed
+		result.Input, result.Output, result.Error = call.Input, call.Output, call.Error
+		results = append(results, &result)
+	}
+	var err error
+	for i, child := range call.Calls {
+		if results, err = f.collect(results, child, append(traceAddress, i), base, limit); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// TraceFilter re-executes the blocks in the given range with the call tracer and
+// returns the calls, including internal ones, which match the given addresses.
+func (api *PrivateDebugAPI) TraceFilter(ctx context.Context, args TraceFilterArgs) ([]*traceFilterResult, error) {
+	from, to, err := api.traceFilterRange(args)
+	if err != nil {
+		return nil, err
+	}
+	config := &TraceConfig{Tracer: &callTracerName, Timeout: args.Timeout, Reexec: args.Reexec}
+	filter := newTraceFilter(args)
+
+	results := []*traceFilterResult{}
+	for number := from; number <= to; number++ {
+		block := api.cn.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		if block.Transactions().Len() == 0 {
+			continue
+		}
+		traces, err := api.traceBlock(ctx, block, config)
+		if err != nil {
+			return nil, err
+		}
+		if results, err = filterBlockTraces(results, filter, block, traces, maxTraceFilterResults); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// traceFilterRange resolves and validates the block range of the given arguments.
+func (api *PrivateDebugAPI) traceFilterRange(args TraceFilterArgs) (uint64, uint64, error) {
+	current := api.cn.blockchain.CurrentBlock().NumberU64()
+	resolve := func(number *rpc.BlockNumber) uint64 {
+		if number == nil || *number == rpc.LatestBlockNumber || *number == rpc.PendingBlockNumber {
+			return current
+		}
+		return uint64(*number)
+	}
+	from, to := resolve(args.FromBlock), resolve(args.ToBlock)
+	if from > to {
+		return 0, 0, errTraceFilterInvalidRange
+	}
+	if to > current {
+		return 0, 0, fmt.Errorf("block #%d not found", to)
+	}
+	if to-from+1 > maxTraceFilterBlockRange {
+		return 0, 0, fmt.Errorf("block range too large: %d > %d", to-from+1, maxTraceFilterBlockRange)
+	}
+	return from, to, nil
+}
+
+// filterBlockTraces decodes the call tracer results of the given block and appends
+// the matching calls to results.
+func filterBlockTraces(results []*traceFilterResult, filter *traceFilter, block *types.Block, traces []*txTraceResult, limit int) ([]*traceFilterResult, error) {
+	txs := block.Transactions()
+	for i, trace := range traces {
+		if trace.Error != "" {
+			return nil, fmt.Errorf("failed to trace tx %x: %s", txs[i].Hash(), trace.Error)
+		}
+		raw, ok := trace.Result.(json.RawMessage)
+		if !ok {
+			return nil, fmt.Errorf("unexpected trace result type %T", trace.Result)
+		}
+		call := new(callFrame)
+		if err := json.Unmarshal(raw, call); err != nil {
+			return nil, err
+		}
+		base := traceFilterResult{
+			BlockNumber:         hexutil.Uint64(block.NumberU64()),
+			BlockHash:           block.Hash(),
+			TransactionHash:     txs[i].Hash(),
+			TransactionPosition: hexutil.Uint(i),
+		}
+		var err error
+		if results, err = filter.collect(results, call, []int{}, base, limit); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterBlockTraces(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	var (
+		eoa      = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		contract = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		target   = common.HexToAddress("0x0000000000000000000000000000000000000003")
+		other    = common.HexToAddress("0x0000000000000000000000000000000000000004")
+	)
+	callJSON := func(from, to common.Address, calls string) json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`{"type":"CALL","from":"%s","to":"%s","value":"0x0","gas":"0x5208","gasUsed":"0x5208","input":"0x","output":"0x","time":"1ms","calls":[%s]}`,
+			from.Hex(), to.Hex(), calls))
+	}
+	internalJSON := func(from, to common.Address) string {
+		return fmt.Sprintf(`{"type":"CALL","from":"%s","to":"%s","value":"0x1","gas":"0x100","gasUsed":"0x10","input":"0x","output":"0x"}`, from.Hex(), to.Hex())
+	}
+
+	// Block 1: eoa -> contract -> (target, other), eoa -> other
+	// Block 2: eoa -> contract -> other -> target
+	block1 := types.NewBlock(&types.Header{Number: big.NewInt(1)}, types.Transactions{
+		types.NewTransaction(0, contract, big.NewInt(0), 0, big.NewInt(0), nil),
+		types.NewTransaction(1, other, big.NewInt(0), 0, big.NewInt(0), nil),
+	}, nil)
+	traces1 := []*txTraceResult{
+		{Result: callJSON(eoa, contract, internalJSON(contract, target)+","+internalJSON(contract, other))},
+		{Result: callJSON(eoa, other, "")},
+	}
+	block2 := types.NewBlock(&types.Header{Number: big.NewInt(2)}, types.Transactions{
+		types.NewTransaction(2, contract, big.NewInt(0), 0, big.NewInt(0), nil),
+	}, nil)
+	nested := fmt.Sprintf(`{"type":"CALL","from":"%s","to":"%s","calls":[%s]}`, contract.Hex(), other.Hex(), internalJSON(other, target))
+	traces2 := []*txTraceResult{
+		{Result: callJSON(eoa, contract, nested)},
+	}
+
+	// Only the calls to the target are returned over the range.
+	filter := newTraceFilter(TraceFilterArgs{ToAddress: []common.Address{target}})
+	results, err := filterBlockTraces(nil, filter, block1, traces1, maxTraceFilterResults)
+	assert.NoError(t, err)
+	results, err = filterBlockTraces(results, filter, block2, traces2, maxTraceFilterResults)
+	assert.NoError(t, err)
+
+	if assert.Equal(t, 2, len(results)) {
+		assert.Equal(t, uint64(1), uint64(results[0].BlockNumber))
+		assert.Equal(t, block1.Transactions()[0].Hash(), results[0].TransactionHash)
+		assert.Equal(t, contract, results[0].From)
+		assert.Equal(t, []int{0}, results[0].TraceAddress)
+		assert.Equal(t, big.NewInt(1), results[0].Value.ToInt())
+
+		assert.Equal(t, uint64(2), uint64(results[1].BlockNumber))
+		assert.Equal(t, block2.Transactions()[0].Hash(), results[1].TransactionHash)
+		assert.Equal(t, other, results[1].From)
+		assert.Equal(t, []int{0, 0}, results[1].TraceAddress)
+	}
+	for _, result := range results {
+		assert.Equal(t, target, result.To)
+	}
+
+	// Both sender and recipient are matched if both are given.
+	filter = newTraceFilter(TraceFilterArgs{FromAddress: []common.Address{eoa}, ToAddress: []common.Address{other}})
+	results, err = filterBlockTraces(nil, filter, block1, traces1, maxTraceFilterResults)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(results)) {
+		assert.Equal(t, block1.Transactions()[1].Hash(), results[0].TransactionHash)
+		assert.Equal(t, uint(1), uint(results[0].TransactionPosition))
+		assert.Equal(t, []int{}, results[0].TraceAddress)
+	}
+
+	// The number of results is bounded.
+	filter = newTraceFilter(TraceFilterArgs{})
+	_, err = filterBlockTraces(nil, filter, block1, traces1, 3)
+	assert.Equal(t, errTraceFilterTooManyCalls, err)
+}
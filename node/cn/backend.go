@@ -27,7 +27,9 @@ import (
 	"github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/bloombits"
+	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/blockchain/vm"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
@@ -76,7 +78,12 @@ type CN struct {
 	lesServer       LesServer
 
 	// DB interfaces
-	chainDB database.DBManager // Block chain database
+	chainDB        database.DBManager         // Block chain database
+	diskSpaceGuard *blockchain.DiskSpaceGuard // Halts accepting blocks and txs on low disk space, nil if disabled
+
+	keyAgeMonitor *KeyAgeMonitor // Reports how long the node key and the rewardbase have been in use
+
+	nodeConfig map[string]interface{} // Resolved node settings reported by debug_effectiveConfig
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
@@ -144,6 +151,204 @@ func senderTxHashIndexer(db database.DBManager, chainEvent <-chan blockchain.Cha
 	}
 }
 
+// accountCreationIndexer stores the creation metadata of the accounts created
+// in the blocks delivered by chainEvent, and reverts it for the blocks delivered
+// by chainSideEvent.
+func accountCreationIndexer(bc *blockchain.BlockChain, db database.DBManager, chainEvent <-chan blockchain.ChainEvent, chainSideEvent <-chan blockchain.ChainSideEvent, chainSubscription, chainSideSubscription event.Subscription) {
+	defer chainSubscription.Unsubscribe()
+	defer chainSideSubscription.Unsubscribe()
+
+	for {
+		select {
+		case event := <-chainEvent:
+			writeAccountCreationInfos(bc, db, event.Block)
+
+		case event := <-chainSideEvent:
+			revertAccountCreationInfos(bc, db, event.Block)
+
+		case <-chainSubscription.Err():
+			return
+		case <-chainSideSubscription.Err():
+			return
+		}
+	}
+}
+
+// writeAccountCreationInfos stores the creation metadata of the accounts created
+// by the successful transactions of the given block, either explicitly by an
+// account creation transaction or implicitly by receiving value or being deployed.
+// An account is created by a block if it exists in the state of the block but not
+// in the state of its parent. Accounts created by internal transactions are not
+// indexed.
+func writeAccountCreationInfos(bc *blockchain.BlockChain, db database.DBManager, block *types.Block) {
+	var (
+		receipts = db.ReadReceipts(block.Hash(), block.NumberU64())
+		blockNum = block.NumberU64()
+		created  = make(map[common.Address]bool)
+
+		parent, current *state.StateDB
+	)
+	for i, tx := range block.Transactions() {
+		if i >= len(receipts) || receipts[i].Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		addr, ok := createdAccount(tx, receipts[i])
+		if !ok || created[addr] {
+			continue
+		}
+		if parent == nil {
+			var err error
+			if parent, err = bc.StateAt(bc.GetHeader(block.ParentHash(), blockNum-1).Root); err != nil {
+				logger.Error("Failed to read the parent state to index account creations",
+					"blockNum", blockNum, "err", err)
+				return
+			}
+			if current, err = bc.StateAt(block.Root()); err != nil {
+				logger.Error("Failed to read the state to index account creations",
+					"blockNum", blockNum, "err", err)
+				return
+			}
+		}
+		if parent.Exist(addr) || !current.Exist(addr) {
+			continue
+		}
+		created[addr] = true
+
+		info := &database.AccountCreationInfo{
+			TxHash:      tx.Hash(),
+			BlockHash:   block.Hash(),
+			BlockNumber: blockNum,
+			TxType:      tx.Type(),
+		}
+		if data, ok := tx.GetTxInternalData().(*types.TxInternalDataAccountCreation); ok {
+			info.HumanReadable = data.HumanReadable
+		}
+		if err := db.WriteAccountCreationInfo(addr, info); err != nil {
+			logger.Error("Failed to store account creation info to database",
+				"blockNum", blockNum, "address", addr, "txHash", tx.Hash(), "err", err)
+		}
+	}
+}
+
+// revertAccountCreationInfos deletes the creation metadata recorded for the given
+// block, which is not in the canonical chain anymore, and indexes the canonical
+// block of the same number instead, as it is not delivered by a chain event if it
+// was inserted as a side block before the reorg.
+func revertAccountCreationInfos(bc *blockchain.BlockChain, db database.DBManager, block *types.Block) {
+	for i, receipt := range db.ReadReceipts(block.Hash(), block.NumberU64()) {
+		if i >= len(block.Transactions()) {
+			break
+		}
+		addr, ok := createdAccount(block.Transactions()[i], receipt)
+		if !ok {
+			continue
+		}
+		if info := db.ReadAccountCreationInfo(addr); info != nil && info.BlockHash == block.Hash() {
+			if err := db.DeleteAccountCreationInfo(addr); err != nil {
+				logger.Error("Failed to delete account creation info from database",
+					"blockNum", block.NumberU64(), "address", addr, "err", err)
+			}
+		}
+	}
+	hash := db.ReadCanonicalHash(block.NumberU64())
+	if hash == (common.Hash{}) || hash == block.Hash() {
+		return
+	}
+	if canonical := bc.GetBlock(hash, block.NumberU64()); canonical != nil {
+		writeAccountCreationInfos(bc, db, canonical)
+	}
+}
+
+// createdAccount returns the account which the given transaction may have created:
+// the deployed contract or the recipient.
+func createdAccount(tx *types.Transaction, receipt *types.Receipt) (common.Address, bool) {
+	if receipt.ContractAddress != (common.Address{}) {
+		return receipt.ContractAddress, true
+	}
+	if to := tx.To(); to != nil {
+		return *to, true
+	}
+	return common.Address{}, false
+}
+
+// accountKeyHistoryIndexer stores the account key changes made in the blocks
+// delivered by chainEvent.
+func accountKeyHistoryIndexer(bc *blockchain.BlockChain, db database.DBManager, chainEvent <-chan blockchain.ChainEvent, subscription event.Subscription) {
+	defer subscription.Unsubscribe()
+
+	for {
+		select {
+		case event := <-chainEvent:
+			writeAccountKeyChanges(bc, db, event.Block)
+
+		case <-subscription.Err():
+			return
+		}
+	}
+}
+
+// writeAccountKeyChanges stores the old and new keys of the accounts updated by
+// the successful account update transactions of the given block. The old keys
+// are read from the state of the parent block.
+func writeAccountKeyChanges(bc *blockchain.BlockChain, db database.DBManager, block *types.Block) {
+	var (
+		receipts = db.ReadReceipts(block.Hash(), block.NumberU64())
+		blockNum = block.NumberU64()
+		keys     = make(map[common.Address]accountkey.AccountKey)
+		parent   *state.StateDB
+	)
+	for i, tx := range block.Transactions() {
+		var from common.Address
+		var txKey accountkey.AccountKey
+		switch data := tx.GetTxInternalData().(type) {
+		case *types.TxInternalDataAccountUpdate:
+			from, txKey = data.From, data.Key
+		case *types.TxInternalDataFeeDelegatedAccountUpdate:
+			from, txKey = data.From, data.Key
+		case *types.TxInternalDataFeeDelegatedAccountUpdateWithRatio:
+			from, txKey = data.From, data.Key
+		default:
+			continue
+		}
+		if i >= len(receipts) || receipts[i].Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		oldKey, ok := keys[from]
+		if !ok {
+			if parent == nil {
+				var err error
+				if parent, err = bc.StateAt(bc.GetHeader(block.ParentHash(), blockNum-1).Root); err != nil {
+					logger.Error("Failed to read the parent state to index account key changes",
+						"blockNum", blockNum, "err", err)
+					return
+				}
+			}
+			oldKey = parent.GetKey(from)
+		}
+		// Apply the new key the same way as the state transition does.
+		newKey := oldKey.DeepCopy()
+		if newKey.Type() != txKey.Type() || newKey.Update(txKey, blockNum) != nil {
+			newKey = txKey
+		}
+		keys[from] = newKey
+
+		oldEnc, _ := rlp.EncodeToBytes(accountkey.NewAccountKeySerializerWithAccountKey(oldKey))
+		newEnc, _ := rlp.EncodeToBytes(accountkey.NewAccountKeySerializerWithAccountKey(newKey))
+		change := &database.AccountKeyChange{
+			TxHash:      tx.Hash(),
+			BlockHash:   block.Hash(),
+			BlockNumber: blockNum,
+			TxIndex:     uint64(i),
+			OldKey:      oldEnc,
+			NewKey:      newEnc,
+		}
+		if err := db.WriteAccountKeyChange(from, change); err != nil {
+			logger.Error("Failed to store account key change to database",
+				"blockNum", blockNum, "address", from, "txHash", tx.Hash(), "err", err)
+		}
+	}
+}
+
 // New creates a new CN object (including the
 // initialisation of the common CN object)
 func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
@@ -169,7 +374,7 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 
 	// NOTE-Klaytn Now we use ChainConfig.UnitPrice from genesis.json.
 	//         So let's update cn.Config.GasPrice using ChainConfig.UnitPrice.
-	config.GasPrice = new(big.Int).SetUint64(chainConfig.UnitPrice)
+	config.GasPrice = chainConfig.GetUnitPrice()
 
 	logger.Info("Initialised chain configuration", "config", chainConfig)
 	governance := governance.NewGovernance(chainConfig, chainDB)
@@ -188,7 +393,10 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDB, params.BloomBitsBlocks),
 		governance:     governance,
+		keyAgeMonitor:  NewKeyAgeMonitor(chainDB, config.KeyRotationAge),
+		nodeConfig:     ctx.EffectiveConfig(),
 	}
+	cn.keyAgeMonitor.Track(nodeKeyName, crypto.PubkeyToAddress(ctx.NodeKey().PublicKey), nodeKeyAgeGauge)
 
 	// istanbul BFT. Derive and set node's address using nodekey
 	if chainConfig.Istanbul != nil {
@@ -204,11 +412,16 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		}
 		chainDB.WriteDatabaseVersion(blockchain.BlockChainVersion)
 	}
+	// The gc mode is stored so that offline pruning can refuse archive data directories.
+	if archive, stored := chainDB.ReadArchiveMode(); !stored || archive != config.NoPruning {
+		chainDB.WriteArchiveMode(config.NoPruning)
+	}
 	var (
 		vmConfig    = vm.Config{EnablePreimageRecording: config.EnablePreimageRecording}
 		cacheConfig = &blockchain.CacheConfig{StateDBCaching: config.StateDBCaching,
 			ArchiveMode: config.NoPruning, CacheSize: config.TrieCacheSize, BlockInterval: config.TrieBlockInterval,
-			TxPoolStateCache: config.TxPoolStateCache, TrieCacheLimit: config.TrieCacheLimit, SenderTxHashIndexing: config.SenderTxHashIndexing}
+			TxPoolStateCache: config.TxPoolStateCache, TrieCacheLimit: config.TrieCacheLimit, TrieCommitBatchSize: config.TrieCommitBatchSize,
+			TrieMaxDirtyBuffer: config.TrieMaxDirtyBuffer, SenderTxHashIndexing: config.SenderTxHashIndexing}
 	)
 	var err error
 
@@ -217,6 +430,7 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		return nil, err
 	}
 	governance.SetBlockchain(cn.blockchain)
+	cn.blockchain.SetGasPriceFloorReader(governance)
 	// Synchronize proposerpolicy & useGiniCoeff
 	if cn.blockchain.Config().Istanbul != nil {
 		cn.blockchain.Config().Istanbul.ProposerPolicy = governance.ChainConfig.Istanbul.ProposerPolicy
@@ -231,6 +445,20 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		go senderTxHashIndexer(chainDB, ch, chainEventSubscription)
 	}
 
+	if config.AccountCreationIndexing {
+		ch := make(chan blockchain.ChainEvent, 255)
+		chainEventSubscription := cn.blockchain.SubscribeChainEvent(ch)
+		sideCh := make(chan blockchain.ChainSideEvent, 255)
+		chainSideEventSubscription := cn.blockchain.SubscribeChainSideEvent(sideCh)
+		go accountCreationIndexer(cn.blockchain, chainDB, ch, sideCh, chainEventSubscription, chainSideEventSubscription)
+	}
+
+	if config.AccountKeyHistoryIndexing {
+		ch := make(chan blockchain.ChainEvent, 255)
+		chainEventSubscription := cn.blockchain.SubscribeChainEvent(ch)
+		go accountKeyHistoryIndexer(cn.blockchain, chainDB, ch, chainEventSubscription)
+	}
+
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		logger.Error("Rewinding chain to upgrade configuration", "err", compat)
@@ -247,7 +475,19 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	cn.txPool = blockchain.NewTxPool(config.TxPool, cn.chainConfig, cn.blockchain)
 	governance.SetTxPool(cn.txPool)
 	// Synchronize unitprice
-	cn.txPool.SetGasPrice(big.NewInt(0).SetUint64(governance.ChainConfig.UnitPrice))
+	cn.txPool.SetGasPrice(governance.ChainConfig.GetUnitPrice())
+
+	if config.BlockVerifyLevel != "" {
+		cn.blockchain.SetBlockVerifyLevel(config.BlockVerifyLevel)
+	}
+
+	if config.MinFreeDiskSpace > 0 {
+		if dir := ctx.ResolvePath("chaindata"); dir != "" {
+			cn.diskSpaceGuard = blockchain.NewDiskSpaceGuard(dir, config.MinFreeDiskSpace*1024*1024)
+			cn.blockchain.SetDiskSpaceGuard(cn.diskSpaceGuard)
+			cn.txPool.SetDiskSpaceGuard(cn.diskSpaceGuard)
+		}
+	}
 
 	if cn.protocolManager, err = NewProtocolManager(cn.chainConfig, config.SyncMode, config.NetworkId, cn.eventMux, cn.txPool, cn.engine, cn.blockchain, chainDB, ctx.NodeType(), config); err != nil {
 		return nil, err
@@ -275,6 +515,9 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 			cn.protocolManager.SetRewardbaseWallet(wallet)
 		}
 		cn.protocolManager.SetRewardbase(cn.rewardbase)
+		if cn.rewardbase != (common.Address{}) {
+			cn.keyAgeMonitor.Track(rewardbaseName, cn.rewardbase, rewardbaseAgeGauge)
+		}
 	}
 
 	if chainConfig.Istanbul != nil && cn.chainConfig.Istanbul.ProposerPolicy == uint64(istanbul.WeightedRandom) {
@@ -285,6 +528,13 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	cn.miner = work.New(cn, cn.chainConfig, cn.EventMux(), cn.engine, ctx.NodeType(), crypto.PubkeyToAddress(ctx.NodeKey().PublicKey), cn.config.TxResendUseLegacy)
 	// istanbul BFT
 	cn.miner.SetExtra(makeExtraData(config.ExtraData))
+	if config.TxOrderer != "" {
+		orderer, err := work.GetTxOrderer(config.TxOrderer)
+		if err != nil {
+			return nil, err
+		}
+		cn.miner.SetTxOrderer(orderer)
+	}
 
 	cn.APIBackend = &CNAPIBackend{cn, nil}
 
@@ -337,7 +587,8 @@ func makeExtraData(extra []byte) []byte {
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) database.DBManager {
 	dbc := &database.DBConfig{Dir: name, DBType: database.LevelDB, ParallelDBWrite: config.ParallelDBWrite, Partitioned: config.PartitionedDB, NumStateTriePartitions: config.NumStateTriePartitions,
 		LevelDBCacheSize: config.LevelDBCacheSize, OpenFilesLimit: database.GetOpenFilesLimit(), LevelDBCompression: config.LevelDBCompression,
-		LevelDBBufferPool: config.LevelDBBufferPool}
+		LevelDBBufferPool: config.LevelDBBufferPool, PartitionRatioOverride: config.DBPartitionRatios, BodyCompression: config.BodyCompression, ReadOnly: config.DBReadOnly,
+		WriteThroughCaching: config.WriteThroughCaching}
 	return ctx.OpenDatabase(dbc)
 }
 
@@ -358,6 +609,8 @@ func (s *CN) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	traceAPI := NewPrivateTraceAPI(s.chainConfig, s)
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -365,6 +618,11 @@ func (s *CN) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicKlayAPI(s),
 			Public:    true,
+		}, {
+			Namespace: "klay",
+			Version:   "1.0",
+			Service:   NewPublicTouchedAccountsAPI(traceAPI),
+			Public:    true,
 		}, {
 			Namespace: "klay",
 			Version:   "1.0",
@@ -398,6 +656,10 @@ func (s *CN) APIs() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   NewPrivateDebugAPI(s.chainConfig, s),
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   traceAPI,
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -408,6 +670,11 @@ func (s *CN) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   governance.NewGovernanceAPI(s.governance),
 			Public:    true,
+		}, {
+			Namespace: "governance",
+			Version:   "1.0",
+			Service:   reward.NewPublicRewardAPI(s.chainConfig, s.blockchain, s.Rewardbase),
+			Public:    true,
 		}, {
 			Namespace: "klay",
 			Version:   "1.0",
@@ -470,9 +737,14 @@ func (s *CN) SetRewardbase(rewardbase common.Address) {
 	}
 	s.protocolManager.SetRewardbase(rewardbase)
 	s.protocolManager.SetRewardbaseWallet(wallet)
+	s.keyAgeMonitor.Track(rewardbaseName, rewardbase, rewardbaseAgeGauge)
 }
 
 func (s *CN) StartMining(local bool) error {
+	// Blocks cannot be written to a read-only database.
+	if s.chainDB.IsReadOnly() {
+		return database.ErrReadOnlyDatabase
+	}
 	if local {
 		// If local (CPU) mining is started, we can disable the transaction rejection
 		// mechanism introduced to speed sync times. CPU mining on mainnet is ludicrous
@@ -505,6 +777,10 @@ func (s *CN) ReBroadcastTxs(transactions types.Transactions) {
 // Protocols implements node.Service, returning all the currently configured
 // network protocols to start.
 func (s *CN) Protocols() []p2p.Protocol {
+	// A node on a read-only database neither syncs nor imports blocks from its peers.
+	if s.chainDB.IsReadOnly() {
+		return nil
+	}
 	if s.lesServer == nil {
 		return s.protocolManager.SubProtocols
 	}
@@ -517,6 +793,11 @@ func (s *CN) Start(srvr p2p.Server) error {
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers()
 
+	if s.diskSpaceGuard != nil {
+		s.diskSpaceGuard.Start()
+	}
+	s.keyAgeMonitor.Start()
+
 	// Start the RPC service
 	s.netRPCService = api.NewPublicNetAPI(srvr, s.NetVersion())
 
@@ -533,6 +814,10 @@ func (s *CN) Start(srvr p2p.Server) error {
 // Stop implements node.Service, terminating all internal goroutines used by the
 // Klaytn protocol.
 func (s *CN) Stop() error {
+	if s.diskSpaceGuard != nil {
+		s.diskSpaceGuard.Stop()
+	}
+	s.keyAgeMonitor.Stop()
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
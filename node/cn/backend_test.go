@@ -0,0 +1,245 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	klaytnapi "github.com/klaytn/klaytn/api"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+func TestWriteAccountKeyChanges(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+
+		db    = database.NewMemoryDBManager()
+		gspec = &blockchain.Genesis{Config: params.TestChainConfig, Alloc: blockchain.GenesisAlloc{
+			from: {Balance: big.NewInt(params.KLAY)},
+		}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, big.NewInt(1))
+	)
+	// The key is updated twice, each update being signed by the current key.
+	newKeys := []accountkey.AccountKey{
+		accountkey.NewAccountKeyPublicWithValue(&key1.PublicKey),
+		accountkey.NewAccountKeyPublicWithValue(&key2.PublicKey),
+	}
+	signKeys := []*ecdsa.PrivateKey{key, key1}
+	var txs []*types.Transaction
+	for i, newKey := range newKeys {
+		tx, err := types.NewTransactionWithMap(types.TxTypeAccountUpdate, map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:      uint64(i),
+			types.TxValueKeyGasLimit:   uint64(1000000),
+			types.TxValueKeyGasPrice:   big.NewInt(0),
+			types.TxValueKeyFrom:       from,
+			types.TxValueKeyAccountKey: newKey,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SignWithKeys(signer, []*ecdsa.PrivateKey{signKeys[i]}); err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 3, func(i int, gen *blockchain.BlockGen) {
+		if i < len(txs) {
+			gen.AddTx(txs[i])
+		}
+	})
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range blocks {
+		writeAccountKeyChanges(chain, db, block)
+	}
+
+	history, err := db.ReadAccountKeyHistory(from, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(newKeys) {
+		t.Fatalf("history length mismatch: have %d, want %d", len(history), len(newKeys))
+	}
+	oldKeys := []accountkey.AccountKey{accountkey.NewAccountKeyLegacy(), newKeys[0]}
+	for i, change := range history {
+		if change.BlockNumber != uint64(i+1) || change.TxHash != txs[i].Hash() {
+			t.Errorf("change %d: have block %d tx %x, want block %d tx %x", i, change.BlockNumber, change.TxHash, i+1, txs[i].Hash())
+		}
+		if have := decodeAccountKey(t, change.OldKey); !have.Equal(oldKeys[i]) {
+			t.Errorf("change %d: old key mismatch: have %v, want %v", i, have, oldKeys[i])
+		}
+		if have := decodeAccountKey(t, change.NewKey); !have.Equal(newKeys[i]) {
+			t.Errorf("change %d: new key mismatch: have %v, want %v", i, have, newKeys[i])
+		}
+	}
+
+	// Other accounts have no history.
+	if history, err := db.ReadAccountKeyHistory(common.HexToAddress("0x1"), 0, 3); err != nil || len(history) != 0 {
+		t.Errorf("unknown account: have %v (%v), want empty history", history, err)
+	}
+}
+
+func decodeAccountKey(t *testing.T, enc []byte) accountkey.AccountKey {
+	serializer := accountkey.NewAccountKeySerializer()
+	if err := rlp.DecodeBytes(enc, serializer); err != nil {
+		t.Fatal(err)
+	}
+	return serializer.GetKey()
+}
+
+// Tests that the accounts created by receiving value or being deployed are indexed
+// with their creation transactions and served by klay_getAccountCreationInfo, and
+// that the index follows reorgs.
+func TestAccountCreationIndexer(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		existing = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		created  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		reorged  = common.HexToAddress("0x3000000000000000000000000000000000000003")
+
+		db    = database.NewMemoryDBManager()
+		gspec = &blockchain.Genesis{Config: params.TestChainConfig, Alloc: blockchain.GenesisAlloc{
+			from:     {Balance: big.NewInt(params.KLAY)},
+			existing: {Balance: big.NewInt(1)},
+		}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, big.NewInt(1))
+	)
+	newTx := func(nonce uint64, to *common.Address, data []byte) *types.Transaction {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(0), 1000000, big.NewInt(0), data)
+		} else {
+			tx = types.NewTransaction(nonce, *to, big.NewInt(1), 1000000, big.NewInt(0), nil)
+		}
+		tx, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	// The first block transfers value to an existing and a new account, the second
+	// one deploys a contract whose code is 0x01.
+	txs := []*types.Transaction{newTx(0, &existing, nil), newTx(1, &created, nil), newTx(2, nil, common.FromHex("0x600160005360016000f3"))}
+	contract := crypto.CreateAddress(from, 2)
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 2, func(i int, gen *blockchain.BlockGen) {
+		if i == 0 {
+			gen.AddTx(txs[0])
+			gen.AddTx(txs[1])
+		} else {
+			gen.AddTx(txs[2])
+		}
+	})
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range blocks {
+		writeAccountCreationInfos(chain, db, block)
+	}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("klay", klaytnapi.NewPublicBlockChainAPI(&CNAPIBackend{cn: &CN{chainDB: db}})); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	checkInfo := func(addr common.Address, tx *types.Transaction, block *types.Block) {
+		var info map[string]interface{}
+		if err := client.Call(&info, "klay_getAccountCreationInfo", addr); err != nil {
+			t.Fatal(err)
+		}
+		if tx == nil {
+			if info != nil {
+				t.Errorf("account %x: have creation info %v, want nil", addr, info)
+			}
+			return
+		}
+		want := map[string]interface{}{
+			"transactionHash": tx.Hash().Hex(),
+			"type":            types.TxTypeLegacyTransaction.String(),
+			"typeInt":         float64(types.TxTypeLegacyTransaction),
+			"blockHash":       block.Hash().Hex(),
+			"blockNumber":     hexutil.Uint64(block.NumberU64()).String(),
+			"humanReadable":   false,
+		}
+		if len(info) != len(want) {
+			t.Fatalf("account %x: creation info mismatch: have %v, want %v", addr, info, want)
+		}
+		for k, v := range want {
+			if info[k] != v {
+				t.Errorf("account %x: creation info %s mismatch: have %v, want %v", addr, k, info[k], v)
+			}
+		}
+	}
+	checkInfo(created, txs[1], blocks[0])
+	checkInfo(contract, txs[2], blocks[1])
+	// Accounts which existed before are not indexed.
+	checkInfo(existing, nil, nil)
+	checkInfo(from, nil, nil)
+
+	// A longer fork replaces both blocks and creates another account.
+	forkTx := newTx(0, &reorged, nil)
+	fork, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 3, func(i int, gen *blockchain.BlockGen) {
+		if i == 0 {
+			gen.AddTx(forkTx)
+		}
+	})
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatal(err)
+	}
+	// The replaced blocks are delivered as chain side events.
+	for _, block := range blocks {
+		revertAccountCreationInfos(chain, db, block)
+	}
+	for _, addr := range []common.Address{created, contract} {
+		if info := db.ReadAccountCreationInfo(addr); info != nil {
+			t.Errorf("account %x: creation info of a replaced block not deleted: %v", addr, info)
+		}
+	}
+	checkInfo(reorged, forkTx, fork[0])
+}
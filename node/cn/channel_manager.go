@@ -65,6 +65,9 @@ func NewChannelManager(channelSize int) *ChannelManager {
 	channelMgr.RegisterMsgCode(MiscChannel, StatusMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, NodeDataRequestMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, NodeDataMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, NodeDataRangeRequestMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, ReceiptsByBlockRequestMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, ReceiptsByBlockMsg)
 
 	return channelMgr
 }
@@ -74,6 +77,18 @@ func (cm *ChannelManager) RegisterChannelWithIndex(idx int, channelId uint, chan
 	cm.msgChannels[idx][channelId] = channel
 }
 
+// RegisterPrioritizedChannelsWithIndex registers priorityChannel for the channels in
+// HighPriorityChannels and channel for the other non-consensus channels.
+func (cm *ChannelManager) RegisterPrioritizedChannelsWithIndex(idx int, priorityChannel, channel chan p2p.Msg) {
+	for _, channelId := range []uint{BlockChannel, TxChannel, MiscChannel} {
+		if HighPriorityChannels[channelId] {
+			cm.RegisterChannelWithIndex(idx, channelId, priorityChannel)
+		} else {
+			cm.RegisterChannelWithIndex(idx, channelId, channel)
+		}
+	}
+}
+
 // RegisterMsgCode registers the channel id corresponding to msgCode.
 func (cm *ChannelManager) RegisterMsgCode(channelId uint, msgCode uint64) {
 	cm.msgCodes[msgCode] = channelId
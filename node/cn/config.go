@@ -27,6 +27,7 @@ import (
 	"github.com/klaytn/klaytn/consensus/istanbul"
 	"github.com/klaytn/klaytn/datasync/downloader"
 	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/node/cn/gasprice"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/storage/database"
@@ -46,7 +47,19 @@ var DefaultConfig = Config{
 	TrieCacheSize:     512,
 	TrieTimeout:       5 * time.Minute,
 	TrieBlockInterval: blockchain.DefaultBlockInterval,
+	BlockVerifyLevel:  blockchain.VerifyLevelFull,
 	GasPrice:          big.NewInt(18 * params.Ston),
+	PeerQueueTxs:      maxQueuedTxs,
+	PeerQueueProps:    maxQueuedProps,
+	PeerQueueAnns:     maxQueuedAnns,
+	PeerKnownTxs:      maxKnownTxs,
+	PeerKnownBlocks:   maxKnownBlocks,
+
+	CNHandshakeTimeout: handshakeTimeout,
+	PNHandshakeTimeout: handshakeTimeout,
+	ENHandshakeTimeout: handshakeTimeout,
+
+	PeerBroadcastRestarts: maxBroadcastRestarts,
 
 	TxPool: blockchain.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
@@ -89,24 +102,71 @@ type Config struct {
 	//LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
 
 	// Database options
-	SkipBcVersionCheck     bool `toml:"-"`
-	PartitionedDB          bool
-	NumStateTriePartitions uint
-	LevelDBCompression     database.LevelDBCompressionType
-	LevelDBBufferPool      bool
-	LevelDBCacheSize       int
-	TrieCacheSize          int
-	TrieTimeout            time.Duration
-	TrieBlockInterval      uint
-	SenderTxHashIndexing   bool
-	ParallelDBWrite        bool
-	StateDBCaching         bool
-	TxPoolStateCache       bool
-	TrieCacheLimit         int
+	SkipBcVersionCheck        bool `toml:"-"`
+	PartitionedDB             bool
+	NumStateTriePartitions    uint
+	LevelDBCompression        database.LevelDBCompressionType
+	LevelDBBufferPool         bool
+	DBPartitionRatios         []int `toml:",omitempty"` // Ratios of the cache of each partition, nil for the default ratios
+	BodyCompression           database.BodyCompressionType
+	DBReadOnly                bool
+	LevelDBCacheSize          int
+	TrieCacheSize             int
+	TrieTimeout               time.Duration
+	TrieBlockInterval         uint
+	SenderTxHashIndexing      bool
+	AccountCreationIndexing   bool
+	AccountKeyHistoryIndexing bool
+	ParallelDBWrite           bool
+	WriteThroughCaching       bool
+	MinFreeDiskSpace          uint64 // in MiB, 0 disables the disk space guard
+	StateDBCaching            bool
+	TxPoolStateCache          bool
+	TrieCacheLimit            int
+	TrieCommitBatchSize       int // in KiB, 0 uses the default batch size
+	TrieMaxDirtyBuffer        int // in MiB, 0 disables the dirty buffer budget
+	BlockVerifyLevel          blockchain.BlockVerifyLevel
+
+	// Maximum number of concurrent peer handshakes, 0 for unlimited
+	MaxConcurrentHandshakes int
+
+	// Disconnect peers whose fork identifier shows they have not crossed a fork we have crossed
+	EvictStaleForkPeers bool
+
+	// Suppress relaying blocks of peers while syncing more than this number of
+	// blocks behind the sync target, 0 to always relay. Mined blocks are always announced
+	CatchUpGossipThreshold uint64
+
+	// Timeouts of the handshake with consensus, proxy and endpoint node peers, 0 uses the default timeout
+	CNHandshakeTimeout time.Duration
+	PNHandshakeTimeout time.Duration
+	ENHandshakeTimeout time.Duration
+
+	// Sizes of the broadcast queues of each peer, 0 uses the default size
+	PeerQueueTxs   int
+	PeerQueueProps int
+	PeerQueueAnns  int
+
+	// Sizes of the caches of the transaction and block hashes known by each peer, 0 uses the default size
+	PeerKnownTxs    int
+	PeerKnownBlocks int
+
+	// Maximum number of restarts of a peer's panicked broadcast loop before the peer is dropped
+	PeerBroadcastRestarts int
+
+	// Maximum number of peers the downloader fetches the same kind of data from at once, 0 for unlimited
+	SyncMaxPeers int
+
+	// Average database write latency above which the downloader backs off block imports, 0 to disable
+	SyncMaxWriteLatency time.Duration
+
+	// Age after which the node key and the rewardbase should be rotated, 0 disables the warning
+	KeyRotationAge time.Duration
 
 	// Mining-related options
 	ServiceChainSigner common.Address `toml:",omitempty"`
 	ExtraData          []byte         `toml:",omitempty"`
+	TxOrderer          string         `toml:",omitempty"`
 	GasPrice           *big.Int
 
 	// Reward
@@ -126,6 +186,9 @@ type Config struct {
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
+	// Global flags set on the command line, with the values of sensitive flags masked
+	Flags map[string]string `toml:"-"`
+
 	WsEndpoint string `toml:",omitempty"`
 
 	// Tx Resending options
@@ -133,6 +196,9 @@ type Config struct {
 	TxResendCount     int
 	TxResendUseLegacy bool
 
+	// Node types receiving transaction broadcasts, empty for all types
+	TxBroadcastTargets []p2p.ConnType `toml:",omitempty"`
+
 	// Service Chain
 	NoAccountCreation bool
 
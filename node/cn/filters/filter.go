@@ -22,6 +22,7 @@ package filters
 
 import (
 	"context"
+	"fmt"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/bloombits"
 	"github.com/klaytn/klaytn/blockchain/types"
@@ -112,6 +113,10 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
+	// The logs are derived from the receipts, which may have been pruned.
+	if earliest := f.db.ReadEarliestReceiptsBlockNumber(); f.begin >= 0 && uint64(f.begin) < earliest {
+		return nil, fmt.Errorf("receipts of block %d have been pruned; the earliest available block is %d", f.begin, earliest)
+	}
 	// Gather all indexed logs, and finish with non indexed ones
 	var (
 		logs []*types.Log
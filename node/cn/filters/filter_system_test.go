@@ -392,6 +392,40 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 }
 */
 
+// TestGetLogsPrunedReceipts tests that getting the logs of the blocks whose
+// receipts have been pruned fails.
+func TestGetLogsPrunedReceipts(t *testing.T) {
+	var (
+		mux        = new(event.TypeMux)
+		db         = database.NewMemoryDBManager()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+	)
+	var parent common.Hash
+	for i := int64(0); i < 5; i++ {
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(i), BlockScore: big.NewInt(1), Time: big.NewInt(i)}
+		db.WriteHeader(header)
+		db.WriteCanonicalHash(header.Hash(), header.Number.Uint64())
+		db.WriteHeadBlockHash(header.Hash())
+		parent = header.Hash()
+	}
+	db.WriteEarliestReceiptsBlockNumber(3)
+
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(2), ToBlock: big.NewInt(4)}); err == nil {
+		t.Error("getting the logs of pruned blocks should fail")
+	}
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(3), ToBlock: big.NewInt(4)}); err != nil {
+		t.Errorf("failed to get the logs of unpruned blocks: %v", err)
+	}
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{}); err != nil {
+		t.Errorf("failed to get the logs of the latest block: %v", err)
+	}
+}
+
 // TestLogFilter tests whether log filters match the correct logs that are posted to the event feed.
 func TestLogFilter(t *testing.T) {
 	t.Parallel()
@@ -11,6 +11,7 @@ import (
 	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/consensus/istanbul"
 	"github.com/klaytn/klaytn/datasync/downloader"
+	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/node/cn/gasprice"
 	"github.com/klaytn/klaytn/storage/database"
 )
@@ -20,41 +21,69 @@ var _ = (*configMarshaling)(nil)
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                 *blockchain.Genesis `toml:",omitempty"`
-		NetworkId               uint64
-		SyncMode                downloader.SyncMode
-		NoPruning               bool
-		MainChainAccountAddr    *common.Address `toml:",omitempty"`
-		AnchoringPeriod         uint64
-		SentChainTxsLimit       uint64
-		SkipBcVersionCheck      bool `toml:"-"`
-		PartitionedDB           bool
-		NumStateTriePartitions  uint
-		LevelDBCompression      database.LevelDBCompressionType
-		LevelDBBufferPool       bool
-		LevelDBCacheSize        int
-		TrieCacheSize           int
-		TrieTimeout             time.Duration
-		TrieBlockInterval       uint
-		SenderTxHashIndexing    bool
-		ParallelDBWrite         bool
-		StateDBCaching          bool
-		TxPoolStateCache        bool
-		TrieCacheLimit          int
-		ServiceChainSigner      common.Address `toml:",omitempty"`
-		ExtraData               hexutil.Bytes  `toml:",omitempty"`
-		GasPrice                *big.Int
-		Rewardbase              common.Address `toml:",omitempty"`
-		TxPool                  blockchain.TxPoolConfig
-		GPO                     gasprice.Config
-		EnablePreimageRecording bool
-		Istanbul                istanbul.Config
-		DocRoot                 string `toml:"-"`
-		WsEndpoint              string `toml:",omitempty"`
-		TxResendInterval        uint64
-		TxResendCount           int
-		TxResendUseLegacy       bool
-		NoAccountCreation       bool
+		Genesis                   *blockchain.Genesis `toml:",omitempty"`
+		NetworkId                 uint64
+		SyncMode                  downloader.SyncMode
+		NoPruning                 bool
+		MainChainAccountAddr      *common.Address `toml:",omitempty"`
+		AnchoringPeriod           uint64
+		SentChainTxsLimit         uint64
+		SkipBcVersionCheck        bool `toml:"-"`
+		PartitionedDB             bool
+		NumStateTriePartitions    uint
+		LevelDBCompression        database.LevelDBCompressionType
+		LevelDBBufferPool         bool
+		DBPartitionRatios         []int `toml:",omitempty"`
+		BodyCompression           database.BodyCompressionType
+		DBReadOnly                bool
+		LevelDBCacheSize          int
+		TrieCacheSize             int
+		TrieTimeout               time.Duration
+		TrieBlockInterval         uint
+		SenderTxHashIndexing      bool
+		AccountCreationIndexing   bool
+		AccountKeyHistoryIndexing bool
+		ParallelDBWrite           bool
+		WriteThroughCaching       bool
+		MinFreeDiskSpace          uint64
+		StateDBCaching            bool
+		TxPoolStateCache          bool
+		TrieCacheLimit            int
+		TrieCommitBatchSize       int
+		TrieMaxDirtyBuffer        int
+		BlockVerifyLevel          blockchain.BlockVerifyLevel
+		MaxConcurrentHandshakes   int
+		EvictStaleForkPeers       bool
+		CatchUpGossipThreshold    uint64
+		CNHandshakeTimeout        time.Duration
+		PNHandshakeTimeout        time.Duration
+		ENHandshakeTimeout        time.Duration
+		PeerQueueTxs              int
+		PeerQueueProps            int
+		PeerQueueAnns             int
+		PeerKnownTxs              int
+		PeerKnownBlocks           int
+		PeerBroadcastRestarts     int
+		SyncMaxPeers              int
+		SyncMaxWriteLatency       time.Duration
+		KeyRotationAge            time.Duration
+		ServiceChainSigner        common.Address `toml:",omitempty"`
+		ExtraData                 hexutil.Bytes  `toml:",omitempty"`
+		TxOrderer                 string         `toml:",omitempty"`
+		GasPrice                  *big.Int
+		Rewardbase                common.Address `toml:",omitempty"`
+		TxPool                    blockchain.TxPoolConfig
+		GPO                       gasprice.Config
+		EnablePreimageRecording   bool
+		Istanbul                  istanbul.Config
+		DocRoot                   string            `toml:"-"`
+		Flags                     map[string]string `toml:"-"`
+		WsEndpoint                string            `toml:",omitempty"`
+		TxResendInterval          uint64
+		TxResendCount             int
+		TxResendUseLegacy         bool
+		TxBroadcastTargets        []p2p.ConnType `toml:",omitempty"`
+		NoAccountCreation         bool
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -69,17 +98,43 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NumStateTriePartitions = c.NumStateTriePartitions
 	enc.LevelDBCompression = c.LevelDBCompression
 	enc.LevelDBBufferPool = c.LevelDBBufferPool
+	enc.DBPartitionRatios = c.DBPartitionRatios
+	enc.BodyCompression = c.BodyCompression
+	enc.DBReadOnly = c.DBReadOnly
 	enc.LevelDBCacheSize = c.LevelDBCacheSize
 	enc.TrieCacheSize = c.TrieCacheSize
 	enc.TrieTimeout = c.TrieTimeout
 	enc.TrieBlockInterval = c.TrieBlockInterval
 	enc.SenderTxHashIndexing = c.SenderTxHashIndexing
+	enc.AccountCreationIndexing = c.AccountCreationIndexing
+	enc.AccountKeyHistoryIndexing = c.AccountKeyHistoryIndexing
 	enc.ParallelDBWrite = c.ParallelDBWrite
+	enc.WriteThroughCaching = c.WriteThroughCaching
+	enc.MinFreeDiskSpace = c.MinFreeDiskSpace
 	enc.StateDBCaching = c.StateDBCaching
 	enc.TxPoolStateCache = c.TxPoolStateCache
 	enc.TrieCacheLimit = c.TrieCacheLimit
+	enc.TrieCommitBatchSize = c.TrieCommitBatchSize
+	enc.TrieMaxDirtyBuffer = c.TrieMaxDirtyBuffer
+	enc.BlockVerifyLevel = c.BlockVerifyLevel
+	enc.MaxConcurrentHandshakes = c.MaxConcurrentHandshakes
+	enc.EvictStaleForkPeers = c.EvictStaleForkPeers
+	enc.CatchUpGossipThreshold = c.CatchUpGossipThreshold
+	enc.CNHandshakeTimeout = c.CNHandshakeTimeout
+	enc.PNHandshakeTimeout = c.PNHandshakeTimeout
+	enc.ENHandshakeTimeout = c.ENHandshakeTimeout
+	enc.PeerQueueTxs = c.PeerQueueTxs
+	enc.PeerQueueProps = c.PeerQueueProps
+	enc.PeerQueueAnns = c.PeerQueueAnns
+	enc.PeerKnownTxs = c.PeerKnownTxs
+	enc.PeerKnownBlocks = c.PeerKnownBlocks
+	enc.PeerBroadcastRestarts = c.PeerBroadcastRestarts
+	enc.SyncMaxPeers = c.SyncMaxPeers
+	enc.SyncMaxWriteLatency = c.SyncMaxWriteLatency
+	enc.KeyRotationAge = c.KeyRotationAge
 	enc.ServiceChainSigner = c.ServiceChainSigner
 	enc.ExtraData = c.ExtraData
+	enc.TxOrderer = c.TxOrderer
 	enc.GasPrice = c.GasPrice
 	enc.Rewardbase = c.Rewardbase
 	enc.TxPool = c.TxPool
@@ -87,10 +142,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.Istanbul = c.Istanbul
 	enc.DocRoot = c.DocRoot
+	enc.Flags = c.Flags
 	enc.WsEndpoint = c.WsEndpoint
 	enc.TxResendInterval = c.TxResendInterval
 	enc.TxResendCount = c.TxResendCount
 	enc.TxResendUseLegacy = c.TxResendUseLegacy
+	enc.TxBroadcastTargets = c.TxBroadcastTargets
 	enc.NoAccountCreation = c.NoAccountCreation
 	return &enc, nil
 }
@@ -98,41 +155,69 @@ func (c Config) MarshalTOML() (interface{}, error) {
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                 *blockchain.Genesis `toml:",omitempty"`
-		NetworkId               *uint64
-		SyncMode                *downloader.SyncMode
-		NoPruning               *bool
-		MainChainAccountAddr    *common.Address `toml:",omitempty"`
-		AnchoringPeriod         *uint64
-		SentChainTxsLimit       *uint64
-		SkipBcVersionCheck      *bool `toml:"-"`
-		PartitionedDB           *bool
-		NumStateTriePartitions  *uint
-		LevelDBCompression      *database.LevelDBCompressionType
-		LevelDBBufferPool       *bool
-		LevelDBCacheSize        *int
-		TrieCacheSize           *int
-		TrieTimeout             *time.Duration
-		TrieBlockInterval       *uint
-		SenderTxHashIndexing    *bool
-		ParallelDBWrite         *bool
-		StateDBCaching          *bool
-		TxPoolStateCache        *bool
-		TrieCacheLimit          *int
-		ServiceChainSigner      *common.Address `toml:",omitempty"`
-		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
-		GasPrice                *big.Int
-		Rewardbase              *common.Address `toml:",omitempty"`
-		TxPool                  *blockchain.TxPoolConfig
-		GPO                     *gasprice.Config
-		EnablePreimageRecording *bool
-		Istanbul                *istanbul.Config
-		DocRoot                 *string `toml:"-"`
-		WsEndpoint              *string `toml:",omitempty"`
-		TxResendInterval        *uint64
-		TxResendCount           *int
-		TxResendUseLegacy       *bool
-		NoAccountCreation       *bool
+		Genesis                   *blockchain.Genesis `toml:",omitempty"`
+		NetworkId                 *uint64
+		SyncMode                  *downloader.SyncMode
+		NoPruning                 *bool
+		MainChainAccountAddr      *common.Address `toml:",omitempty"`
+		AnchoringPeriod           *uint64
+		SentChainTxsLimit         *uint64
+		SkipBcVersionCheck        *bool `toml:"-"`
+		PartitionedDB             *bool
+		NumStateTriePartitions    *uint
+		LevelDBCompression        *database.LevelDBCompressionType
+		LevelDBBufferPool         *bool
+		DBPartitionRatios         []int `toml:",omitempty"`
+		BodyCompression           *database.BodyCompressionType
+		DBReadOnly                *bool
+		LevelDBCacheSize          *int
+		TrieCacheSize             *int
+		TrieTimeout               *time.Duration
+		TrieBlockInterval         *uint
+		SenderTxHashIndexing      *bool
+		AccountCreationIndexing   *bool
+		AccountKeyHistoryIndexing *bool
+		ParallelDBWrite           *bool
+		WriteThroughCaching       *bool
+		MinFreeDiskSpace          *uint64
+		StateDBCaching            *bool
+		TxPoolStateCache          *bool
+		TrieCacheLimit            *int
+		TrieCommitBatchSize       *int
+		TrieMaxDirtyBuffer        *int
+		BlockVerifyLevel          *blockchain.BlockVerifyLevel
+		MaxConcurrentHandshakes   *int
+		EvictStaleForkPeers       *bool
+		CatchUpGossipThreshold    *uint64
+		CNHandshakeTimeout        *time.Duration
+		PNHandshakeTimeout        *time.Duration
+		ENHandshakeTimeout        *time.Duration
+		PeerQueueTxs              *int
+		PeerQueueProps            *int
+		PeerQueueAnns             *int
+		PeerKnownTxs              *int
+		PeerKnownBlocks           *int
+		PeerBroadcastRestarts     *int
+		SyncMaxPeers              *int
+		SyncMaxWriteLatency       *time.Duration
+		KeyRotationAge            *time.Duration
+		ServiceChainSigner        *common.Address `toml:",omitempty"`
+		ExtraData                 *hexutil.Bytes  `toml:",omitempty"`
+		TxOrderer                 *string         `toml:",omitempty"`
+		GasPrice                  *big.Int
+		Rewardbase                *common.Address `toml:",omitempty"`
+		TxPool                    *blockchain.TxPoolConfig
+		GPO                       *gasprice.Config
+		EnablePreimageRecording   *bool
+		Istanbul                  *istanbul.Config
+		DocRoot                   *string           `toml:"-"`
+		Flags                     map[string]string `toml:"-"`
+		WsEndpoint                *string           `toml:",omitempty"`
+		TxResendInterval          *uint64
+		TxResendCount             *int
+		TxResendUseLegacy         *bool
+		TxBroadcastTargets        []p2p.ConnType `toml:",omitempty"`
+		NoAccountCreation         *bool
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -174,6 +259,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LevelDBBufferPool != nil {
 		c.LevelDBBufferPool = *dec.LevelDBBufferPool
 	}
+	if dec.DBPartitionRatios != nil {
+		c.DBPartitionRatios = dec.DBPartitionRatios
+	}
+	if dec.BodyCompression != nil {
+		c.BodyCompression = *dec.BodyCompression
+	}
+	if dec.DBReadOnly != nil {
+		c.DBReadOnly = *dec.DBReadOnly
+	}
 	if dec.LevelDBCacheSize != nil {
 		c.LevelDBCacheSize = *dec.LevelDBCacheSize
 	}
@@ -189,9 +283,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.SenderTxHashIndexing != nil {
 		c.SenderTxHashIndexing = *dec.SenderTxHashIndexing
 	}
+	if dec.AccountCreationIndexing != nil {
+		c.AccountCreationIndexing = *dec.AccountCreationIndexing
+	}
+	if dec.AccountKeyHistoryIndexing != nil {
+		c.AccountKeyHistoryIndexing = *dec.AccountKeyHistoryIndexing
+	}
 	if dec.ParallelDBWrite != nil {
 		c.ParallelDBWrite = *dec.ParallelDBWrite
 	}
+	if dec.WriteThroughCaching != nil {
+		c.WriteThroughCaching = *dec.WriteThroughCaching
+	}
+	if dec.MinFreeDiskSpace != nil {
+		c.MinFreeDiskSpace = *dec.MinFreeDiskSpace
+	}
 	if dec.StateDBCaching != nil {
 		c.StateDBCaching = *dec.StateDBCaching
 	}
@@ -201,12 +307,69 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TrieCacheLimit != nil {
 		c.TrieCacheLimit = *dec.TrieCacheLimit
 	}
+	if dec.TrieCommitBatchSize != nil {
+		c.TrieCommitBatchSize = *dec.TrieCommitBatchSize
+	}
+	if dec.TrieMaxDirtyBuffer != nil {
+		c.TrieMaxDirtyBuffer = *dec.TrieMaxDirtyBuffer
+	}
+	if dec.BlockVerifyLevel != nil {
+		c.BlockVerifyLevel = *dec.BlockVerifyLevel
+	}
+	if dec.MaxConcurrentHandshakes != nil {
+		c.MaxConcurrentHandshakes = *dec.MaxConcurrentHandshakes
+	}
+	if dec.EvictStaleForkPeers != nil {
+		c.EvictStaleForkPeers = *dec.EvictStaleForkPeers
+	}
+	if dec.CatchUpGossipThreshold != nil {
+		c.CatchUpGossipThreshold = *dec.CatchUpGossipThreshold
+	}
+	if dec.CNHandshakeTimeout != nil {
+		c.CNHandshakeTimeout = *dec.CNHandshakeTimeout
+	}
+	if dec.PNHandshakeTimeout != nil {
+		c.PNHandshakeTimeout = *dec.PNHandshakeTimeout
+	}
+	if dec.ENHandshakeTimeout != nil {
+		c.ENHandshakeTimeout = *dec.ENHandshakeTimeout
+	}
+	if dec.PeerQueueTxs != nil {
+		c.PeerQueueTxs = *dec.PeerQueueTxs
+	}
+	if dec.PeerQueueProps != nil {
+		c.PeerQueueProps = *dec.PeerQueueProps
+	}
+	if dec.PeerQueueAnns != nil {
+		c.PeerQueueAnns = *dec.PeerQueueAnns
+	}
+	if dec.PeerKnownTxs != nil {
+		c.PeerKnownTxs = *dec.PeerKnownTxs
+	}
+	if dec.PeerKnownBlocks != nil {
+		c.PeerKnownBlocks = *dec.PeerKnownBlocks
+	}
+	if dec.PeerBroadcastRestarts != nil {
+		c.PeerBroadcastRestarts = *dec.PeerBroadcastRestarts
+	}
+	if dec.SyncMaxPeers != nil {
+		c.SyncMaxPeers = *dec.SyncMaxPeers
+	}
+	if dec.SyncMaxWriteLatency != nil {
+		c.SyncMaxWriteLatency = *dec.SyncMaxWriteLatency
+	}
+	if dec.KeyRotationAge != nil {
+		c.KeyRotationAge = *dec.KeyRotationAge
+	}
 	if dec.ServiceChainSigner != nil {
 		c.ServiceChainSigner = *dec.ServiceChainSigner
 	}
 	if dec.ExtraData != nil {
 		c.ExtraData = *dec.ExtraData
 	}
+	if dec.TxOrderer != nil {
+		c.TxOrderer = *dec.TxOrderer
+	}
 	if dec.GasPrice != nil {
 		c.GasPrice = dec.GasPrice
 	}
@@ -228,6 +391,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
+	if dec.Flags != nil {
+		c.Flags = dec.Flags
+	}
 	if dec.WsEndpoint != nil {
 		c.WsEndpoint = *dec.WsEndpoint
 	}
@@ -240,6 +406,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxResendUseLegacy != nil {
 		c.TxResendUseLegacy = *dec.TxResendUseLegacy
 	}
+	if dec.TxBroadcastTargets != nil {
+		c.TxBroadcastTargets = dec.TxBroadcastTargets
+	}
 	if dec.NoAccountCreation != nil {
 		c.NoAccountCreation = *dec.NoAccountCreation
 	}
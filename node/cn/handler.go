@@ -88,6 +88,7 @@ type ProtocolManager struct {
 	maxPeers    int
 
 	downloader *downloader.Downloader
+	syncStatus syncStatus // Sync state deciding whether the node is catching up, the downloader by default
 	fetcher    *fetcher.Fetcher
 	peers      *peerSet
 
@@ -118,6 +119,31 @@ type ProtocolManager struct {
 
 	nodetype          p2p.ConnType
 	txResendUseLegacy bool
+
+	handshakeLimiter *handshakeLimiter // Bounds concurrent handshakes, nil if unlimited
+
+	evictStaleForkPeers bool // Disconnect peers which have not crossed a fork we have crossed
+
+	catchUpGossipThreshold uint64 // Blocks behind the sync target suppressing block relays, 0 to disable
+
+	peerQueueSizes peerQueueSizes // Sizes of the broadcast queues of each peer
+
+	handshakeTimeouts map[p2p.ConnType]time.Duration // Handshake timeouts of the peers of each node type
+
+	txBroadcastTargets map[p2p.ConnType]bool // Node types receiving transaction broadcasts, nil for all types
+
+	syncRate syncRateEstimator // Block import rate used to estimate the time to sync
+
+	receiptsByBlockFeed event.Feed // Feed of the receipts delivered in response to RequestReceiptsByBlock
+}
+
+// ReceiptsByBlockEvent is posted when a peer delivers the receipts requested by
+// RequestReceiptsByBlock. Blocks unknown to the peer are missing in the event.
+// The receipts are not verified against the block headers.
+type ReceiptsByBlockEvent struct {
+	PeerID   string
+	Hashes   []common.Hash
+	Receipts []types.Receipts
 }
 
 // NewProtocolManager returns a new Klaytn sub protocol manager. The Klaytn sub protocol manages peers capable
@@ -139,8 +165,18 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		engine:            engine,
 		nodetype:          nodetype,
 		txResendUseLegacy: cnconfig.TxResendUseLegacy,
+		handshakeLimiter:  newHandshakeLimiter(cnconfig.MaxConcurrentHandshakes, handshakeQueueTimeout),
+
+		evictStaleForkPeers:    cnconfig.EvictStaleForkPeers,
+		catchUpGossipThreshold: cnconfig.CatchUpGossipThreshold,
+		peerQueueSizes:         newPeerQueueSizes(cnconfig),
+		handshakeTimeouts:      newHandshakeTimeouts(cnconfig),
+		txBroadcastTargets:     newTxBroadcastTargets(cnconfig.TxBroadcastTargets),
 	}
 
+	manager.peers.broadcastRestarts = cnconfig.PeerBroadcastRestarts
+	manager.peers.dropPeer = manager.removePeer
+
 	// istanbul BFT
 	if handler, ok := engine.(consensus.Handler); ok {
 		handler.SetBroadcaster(manager, manager.nodetype)
@@ -230,7 +266,8 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		return nil, errIncompatibleConfig
 	}
 	// Construct the different synchronisation mechanisms
-	manager.downloader = downloader.New(mode, chainDB, manager.eventMux, blockchain, nil, manager.removePeer)
+	manager.downloader = downloader.New(mode, chainDB, manager.eventMux, blockchain, nil, manager.removePeer, cnconfig.SyncMaxPeers, cnconfig.SyncMaxWriteLatency)
+	manager.syncStatus = manager.downloader
 
 	validator := func(header *types.Header) error {
 		return engine.VerifyHeader(blockchain, header, true)
@@ -238,16 +275,19 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 	heighter := func() uint64 {
 		return blockchain.CurrentBlock().NumberU64()
 	}
-	inserter := func(blocks types.Blocks) (int, error) {
+	inserter := func(peer string, blocks types.Blocks) (int, error) {
 		// If fast sync is running, deny importing weird blocks
 		if atomic.LoadUint32(&manager.fastSync) == 1 {
 			logger.Warn("Discarded bad propagated block", "number", blocks[0].Number(), "hash", blocks[0].Hash())
 			return 0, nil
 		}
 		atomic.StoreUint32(&manager.acceptTxs, 1) // Mark initial sync done on any fetcher import
+		if manager.isTrustedPeer(peer) {
+			return manager.blockchain.InsertTrustedChain(blocks)
+		}
 		return manager.blockchain.InsertChain(blocks)
 	}
-	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, manager.BroadcastBlockHash, heighter, inserter, manager.removePeer)
+	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.relayBlock, manager.relayBlockHash, heighter, inserter, manager.removePeer)
 
 	if manager.useTxResend() {
 		go manager.txResendLoop(cnconfig.TxResendInterval, cnconfig.TxResendCount)
@@ -291,6 +331,12 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 }
 
+// isTrustedPeer returns true if the peer of the given id is connected as a trusted peer.
+func (pm *ProtocolManager) isTrustedPeer(id string) bool {
+	peer := pm.peers.Peer(id)
+	return peer != nil && peer.IsTrusted()
+}
+
 // getChainID returns the current chain id.
 func (pm *ProtocolManager) getChainID() *big.Int {
 	return pm.blockchain.Config().ChainID
@@ -313,6 +359,11 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 	go pm.txsyncLoop()
 }
 
+// SubscribeReceiptsByBlock registers a subscription of ReceiptsByBlockEvent.
+func (pm *ProtocolManager) SubscribeReceiptsByBlock(ch chan<- ReceiptsByBlockEvent) event.Subscription {
+	return pm.receiptsByBlockFeed.Subscribe(ch)
+}
+
 func (pm *ProtocolManager) Stop() {
 	logger.Info("Stopping Klaytn protocol")
 
@@ -344,7 +395,7 @@ func (pm *ProtocolManager) Stop() {
 }
 
 func (pm *ProtocolManager) newPeer(pv int, p *p2p.Peer, rw p2p.MsgReadWriter) Peer {
-	return newPeer(pv, p, newMeteredMsgWriter(rw))
+	return newPeer(pv, p, newMeteredMsgWriter(rw), pm.peerQueueSizes)
 }
 
 // newPeerWithRWs creates a new Peer object with a slice of p2p.MsgReadWriter.
@@ -353,14 +404,53 @@ func (pm *ProtocolManager) newPeerWithRWs(pv int, p *p2p.Peer, rws []p2p.MsgRead
 	for _, rw := range rws {
 		meteredRWs = append(meteredRWs, newMeteredMsgWriter(rw))
 	}
-	return newPeerWithRWs(pv, p, meteredRWs)
+	return newPeerWithRWs(pv, p, meteredRWs, pm.peerQueueSizes)
+}
+
+// newHandshakeTimeouts returns the handshake timeouts of the peers of each node
+// type configured in config. Non-positive timeouts are left out.
+func newHandshakeTimeouts(config *Config) map[p2p.ConnType]time.Duration {
+	timeouts := make(map[p2p.ConnType]time.Duration)
+	for nodetype, timeout := range map[p2p.ConnType]time.Duration{
+		node.CONSENSUSNODE: config.CNHandshakeTimeout,
+		node.PROXYNODE:     config.PNHandshakeTimeout,
+		node.ENDPOINTNODE:  config.ENHandshakeTimeout,
+	} {
+		if timeout > 0 {
+			timeouts[nodetype] = timeout
+		}
+	}
+	return timeouts
+}
+
+// handshakeTimeout returns the handshake timeout of the peers of the given node
+// type, or the default timeout if none is configured for the type.
+func (pm *ProtocolManager) handshakeTimeout(nodetype p2p.ConnType) time.Duration {
+	if timeout, ok := pm.handshakeTimeouts[nodetype]; ok {
+		return timeout
+	}
+	return handshakeTimeout
+}
+
+// checkForkID returns an error if stale fork peers are evicted and the fork
+// identifier of the peer shows that it has not crossed a fork crossed at the
+// given head block. Peers which did not send a fork identifier are accepted.
+func (pm *ProtocolManager) checkForkID(p Peer, head *big.Int) error {
+	if !pm.evictStaleForkPeers || p.GetForkID() == nil {
+		return nil
+	}
+	if block, stale := pm.chainconfig.StaleFork(head, *p.GetForkID()); stale {
+		staleForkPeerCounter.Inc(1)
+		return errResp(ErrStaleFork, "fork at block %d not crossed", block)
+	}
+	return nil
 }
 
 // handle is the callback invoked to manage the life cycle of a Klaytn peer. When
 // this function terminates, the peer is disconnected.
 func (pm *ProtocolManager) handle(p Peer) error {
 	// Ignore maxPeers if this is a trusted peer
-	if pm.peers.Len() >= pm.maxPeers && !p.GetP2PPeer().Info().Networks[p2p.ConnDefault].Trusted {
+	if pm.peers.Len() >= pm.maxPeers && !p.IsTrusted() {
 		return p2p.DiscTooManyPeers
 	}
 	p.GetP2PPeer().Log().Debug("Klaytn peer connected", "name", p.GetP2PPeer().Name())
@@ -374,11 +464,20 @@ func (pm *ProtocolManager) handle(p Peer) error {
 		td      = pm.blockchain.GetTd(hash, number)
 	)
 
-	err := p.Handshake(pm.networkId, pm.getChainID(), td, hash, genesis.Hash())
+	if !pm.handshakeLimiter.acquire(pm.quitSync) {
+		p.GetP2PPeer().Log().Debug("Klaytn peer handshake not started, too many concurrent handshakes")
+		return p2p.DiscTooManyPeers
+	}
+	err := p.Handshake(pm.networkId, pm.getChainID(), td, hash, genesis.Hash(), pm.chainconfig.ForkID(head.Number), pm.handshakeTimeout(p.ConnType()))
+	pm.handshakeLimiter.release()
 	if err != nil {
 		p.GetP2PPeer().Log().Debug("Klaytn peer handshake failed", "err", err)
 		return err
 	}
+	if err := pm.checkForkID(p, head.Number); err != nil {
+		p.GetP2PPeer().Log().Debug("Klaytn peer is on a stale fork", "err", err)
+		return err
+	}
 	if rw, ok := p.GetRW().(*meteredMsgReadWriter); ok {
 		rw.Init(p.GetVersion())
 	}
@@ -454,6 +553,40 @@ func (pm *ProtocolManager) processMsg(msgCh <-chan p2p.Msg, p Peer, addr common.
 	p.GetP2PPeer().Log().Info("ProtocolManager.processMsg closed", "PeerName", p.GetP2PPeer().Name())
 }
 
+// processPrioritizedMsg processes the messages of highCh and lowCh.
+// The messages of highCh are processed first when both channels have queued messages.
+func (pm *ProtocolManager) processPrioritizedMsg(highCh, lowCh <-chan p2p.Msg, p Peer, addr common.Address, errCh chan<- error) {
+	for {
+		msg, ok := nextPrioritizedMsg(highCh, lowCh)
+		if !ok {
+			break
+		}
+		if err := pm.handleMsg(p, addr, msg); err != nil {
+			p.GetP2PPeer().Log().Error("ProtocolManager failed to handle message", "msg", msg, "err", err)
+			errCh <- err
+			return
+		}
+		msg.Discard()
+	}
+	p.GetP2PPeer().Log().Info("ProtocolManager.processPrioritizedMsg closed", "PeerName", p.GetP2PPeer().Name())
+}
+
+// nextPrioritizedMsg returns the next message, preferring highCh over lowCh.
+// It returns false if either of the channels is closed.
+func nextPrioritizedMsg(highCh, lowCh <-chan p2p.Msg) (p2p.Msg, bool) {
+	select {
+	case msg, ok := <-highCh:
+		return msg, ok
+	default:
+	}
+	select {
+	case msg, ok := <-highCh:
+		return msg, ok
+	case msg, ok := <-lowCh:
+		return msg, ok
+	}
+}
+
 // processConsensusMsg processes the consensus message.
 func (pm *ProtocolManager) processConsensusMsg(msgCh <-chan p2p.Msg, p Peer, addr common.Address, errCh chan<- error) {
 	for msg := range msgCh {
@@ -531,6 +664,11 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 			return err
 		}
 
+	case p.GetVersion() >= klay65 && msg.Code == NodeDataRangeRequestMsg:
+		if err := handleNodeDataRangeRequestMsg(pm, p, msg); err != nil {
+			return err
+		}
+
 	case p.GetVersion() >= klay63 && msg.Code == NodeDataMsg:
 		if err := handleNodeDataMsg(pm, p, msg); err != nil {
 			return err
@@ -546,6 +684,16 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 			return err
 		}
 
+	case p.GetVersion() >= klay66 && msg.Code == ReceiptsByBlockRequestMsg:
+		if err := handleReceiptsByBlockRequestMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay66 && msg.Code == ReceiptsByBlockMsg:
+		if err := handleReceiptsByBlockMsg(pm, p, msg); err != nil {
+			return err
+		}
+
 	case msg.Code == NewBlockHashesMsg:
 		if err := handleNewBlockHashesMsg(pm, p, msg); err != nil {
 			return err
@@ -772,6 +920,48 @@ func handleNodeDataRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	return p.SendNodeData(data)
 }
 
+// handleNodeDataRangeRequestMsg handles node data range request message.
+// The requested entries are served first, followed by their descendant trie
+// nodes in pre-order so that the requester can process the reply in sequence.
+func handleNodeDataRangeRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	// Decode the retrieval message
+	msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
+	if _, err := msgStream.List(); err != nil {
+		return err
+	}
+	// Gather the requested state entries as handleNodeDataRequestMsg does
+	var (
+		hash   common.Hash
+		bytes  int
+		hashes []common.Hash
+		data   [][]byte
+	)
+	for bytes < softResponseLimit && len(data) < downloader.MaxStateFetch {
+		if err := msgStream.Decode(&hash); err == rlp.EOL {
+			break
+		} else if err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if entry, err := pm.blockchain.TrieNode(hash); err == nil {
+			hashes = append(hashes, hash)
+			data = append(data, entry)
+			bytes += len(entry)
+		}
+	}
+	// Fill up the rest of the reply with the subtries below the requested entries
+	for _, hash := range hashes {
+		if bytes >= softResponseLimit || len(data) >= downloader.MaxStateRangeFetch {
+			break
+		}
+		entries := pm.blockchain.TrieNodeDescendants(hash, downloader.MaxStateRangeFetch-len(data), softResponseLimit-bytes)
+		for _, entry := range entries {
+			data = append(data, entry)
+			bytes += len(entry)
+		}
+	}
+	return p.SendNodeData(data)
+}
+
 // handleNodeDataMsg handles node data response message.
 func handleNodeDataMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	// A batch of node state data arrived to one of our previous requests
@@ -838,6 +1028,62 @@ func handleReceiptsMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	return nil
 }
 
+// handleReceiptsByBlockRequestMsg handles receipts by block request message.
+// Unlike handleReceiptsRequestMsg, each block's receipts are paired with the
+// block hash so that unknown blocks can be skipped unambiguously.
+func handleReceiptsByBlockRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	// Decode the retrieval message
+	msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
+	if _, err := msgStream.List(); err != nil {
+		return err
+	}
+	var (
+		hash     common.Hash
+		bytes    int
+		hashes   []common.Hash
+		receipts []rlp.RawValue
+	)
+	for bytes < softResponseLimit && len(receipts) < downloader.MaxReceiptFetch {
+		if err := msgStream.Decode(&hash); err == rlp.EOL {
+			break
+		} else if err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		results := pm.blockchain.GetReceiptsByBlockHash(hash)
+		if results == nil {
+			if header := pm.blockchain.GetHeaderByHash(hash); header == nil || header.ReceiptHash != types.EmptyRootHash {
+				continue
+			}
+		}
+		if encoded, err := rlp.EncodeToBytes(results); err != nil {
+			logger.Error("Failed to encode receipt", "err", err)
+		} else {
+			hashes = append(hashes, hash)
+			receipts = append(receipts, encoded)
+			bytes += len(encoded)
+		}
+	}
+	return p.SendReceiptsByBlockRLP(hashes, receipts)
+}
+
+// handleReceiptsByBlockMsg handles receipts by block response message.
+func handleReceiptsByBlockMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var data blockReceiptsData
+	if err := msg.Decode(&data); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	event := ReceiptsByBlockEvent{
+		PeerID:   p.GetID(),
+		Hashes:   make([]common.Hash, len(data)),
+		Receipts: make([]types.Receipts, len(data)),
+	}
+	for i, block := range data {
+		event.Hashes[i], event.Receipts[i] = block.Hash, block.Receipts
+	}
+	pm.receiptsByBlockFeed.Send(event)
+	return nil
+}
+
 // handleNewBlockHashesMsg handles new block hashes message.
 func handleNewBlockHashesMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	var (
@@ -1055,6 +1301,38 @@ func (pm *ProtocolManager) samplePeersToSendBlock(block *types.Block) []Peer {
 	return sampledPeersWithoutBlock
 }
 
+// isCatchingUp returns whether a sync cycle is running and the head fetched
+// from the sync peer is ahead of the local head by more than the catch-up
+// gossip threshold. The head is the one the downloader has fetched and
+// validated, not the one claimed in the peer's handshake.
+func (pm *ProtocolManager) isCatchingUp() bool {
+	if pm.catchUpGossipThreshold == 0 || pm.syncStatus == nil || !pm.syncStatus.Synchronising() {
+		return false
+	}
+	progress := pm.syncStatus.Progress()
+	return progress.HighestBlock > progress.CurrentBlock &&
+		progress.HighestBlock-progress.CurrentBlock > pm.catchUpGossipThreshold
+}
+
+// relayBlock propagates a block received from a peer unless the node is
+// catching up, in which case the block is stale to the peers. Requests for it
+// are still served.
+func (pm *ProtocolManager) relayBlock(block *types.Block) {
+	if pm.isCatchingUp() {
+		return
+	}
+	pm.BroadcastBlock(block)
+}
+
+// relayBlockHash announces a block received from a peer unless the node is
+// catching up.
+func (pm *ProtocolManager) relayBlockHash(block *types.Block) {
+	if pm.isCatchingUp() {
+		return
+	}
+	pm.BroadcastBlockHash(block)
+}
+
 // BroadcastBlock will propagate a block to a subset of its peers.
 // If current node is CN, it will send block to all PN peers + sampled CN peers without block.
 // However, if there are more than 5 PN peers, it will sample 5 PN peers.
@@ -1091,9 +1369,22 @@ func (pm *ProtocolManager) BroadcastBlockHash(block *types.Block) {
 		"recipients", len(peersWithoutBlock), "duration", common.PrettyDuration(time.Since(block.ReceivedAt)))
 }
 
+// unconditionalTxs returns the given txs except the conditional ones. Conditional txs
+// are not propagated since their conditions are not encoded.
+func unconditionalTxs(txs types.Transactions) types.Transactions {
+	filtered := make(types.Transactions, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Conditions() == nil {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
+
 // BroadcastTxs will propagate a batch of transactions to all peers which are not known to
 // already have the given transaction.
 func (pm *ProtocolManager) BroadcastTxs(txs types.Transactions) {
+	txs = unconditionalTxs(txs)
 	// Broadcast transactions to a batch of peers not knowing about it
 	switch pm.nodetype {
 	case node.CONSENSUSNODE:
@@ -1105,11 +1396,33 @@ func (pm *ProtocolManager) BroadcastTxs(txs types.Transactions) {
 
 func (pm *ProtocolManager) ReBroadcastTxs(txs types.Transactions) {
 	if pm.nodetype != node.CONSENSUSNODE {
-		pm.broadcastNoCNTx(txs, true)
+		pm.broadcastNoCNTx(unconditionalTxs(txs), true)
+	}
+}
+
+// newTxBroadcastTargets returns the set of the given node types. It returns nil,
+// meaning all types, if no type is given.
+func newTxBroadcastTargets(nodetypes []p2p.ConnType) map[p2p.ConnType]bool {
+	if len(nodetypes) == 0 {
+		return nil
 	}
+	targets := make(map[p2p.ConnType]bool, len(nodetypes))
+	for _, nodetype := range nodetypes {
+		targets[nodetype] = true
+	}
+	return targets
+}
+
+// broadcastsTxTo returns whether transactions are broadcast to the peers of
+// the given node type.
+func (pm *ProtocolManager) broadcastsTxTo(nodetype p2p.ConnType) bool {
+	return pm.txBroadcastTargets == nil || pm.txBroadcastTargets[nodetype]
 }
 
 func (pm *ProtocolManager) broadcastCNTx(txs types.Transactions) {
+	if !pm.broadcastsTxTo(node.CONSENSUSNODE) {
+		return
+	}
 	var txset = make(map[Peer]types.Transactions)
 	for _, tx := range txs {
 		peers := pm.peers.CNWithoutTx(tx.Hash())
@@ -1141,14 +1454,18 @@ func (pm *ProtocolManager) sampleResendPeersByType(nodetype p2p.ConnType) []Peer
 	var peers []Peer
 	switch nodetype {
 	case node.ENDPOINTNODE:
-		peers = pm.peers.TypePeers(node.PROXYNODE)
-		if len(peers) == 0 {
+		if pm.broadcastsTxTo(node.PROXYNODE) {
+			peers = pm.peers.TypePeers(node.PROXYNODE)
+		}
+		if len(peers) == 0 && pm.broadcastsTxTo(node.ENDPOINTNODE) {
 			peers = pm.peers.TypePeers(node.ENDPOINTNODE)
 		}
 		peers = samplingPeers(peers, 2)
 	case node.PROXYNODE:
-		peers = pm.peers.TypePeers(node.CONSENSUSNODE)
-		if len(peers) == 0 {
+		if pm.broadcastsTxTo(node.CONSENSUSNODE) {
+			peers = pm.peers.TypePeers(node.CONSENSUSNODE)
+		}
+		if len(peers) == 0 && pm.broadcastsTxTo(node.PROXYNODE) {
 			peers = pm.peers.TypePeers(node.PROXYNODE)
 		}
 		peers = samplingPeers(peers, 2)
@@ -1171,7 +1488,10 @@ func (pm *ProtocolManager) broadcastNoCNTx(txs types.Transactions, resend bool)
 			}
 			txResendCounter.Inc(1)
 		} else {
-			peers := pm.peers.CNWithoutTx(tx.Hash())
+			var peers []Peer
+			if pm.broadcastsTxTo(node.CONSENSUSNODE) {
+				peers = pm.peers.CNWithoutTx(tx.Hash())
+			}
 			if len(peers) > 0 {
 				// TODO-Klaytn optimize pickSize or propagation way
 				peers = samplingPeers(peers, 2)
@@ -1180,13 +1500,17 @@ func (pm *ProtocolManager) broadcastNoCNTx(txs types.Transactions, resend bool)
 				}
 				logger.Trace("Broadcast transaction", "hash", tx.Hash(), "recipients", len(peers))
 			}
-			if pm.nodetype == node.ENDPOINTNODE {
+			if pm.nodetype == node.ENDPOINTNODE && pm.broadcastsTxTo(node.PROXYNODE) {
 				peers = pm.peers.TypePeersWithoutTx(tx.Hash(), node.PROXYNODE)
 				for _, peer := range peers {
 					txset[peer] = append(txset[peer], tx)
 				}
 			}
-			peers = pm.peers.TypePeersWithoutTx(tx.Hash(), pm.nodetype)
+			peers = nil
+			if pm.broadcastsTxTo(pm.nodetype) {
+				// Gossip to a sample of the peers of the same type, the rest will receive the tx from them.
+				peers = pm.peers.SampleRandomPeers(pm.peers.TypePeersWithoutTx(tx.Hash(), pm.nodetype), true)
+			}
 
 			for _, peer := range peers {
 				txset[peer] = append(txset[peer], tx)
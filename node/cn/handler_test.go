@@ -0,0 +1,731 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"fmt"
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNextPrioritizedMsg(t *testing.T) {
+	highCh := make(chan p2p.Msg, 4)
+	lowCh := make(chan p2p.Msg, 4)
+
+	// Bulk sync responses are queued before the block messages.
+	lowCh <- p2p.Msg{Code: NodeDataMsg}
+	lowCh <- p2p.Msg{Code: ReceiptsMsg}
+	highCh <- p2p.Msg{Code: NewBlockMsg}
+	highCh <- p2p.Msg{Code: BlockHeadersMsg}
+
+	expected := []uint64{NewBlockMsg, BlockHeadersMsg, NodeDataMsg, ReceiptsMsg}
+	for i, code := range expected {
+		msg, ok := nextPrioritizedMsg(highCh, lowCh)
+		if !ok {
+			t.Fatalf("message %d: channel closed unexpectedly", i)
+		}
+		if msg.Code != code {
+			t.Errorf("message %d: code mismatch: have %d, want %d", i, msg.Code, code)
+		}
+	}
+
+	close(highCh)
+	if _, ok := nextPrioritizedMsg(highCh, lowCh); ok {
+		t.Error("closed channel should stop the processing")
+	}
+}
+
+func TestHighPriorityChannels(t *testing.T) {
+	chMgr := NewChannelManager(1)
+	high := make(chan p2p.Msg)
+	low := make(chan p2p.Msg)
+	chMgr.RegisterPrioritizedChannelsWithIndex(0, high, low)
+
+	for _, code := range []uint64{NewBlockMsg, BlockHeadersMsg} {
+		if ch, _ := chMgr.GetChannelWithMsgCode(0, code); ch != high {
+			t.Errorf("msg code %d should be handled with high priority", code)
+		}
+	}
+	for _, code := range []uint64{NodeDataMsg, ReceiptsMsg, TxMsg} {
+		if ch, _ := chMgr.GetChannelWithMsgCode(0, code); ch != low {
+			t.Errorf("msg code %d should be handled with normal priority", code)
+		}
+	}
+}
+
+// baselineStatusData is the status message of the peers which do not know fork
+// identifiers. They reject status messages with extra fields.
+type baselineStatusData struct {
+	ProtocolVersion uint32
+	NetworkId       uint64
+	TD              *big.Int
+	CurrentBlock    common.Hash
+	GenesisBlock    common.Hash
+	ChainID         *big.Int
+}
+
+// handshakeForkID runs a handshake between a local peer of the given version and
+// a remote peer over a message pipe and returns the local peer. The remote peer
+// sends the given status message as is, and decodes the local status message
+// into received.
+func handshakeForkID(t *testing.T, version int, local params.ForkID, remote, received interface{}) Peer {
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		if err := p2p.Send(net, StatusMsg, remote); err != nil {
+			errc <- err
+			return
+		}
+		msg, err := net.ReadMsg()
+		if err == nil {
+			err = msg.Decode(received)
+		}
+		errc <- err
+	}()
+
+	p := newPeer(version, p2p.NewPeer(discover.NodeID{1}, "remote", nil), app, defaultPeerQueueSizes)
+	if err := p.Handshake(1, big.NewInt(1), big.NewInt(1), common.Hash{}, common.Hash{}, local, handshakeTimeout); err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("remote handshake failed: %v", err)
+	}
+	return p
+}
+
+func TestPeerQueueSizes(t *testing.T) {
+	pm := &ProtocolManager{peerQueueSizes: newPeerQueueSizes(&Config{PeerQueueTxs: 1024, PeerQueueProps: 8})}
+	_, rw1 := p2p.MsgPipe()
+	_, rw2 := p2p.MsgPipe()
+
+	single := pm.newPeer(klay63, p2p.NewPeer(discover.NodeID{1}, "single", nil), rw1)
+	multi, err := pm.newPeerWithRWs(klay63, p2p.NewPeer(discover.NodeID{2}, "multi", nil), []p2p.MsgReadWriter{rw1, rw2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []*basePeer{single.(*singleChannelPeer).basePeer, multi.(*multiChannelPeer).basePeer} {
+		// The configured sizes are used, and the default size for the unset one.
+		if have := cap(base.queuedTxs); have != 1024 {
+			t.Errorf("tx queue size mismatch: have %d, want %d", have, 1024)
+		}
+		if have := cap(base.queuedProps); have != 8 {
+			t.Errorf("propagation queue size mismatch: have %d, want %d", have, 8)
+		}
+		if have := cap(base.queuedAnns); have != maxQueuedAnns {
+			t.Errorf("announcement queue size mismatch: have %d, want %d", have, maxQueuedAnns)
+		}
+	}
+}
+
+func TestResizeKnownCaches(t *testing.T) {
+	pm := &ProtocolManager{peerQueueSizes: newPeerQueueSizes(&Config{PeerKnownTxs: 2, PeerKnownBlocks: 2})}
+	_, rw := p2p.MsgPipe()
+	p := pm.newPeer(klay63, p2p.NewPeer(discover.NodeID{1}, "peer", nil), rw)
+
+	hashes := make([]common.Hash, 8)
+	for i := range hashes {
+		hashes[i] = common.Hash{byte(i + 1)}
+	}
+	// The configured sizes are used.
+	for _, hash := range hashes[:3] {
+		p.AddToKnownTxs(hash)
+		p.AddToKnownBlocks(hash)
+	}
+	if p.KnowsTx(hashes[0]) || p.KnowsBlock(hashes[0]) {
+		t.Error("the oldest hash should have been evicted")
+	}
+	if !p.KnowsTx(hashes[2]) || !p.KnowsBlock(hashes[2]) {
+		t.Error("the newest hash should be known")
+	}
+
+	// The caches are swapped without migrating the known hashes.
+	p.ResizeKnownCaches(4, 1)
+	if p.KnowsTx(hashes[2]) || p.KnowsBlock(hashes[2]) {
+		t.Error("hashes known before the resize should be forgotten")
+	}
+	for _, hash := range hashes[3:] {
+		p.AddToKnownTxs(hash)
+		p.AddToKnownBlocks(hash)
+	}
+	for i, hash := range hashes[3:] {
+		if known, want := p.KnowsTx(hash), i >= 1; known != want {
+			t.Errorf("tx %d known mismatch after resize: have %v, want %v", i+3, known, want)
+		}
+		if known, want := p.KnowsBlock(hash), i == 4; known != want {
+			t.Errorf("block %d known mismatch after resize: have %v, want %v", i+3, known, want)
+		}
+	}
+}
+
+func TestStaleForkPeerEviction(t *testing.T) {
+	var (
+		config      = &params.ChainConfig{GasPriceFloorBlock: big.NewInt(10)}
+		staleConfig = &params.ChainConfig{}
+		head        = big.NewInt(20)
+		pm          = &ProtocolManager{chainconfig: config, evictStaleForkPeers: true}
+	)
+	status := func(forkID ...params.ForkID) *statusData {
+		return &statusData{
+			ProtocolVersion: klay65,
+			NetworkId:       1,
+			TD:              big.NewInt(1),
+			ChainID:         big.NewInt(1),
+			ForkID:          forkID,
+		}
+	}
+
+	// A peer which has not crossed the fork at block 10 is rejected.
+	stale := handshakeForkID(t, klay65, config.ForkID(head), status(staleConfig.ForkID(head)), new(statusData))
+	if err, want := pm.checkForkID(stale, head), errResp(ErrStaleFork, "fork at block 10 not crossed"); err == nil || err.Error() != want.Error() {
+		t.Errorf("stale fork peer error mismatch: have %v, want %v", err, want)
+	}
+
+	// A syncing peer which has scheduled the fork is accepted.
+	syncing := handshakeForkID(t, klay65, config.ForkID(head), status(config.ForkID(big.NewInt(5))), new(statusData))
+	if err := pm.checkForkID(syncing, head); err != nil {
+		t.Errorf("syncing peer should be accepted: %v", err)
+	}
+
+	// A peer which does not send a fork identifier is accepted.
+	legacy := handshakeForkID(t, klay65, config.ForkID(head), status(), new(statusData))
+	if legacy.GetForkID() != nil {
+		t.Errorf("fork identifier should be nil, have %v", legacy.GetForkID())
+	}
+	if err := pm.checkForkID(legacy, head); err != nil {
+		t.Errorf("peer without a fork identifier should be accepted: %v", err)
+	}
+
+	// Stale fork peers are kept if the eviction is disabled.
+	pm.evictStaleForkPeers = false
+	if err := pm.checkForkID(stale, head); err != nil {
+		t.Errorf("stale fork peer should be accepted if eviction is disabled: %v", err)
+	}
+}
+
+// Tests that the fork identifier is sent only to klay65 or later peers, so that
+// peers which do not know fork identifiers complete the handshake.
+func TestHandshakeForkIDVersion(t *testing.T) {
+	var (
+		config = &params.ChainConfig{GasPriceFloorBlock: big.NewInt(10)}
+		forkID = config.ForkID(big.NewInt(20))
+	)
+
+	// A baseline peer decodes the status message strictly.
+	baseline := &baselineStatusData{klay63, 1, big.NewInt(1), common.Hash{}, common.Hash{}, big.NewInt(1)}
+	if p := handshakeForkID(t, klay63, forkID, baseline, new(baselineStatusData)); p.GetForkID() != nil {
+		t.Errorf("fork identifier of a baseline peer should be nil, have %v", p.GetForkID())
+	}
+
+	// A klay65 peer receives the fork identifier.
+	received := new(statusData)
+	remote := &statusData{ProtocolVersion: klay65, NetworkId: 1, TD: big.NewInt(1), ChainID: big.NewInt(1), ForkID: []params.ForkID{forkID}}
+	handshakeForkID(t, klay65, forkID, remote, received)
+	if len(received.ForkID) != 1 || !reflect.DeepEqual(received.ForkID[0], forkID) {
+		t.Errorf("fork identifier mismatch: have %v, want %v", received.ForkID, forkID)
+	}
+}
+
+// txRecordingPeer is a Peer recording the transactions sent to it.
+type txRecordingPeer struct {
+	Peer
+	id       string
+	connType p2p.ConnType
+	sent     types.Transactions
+}
+
+func (p *txRecordingPeer) GetID() string                 { return p.id }
+func (p *txRecordingPeer) GetAddr() common.Address       { return common.BytesToAddress([]byte(p.id)) }
+func (p *txRecordingPeer) ConnType() p2p.ConnType        { return p.connType }
+func (p *txRecordingPeer) KnowsTx(hash common.Hash) bool { return false }
+func (p *txRecordingPeer) Broadcast()                    {}
+func (p *txRecordingPeer) AsyncSendTransactions(txs []*types.Transaction) {
+	p.sent = append(p.sent, txs...)
+}
+func (p *txRecordingPeer) SendTransactions(txs types.Transactions) error {
+	p.sent = append(p.sent, txs...)
+	return nil
+}
+func (p *txRecordingPeer) ReSendTransactions(txs types.Transactions) error {
+	p.sent = append(p.sent, txs...)
+	return nil
+}
+
+func TestTxBroadcastTargets(t *testing.T) {
+	newPM := func(targets ...p2p.ConnType) (*ProtocolManager, map[p2p.ConnType]*txRecordingPeer) {
+		pm := &ProtocolManager{
+			peers:              newPeerSet(),
+			nodetype:           node.ENDPOINTNODE,
+			txBroadcastTargets: newTxBroadcastTargets(targets),
+		}
+		peers := make(map[p2p.ConnType]*txRecordingPeer)
+		for _, connType := range []p2p.ConnType{node.CONSENSUSNODE, node.PROXYNODE, node.ENDPOINTNODE} {
+			peer := &txRecordingPeer{id: fmt.Sprintf("peer-%d", connType), connType: connType}
+			if err := pm.peers.Register(peer); err != nil {
+				t.Fatal(err)
+			}
+			peers[connType] = peer
+		}
+		return pm, peers
+	}
+	txs := types.Transactions{types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)}
+
+	// All node types receive transactions by default.
+	pm, peers := newPM()
+	pm.BroadcastTxs(txs)
+	for connType, peer := range peers {
+		if len(peer.sent) != 1 {
+			t.Errorf("peer of type %d received %d txs, want 1", connType, len(peer.sent))
+		}
+	}
+
+	// Only the configured node types receive transactions.
+	pm, peers = newPM(node.PROXYNODE, node.ENDPOINTNODE)
+	pm.BroadcastTxs(txs)
+	want := map[p2p.ConnType]int{node.CONSENSUSNODE: 0, node.PROXYNODE: 1, node.ENDPOINTNODE: 1}
+	for connType, peer := range peers {
+		if len(peer.sent) != want[connType] {
+			t.Errorf("peer of type %d received %d txs, want %d", connType, len(peer.sent), want[connType])
+		}
+	}
+
+	// Resending falls back to the configured node types.
+	pm, peers = newPM(node.ENDPOINTNODE)
+	pm.ReBroadcastTxs(txs)
+	want = map[p2p.ConnType]int{node.CONSENSUSNODE: 0, node.PROXYNODE: 0, node.ENDPOINTNODE: 1}
+	for connType, peer := range peers {
+		if len(peer.sent) != want[connType] {
+			t.Errorf("resend: peer of type %d received %d txs, want %d", connType, len(peer.sent), want[connType])
+		}
+	}
+}
+
+// headPeer is a Peer with a fixed head.
+type headPeer struct {
+	Peer
+	id       string
+	connType p2p.ConnType
+	td       *big.Int
+}
+
+func (p *headPeer) GetID() string                 { return p.id }
+func (p *headPeer) GetAddr() common.Address       { return common.BytesToAddress([]byte(p.id)) }
+func (p *headPeer) ConnType() p2p.ConnType        { return p.connType }
+func (p *headPeer) Head() (common.Hash, *big.Int) { return common.Hash{}, p.td }
+func (p *headPeer) Broadcast()                    {}
+func (p *headPeer) Close()                        {}
+
+func TestBestPeerByType(t *testing.T) {
+	ps := newPeerSet()
+	peers := []*headPeer{
+		{id: "cn-1", connType: node.CONSENSUSNODE, td: big.NewInt(30)},
+		{id: "cn-2", connType: node.CONSENSUSNODE, td: big.NewInt(50)},
+		{id: "pn-1", connType: node.PROXYNODE, td: big.NewInt(40)},
+		{id: "en-1", connType: node.ENDPOINTNODE, td: big.NewInt(10)},
+		{id: "en-2", connType: node.ENDPOINTNODE, td: big.NewInt(20)},
+		{id: "en-3", connType: node.ENDPOINTNODE, td: big.NewInt(15)},
+	}
+	for _, p := range peers {
+		if err := ps.Register(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[p2p.ConnType]string{
+		node.CONSENSUSNODE: "cn-2",
+		node.PROXYNODE:     "pn-1",
+		node.ENDPOINTNODE:  "en-2",
+	}
+	for connType, id := range want {
+		best := ps.BestPeerByType(connType)
+		if best == nil || best.GetID() != id {
+			t.Errorf("best peer of type %d mismatch: have %v, want %s", connType, best, id)
+		}
+	}
+	if best := ps.BestPeer(); best.GetID() != "cn-2" {
+		t.Errorf("best peer mismatch: have %s, want cn-2", best.GetID())
+	}
+
+	// There is no peer of an unknown type or of a type without peers.
+	if best := ps.BestPeerByType(node.UNKNOWNNODE); best != nil {
+		t.Errorf("best peer of an unknown type should be nil, have %s", best.GetID())
+	}
+	ps.Unregister("pn-1")
+	if best := ps.BestPeerByType(node.PROXYNODE); best != nil {
+		t.Errorf("best peer of a type without peers should be nil, have %s", best.GetID())
+	}
+}
+
+func TestSampleRandomPeers(t *testing.T) {
+	ps := newPeerSet()
+	ps.sampler = rand.New(rand.NewSource(1))
+
+	for _, n := range []int{0, 1, 2, 4, 10, 50} {
+		candidates := make([]Peer, n)
+		for i := range candidates {
+			candidates[i] = &headPeer{id: fmt.Sprintf("peer-%d", i), connType: node.ENDPOINTNODE}
+		}
+		original := make([]Peer, n)
+		copy(original, candidates)
+
+		// Without sqrt fan-out, all candidates are returned.
+		if all := ps.SampleRandomPeers(candidates, false); len(all) != n {
+			t.Errorf("%d candidates: sampled %d peers without sqrt fan-out, want %d", n, len(all), n)
+		}
+
+		sampled := ps.SampleRandomPeers(candidates, true)
+		want := int(math.Sqrt(float64(n)))
+		if n > 0 && want == 0 {
+			want = 1
+		}
+		if len(sampled) != want {
+			t.Errorf("%d candidates: sampled %d peers, want %d", n, len(sampled), want)
+		}
+
+		// The sample is a subset of the candidates without duplicates.
+		members := make(map[Peer]bool)
+		for _, p := range candidates {
+			members[p] = true
+		}
+		seen := make(map[Peer]bool)
+		for _, p := range sampled {
+			if !members[p] {
+				t.Errorf("%d candidates: sampled peer %s is not a candidate", n, p.GetID())
+			}
+			if seen[p] {
+				t.Errorf("%d candidates: peer %s sampled twice", n, p.GetID())
+			}
+			seen[p] = true
+		}
+		if !reflect.DeepEqual(original, candidates) {
+			t.Errorf("%d candidates: candidates were modified", n)
+		}
+	}
+}
+
+// panickingMsgWriter is a p2p.MsgReadWriter panicking on the first panics writes.
+type panickingMsgWriter struct {
+	p2p.MsgReadWriter
+	panics int
+}
+
+func (rw *panickingMsgWriter) WriteMsg(msg p2p.Msg) error {
+	if rw.panics > 0 {
+		rw.panics--
+		panic("injected broadcast panic")
+	}
+	return rw.MsgReadWriter.WriteMsg(msg)
+}
+
+func TestBroadcastLoopRestart(t *testing.T) {
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	rw := &panickingMsgWriter{MsgReadWriter: app, panics: 1}
+	p := newPeer(klay63, p2p.NewPeer(discover.NodeID{1}, "remote", nil), rw, defaultPeerQueueSizes)
+	dropped := make(chan string, 1)
+	ps := newPeerSet()
+	ps.broadcastRestarts, ps.dropPeer = 1, func(id string) { dropped <- id }
+	if err := ps.Register(p); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	key, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	first, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil), signer, key)
+	tx, _ := types.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil), signer, key)
+
+	// The first broadcast panics, the loop restarts and delivers the second one.
+	p.AsyncSendTransactions(types.Transactions{first})
+	p.AsyncSendTransactions(types.Transactions{tx})
+	msg, err := net.ReadMsg()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txs types.Transactions
+	if err := msg.Decode(&txs); err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+		t.Fatalf("delivered transactions mismatch: have %v, want %x", txs, tx.Hash())
+	}
+	select {
+	case id := <-dropped:
+		t.Fatalf("peer %s dropped after a single panic", id)
+	default:
+	}
+
+	// Another panic exceeds the restart limit and drops the peer.
+	rw.panics = 1
+	p.AsyncSendTransactions(types.Transactions{tx})
+	select {
+	case id := <-dropped:
+		if id != p.GetID() {
+			t.Errorf("dropped peer mismatch: have %s, want %s", id, p.GetID())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("peer not dropped")
+	}
+}
+
+func TestReceiptsByBlockRoundTrip(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		db     = database.NewMemoryDBManager()
+		gspec  = &blockchain.Genesis{Config: params.TestChainConfig, Alloc: blockchain.GenesisAlloc{
+			from: {Balance: big.NewInt(params.KLAY)},
+		}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, big.NewInt(1))
+	)
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 3, func(i int, gen *blockchain.BlockGen) {
+		for j := 0; j < i; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(from), common.Address{0x01}, big.NewInt(1), 21000, nil, nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	// Connect a requesting and a serving peer through an in-memory pipe.
+	server, client := &ProtocolManager{blockchain: chain}, &ProtocolManager{}
+	serverRW, clientRW := p2p.MsgPipe()
+	defer serverRW.Close()
+	serverPeer := newPeer(klay66, p2p.NewPeer(discover.NodeID{1}, "client", nil), serverRW, defaultPeerQueueSizes)
+	clientPeer := newPeer(klay66, p2p.NewPeer(discover.NodeID{2}, "server", nil), clientRW, defaultPeerQueueSizes)
+
+	ch := make(chan ReceiptsByBlockEvent, 1)
+	sub := client.SubscribeReceiptsByBlock(ch)
+	defer sub.Unsubscribe()
+
+	// Blocks unknown to the serving peer are skipped in the response.
+	hashes := []common.Hash{blocks[0].Hash(), {0xff}, blocks[1].Hash(), blocks[2].Hash()}
+	errc := make(chan error, 1)
+	go func() {
+		msg, err := serverRW.ReadMsg()
+		if err != nil {
+			errc <- err
+			return
+		}
+		errc <- server.handleMsg(serverPeer, common.Address{}, msg)
+	}()
+	go func() {
+		if err := clientPeer.RequestReceiptsByBlock(hashes); err != nil {
+			errc <- err
+		}
+	}()
+	msg, err := clientRW.ReadMsg()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.handleMsg(clientPeer, common.Address{}, msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	event := <-ch
+	if event.PeerID != clientPeer.GetID() {
+		t.Errorf("peer mismatch: have %s, want %s", event.PeerID, clientPeer.GetID())
+	}
+	if len(event.Hashes) != len(blocks) || len(event.Receipts) != len(blocks) {
+		t.Fatalf("delivered blocks mismatch: have %d hashes and %d receipts, want %d", len(event.Hashes), len(event.Receipts), len(blocks))
+	}
+	for i, block := range blocks {
+		if event.Hashes[i] != block.Hash() {
+			t.Errorf("block %d: hash mismatch: have %x, want %x", i, event.Hashes[i], block.Hash())
+		}
+		if len(event.Receipts[i]) != i {
+			t.Errorf("block %d: receipt count mismatch: have %d, want %d", i, len(event.Receipts[i]), i)
+		}
+		if root := types.DeriveSha(event.Receipts[i]); root != block.ReceiptHash() {
+			t.Errorf("block %d: receipt root mismatch: have %x, want %x", i, root, block.ReceiptHash())
+		}
+	}
+}
+
+func TestRequestReceiptsByBlockUnsupported(t *testing.T) {
+	_, rw := p2p.MsgPipe()
+	defer rw.Close()
+
+	p := newPeer(klay65, p2p.NewPeer(discover.NodeID{1}, "old", nil), rw, defaultPeerQueueSizes)
+	if err := p.RequestReceiptsByBlock([]common.Hash{{0x01}}); err != errNotSupported {
+		t.Fatalf("error mismatch: have %v, want %v", err, errNotSupported)
+	}
+}
+
+func TestHandshakeTimeout(t *testing.T) {
+	pm := &ProtocolManager{handshakeTimeouts: newHandshakeTimeouts(&Config{ENHandshakeTimeout: 50 * time.Millisecond})}
+	if have := pm.handshakeTimeout(node.CONSENSUSNODE); have != handshakeTimeout {
+		t.Errorf("default timeout mismatch: have %v, want %v", have, handshakeTimeout)
+	}
+	timeout := pm.handshakeTimeout(node.ENDPOINTNODE)
+	if timeout != 50*time.Millisecond {
+		t.Fatalf("configured timeout mismatch: have %v, want %v", timeout, 50*time.Millisecond)
+	}
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	// The remote peer delays its status message past the timeout.
+	status := &statusData{ProtocolVersion: klay63, NetworkId: 1, TD: big.NewInt(1), ChainID: big.NewInt(1)}
+	go func() {
+		if msg, err := net.ReadMsg(); err == nil {
+			msg.Discard()
+		}
+		time.Sleep(4 * timeout)
+		p2p.Send(net, StatusMsg, status)
+	}()
+	p := newPeer(klay63, p2p.NewPeer(discover.NodeID{1}, "remote", nil), app, defaultPeerQueueSizes)
+	if err := p.Handshake(1, big.NewInt(1), big.NewInt(1), common.Hash{}, common.Hash{}, params.ForkID{}, timeout); err != p2p.DiscReadTimeout {
+		t.Fatalf("handshake error mismatch: have %v, want %v", err, p2p.DiscReadTimeout)
+	}
+
+	// A status message read after the deadline is not accepted either.
+	app2, net2 := p2p.MsgPipe()
+	defer app2.Close()
+	defer net2.Close()
+	go p2p.Send(net2, StatusMsg, status)
+	p2 := newPeer(klay63, p2p.NewPeer(discover.NodeID{2}, "remote", nil), app2, defaultPeerQueueSizes)
+	err := p2.(*singleChannelPeer).readStatus(1, new(statusData), common.Hash{}, big.NewInt(1), time.Now().Add(-time.Second))
+	if err != p2p.DiscReadTimeout {
+		t.Fatalf("read status error mismatch: have %v, want %v", err, p2p.DiscReadTimeout)
+	}
+}
+
+// blockRecordingPeer is a Peer with a fixed head recording the announced blocks.
+type blockRecordingPeer struct {
+	Peer
+	id             string
+	td             *big.Int
+	blocks, hashes int
+}
+
+func (p *blockRecordingPeer) GetID() string                                     { return p.id }
+func (p *blockRecordingPeer) GetAddr() common.Address                           { return common.BytesToAddress([]byte(p.id)) }
+func (p *blockRecordingPeer) ConnType() p2p.ConnType                            { return node.ENDPOINTNODE }
+func (p *blockRecordingPeer) Head() (common.Hash, *big.Int)                     { return common.Hash{}, p.td }
+func (p *blockRecordingPeer) Broadcast()                                        {}
+func (p *blockRecordingPeer) Close()                                            {}
+func (p *blockRecordingPeer) KnowsBlock(hash common.Hash) bool                  { return false }
+func (p *blockRecordingPeer) AsyncSendNewBlock(block *types.Block, td *big.Int) { p.blocks++ }
+func (p *blockRecordingPeer) AsyncSendNewBlockHash(block *types.Block)          { p.hashes++ }
+
+// fakeSyncStatus is a syncStatus with a fixed sync state.
+type fakeSyncStatus struct {
+	syncing  bool
+	progress klaytn.SyncProgress
+}
+
+func (s *fakeSyncStatus) Synchronising() bool           { return s.syncing }
+func (s *fakeSyncStatus) Progress() klaytn.SyncProgress { return s.progress }
+
+func TestCatchUpGossipSuppression(t *testing.T) {
+	var (
+		db      = database.NewMemoryDBManager()
+		gspec   = &blockchain.Genesis{Config: params.TestChainConfig}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 2, nil)
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	head := chain.CurrentBlock()
+
+	// The peer claims a far higher blockscore, which alone must not suppress relays.
+	status := &fakeSyncStatus{progress: klaytn.SyncProgress{CurrentBlock: head.NumberU64()}}
+	pm := &ProtocolManager{blockchain: chain, peers: newPeerSet(), nodetype: node.ENDPOINTNODE, syncStatus: status, catchUpGossipThreshold: 10}
+	peer := &blockRecordingPeer{id: "en-1", td: big.NewInt(1000000)}
+	if err := pm.peers.Register(peer); err != nil {
+		t.Fatal(err)
+	}
+	relay := func(wantBlocks, wantHashes int) {
+		t.Helper()
+		pm.relayBlock(head)
+		pm.relayBlockHash(head)
+		if peer.blocks != wantBlocks || peer.hashes != wantHashes {
+			t.Errorf("announced %d blocks and %d hashes, want %d and %d", peer.blocks, peer.hashes, wantBlocks, wantHashes)
+		}
+	}
+	if pm.isCatchingUp() {
+		t.Fatal("node should not be catching up without a sync cycle")
+	}
+	relay(1, 1)
+
+	// Relays are suppressed while the fetched sync target is ahead by more than the threshold.
+	status.syncing = true
+	status.progress.HighestBlock = head.NumberU64() + 11
+	if !pm.isCatchingUp() {
+		t.Fatal("node should be catching up")
+	}
+	relay(1, 1)
+
+	// Mined blocks are announced even while catching up.
+	pm.BroadcastBlock(head)
+	pm.BroadcastBlockHash(head)
+	if peer.blocks != 2 || peer.hashes != 2 {
+		t.Errorf("mined block not announced while catching up: %d blocks, %d hashes", peer.blocks, peer.hashes)
+	}
+
+	// Relays resume once the lag is within the threshold.
+	status.progress.HighestBlock = head.NumberU64() + 10
+	if pm.isCatchingUp() {
+		t.Fatal("node should not be catching up")
+	}
+	relay(3, 3)
+
+	// A zero threshold never suppresses relays.
+	pm.catchUpGossipThreshold = 0
+	status.progress.HighestBlock = head.NumberU64() + 1000
+	if pm.isCatchingUp() {
+		t.Error("node should not be catching up without a threshold")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"time"
+)
+
+// handshakeQueueTimeout is the maximum time a peer waits for a handshake slot
+// before it is disconnected.
+const handshakeQueueTimeout = 10 * time.Second
+
+// handshakeLimiter bounds the number of concurrent Klaytn protocol handshakes so
+// that a burst of incoming connections does not spike CPU and goroutine usage.
+// Excess handshakes are queued until a slot is released or the queue timeout
+// expires. A nil handshakeLimiter does not limit handshakes.
+type handshakeLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// newHandshakeLimiter returns a handshakeLimiter allowing at most max concurrent
+// handshakes. It returns nil if max is not positive.
+func newHandshakeLimiter(max int, timeout time.Duration) *handshakeLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &handshakeLimiter{slots: make(chan struct{}, max), timeout: timeout}
+}
+
+// acquire waits for a handshake slot. It returns false if no slot became
+// available within the queue timeout or quit was closed.
+func (l *handshakeLimiter) acquire(quit <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-quit:
+		return false
+	}
+}
+
+// release returns a handshake slot taken by acquire.
+func (l *handshakeLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Tests that simultaneous handshakes never exceed the limit and that queued
+// handshakes are not dropped.
+func TestHandshakeLimiterConcurrency(t *testing.T) {
+	var (
+		limit     = 4
+		attempts  = 64
+		limiter   = newHandshakeLimiter(limit, 5*time.Second)
+		quit      = make(chan struct{})
+		running   int32
+		maxSeen   int32
+		succeeded int32
+		wg        sync.WaitGroup
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !limiter.acquire(quit) {
+				return
+			}
+			defer limiter.release()
+
+			n := atomic.AddInt32(&running, 1)
+			for {
+				seen := atomic.LoadInt32(&maxSeen)
+				if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&succeeded, 1)
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen > int32(limit) {
+		t.Errorf("concurrent handshakes exceeded the limit: have %d, want at most %d", maxSeen, limit)
+	}
+	if succeeded != int32(attempts) {
+		t.Errorf("handshakes dropped: have %d succeeded, want %d", succeeded, attempts)
+	}
+}
+
+func TestHandshakeLimiterTimeout(t *testing.T) {
+	limiter := newHandshakeLimiter(1, 10*time.Millisecond)
+	quit := make(chan struct{})
+	if !limiter.acquire(quit) {
+		t.Fatal("failed to acquire a free slot")
+	}
+	if limiter.acquire(quit) {
+		t.Fatal("acquired a slot beyond the limit")
+	}
+	limiter.release()
+	if !limiter.acquire(quit) {
+		t.Fatal("failed to acquire a released slot")
+	}
+
+	// Queued handshakes give up when the protocol manager stops.
+	limiter.timeout = time.Minute
+	close(quit)
+	if limiter.acquire(quit) {
+		t.Fatal("acquired a slot after quit")
+	}
+}
+
+func TestHandshakeLimiterDisabled(t *testing.T) {
+	limiter := newHandshakeLimiter(0, time.Second)
+	if limiter != nil {
+		t.Fatal("non-positive limit should disable the limiter")
+	}
+	for i := 0; i < 10; i++ {
+		if !limiter.acquire(nil) {
+			t.Fatal("disabled limiter should not block handshakes")
+		}
+	}
+	limiter.release()
+}
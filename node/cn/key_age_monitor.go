@@ -0,0 +1,155 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/metrics"
+	"github.com/klaytn/klaytn/storage/database"
+	"sync"
+	"time"
+)
+
+// keyAgeCheckInterval is the interval at which KeyAgeMonitor updates the key ages.
+const keyAgeCheckInterval = time.Minute
+
+const (
+	nodeKeyName    = "nodekey"
+	rewardbaseName = "rewardbase"
+)
+
+var (
+	nodeKeyAgeGauge    = metrics.NewRegisteredGauge("klay/security/keyage/nodekey", nil)
+	rewardbaseAgeGauge = metrics.NewRegisteredGauge("klay/security/keyage/rewardbase", nil)
+)
+
+// trackedKey is a key whose age is monitored by KeyAgeMonitor.
+type trackedKey struct {
+	addr   common.Address
+	since  time.Time     // when the key is first used by this node
+	gauge  metrics.Gauge // age of the key in seconds
+	warned bool          // true if the rotation warning has been logged
+}
+
+// KeyAgeMonitor keeps track of how long the node key and the rewardbase have
+// been in use. The ages are exposed as metrics and a warning is logged once a
+// key has been in use longer than the rotation age. When a key is first seen is
+// stored in the database, so that the ages survive restarts.
+type KeyAgeMonitor struct {
+	db          database.DBManager
+	rotationAge time.Duration // 0 disables the rotation warning
+	now         func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*trackedKey
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewKeyAgeMonitor returns a KeyAgeMonitor warning about the keys which have
+// been in use longer than rotationAge.
+func NewKeyAgeMonitor(db database.DBManager, rotationAge time.Duration) *KeyAgeMonitor {
+	return &KeyAgeMonitor{
+		db:          db,
+		rotationAge: rotationAge,
+		now:         time.Now,
+		keys:        make(map[string]*trackedKey),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start updates the key ages and starts the periodic update.
+func (m *KeyAgeMonitor) Start() {
+	m.check()
+
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop terminates the periodic update.
+func (m *KeyAgeMonitor) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// Track starts tracking the age of the key named name. If the key is already
+// tracked or stored in the database with the same address, its age is kept;
+// otherwise the age is reset.
+func (m *KeyAgeMonitor) Track(name string, addr common.Address, gauge metrics.Gauge) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if key, ok := m.keys[name]; ok && key.addr == addr {
+		return
+	}
+	now := m.now()
+	since := now
+	if storedAddr, seen, ok := m.db.ReadKeyFirstSeen(name); ok && storedAddr == addr {
+		since = time.Unix(int64(seen), 0)
+	} else {
+		m.db.WriteKeyFirstSeen(name, addr, uint64(now.Unix()))
+	}
+	m.keys[name] = &trackedKey{addr: addr, since: since, gauge: gauge}
+	gauge.Update(int64(now.Sub(since) / time.Second))
+}
+
+// Age returns how long the key named name has been in use.
+func (m *KeyAgeMonitor) Age(name string) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[name]
+	if !ok {
+		return 0, false
+	}
+	return m.now().Sub(key.since), true
+}
+
+func (m *KeyAgeMonitor) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(keyAgeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.check()
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// check updates the age metrics and warns about the keys older than the rotation age.
+func (m *KeyAgeMonitor) check() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for name, key := range m.keys {
+		age := now.Sub(key.since)
+		key.gauge.Update(int64(age / time.Second))
+
+		if m.rotationAge > 0 && age >= m.rotationAge && !key.warned {
+			key.warned = true
+			logger.Warn("Key has been in use longer than the rotation age, consider rotating it",
+				"key", name, "address", key.addr, "age", common.PrettyDuration(age), "rotationAge", m.rotationAge)
+		}
+	}
+}
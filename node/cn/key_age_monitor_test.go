@@ -0,0 +1,116 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/metrics"
+	"github.com/klaytn/klaytn/storage/database"
+	"testing"
+	"time"
+)
+
+func TestKeyAgeMonitor(t *testing.T) {
+	var (
+		now     = time.Unix(1000000, 0)
+		monitor = NewKeyAgeMonitor(database.NewMemoryDBManager(), 24*time.Hour)
+		gauge   = new(metrics.StandardGauge)
+		addr    = common.HexToAddress("0x1")
+	)
+	monitor.now = func() time.Time { return now }
+	monitor.Track(rewardbaseName, addr, gauge)
+
+	now = now.Add(time.Hour)
+	monitor.check()
+	if gauge.Value() != int64(time.Hour/time.Second) {
+		t.Fatalf("age metric mismatch: have %d, want %d", gauge.Value(), int64(time.Hour/time.Second))
+	}
+	if monitor.keys[rewardbaseName].warned {
+		t.Fatal("rotation warning should not fire before the rotation age")
+	}
+
+	// Tracking the same key again keeps its age.
+	monitor.Track(rewardbaseName, addr, gauge)
+	if age, _ := monitor.Age(rewardbaseName); age != time.Hour {
+		t.Fatalf("age mismatch: have %v, want %v", age, time.Hour)
+	}
+
+	now = now.Add(24 * time.Hour)
+	monitor.check()
+	if gauge.Value() != int64(25*time.Hour/time.Second) {
+		t.Fatalf("age metric mismatch: have %d, want %d", gauge.Value(), int64(25*time.Hour/time.Second))
+	}
+	if !monitor.keys[rewardbaseName].warned {
+		t.Fatal("rotation warning should fire past the rotation age")
+	}
+
+	// Rotating the key resets its age.
+	monitor.Track(rewardbaseName, common.HexToAddress("0x2"), gauge)
+	monitor.check()
+	if gauge.Value() != 0 {
+		t.Fatalf("age metric should be reset after rotation: have %d", gauge.Value())
+	}
+	if monitor.keys[rewardbaseName].warned {
+		t.Fatal("rotation warning should be reset after rotation")
+	}
+}
+
+func TestKeyAgeMonitorWarningDisabled(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	monitor := NewKeyAgeMonitor(database.NewMemoryDBManager(), 0)
+	monitor.now = func() time.Time { return now }
+	monitor.Track(nodeKeyName, common.HexToAddress("0x1"), new(metrics.StandardGauge))
+
+	now = now.Add(365 * 24 * time.Hour)
+	monitor.check()
+	if monitor.keys[nodeKeyName].warned {
+		t.Fatal("rotation warning should not fire when the rotation age is 0")
+	}
+}
+
+func TestKeyAgeMonitorRestart(t *testing.T) {
+	var (
+		db    = database.NewMemoryDBManager()
+		now   = time.Unix(1000000, 0)
+		gauge = new(metrics.StandardGauge)
+		addr  = common.HexToAddress("0x1")
+	)
+	monitor := NewKeyAgeMonitor(db, 24*time.Hour)
+	monitor.now = func() time.Time { return now }
+	monitor.Track(nodeKeyName, addr, gauge)
+
+	// A restarted monitor keeps the age of the same key.
+	now = now.Add(time.Hour)
+	monitor = NewKeyAgeMonitor(db, 24*time.Hour)
+	monitor.now = func() time.Time { return now }
+	monitor.Track(nodeKeyName, addr, gauge)
+	if age, _ := monitor.Age(nodeKeyName); age != time.Hour {
+		t.Fatalf("age mismatch after restart: have %v, want %v", age, time.Hour)
+	}
+	if gauge.Value() != int64(time.Hour/time.Second) {
+		t.Fatalf("age metric mismatch after restart: have %d, want %d", gauge.Value(), int64(time.Hour/time.Second))
+	}
+
+	// A restarted monitor with a rotated key resets the age.
+	now = now.Add(time.Hour)
+	monitor = NewKeyAgeMonitor(db, 24*time.Hour)
+	monitor.now = func() time.Time { return now }
+	monitor.Track(nodeKeyName, common.HexToAddress("0x2"), gauge)
+	if age, _ := monitor.Age(nodeKeyName); age != 0 {
+		t.Fatalf("age mismatch after rotation: have %v, want 0", age)
+	}
+}
@@ -66,6 +66,7 @@ var (
 	cnPeerCountGauge          = metrics.NewRegisteredGauge("p2p/CNPeerCountGauge", nil)
 	pnPeerCountGauge          = metrics.NewRegisteredGauge("p2p/PNPeerCountGauge", nil)
 	enPeerCountGauge          = metrics.NewRegisteredGauge("p2p/ENPeerCountGauge", nil)
+	staleForkPeerCounter      = metrics.NewRegisteredCounter("p2p/StaleForkPeerCounter", nil)
 )
 
 // meteredMsgReadWriter is a wrapper around a p2p.MsgReadWriter, capable of
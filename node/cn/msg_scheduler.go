@@ -0,0 +1,138 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"errors"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"sync"
+)
+
+// Priorities of the messages sent to a multichannel peer. When several messages
+// wait for the same connection, the one with the highest priority is written
+// first so that block propagation is not delayed by transaction floods.
+const (
+	lowMsgPriority    = iota // transactions
+	normalMsgPriority        // requests and the other responses
+	highMsgPriority          // block propagation and block responses
+	numMsgPriorities
+)
+
+var errMsgSchedulerClosed = errors.New("peer is closed")
+
+// msgPriority returns the sending priority of the given message code.
+func msgPriority(msgcode uint64) int {
+	switch msgcode {
+	case TxMsg:
+		return lowMsgPriority
+	case NewBlockMsg, NewBlockHashesMsg, BlockHeadersMsg, BlockBodiesMsg,
+		BlockHeaderFetchResponseMsg, BlockBodiesFetchResponseMsg:
+		return highMsgPriority
+	default:
+		return normalMsgPriority
+	}
+}
+
+// scheduledMsg is an encoded message waiting to be written.
+type scheduledMsg struct {
+	msg   p2p.Msg
+	errCh chan error
+}
+
+// msgScheduler serializes the writes to a connection of a multichannel peer,
+// always writing the pending message with the highest priority first. Messages
+// with the same priority are written in order.
+type msgScheduler struct {
+	rw p2p.MsgReadWriter
+
+	mu      sync.Mutex
+	pending [numMsgPriorities][]*scheduledMsg
+
+	wakeCh chan struct{}
+	term   <-chan struct{}
+}
+
+// newMsgScheduler returns a msgScheduler writing to rw until term is closed.
+func newMsgScheduler(rw p2p.MsgReadWriter, term <-chan struct{}) *msgScheduler {
+	s := &msgScheduler{
+		rw:     rw,
+		wakeCh: make(chan struct{}, 1),
+		term:   term,
+	}
+	go s.loop()
+	return s
+}
+
+// send encodes the data and waits until the message is written.
+func (s *msgScheduler) send(msgcode uint64, data interface{}) error {
+	size, r, err := rlp.EncodeToReader(data)
+	if err != nil {
+		return err
+	}
+	m := &scheduledMsg{
+		msg:   p2p.Msg{Code: msgcode, Size: uint32(size), Payload: r},
+		errCh: make(chan error, 1),
+	}
+
+	priority := msgPriority(msgcode)
+	s.mu.Lock()
+	s.pending[priority] = append(s.pending[priority], m)
+	s.mu.Unlock()
+
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+
+	select {
+	case err := <-m.errCh:
+		return err
+	case <-s.term:
+		return errMsgSchedulerClosed
+	}
+}
+
+// next pops the pending message with the highest priority.
+func (s *msgScheduler) next() *scheduledMsg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for priority := numMsgPriorities - 1; priority >= 0; priority-- {
+		if queue := s.pending[priority]; len(queue) > 0 {
+			m := queue[0]
+			queue[0] = nil
+			s.pending[priority] = queue[1:]
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *msgScheduler) loop() {
+	for {
+		if m := s.next(); m != nil {
+			m.errCh <- s.rw.WriteMsg(m.msg)
+			continue
+		}
+		select {
+		case <-s.wakeCh:
+		case <-s.term:
+			return
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMsgWriter records the codes of the written messages. Like a
+// connection, it writes one message at a time, taking delay per write.
+type recordingMsgWriter struct {
+	p2p.MsgReadWriter
+	delay   time.Duration
+	blockCh chan struct{} // if not nil, the first write waits until it is closed
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	codes   []uint64
+}
+
+func (w *recordingMsgWriter) WriteMsg(msg p2p.Msg) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	w.mu.Lock()
+	first := len(w.codes) == 0
+	w.codes = append(w.codes, msg.Code)
+	w.mu.Unlock()
+
+	if first && w.blockCh != nil {
+		<-w.blockCh
+	}
+	time.Sleep(w.delay)
+	return msg.Discard()
+}
+
+func (w *recordingMsgWriter) writtenCodes() []uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]uint64{}, w.codes...)
+}
+
+func (s *msgScheduler) numPending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, queue := range s.pending {
+		n += len(queue)
+	}
+	return n
+}
+
+func TestMsgSchedulerPriority(t *testing.T) {
+	term := make(chan struct{})
+	defer close(term)
+	rw := &recordingMsgWriter{blockCh: make(chan struct{})}
+	s := newMsgScheduler(rw, term)
+
+	var wg sync.WaitGroup
+	send := func(code uint64) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.send(code, []uint{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// The first transaction occupies the connection while the others queue up.
+	send(TxMsg)
+	for len(rw.writtenCodes()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	send(TxMsg)
+	send(TxMsg)
+	for s.numPending() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	send(BlockHeadersRequestMsg)
+	send(NewBlockMsg)
+	for s.numPending() != 4 {
+		time.Sleep(time.Millisecond)
+	}
+	close(rw.blockCh)
+	wg.Wait()
+
+	want := []uint64{TxMsg, NewBlockMsg, BlockHeadersRequestMsg, TxMsg, TxMsg}
+	have := rw.writtenCodes()
+	if len(have) != len(want) {
+		t.Fatalf("written messages mismatch: have %v, want %v", have, want)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Fatalf("written messages mismatch: have %v, want %v", have, want)
+		}
+	}
+
+	// Sending fails once the peer is closed.
+	closed := make(chan struct{})
+	close(closed)
+	if err := newMsgScheduler(&recordingMsgWriter{blockCh: make(chan struct{})}, closed).send(TxMsg, []uint{}); err != errMsgSchedulerClosed {
+		t.Fatalf("send to a closed peer: have %v, want %v", err, errMsgSchedulerClosed)
+	}
+}
+
+func TestMsgSchedulerNotStartedForRejectedPeer(t *testing.T) {
+	pm := &ProtocolManager{peers: newPeerSet(), peerQueueSizes: newPeerQueueSizes(&Config{})}
+	rw := &recordingMsgWriter{}
+	peer, err := pm.newPeerWithRWs(klay63, p2p.NewPeer(discover.NodeID{1}, "rejected", nil), []p2p.MsgReadWriter{rw, rw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := peer.(*multiChannelPeer)
+	if p.schedulers != nil {
+		t.Fatal("schedulers started before the peer is handled")
+	}
+
+	// A peer rejected before registration must not leave schedulers running.
+	if err := p.Handle(pm); err != p2p.DiscTooManyPeers {
+		t.Fatalf("handle error mismatch: have %v, want %v", err, p2p.DiscTooManyPeers)
+	}
+	if p.schedulers != nil {
+		t.Fatal("schedulers started for a rejected peer")
+	}
+}
+
+// BenchmarkNewBlockLatencyUnderTxFlood measures how long sending a NewBlockMsg
+// takes while transactions are flooded to the same connection, with the
+// messages written directly ("direct") or through the msgScheduler ("scheduled").
+func BenchmarkNewBlockLatencyUnderTxFlood(b *testing.B) {
+	const flooders = 16
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	txs := types.Transactions{types.NewTransaction(0, [20]byte{}, big.NewInt(0), 0, big.NewInt(0), nil)}
+
+	run := func(b *testing.B, sender func(p *multiChannelPeer, code uint64, data interface{}) error) {
+		rw := &recordingMsgWriter{delay: 20 * time.Microsecond}
+		pm := &ProtocolManager{peerQueueSizes: newPeerQueueSizes(&Config{})}
+		peer, err := pm.newPeerWithRWs(klay63, p2p.NewPeer(discover.NodeID{1}, "bench", nil), []p2p.MsgReadWriter{rw, rw})
+		if err != nil {
+			b.Fatal(err)
+		}
+		p := peer.(*multiChannelPeer)
+		p.startSchedulers()
+		defer p.Close()
+
+		// Put TxMsg on the default connection to share it with the block messages.
+		defer func(orig int) { ChannelOfMessage[TxMsg] = orig }(ChannelOfMessage[TxMsg])
+		ChannelOfMessage[TxMsg] = p2p.ConnDefault
+
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < flooders; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						sender(p, TxMsg, txs)
+					}
+				}
+			}()
+		}
+
+		// Only the block sends are timed, so ns/op is the latency of a block message.
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := sender(p, NewBlockMsg, []interface{}{block, big.NewInt(1)}); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+		close(stop)
+		wg.Wait()
+	}
+
+	b.Run("direct", func(b *testing.B) {
+		run(b, func(p *multiChannelPeer, code uint64, data interface{}) error {
+			return p2p.Send(p.rws[ChannelOfMessage[code]], code, data)
+		})
+	})
+	b.Run("scheduled", func(b *testing.B) {
+		run(b, func(p *multiChannelPeer, code uint64, data interface{}) error {
+			return p.msgSender(code, data)
+		})
+	})
+}
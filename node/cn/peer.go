@@ -28,10 +28,15 @@ import (
 	"github.com/klaytn/klaytn/consensus"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/datasync/downloader"
+	"github.com/klaytn/klaytn/metrics"
 	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/ser/rlp"
+	"math"
 	"math/big"
+	"math/rand"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -42,32 +47,73 @@ var (
 	errClosed            = errors.New("peer set is closed")
 	errAlreadyRegistered = errors.New("peer is already registered")
 	errNotRegistered     = errors.New("peer is not registered")
+	errNotSupported      = errors.New("message is not supported by the peer")
 )
 
 const (
 	maxKnownTxs    = 32768 // Maximum transactions hashes to keep in the known list (prevent DOS)
 	maxKnownBlocks = 1024  // Maximum block hashes to keep in the known list (prevent DOS)
 
-	// maxQueuedTxs is the maximum number of transaction lists to queue up before
+	// maxQueuedTxs is the default maximum number of transaction lists to queue up before
 	// dropping broadcasts. This is a sensitive number as a transaction list might
 	// contain a single transaction, or thousands.
 	maxQueuedTxs = 128
 
-	// maxQueuedProps is the maximum number of block propagations to queue up before
+	// maxQueuedProps is the default maximum number of block propagations to queue up before
 	// dropping broadcasts. There's not much point in queueing stale blocks, so a few
 	// that might cover uncles should be enough.
 	// TODO-Klaytn-Refactoring Look into the usage of maxQueuedProps and remove it if needed
 	maxQueuedProps = 4
 
-	// maxQueuedAnns is the maximum number of block announcements to queue up before
+	// maxQueuedAnns is the default maximum number of block announcements to queue up before
 	// dropping broadcasts. Similarly to block propagations, there's no point to queue
 	// above some healthy uncle limit, so use that.
 	// TODO-Klaytn-Refactoring Look into the usage of maxQueuedAnns and remove it if needed
 	maxQueuedAnns = 4
 
+	// maxBroadcastRestarts is the default maximum number of times the broadcast loop
+	// of a peer is restarted after a panic before the peer is dropped.
+	maxBroadcastRestarts = 3
+
+	// handshakeTimeout is the default timeout of the protocol handshake.
 	handshakeTimeout = 5 * time.Second
 )
 
+// peerQueueSizes are the sizes of the broadcast queues and the known hash caches of a peer.
+type peerQueueSizes struct {
+	txs   int // Maximum number of queued transaction lists
+	props int // Maximum number of queued block propagations
+	anns  int // Maximum number of queued block announcements
+
+	knownTxs    int // Maximum number of transaction hashes known by the peer
+	knownBlocks int // Maximum number of block hashes known by the peer
+}
+
+var defaultPeerQueueSizes = peerQueueSizes{txs: maxQueuedTxs, props: maxQueuedProps, anns: maxQueuedAnns,
+	knownTxs: maxKnownTxs, knownBlocks: maxKnownBlocks}
+
+// newPeerQueueSizes returns the queue and cache sizes configured in config. The
+// default size is used for a queue or a cache whose size is not positive.
+func newPeerQueueSizes(config *Config) peerQueueSizes {
+	sizes := defaultPeerQueueSizes
+	if config.PeerQueueTxs > 0 {
+		sizes.txs = config.PeerQueueTxs
+	}
+	if config.PeerQueueProps > 0 {
+		sizes.props = config.PeerQueueProps
+	}
+	if config.PeerQueueAnns > 0 {
+		sizes.anns = config.PeerQueueAnns
+	}
+	if config.PeerKnownTxs > 0 {
+		sizes.knownTxs = config.PeerKnownTxs
+	}
+	if config.PeerKnownBlocks > 0 {
+		sizes.knownBlocks = config.PeerKnownBlocks
+	}
+	return sizes
+}
+
 // PeerInfo represents a short summary of the Klaytn sub-protocol metadata known
 // about a connected peer.
 type PeerInfo struct {
@@ -160,6 +206,14 @@ type Peer interface {
 	// ones requested from an already RLP encoded format.
 	SendReceiptsRLP(receipts []rlp.RawValue) error
 
+	// SendReceiptsByBlockRLP sends the receipts of a batch of blocks, each paired
+	// with its block hash, from an already RLP encoded format.
+	SendReceiptsByBlockRLP(hashes []common.Hash, receipts []rlp.RawValue) error
+
+	// RequestReceiptsByBlock fetches all the receipts of a batch of blocks from a
+	// remote node. It returns errNotSupported if the remote node can't serve them.
+	RequestReceiptsByBlock(hashes []common.Hash) error
+
 	// FetchBlockHeader is a wrapper around the header query functions to fetch a
 	// single header. It is used solely by the fetcher.
 	FetchBlockHeader(hash common.Hash) error
@@ -170,8 +224,9 @@ type Peer interface {
 	FetchBlockBodies(hashes []common.Hash) error
 
 	// Handshake executes the Klaytn protocol handshake, negotiating version number,
-	// network IDs, difficulties, head, and genesis blocks and returning error.
-	Handshake(network uint64, chainID, td *big.Int, head common.Hash, genesis common.Hash) error
+	// network IDs, difficulties, head, genesis blocks and fork identifiers and returning error.
+	// It returns p2p.DiscReadTimeout if the handshake does not complete within timeout.
+	Handshake(network uint64, chainID, td *big.Int, head common.Hash, genesis common.Hash, forkID params.ForkID, timeout time.Duration) error
 
 	// ConnType returns the conntype of the peer.
 	ConnType() p2p.ConnType
@@ -185,6 +240,12 @@ type Peer interface {
 	// GetChainID returns the chain id of the peer.
 	GetChainID() *big.Int
 
+	// GetForkID returns the fork identifier of the peer, nil if the peer did not send one.
+	GetForkID() *params.ForkID
+
+	// IsTrusted returns true if the peer was connected as a trusted peer.
+	IsTrusted() bool
+
 	// GetAddr returns the address of the peer.
 	GetAddr() common.Address
 
@@ -200,6 +261,9 @@ type Peer interface {
 	// KnowsTx returns if the peer is known to have the transaction, based on knownTxsCache.
 	KnowsTx(hash common.Hash) bool
 
+	// ResizeKnownCaches replaces knownTxsCache and knownBlocksCache with empty caches of the given sizes.
+	ResizeKnownCaches(txSize, blockSize int)
+
 	// GetP2PPeer returns the p2p.
 	GetP2PPeer() *p2p.Peer
 
@@ -230,6 +294,7 @@ type basePeer struct {
 	rw p2p.MsgReadWriter
 
 	version  int         // Protocol version negotiated
+	trusted  bool        // Whether the peer was connected as a trusted peer
 	forkDrop *time.Timer // Timed connection dropper if forks aren't validated in time
 
 	head common.Hash
@@ -243,21 +308,30 @@ type basePeer struct {
 	queuedAnns       chan *types.Block         // Queue of blocks to announce to the peer
 	term             chan struct{}             // Termination channel to stop the broadcaster
 
-	chainID *big.Int // ChainID to sign a transaction
+	chainID *big.Int       // ChainID to sign a transaction
+	forkID  *params.ForkID // Fork identifier sent in the handshake, nil if not sent
 }
 
-// newKnownBlockCache returns an empty cache for knownBlocksCache.
-func newKnownBlockCache() common.Cache {
-	return common.NewCache(common.FIFOCacheConfig{CacheSize: maxKnownBlocks})
+// newKnownBlockCache returns an empty cache for knownBlocksCache holding size
+// hashes, or maxKnownBlocks hashes if size is not positive.
+func newKnownBlockCache(size int) common.Cache {
+	if size <= 0 {
+		size = maxKnownBlocks
+	}
+	return common.NewCache(common.FIFOCacheConfig{CacheSize: size})
 }
 
-// newKnownTxCache returns an empty cache for knownTxsCache.
-func newKnownTxCache() common.Cache {
-	return common.NewCache(common.FIFOCacheConfig{CacheSize: maxKnownTxs})
+// newKnownTxCache returns an empty cache for knownTxsCache holding size hashes,
+// or maxKnownTxs hashes if size is not positive.
+func newKnownTxCache(size int) common.Cache {
+	if size <= 0 {
+		size = maxKnownTxs
+	}
+	return common.NewCache(common.FIFOCacheConfig{CacheSize: size})
 }
 
 // newPeer returns new Peer interface.
-func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) Peer {
+func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter, queueSizes peerQueueSizes) Peer {
 	id := p.ID()
 
 	return &singleChannelPeer{
@@ -265,12 +339,13 @@ func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) Peer {
 			Peer:             p,
 			rw:               rw,
 			version:          version,
+			trusted:          p.Trusted(),
 			id:               fmt.Sprintf("%x", id[:8]),
-			knownTxsCache:    newKnownTxCache(),
-			knownBlocksCache: newKnownBlockCache(),
-			queuedTxs:        make(chan []*types.Transaction, maxQueuedTxs),
-			queuedProps:      make(chan *propEvent, maxQueuedProps),
-			queuedAnns:       make(chan *types.Block, maxQueuedAnns),
+			knownTxsCache:    newKnownTxCache(queueSizes.knownTxs),
+			knownBlocksCache: newKnownBlockCache(queueSizes.knownBlocks),
+			queuedTxs:        make(chan []*types.Transaction, queueSizes.txs),
+			queuedProps:      make(chan *propEvent, queueSizes.props),
+			queuedAnns:       make(chan *types.Block, queueSizes.anns),
 			term:             make(chan struct{}),
 		},
 	}
@@ -296,6 +371,13 @@ var ChannelOfMessage = map[uint64]int{
 	NodeDataMsg:        p2p.ConnDefault,
 	ReceiptsRequestMsg: p2p.ConnDefault,
 	ReceiptsMsg:        p2p.ConnDefault,
+
+	// Protocol messages belonging to klay/65
+	NodeDataRangeRequestMsg: p2p.ConnDefault,
+
+	// Protocol messages belonging to klay/66
+	ReceiptsByBlockRequestMsg: p2p.ConnDefault,
+	ReceiptsByBlockMsg:        p2p.ConnDefault,
 }
 
 var ConcurrentOfChannel = []int{
@@ -303,24 +385,32 @@ var ConcurrentOfChannel = []int{
 	p2p.ConnTxMsg:   3,
 }
 
+// HighPriorityChannels is a set of channels whose messages are processed ahead of
+// the queued messages of the other channels on the same connection. Block messages
+// are prioritized so that consensus is not delayed by large sync responses.
+var HighPriorityChannels = map[uint]bool{
+	BlockChannel: true,
+}
+
 // newPeerWithRWs creates a new Peer object with a slice of p2p.MsgReadWriter.
-func newPeerWithRWs(version int, p *p2p.Peer, rws []p2p.MsgReadWriter) (Peer, error) {
+func newPeerWithRWs(version int, p *p2p.Peer, rws []p2p.MsgReadWriter, queueSizes peerQueueSizes) (Peer, error) {
 	id := p.ID()
 
 	lenRWs := len(rws)
 	if lenRWs == 1 {
-		return newPeer(version, p, rws[p2p.ConnDefault]), nil
+		return newPeer(version, p, rws[p2p.ConnDefault], queueSizes), nil
 	} else if lenRWs > 1 {
 		bPeer := &basePeer{
 			Peer:             p,
 			rw:               rws[p2p.ConnDefault],
 			version:          version,
+			trusted:          p.Trusted(),
 			id:               fmt.Sprintf("%x", id[:8]),
-			knownTxsCache:    newKnownTxCache(),
-			knownBlocksCache: newKnownBlockCache(),
-			queuedTxs:        make(chan []*types.Transaction, maxQueuedTxs),
-			queuedProps:      make(chan *propEvent, maxQueuedProps),
-			queuedAnns:       make(chan *types.Block, maxQueuedAnns),
+			knownTxsCache:    newKnownTxCache(queueSizes.knownTxs),
+			knownBlocksCache: newKnownBlockCache(queueSizes.knownBlocks),
+			queuedTxs:        make(chan []*types.Transaction, queueSizes.txs),
+			queuedProps:      make(chan *propEvent, queueSizes.props),
+			queuedAnns:       make(chan *types.Block, queueSizes.anns),
 			term:             make(chan struct{}),
 		}
 		return &multiChannelPeer{
@@ -337,6 +427,9 @@ func newPeerWithRWs(version int, p *p2p.Peer, rws []p2p.MsgReadWriter) (Peer, er
 // and transaction broadcasts into the remote peer. The goal is to have an async
 // writer that does not lock up node internals.
 func (p *basePeer) Broadcast() {
+	unregister := p.registerQueueGauges()
+	defer unregister()
+
 	for {
 		select {
 		case txs := <-p.queuedTxs:
@@ -370,6 +463,28 @@ func (p *basePeer) Broadcast() {
 	}
 }
 
+// registerQueueGauges registers the gauges of the occupancy of the broadcast queues
+// of the peer. It returns the function unregistering them.
+func (p *basePeer) registerQueueGauges() func() {
+	queues := map[string]func() int{
+		"txs":   func() int { return len(p.queuedTxs) },
+		"props": func() int { return len(p.queuedProps) },
+		"anns":  func() int { return len(p.queuedAnns) },
+	}
+	names := make([]string, 0, len(queues))
+	for queue, occupancy := range queues {
+		occupancy := occupancy
+		name := fmt.Sprintf("klay/peer/%s/queue/%s", p.id, queue)
+		metrics.NewRegisteredFunctionalGauge(name, nil, func() int64 { return int64(occupancy()) })
+		names = append(names, name)
+	}
+	return func() {
+		for _, name := range names {
+			metrics.Unregister(name)
+		}
+	}
+}
+
 // Close signals the broadcast goroutine to terminate.
 func (p *basePeer) Close() {
 	close(p.term)
@@ -408,13 +523,38 @@ func (p *basePeer) SetHead(hash common.Hash, td *big.Int) {
 // AddToKnownBlocks adds a block hash to knownBlocksCache for the peer, ensuring that the block will
 // never be propagated to this particular peer.
 func (p *basePeer) AddToKnownBlocks(hash common.Hash) {
-	p.knownBlocksCache.Add(hash, struct{}{})
+	p.knownBlocks().Add(hash, struct{}{})
 }
 
 // AddToKnownTxs adds a transaction hash to knownTxsCache for the peer, ensuring that it
 // will never be propagated to this particular peer.
 func (p *basePeer) AddToKnownTxs(hash common.Hash) {
-	p.knownTxsCache.Add(hash, struct{}{})
+	p.knownTxs().Add(hash, struct{}{})
+}
+
+// knownBlocks returns the current knownBlocksCache of the peer.
+func (p *basePeer) knownBlocks() common.Cache {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.knownBlocksCache
+}
+
+// knownTxs returns the current knownTxsCache of the peer.
+func (p *basePeer) knownTxs() common.Cache {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.knownTxsCache
+}
+
+// ResizeKnownCaches replaces the known transaction and block caches of the peer
+// with empty caches of the given sizes. The known hashes are not migrated, so
+// they may be propagated to the peer again. A non-positive size uses the default.
+func (p *basePeer) ResizeKnownCaches(txSize, blockSize int) {
+	txs, blocks := newKnownTxCache(txSize), newKnownBlockCache(blockSize)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.knownTxsCache, p.knownBlocksCache = txs, blocks
 }
 
 // Send writes an RLP-encoded message with the given code.
@@ -530,6 +670,12 @@ func (p *basePeer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p2p.Send(p.rw, ReceiptsMsg, receipts)
 }
 
+// SendReceiptsByBlockRLP sends the receipts of a batch of blocks, each paired
+// with its block hash, from an already RLP encoded format.
+func (p *basePeer) SendReceiptsByBlockRLP(hashes []common.Hash, receipts []rlp.RawValue) error {
+	return p2p.Send(p.rw, ReceiptsByBlockMsg, newReceiptsByBlockData(hashes, receipts))
+}
+
 // FetchBlockHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *basePeer) FetchBlockHeader(hash common.Hash) error {
@@ -572,53 +718,85 @@ func (p *basePeer) RequestNodeData(hashes []common.Hash) error {
 	return p2p.Send(p.rw, NodeDataRequestMsg, hashes)
 }
 
+// RequestNodeDataRange fetches the state data of the specified hashes together
+// with as many of their descendant trie nodes as the remote node is willing to serve.
+func (p *basePeer) RequestNodeDataRange(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of state data ranges", "count", len(hashes))
+	return p2p.Send(p.rw, NodeDataRangeRequestMsg, hashes)
+}
+
 // RequestReceipts fetches a batch of transaction receipts from a remote node.
 func (p *basePeer) RequestReceipts(hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
 	return p2p.Send(p.rw, ReceiptsRequestMsg, hashes)
 }
 
+// RequestReceiptsByBlock fetches all the receipts of a batch of blocks from a
+// remote node. It returns errNotSupported if the remote node can't serve them.
+func (p *basePeer) RequestReceiptsByBlock(hashes []common.Hash) error {
+	if p.version < klay66 {
+		return errNotSupported
+	}
+	p.Log().Debug("Fetching batch of receipts by block", "count", len(hashes))
+	return p2p.Send(p.rw, ReceiptsByBlockRequestMsg, hashes)
+}
+
 // Handshake executes the Klaytn protocol handshake, negotiating version number,
-// network IDs, difficulties, head and genesis blocks.
-func (p *basePeer) Handshake(network uint64, chainID, td *big.Int, head common.Hash, genesis common.Hash) error {
+// network IDs, difficulties, head and genesis blocks and fork identifiers.
+// It returns p2p.DiscReadTimeout if the handshake does not complete within timeout.
+func (p *basePeer) Handshake(network uint64, chainID, td *big.Int, head common.Hash, genesis common.Hash, forkID params.ForkID, timeout time.Duration) error {
 	// Send out own handshake in a new thread
 	errc := make(chan error, 2)
 	var status statusData // safe to read after two values have been received from errc
 
 	go func() {
-		errc <- p2p.Send(p.rw, StatusMsg, &statusData{
+		status := &statusData{
 			ProtocolVersion: uint32(p.version),
 			NetworkId:       network,
 			TD:              td,
 			CurrentBlock:    head,
 			GenesisBlock:    genesis,
 			ChainID:         chainID,
-		})
+		}
+		if p.version >= klay65 {
+			status.ForkID = []params.ForkID{forkID}
+		}
+		errc <- p2p.Send(p.rw, StatusMsg, status)
 	}()
+	deadline := time.Now().Add(timeout)
 	go func() {
-		errc <- p.readStatus(network, &status, genesis, chainID)
+		errc <- p.readStatus(network, &status, genesis, chainID, deadline)
 	}()
-	timeout := time.NewTimer(handshakeTimeout)
-	defer timeout.Stop()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 	for i := 0; i < 2; i++ {
 		select {
 		case err := <-errc:
 			if err != nil {
 				return err
 			}
-		case <-timeout.C:
+		case <-timer.C:
 			return p2p.DiscReadTimeout
 		}
 	}
 	p.td, p.head, p.chainID = status.TD, status.CurrentBlock, status.ChainID
+	if len(status.ForkID) > 0 {
+		p.forkID = &status.ForkID[0]
+	}
 	return nil
 }
 
-func (p *basePeer) readStatus(network uint64, status *statusData, genesis common.Hash, chainID *big.Int) error {
+// readStatus reads the status message of the remote peer. A status message
+// arriving after the deadline is discarded without being decoded.
+func (p *basePeer) readStatus(network uint64, status *statusData, genesis common.Hash, chainID *big.Int, deadline time.Time) error {
 	msg, err := p.rw.ReadMsg()
 	if err != nil {
 		return err
 	}
+	if time.Now().After(deadline) {
+		msg.Discard()
+		return p2p.DiscReadTimeout
+	}
 	if msg.Code != StatusMsg {
 		return errResp(ErrNoStatusMsg, "first msg has code %x (!= %x)", msg.Code, StatusMsg)
 	}
@@ -671,6 +849,16 @@ func (p *basePeer) GetChainID() *big.Int {
 	return p.chainID
 }
 
+// GetForkID returns the fork identifier of the peer, nil if the peer did not send one.
+func (p *basePeer) GetForkID() *params.ForkID {
+	return p.forkID
+}
+
+// IsTrusted returns true if the peer was connected as a trusted peer.
+func (p *basePeer) IsTrusted() bool {
+	return p.trusted
+}
+
 // GetAddr returns the address of the peer.
 func (p *basePeer) GetAddr() common.Address {
 	return p.addr
@@ -688,13 +876,13 @@ func (p *basePeer) GetVersion() int {
 
 // KnowsBlock returns if the peer is known to have the block, based on knownBlocksCache.
 func (p *basePeer) KnowsBlock(hash common.Hash) bool {
-	_, ok := p.knownBlocksCache.Get(hash)
+	_, ok := p.knownBlocks().Get(hash)
 	return ok
 }
 
 // KnowsTx returns if the peer is known to have the transaction, based on knownTxsCache.
 func (p *basePeer) KnowsTx(hash common.Hash) bool {
-	_, ok := p.knownTxsCache.Get(hash)
+	_, ok := p.knownTxs().Get(hash)
 	return ok
 }
 
@@ -736,6 +924,8 @@ type multiChannelPeer struct {
 	*basePeer                     // basePeer is a set of data structures that the peer implementation has in common
 	rws       []p2p.MsgReadWriter // rws is a slice of p2p.MsgReadWriter for peer-to-peer transmission and reception
 
+	schedulers []*msgScheduler // schedulers prioritize the messages sent through rws, one per connection
+
 	chMgr *ChannelManager
 }
 
@@ -753,6 +943,9 @@ func (p *multiChannelPeer) RegisterConsensusMsgCode(msgCode uint64) {
 // and transaction broadcasts into the remote peer. The goal is to have an async
 // writer that does not lock up node internals.
 func (p *multiChannelPeer) Broadcast() {
+	unregister := p.registerQueueGauges()
+	defer unregister()
+
 	for {
 		select {
 		case txs := <-p.queuedTxs:
@@ -859,6 +1052,12 @@ func (p *multiChannelPeer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p.msgSender(ReceiptsMsg, receipts)
 }
 
+// SendReceiptsByBlockRLP sends the receipts of a batch of blocks, each paired
+// with its block hash, from an already RLP encoded format.
+func (p *multiChannelPeer) SendReceiptsByBlockRLP(hashes []common.Hash, receipts []rlp.RawValue) error {
+	return p.msgSender(ReceiptsByBlockMsg, newReceiptsByBlockData(hashes, receipts))
+}
+
 // FetchBlockHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *multiChannelPeer) FetchBlockHeader(hash common.Hash) error {
@@ -901,16 +1100,43 @@ func (p *multiChannelPeer) RequestNodeData(hashes []common.Hash) error {
 	return p.msgSender(NodeDataRequestMsg, hashes)
 }
 
+// RequestNodeDataRange fetches the state data of the specified hashes together
+// with as many of their descendant trie nodes as the remote node is willing to serve.
+func (p *multiChannelPeer) RequestNodeDataRange(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of state data ranges", "count", len(hashes))
+	return p.msgSender(NodeDataRangeRequestMsg, hashes)
+}
+
 // RequestReceipts fetches a batch of transaction receipts from a remote node.
 func (p *multiChannelPeer) RequestReceipts(hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
 	return p.msgSender(ReceiptsRequestMsg, hashes)
 }
 
-// msgSender sends data to the peer.
+// RequestReceiptsByBlock fetches all the receipts of a batch of blocks from a
+// remote node. It returns errNotSupported if the remote node can't serve them.
+func (p *multiChannelPeer) RequestReceiptsByBlock(hashes []common.Hash) error {
+	if p.version < klay66 {
+		return errNotSupported
+	}
+	p.Log().Debug("Fetching batch of receipts by block", "count", len(hashes))
+	return p.msgSender(ReceiptsByBlockRequestMsg, hashes)
+}
+
+// startSchedulers starts the message schedulers of the connections, which run
+// until the peer is closed. It is called right before the peer is registered,
+// so that a peer rejected earlier leaves no goroutines behind.
+func (p *multiChannelPeer) startSchedulers() {
+	p.schedulers = make([]*msgScheduler, len(p.rws))
+	for i, rw := range p.rws {
+		p.schedulers[i] = newMsgScheduler(rw, p.term)
+	}
+}
+
+// msgSender sends data to the peer through the scheduler of the connection of the message.
 func (p *multiChannelPeer) msgSender(msgcode uint64, data interface{}) error {
-	if ch, ok := ChannelOfMessage[msgcode]; ok && len(p.rws) > ch {
-		return p2p.Send(p.rws[ch], msgcode, data)
+	if ch, ok := ChannelOfMessage[msgcode]; ok && len(p.schedulers) > ch {
+		return p.schedulers[ch].send(msgcode, data)
 	} else {
 		return errors.New("RW not found for message")
 	}
@@ -967,7 +1193,7 @@ func (p *multiChannelPeer) ReadMsg(rw p2p.MsgReadWriter, connectionOrder int, er
 // this function terminates, the Peer is disconnected.
 func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 	// Ignore maxPeers if this is a trusted peer
-	if pm.peers.Len() >= pm.maxPeers && !p.GetP2PPeer().Info().Networks[p2p.ConnDefault].Trusted {
+	if pm.peers.Len() >= pm.maxPeers && !p.IsTrusted() {
 		return p2p.DiscTooManyPeers
 	}
 	p.GetP2PPeer().Log().Debug("Klaytn peer connected", "name", p.GetP2PPeer().Name())
@@ -981,18 +1207,29 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 		td      = pm.blockchain.GetTd(hash, number)
 	)
 
-	err := p.Handshake(pm.networkId, pm.getChainID(), td, hash, genesis.Hash())
+	if !pm.handshakeLimiter.acquire(pm.quitSync) {
+		p.GetP2PPeer().Log().Debug("Klaytn peer handshake not started, too many concurrent handshakes")
+		return p2p.DiscTooManyPeers
+	}
+	err := p.Handshake(pm.networkId, pm.getChainID(), td, hash, genesis.Hash(), pm.chainconfig.ForkID(head.Number), pm.handshakeTimeout(p.ConnType()))
+	pm.handshakeLimiter.release()
 	if err != nil {
 		p.GetP2PPeer().Log().Debug("Klaytn peer handshake failed", "err", err)
 		return err
 	}
+	if err := pm.checkForkID(p, head.Number); err != nil {
+		p.GetP2PPeer().Log().Debug("Klaytn peer is on a stale fork", "err", err)
+		return err
+	}
 
 	p.UpdateRWImplementationVersion()
+	p.startSchedulers()
 
 	// Register the peer locally
 	if err := pm.peers.Register(p); err != nil {
 		// if starting node with unlock account, can't register peer until finish unlock
 		p.GetP2PPeer().Log().Info("Klaytn peer registration failed", "err", err)
+		p.Close()
 		return err
 	}
 	defer pm.removePeer(p.GetID())
@@ -1027,14 +1264,17 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 		isCN = true
 	}
 
+	priorityChannels := make([]chan p2p.Msg, 0, lenRWs)
 	for idx := range p.rws {
 		channel := make(chan p2p.Msg, channelSizePerPeer)
 		defer close(channel)
 		messageChannels = append(messageChannels, channel)
 
-		p.chMgr.RegisterChannelWithIndex(idx, BlockChannel, channel)
-		p.chMgr.RegisterChannelWithIndex(idx, TxChannel, channel)
-		p.chMgr.RegisterChannelWithIndex(idx, MiscChannel, channel)
+		priorityChannel := make(chan p2p.Msg, channelSizePerPeer)
+		defer close(priorityChannel)
+		priorityChannels = append(priorityChannels, priorityChannel)
+
+		p.chMgr.RegisterPrioritizedChannelsWithIndex(idx, priorityChannel, channel)
 
 		if isCN {
 			p.chMgr.RegisterChannelWithIndex(idx, ConsensusChannel, consensusChannel)
@@ -1054,7 +1294,7 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 
 	for connIdx, messageChannel := range messageChannels {
 		for i := 0; i < ConcurrentOfChannel[connIdx]; i++ {
-			go pm.processMsg(messageChannel, p, addr, errChannel)
+			go pm.processPrioritizedMsg(priorityChannels[connIdx], messageChannel, p, addr, errChannel)
 		}
 	}
 
@@ -1086,6 +1326,12 @@ type peerSet struct {
 	closed  bool
 
 	validator map[p2p.ConnType]p2p.PeerTypeValidator
+
+	broadcastRestarts int             // Maximum number of restarts of a panicked broadcast loop
+	dropPeer          func(id string) // Drops a peer whose broadcast loop panicked too often
+
+	sampler     *rand.Rand // Source of randomness of SampleRandomPeers
+	samplerLock sync.Mutex
 }
 
 // newPeerSet creates a new peer set to track the active participants.
@@ -1096,6 +1342,7 @@ func newPeerSet() *peerSet {
 		pnpeers:   make(map[common.Address]Peer),
 		enpeers:   make(map[common.Address]Peer),
 		validator: make(map[p2p.ConnType]p2p.PeerTypeValidator),
+		sampler:   rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 
 	peerSet.validator[node.CONSENSUSNODE] = ByPassValidator{}
@@ -1149,11 +1396,40 @@ func (ps *peerSet) Register(p Peer) error {
 	cnPeerCountGauge.Update(int64(len(ps.cnpeers)))
 	pnPeerCountGauge.Update(int64(len(ps.pnpeers)))
 	enPeerCountGauge.Update(int64(len(ps.enpeers)))
-	go p.Broadcast()
+	go ps.broadcastLoop(p)
 
 	return nil
 }
 
+// broadcastLoop runs the broadcast loop of the peer, restarting it if it panics so
+// that the peer does not silently stop receiving broadcasts. The peer is dropped
+// if the loop panics more than broadcastRestarts times.
+func (ps *peerSet) broadcastLoop(p Peer) {
+	for restarts := 0; runBroadcast(p); restarts++ {
+		if restarts >= ps.broadcastRestarts {
+			logger.Error("Dropping peer with failing broadcast loop", "peer", p.GetID(), "restarts", restarts)
+			if ps.dropPeer != nil {
+				ps.dropPeer(p.GetID())
+			}
+			return
+		}
+		logger.Warn("Restarting peer broadcast loop", "peer", p.GetID(), "restarts", restarts+1)
+	}
+}
+
+// runBroadcast runs the broadcast loop of the peer until it ends. It reports
+// whether the loop ended with a panic, which is logged.
+func runBroadcast(p Peer) (panicked bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Error("Peer broadcast loop panicked", "peer", p.GetID(), "err", err, "stack", string(debug.Stack()))
+			panicked = true
+		}
+	}()
+	p.Broadcast()
+	return false
+}
+
 // Unregister removes a remote peer from the active set, disabling any further
 // actions to/from that particular entity.
 func (ps *peerSet) Unregister(id string) error {
@@ -1333,6 +1609,28 @@ func (ps *peerSet) TypePeers(nodetype p2p.ConnType) []Peer {
 	return list
 }
 
+// SampleRandomPeers returns a random subset of the given candidates to gossip to.
+// If sqrtFanout is set, the subset has the square root of the number of candidates
+// (at least one), relying on further propagation to reach the others. Otherwise,
+// all candidates are returned. The given slice is not modified.
+func (ps *peerSet) SampleRandomPeers(candidates []Peer, sqrtFanout bool) []Peer {
+	if !sqrtFanout || len(candidates) <= 1 {
+		return candidates
+	}
+	size := int(math.Sqrt(float64(len(candidates))))
+
+	sampled := make([]Peer, len(candidates))
+	copy(sampled, candidates)
+
+	ps.samplerLock.Lock()
+	defer ps.samplerLock.Unlock()
+	for i := 0; i < size; i++ {
+		j := i + ps.sampler.Intn(len(sampled)-i)
+		sampled[i], sampled[j] = sampled[j], sampled[i]
+	}
+	return sampled[:size]
+}
+
 // PeersWithoutTx retrieves a list of peers that do not have a given transaction
 // in their set of known hashes.
 func (ps *peerSet) PeersWithoutTx(hash common.Hash) []Peer {
@@ -1431,6 +1729,36 @@ func (ps *peerSet) BestPeer() Peer {
 	return bestPeer
 }
 
+// BestPeerByType retrieves the known peer of the given node type with the
+// currently highest total blockscore. It returns nil if there is no such peer.
+func (ps *peerSet) BestPeerByType(nodetype p2p.ConnType) Peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	var peersByNodeType map[common.Address]Peer
+	switch nodetype {
+	case node.CONSENSUSNODE:
+		peersByNodeType = ps.cnpeers
+	case node.PROXYNODE:
+		peersByNodeType = ps.pnpeers
+	case node.ENDPOINTNODE:
+		peersByNodeType = ps.enpeers
+	default:
+		return nil
+	}
+
+	var (
+		bestPeer Peer
+		bestTd   *big.Int
+	)
+	for _, p := range peersByNodeType {
+		if _, td := p.Head(); bestPeer == nil || td.Cmp(bestTd) > 0 {
+			bestPeer, bestTd = p, td
+		}
+	}
+	return bestPeer
+}
+
 // Close disconnects all peers.
 // No new peers can be registered after Close has returned.
 func (ps *peerSet) Close() {
@@ -22,10 +22,12 @@ package cn
 
 import (
 	"fmt"
+	"github.com/klaytn/klaytn"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/ser/rlp"
 	"io"
 	"math/big"
@@ -35,6 +37,8 @@ import (
 const (
 	klay62 = 62
 	klay63 = 63
+	klay65 = 65 // peers able to serve NodeDataRangeRequestMsg
+	klay66 = 66 // peers able to serve ReceiptsByBlockRequestMsg
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
@@ -70,6 +74,14 @@ const (
 	NodeDataMsg        = 0x0d
 	ReceiptsRequestMsg = 0x0e
 	ReceiptsMsg        = 0x0f
+
+	// Protocol messages belonging to klay/65
+	NodeDataRangeRequestMsg = 0x10
+
+	// Protocol messages belonging to klay/66
+	// 0x11 to 0x14 are reserved for the messages of the istanbul consensus.
+	ReceiptsByBlockRequestMsg = 0x15
+	ReceiptsByBlockMsg        = 0x16
 )
 
 type errCode int
@@ -87,6 +99,7 @@ const (
 	ErrSuspendedPeer
 	ErrUnexpectedTxType
 	ErrFailedToGetStateDB
+	ErrStaleFork
 )
 
 func (e errCode) String() string {
@@ -107,6 +120,7 @@ var errorToString = map[int]string{
 	ErrSuspendedPeer:           "Suspended peer",
 	ErrUnexpectedTxType:        "Unexpected tx type",
 	ErrFailedToGetStateDB:      "Failed to get stateDB",
+	ErrStaleFork:               "Stale fork",
 }
 
 type txPool interface {
@@ -124,6 +138,16 @@ type txPool interface {
 	SubscribeNewTxsEvent(chan<- blockchain.NewTxsEvent) event.Subscription
 }
 
+// syncStatus reports the chain synchronisation of the downloader.
+type syncStatus interface {
+	// Synchronising should return whether a sync cycle is running.
+	Synchronising() bool
+
+	// Progress should return the local head and the head fetched from the
+	// sync peer in the current sync cycle.
+	Progress() klaytn.SyncProgress
+}
+
 // statusData is the network packet for the status message.
 type statusData struct {
 	ProtocolVersion uint32
@@ -132,6 +156,12 @@ type statusData struct {
 	CurrentBlock    common.Hash
 	GenesisBlock    common.Hash
 	ChainID         *big.Int // ChainID to sign a transaction.
+
+	// ForkID holds at most one fork identifier. It is a tail field so that status
+	// messages of peers which do not send a fork identifier are still accepted.
+	// It is sent only to klay65 or later peers, since older peers reject status
+	// messages with extra fields.
+	ForkID []params.ForkID `rlp:"tail"`
 }
 
 // newBlockHashesData is the network packet for the block announcements.
@@ -197,3 +227,25 @@ type blockBody struct {
 
 // blockBodiesData is the network packet for block content distribution.
 type blockBodiesData []*blockBody
+
+// receiptsByBlockData is the network packet for the receipts of a batch of
+// blocks, sent in an already RLP encoded format.
+type receiptsByBlockData []struct {
+	Hash     common.Hash  // Hash of the block
+	Receipts rlp.RawValue // RLP encoded receipts of the block
+}
+
+// newReceiptsByBlockData pairs the given block hashes with the encoded receipts.
+func newReceiptsByBlockData(hashes []common.Hash, receipts []rlp.RawValue) receiptsByBlockData {
+	data := make(receiptsByBlockData, len(hashes))
+	for i := range hashes {
+		data[i].Hash, data[i].Receipts = hashes[i], receipts[i]
+	}
+	return data
+}
+
+// blockReceiptsData is the decoded form of receiptsByBlockData.
+type blockReceiptsData []struct {
+	Hash     common.Hash    // Hash of the block
+	Receipts types.Receipts // Receipts of the block
+}
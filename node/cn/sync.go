@@ -46,10 +46,13 @@ type txsync struct {
 
 // syncTransactions starts sending all currently pending transactions to the given peer.
 func (pm *ProtocolManager) syncTransactions(p Peer) {
+	if !pm.broadcastsTxTo(p.ConnType()) {
+		return
+	}
 	var txs types.Transactions
 	pending, _ := pm.txpool.Pending()
 	for _, batch := range pending {
-		txs = append(txs, batch...)
+		txs = append(txs, unconditionalTxs(batch)...)
 	}
 	if len(txs) == 0 {
 		return
@@ -142,6 +145,8 @@ func (pm *ProtocolManager) syncer() {
 	// Wait for different events to fire synchronisation operations
 	forceSync := time.NewTicker(forceSyncCycle)
 	defer forceSync.Stop()
+	sampleSyncRate := time.NewTicker(syncRateCycle)
+	defer sampleSyncRate.Stop()
 
 	for {
 		select {
@@ -156,6 +161,11 @@ func (pm *ProtocolManager) syncer() {
 			// Force a sync even if not enough peers are present
 			go pm.synchronise(pm.peers.BestPeer())
 
+		case now := <-sampleSyncRate.C:
+			// The downloader reports the fast block during a fast sync, where the
+			// current block does not move.
+			pm.syncRate.update(pm.downloader.Progress().CurrentBlock, now)
+
 		case <-pm.noMorePeers:
 			return
 		}
@@ -0,0 +1,119 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"github.com/klaytn/klaytn/common/hexutil"
+	"sync"
+	"time"
+)
+
+const (
+	// syncRateCycle is the time interval to sample the block import rate.
+	syncRateCycle = 10 * time.Second
+
+	// syncRateSmoothing is the weight of the latest sample in the moving average
+	// of the block import rate.
+	syncRateSmoothing = 0.3
+)
+
+// syncRateEstimator tracks the block import rate as an exponential moving average
+// of the rates between samples of the current block number.
+type syncRateEstimator struct {
+	mu         sync.Mutex
+	rate       float64 // Blocks imported per second
+	lastNumber uint64
+	lastTime   time.Time
+}
+
+// update adds a sample of the current block number taken at the given time.
+func (e *syncRateEstimator) update(number uint64, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.lastTime.IsZero() && now.After(e.lastTime) {
+		var imported uint64
+		if number > e.lastNumber {
+			imported = number - e.lastNumber
+		}
+		rate := float64(imported) / now.Sub(e.lastTime).Seconds()
+		e.rate = syncRateSmoothing*rate + (1-syncRateSmoothing)*e.rate
+	}
+	e.lastNumber, e.lastTime = number, now
+}
+
+// importRate returns the moving average of the block import rate.
+func (e *syncRateEstimator) importRate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.rate
+}
+
+// SyncProgressResult is the result of a klay_syncProgress API call.
+type SyncProgressResult struct {
+	StartingBlock hexutil.Uint64  `json:"startingBlock"`
+	CurrentBlock  hexutil.Uint64  `json:"currentBlock"`
+	HighestBlock  hexutil.Uint64  `json:"highestBlock"`
+	ImportRate    float64         `json:"importRate"` // Blocks imported per second
+	ETA           *hexutil.Uint64 `json:"eta"`        // Estimated seconds to reach the highest block, nil if unknown
+}
+
+// newSyncProgressResult returns the progress of a sync from current to highest
+// with the ETA estimated from the given import rate.
+func newSyncProgressResult(starting, current, highest uint64, rate float64) *SyncProgressResult {
+	if highest < current {
+		highest = current
+	}
+	result := &SyncProgressResult{
+		StartingBlock: hexutil.Uint64(starting),
+		CurrentBlock:  hexutil.Uint64(current),
+		HighestBlock:  hexutil.Uint64(highest),
+		ImportRate:    rate,
+	}
+	if remaining := highest - current; remaining == 0 {
+		result.ETA = new(hexutil.Uint64)
+	} else if rate > 0 {
+		eta := hexutil.Uint64(float64(remaining)/rate + 0.5)
+		result.ETA = &eta
+	}
+	return result
+}
+
+// syncProgress returns the sync progress towards the head of the best peer with
+// the estimated time to reach it.
+func (pm *ProtocolManager) syncProgress() *SyncProgressResult {
+	progress := pm.downloader.Progress()
+	current := progress.CurrentBlock
+
+	// The head of the best peer is known locally only if it was announced, so the
+	// highest block of the running sync is used as well.
+	highest := progress.HighestBlock
+	if peer := pm.peers.BestPeer(); peer != nil {
+		hash, _ := peer.Head()
+		if header := pm.blockchain.GetHeaderByHash(hash); header != nil && header.Number.Uint64() > highest {
+			highest = header.Number.Uint64()
+		}
+	}
+	return newSyncProgressResult(progress.StartingBlock, current, highest, pm.syncRate.importRate())
+}
+
+// SyncProgress returns the progress of the synchronisation towards the head of the
+// best peer, the block import rate and the estimated time to complete it.
+func (api *PublicKlayAPI) SyncProgress() *SyncProgressResult {
+	return api.cn.protocolManager.syncProgress()
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSyncProgressETA(t *testing.T) {
+	var (
+		estimator syncRateEstimator
+		now       = time.Unix(1000, 0)
+		number    = uint64(100)
+	)
+	// Import 50 blocks per sample, a rate of 5 blocks per second.
+	estimator.update(number, now)
+	for i := 0; i < 30; i++ {
+		number += 50
+		now = now.Add(syncRateCycle)
+		estimator.update(number, now)
+	}
+	rate := estimator.importRate()
+	if math.Abs(rate-5) > 0.01 {
+		t.Fatalf("import rate mismatch: have %f, want 5", rate)
+	}
+
+	result := newSyncProgressResult(100, number, number+1000, rate)
+	if result.ETA == nil || *result.ETA != 200 {
+		t.Errorf("ETA mismatch: have %v, want 200", result.ETA)
+	}
+	if uint64(result.CurrentBlock) != number || uint64(result.HighestBlock) != number+1000 {
+		t.Errorf("block range mismatch: have %d-%d, want %d-%d", result.CurrentBlock, result.HighestBlock, number, number+1000)
+	}
+
+	// A stall decays the rate and increases the ETA.
+	estimator.update(number, now.Add(syncRateCycle))
+	if stalled := estimator.importRate(); stalled >= rate {
+		t.Errorf("import rate not decayed by a stall: have %f, before %f", stalled, rate)
+	}
+
+	// A synced node has a zero ETA, and the ETA is unknown without imports.
+	if result := newSyncProgressResult(0, number, number, 0); result.ETA == nil || *result.ETA != 0 {
+		t.Errorf("synced ETA mismatch: have %v, want 0", result.ETA)
+	}
+	if result := newSyncProgressResult(0, number, number+1, 0); result.ETA != nil {
+		t.Errorf("ETA without imports: have %d, want unknown", *result.ETA)
+	}
+}
@@ -30,11 +30,13 @@ import (
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/networks/rpc"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 const (
@@ -87,6 +89,10 @@ type Config struct {
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
 
+	// MaxUnlockedAccounts is the maximum number of keystore accounts which can be
+	// unlocked at the same time. Zero means unlimited.
+	MaxUnlockedAccounts int `toml:",omitempty"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -150,6 +156,19 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// WSSubscriptionBuffer is the maximum number of notifications buffered for
+	// each websocket subscription. Zero means notifications are written
+	// synchronously without buffering.
+	WSSubscriptionBuffer int `toml:",omitempty"`
+
+	// WSSubscriptionBufferPolicy is applied when the buffer of a websocket
+	// subscription is full: "drop-oldest" or "disconnect".
+	WSSubscriptionBufferPolicy rpc.SubscriptionBufferPolicy `toml:",omitempty"`
+
+	// RPCSlowLogThreshold is the duration after which an RPC call is logged as
+	// slow. It applies to every RPC endpoint. Zero disables the slow call log.
+	RPCSlowLogThreshold time.Duration `toml:",omitempty"`
+
 	// GRPCHost is the host interface on which to start the gRPC server. If
 	// this field is empty, no gRPC API endpoint will be started.
 	GRPCHost string `toml:",omitempty"`
@@ -252,6 +271,29 @@ func DefaultGRPCEndpoint() string {
 	return config.GRPCEndpoint()
 }
 
+// EffectiveConfig returns the resolved settings of the node reported for support
+// purposes. The node key is not included.
+func (c *Config) EffectiveConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"dataDir": c.DataDir,
+		"dbType":  c.DBType,
+		"p2p": map[string]interface{}{
+			"listenAddr":             c.P2P.ListenAddr,
+			"maxPhysicalConnections": c.P2P.MaxPhysicalConnections,
+			"connectionType":         c.P2P.ConnectionType,
+			"noDiscovery":            c.P2P.NoDiscovery,
+		},
+		"rpc": map[string]interface{}{
+			"ipc":         c.IPCEndpoint(),
+			"http":        c.HTTPEndpoint(),
+			"httpModules": c.HTTPModules,
+			"ws":          c.WSEndpoint(),
+			"wsModules":   c.WSModules,
+			"grpc":        c.GRPCEndpoint(),
+		},
+	}
+}
+
 // NodeName returns the devp2p node identifier.
 func (c *Config) NodeName() string {
 	name := c.name()
@@ -443,8 +485,10 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 		return nil, "", err
 	}
 	// Assemble the account manager and supported backends
+	ks := keystore.NewKeyStore(keydir, scryptN, scryptP)
+	ks.SetMaxUnlocked(conf.MaxUnlockedAccounts)
 	backends := []accounts.Backend{
-		keystore.NewKeyStore(keydir, scryptN, scryptP),
+		ks,
 	}
 	return accounts.NewManager(backends...), ephemeral, nil
 }
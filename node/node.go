@@ -362,7 +362,7 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		}
 	}
 	// start gRPC server
-	if err := n.startgRPC(apis); err != nil {
+	if err := n.startgRPC(apis, chainHeadFeed(services)); err != nil {
 		n.stopHTTP()
 		n.stopIPC()
 		n.stopInProc()
@@ -378,6 +378,7 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 func (n *Node) startInProc(apis []rpc.API) error {
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	for _, api := range apis {
 		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 			return err
@@ -405,6 +406,7 @@ func (n *Node) startIPC(apis []rpc.API) error {
 	if err != nil {
 		return err
 	}
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	n.ipcListener = listener
 	n.ipcHandler = handler
 	n.logger.Info("IPC endpoint opened", "url", n.ipcEndpoint)
@@ -425,13 +427,27 @@ func (n *Node) stopIPC() {
 	}
 }
 
+// chainHeadFeed returns the component of the given services providing the chain
+// head events, or nil if there is no such component.
+func chainHeadFeed(services map[reflect.Type]Service) grpc.ChainHeadFeed {
+	for _, service := range services {
+		for _, component := range service.Components() {
+			if feed, ok := component.(grpc.ChainHeadFeed); ok {
+				return feed
+			}
+		}
+	}
+	return nil
+}
+
 // startgRPC initializes and starts the gRPC endpoint.
-func (n *Node) startgRPC(apis []rpc.API) error {
+func (n *Node) startgRPC(apis []rpc.API, feed grpc.ChainHeadFeed) error {
 	if n.grpcEndpoint == "" {
 		return nil
 	}
 
 	handler := rpc.NewServer()
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	for _, api := range apis {
 		if api.Public {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
@@ -445,6 +461,7 @@ func (n *Node) startgRPC(apis []rpc.API) error {
 	n.grpcHandler = handler
 	n.grpcListener = listener
 	listener.SetRPCServer(handler)
+	listener.SetChainHeadFeed(feed)
 
 	go listener.Start()
 	n.logger.Info("gRPC endpoint opened", "url", n.grpcEndpoint)
@@ -461,6 +478,7 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if err != nil {
 		return err
 	}
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	n.logger.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","))
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
@@ -480,6 +498,7 @@ func (n *Node) startFastHTTP(endpoint string, apis []rpc.API, modules []string,
 	if err != nil {
 		return err
 	}
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	n.logger.Info("FastHTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","))
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
@@ -503,16 +522,41 @@ func (n *Node) stopHTTP() {
 	}
 }
 
+// rpcHandler returns the running RPC request handler of the given transport.
+// The caller must hold n.lock.
+func (n *Node) rpcHandler(transport string) (*rpc.Server, error) {
+	var handler *rpc.Server
+	switch transport {
+	case "inproc":
+		handler = n.inprocHandler
+	case "ipc":
+		handler = n.ipcHandler
+	case "http":
+		handler = n.httpHandler
+	case "ws":
+		handler = n.wsHandler
+	case "grpc":
+		handler = n.grpcHandler
+	default:
+		return nil, fmt.Errorf("unknown RPC transport %q", transport)
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("%s RPC not running", transport)
+	}
+	return handler, nil
+}
+
 // startWS initializes and starts the websocket RPC endpoint.
 func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins []string, exposeAll bool) error {
 	// Short circuit if the WS endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, n.config.WSSubscriptionBuffer, n.config.WSSubscriptionBufferPolicy)
 	if err != nil {
 		return err
 	}
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	n.logger.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()))
 	// All listeners booted successfully
 	n.wsEndpoint = endpoint
@@ -528,10 +572,11 @@ func (n *Node) startFastWS(endpoint string, apis []rpc.API, modules []string, ws
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartFastWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartFastWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, n.config.WSSubscriptionBuffer, n.config.WSSubscriptionBufferPolicy)
 	if err != nil {
 		return err
 	}
+	handler.SetSlowLogThreshold(n.config.RPCSlowLogThreshold)
 	n.logger.Info("FastWebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()))
 	// All listeners booted successfully
 	n.wsEndpoint = endpoint
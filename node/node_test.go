@@ -577,3 +577,68 @@ func TestAPIGather(t *testing.T) {
 		}
 	}
 }
+
+// Tests that a namespace disabled on the HTTP transport rejects its calls while
+// the other namespaces and transports keep serving.
+func TestSetNamespaceEnabled(t *testing.T) {
+	config := testNodeConfig()
+	config.HTTPHost = "127.0.0.1"
+	config.HTTPModules = []string{"debug", "klay"}
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	inproc, err := stack.Attach()
+	if err != nil {
+		t.Fatalf("failed to connect to the inproc API server: %v", err)
+	}
+	defer inproc.Close()
+	http, err := rpc.DialHTTP("http://" + stack.httpListener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect to the HTTP API server: %v", err)
+	}
+	defer http.Close()
+
+	var metrics map[string]interface{}
+	if err := http.Call(&metrics, "debug_metrics", false); err != nil {
+		t.Fatalf("debug_metrics failed before disabling debug: %v", err)
+	}
+
+	var ok bool
+	if err := inproc.Call(&ok, "admin_setNamespaceEnabled", "http", "debug", false); err != nil || !ok {
+		t.Fatalf("failed to disable debug over HTTP: %v", err)
+	}
+	if err := http.Call(&metrics, "debug_metrics", false); err == nil {
+		t.Fatal("debug_metrics succeeded over HTTP while debug is disabled")
+	}
+	var version string
+	if err := http.Call(&version, "klay_clientVersion"); err != nil {
+		t.Fatalf("klay_clientVersion failed over HTTP: %v", err)
+	}
+	if err := inproc.Call(&metrics, "debug_metrics", false); err != nil {
+		t.Fatalf("debug_metrics failed over inproc: %v", err)
+	}
+
+	if err := inproc.Call(&ok, "admin_setNamespaceEnabled", "http", "debug", true); err != nil || !ok {
+		t.Fatalf("failed to enable debug over HTTP: %v", err)
+	}
+	if err := http.Call(&metrics, "debug_metrics", false); err != nil {
+		t.Fatalf("debug_metrics failed after enabling debug: %v", err)
+	}
+
+	if err := inproc.Call(&ok, "admin_setNamespaceEnabled", "ws", "debug", false); err == nil {
+		t.Fatal("expected an error for a transport which isn't running")
+	}
+
+	if err := inproc.Call(&ok, "admin_setNamespaceEnabled", "inproc", "admin", false); err == nil {
+		t.Fatal("expected an error for disabling the admin namespace")
+	}
+	if err := inproc.Call(&ok, "admin_setNamespaceEnabled", "inproc", "debug", false); err != nil || !ok {
+		t.Fatalf("admin namespace is not available after rejecting to disable it: %v", err)
+	}
+}
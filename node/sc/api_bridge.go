@@ -28,7 +28,8 @@ import (
 )
 
 var (
-	ErrInvalidBridgePair = errors.New("invalid bridge pair")
+	ErrInvalidBridgePair     = errors.New("invalid bridge pair")
+	ErrNoMainChainConnection = errors.New("main chain connection is not configured")
 )
 
 // MainBridgeAPI Implementation for main-bridge node
@@ -427,6 +428,15 @@ func (sbapi *SubBridgeAPI) NodeInfo() (*p2p.NodeInfo, error) {
 	return server.NodeInfo(), nil
 }
 
+// GetMainChainConnectionStatus returns the status of the connection to the main chain.
+func (sbapi *SubBridgeAPI) GetMainChainConnectionStatus() (*RemoteConnectionStatus, error) {
+	rb, ok := sbapi.sc.remoteBackend.(*RemoteBackend)
+	if !ok || rb == nil {
+		return nil, ErrNoMainChainConnection
+	}
+	return rb.Status(), nil
+}
+
 func (sbapi *SubBridgeAPI) GetMainChainAccountAddr() string {
 	return sbapi.sc.config.MainChainAccountAddr.Hex()
 }
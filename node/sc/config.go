@@ -97,7 +97,7 @@ type SCConfig struct {
 	SentChainTxsLimit       uint64
 
 	ParentChainID      uint64
-	MainChainURL       string
+	MainChainURL       string // comma separated urls are used in turn on failover
 	VTRecovery         bool
 	VTRecoveryInterval uint64
 }
@@ -20,7 +20,6 @@ import (
 	"errors"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
-	"github.com/klaytn/klaytn/ser/rlp"
 )
 
 var (
@@ -107,14 +106,9 @@ func (mce *MainChainEventHandler) writeChildChainTxHashFromBlock(block *types.Bl
 				continue
 			}
 
-			chainHashes := new(types.ChainHashes)
-			data, err := tx.AnchoredData()
+			chainHashes, err := types.DecodeAnchoringData(tx)
 			if err != nil {
-				logger.Error("writeChildChainTxHashFromBlock : failed to get anchoring data from the tx", "txHash", tx.Hash().String())
-				continue
-			}
-			if err := rlp.DecodeBytes(data, chainHashes); err != nil {
-				logger.Error("writeChildChainTxHashFromBlock : failed to decode anchoring data")
+				logger.Error("writeChildChainTxHashFromBlock : failed to decode anchoring data from the tx", "txHash", tx.Hash().String(), "err", err)
 				continue
 			}
 			mce.mainbridge.chainDB.WriteChildChainTxHash(chainHashes.BlockHash, tx.Hash())
@@ -24,49 +24,89 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/pkg/errors"
 	"math/big"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 var (
 	ConnectionFailErr = errors.New("fail to connect remote chain")
 )
 
+const (
+	minReconnectBackoff = 1 * time.Second
+	maxReconnectBackoff = 1 * time.Minute
+)
+
+// RemoteConnectionStatus is the status of the connection to the remote chain.
+type RemoteConnectionStatus struct {
+	URL         string    `json:"url"`
+	URLs        []string  `json:"urls"`
+	Connected   bool      `json:"connected"`
+	Reconnects  uint64    `json:"reconnects"`
+	NextAttempt time.Time `json:"nextAttempt"`
+}
+
 // TODO-Klaytn currently RemoteBackend is only for ServiceChain, especially Bridge SmartContract
 type RemoteBackend struct {
-	subBrige  *SubBridge
-	targetUrl string
+	subBrige   *SubBridge
+	targetUrls []string // targetUrls are the remote chain urls which are used in turn on failover
 
 	klayClient *client.Client
+
+	mu          sync.Mutex // mu protects the fields below
+	urlIdx      int        // urlIdx is the index of the url currently used
+	backoff     time.Duration
+	nextAttempt time.Time
+	reconnects  uint64
+
+	dial func(rawUrl string) (*client.Client, error)
 }
 
+// NewRemoteBackend returns a RemoteBackend connected to one of the comma separated urls.
 func NewRemoteBackend(main *SubBridge, rawUrl string) (*RemoteBackend, error) {
-	client, err := client.Dial(rawUrl)
-	if err != nil {
-		logger.Error("fail to connect RemoteChain", "url", rawUrl, "err", err)
-		client = nil
-	} else {
-		logger.Info("success to connect RemoteChain", "url", rawUrl)
+	var urls []string
+	for _, url := range strings.Split(rawUrl, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, errors.New("no remote chain url")
 	}
 
-	return &RemoteBackend{
+	rb := &RemoteBackend{
 		subBrige:   main,
-		targetUrl:  rawUrl,
-		klayClient: client,
-	}, nil
+		targetUrls: urls,
+		dial:       client.Dial,
+	}
+	for idx, url := range urls {
+		client, err := rb.dial(url)
+		if err != nil {
+			logger.Error("fail to connect RemoteChain", "url", url, "err", err)
+			continue
+		}
+		logger.Info("success to connect RemoteChain", "url", url)
+		rb.klayClient = client
+		rb.urlIdx = idx
+		break
+	}
+	return rb, nil
 }
 
 func (rb *RemoteBackend) checkConnection() bool {
 	if rb.klayClient == nil {
 		logger.Error("klayclient is nil so try to reconnect")
-		return rb.tryReconnect()
+		return rb.tryReconnect(false)
 	}
 	if atomic.CompareAndSwapInt64(&rb.subBrige.checkConnection, 1, 0) {
 		rb.klayClient.Close()
 		logger.Error("klayclient is disconnected so try to reconnect")
-		connected := rb.tryReconnect()
+		connected := rb.tryReconnect(true)
 		if !connected {
 			atomic.StoreInt64(&rb.subBrige.checkConnection, 1)
-		} else {
+		} else if rb.subBrige.bridgeManager != nil {
 			rb.subBrige.bridgeManager.ResetAllSubscribedEvents()
 		}
 		return connected
@@ -74,16 +114,59 @@ func (rb *RemoteBackend) checkConnection() bool {
 	return true
 }
 
-func (rb *RemoteBackend) tryReconnect() bool {
-	client, err := client.Dial(rb.targetUrl)
-	if err != nil {
-		logger.Error("fail to reconnect RemoteChain", "url", rb.targetUrl, "err", err)
+// tryReconnect tries to connect the remote chain urls in turn. If next is true, the
+// url after the currently used one is tried first. When every url fails, further
+// attempts are suspended with an exponential backoff.
+func (rb *RemoteBackend) tryReconnect(next bool) bool {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if time.Now().Before(rb.nextAttempt) {
 		return false
 	}
-	logger.Info("success to reconnect RemoteChain", "url", rb.targetUrl)
+	if next {
+		rb.urlIdx = (rb.urlIdx + 1) % len(rb.targetUrls)
+	}
+	for i := 0; i < len(rb.targetUrls); i++ {
+		idx := (rb.urlIdx + i) % len(rb.targetUrls)
+		url := rb.targetUrls[idx]
+		client, err := rb.dial(url)
+		if err != nil {
+			logger.Error("fail to reconnect RemoteChain", "url", url, "err", err)
+			continue
+		}
+		logger.Info("success to reconnect RemoteChain", "url", url)
+
+		rb.klayClient = client
+		rb.urlIdx = idx
+		rb.backoff = 0
+		rb.nextAttempt = time.Time{}
+		rb.reconnects++
+		return true
+	}
 
-	rb.klayClient = client
-	return true
+	if rb.backoff *= 2; rb.backoff < minReconnectBackoff {
+		rb.backoff = minReconnectBackoff
+	} else if rb.backoff > maxReconnectBackoff {
+		rb.backoff = maxReconnectBackoff
+	}
+	rb.nextAttempt = time.Now().Add(rb.backoff)
+	logger.Warn("fail to reconnect every RemoteChain url", "retryAfter", rb.backoff)
+	return false
+}
+
+// Status returns the status of the connection to the remote chain.
+func (rb *RemoteBackend) Status() *RemoteConnectionStatus {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	return &RemoteConnectionStatus{
+		URL:         rb.targetUrls[rb.urlIdx],
+		URLs:        rb.targetUrls,
+		Connected:   rb.klayClient != nil && atomic.LoadInt64(&rb.subBrige.checkConnection) == 0,
+		Reconnects:  rb.reconnects,
+		NextAttempt: rb.nextAttempt,
+	}
 }
 
 func (rb *RemoteBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
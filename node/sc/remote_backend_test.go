@@ -0,0 +1,117 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"context"
+	"github.com/klaytn/klaytn/client"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type TestChainIDService struct {
+	chainID *big.Int
+}
+
+func (s *TestChainIDService) ChainID() *hexutil.Big {
+	return (*hexutil.Big)(s.chainID)
+}
+
+// testParentChain is a websocket RPC endpoint serving klay_chainID.
+type testParentChain struct {
+	rpcServer  *rpc.Server
+	httpServer *httptest.Server
+	url        string
+}
+
+func newTestParentChain(t *testing.T, chainID int64) *testParentChain {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("klay", &TestChainIDService{big.NewInt(chainID)}))
+	httpServer := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+	return &testParentChain{
+		rpcServer:  rpcServer,
+		httpServer: httpServer,
+		url:        "ws" + strings.TrimPrefix(httpServer.URL, "http"),
+	}
+}
+
+// drop stops the endpoint and closes its connections.
+func (c *testParentChain) drop() {
+	c.rpcServer.Stop()
+	c.httpServer.Close()
+}
+
+// TestRemoteBackendFailover tests that a dropped parent chain connection is
+// replaced by the next url, and reconnection is backed off when every url fails.
+func TestRemoteBackendFailover(t *testing.T) {
+	chain1 := newTestParentChain(t, 1001)
+	chain2 := newTestParentChain(t, 1002)
+	defer chain2.drop()
+
+	sb := &SubBridge{}
+	rb, err := NewRemoteBackend(sb, chain1.url+", "+chain2.url)
+	require.NoError(t, err)
+
+	var dials int32
+	rb.dial = func(rawUrl string) (*client.Client, error) {
+		atomic.AddInt32(&dials, 1)
+		return client.Dial(rawUrl)
+	}
+
+	chainID, err := rb.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1001), chainID.Int64())
+	assert.Equal(t, chain1.url, rb.Status().URL)
+	assert.True(t, rb.Status().Connected)
+
+	// The bridge handler marks the connection to be checked when the peer is dropped.
+	chain1.drop()
+	atomic.StoreInt64(&sb.checkConnection, 1)
+
+	chainID, err = rb.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1002), chainID.Int64())
+
+	status := rb.Status()
+	assert.Equal(t, chain2.url, status.URL)
+	assert.Equal(t, []string{chain1.url, chain2.url}, status.URLs)
+	assert.True(t, status.Connected)
+	assert.Equal(t, uint64(1), status.Reconnects)
+
+	// Every url fails, so the next attempt is backed off.
+	chain2.drop()
+	atomic.StoreInt64(&sb.checkConnection, 1)
+
+	_, err = rb.ChainID(context.Background())
+	assert.Equal(t, ConnectionFailErr, err)
+	status = rb.Status()
+	assert.False(t, status.Connected)
+	assert.True(t, status.NextAttempt.After(time.Now()))
+
+	dialed := atomic.LoadInt32(&dials)
+	_, err = rb.ChainID(context.Background())
+	assert.Equal(t, ConnectionFailErr, err)
+	assert.Equal(t, dialed, atomic.LoadInt32(&dials), "reconnection should wait for the backoff")
+}
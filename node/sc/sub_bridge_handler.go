@@ -339,14 +339,9 @@ func (sbh *SubBridgeHandler) writeServiceChainTxReceipts(bc *blockchain.BlockCha
 		txHash := receipt.TxHash
 		if tx := sbh.subbridge.GetBridgeTxPool().Get(txHash); tx != nil {
 			if tx.Type() == types.TxTypeChainDataAnchoring {
-				chainHashes := new(types.ChainHashes)
-				data, err := tx.AnchoredData()
+				chainHashes, err := types.DecodeAnchoringData(tx)
 				if err != nil {
-					logger.Error("failed to get anchoring tx type from the tx", "txHash", txHash.String())
-					return
-				}
-				if err := rlp.DecodeBytes(data, chainHashes); err != nil {
-					logger.Error("failed to RLP decode ChainHashes", "txHash", txHash.String())
+					logger.Error("failed to decode anchoring data from the tx", "txHash", txHash.String(), "err", err)
 					return
 				}
 				sbh.WriteReceiptFromParentChain(chainHashes.BlockHash, (*types.Receipt)(receipt))
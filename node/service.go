@@ -70,6 +70,11 @@ func (ctx *ServiceContext) NodeKey() *ecdsa.PrivateKey {
 	return ctx.config.NodeKey()
 }
 
+// EffectiveConfig returns the resolved settings of the node reported for support purposes.
+func (ctx *ServiceContext) EffectiveConfig() map[string]interface{} {
+	return ctx.config.EffectiveConfig()
+}
+
 func (ctx *ServiceContext) NodeType() p2p.ConnType {
 	return ctx.config.P2P.ConnectionType
 }
@@ -254,6 +254,28 @@ func (api *PrivateAdminAPI) StopWS() (bool, error) {
 	return true, nil
 }
 
+// SetNamespaceEnabled enables or disables an API namespace on a running RPC
+// transport ("inproc", "ipc", "http", "ws" or "grpc"). Calls to a disabled
+// namespace are rejected until it is enabled again or the transport restarts.
+// The admin namespace cannot be disabled, since it is needed to enable the
+// namespaces again.
+func (api *PrivateAdminAPI) SetNamespaceEnabled(transport, namespace string, enabled bool) (bool, error) {
+	if namespace == "admin" && !enabled {
+		return false, errAdminNamespaceDisabled
+	}
+	api.node.lock.Lock()
+	defer api.node.lock.Unlock()
+
+	handler, err := api.node.rpcHandler(transport)
+	if err != nil {
+		return false, err
+	}
+	if err := handler.SetNamespaceEnabled(namespace, enabled); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // PublicAdminAPI is the collection of administrative API methods exposed over
 // both secure and unsecure RPC channels.
 type PublicAdminAPI struct {
@@ -24,6 +24,9 @@ import (
 	"fmt"
 	"github.com/klaytn/klaytn/common"
 	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // Genesis hashes to enforce below configs on.
@@ -126,6 +129,8 @@ type ChainConfig struct {
 	UnitPrice     uint64            `json:"unitPrice"`
 	DeriveShaImpl int               `json:"deriveShaImpl"`
 	Governance    *GovernanceConfig `json:"governance"`
+
+	GasPriceFloorBlock *big.Int `json:"gasPriceFloorBlock,omitempty"` // GasPriceFloor switch block (nil = no fork, 0 = already activated)
 }
 
 // GovernanceConfig stores governance information for a network
@@ -133,6 +138,7 @@ type GovernanceConfig struct {
 	GoverningNode  common.Address `json:"governingNode"`
 	GovernanceMode string         `json:"governanceMode"`
 	Reward         *RewardConfig  `json:"reward,omitempty"`
+	GasPriceFloors string         `json:"gasPriceFloors,omitempty"` // per-tx-type gas price floors, see ParseGasPriceFloors
 }
 
 func (g *GovernanceConfig) DeferredTxFee() bool {
@@ -212,6 +218,12 @@ func (c *ChainConfig) String() string {
 	}
 }
 
+// GetUnitPrice returns the unit price of the chain. Klaytn has no tip or fee cap;
+// every transaction must pay exactly this price per gas.
+func (c *ChainConfig) GetUnitPrice() *big.Int {
+	return new(big.Int).SetUint64(c.UnitPrice)
+}
+
 // GasTable returns the gas table corresponding to the current phase.
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -238,9 +250,149 @@ func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *Confi
 }
 
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
+	if isForkIncompatible(c.GasPriceFloorBlock, newcfg.GasPriceFloorBlock, head) {
+		return newCompatError("GasPriceFloor fork block", c.GasPriceFloorBlock, newcfg.GasPriceFloorBlock)
+	}
 	return nil
 }
 
+// IsGasPriceFloorForkEnabled returns whether num is either equal to the GasPriceFloor fork block or greater.
+// After the fork, a block containing a transaction priced below the governance floor of its type is invalid.
+func (c *ChainConfig) IsGasPriceFloorForkEnabled(num *big.Int) bool {
+	return isForked(c.GasPriceFloorBlock, num)
+}
+
+// GasPriceFloors returns the minimum gas prices of the transaction types configured in the
+// chain config, if enforced at num. It returns nil if no floor is enforced at num.
+func (c *ChainConfig) GasPriceFloors(num *big.Int) (map[uint64]*big.Int, error) {
+	if !c.IsGasPriceFloorForkEnabled(num) || c.Governance == nil {
+		return nil, nil
+	}
+	return ParseGasPriceFloors(c.Governance.GasPriceFloors)
+}
+
+// ParseGasPriceFloors parses a comma separated list of "txType:gasPrice" pairs, such as
+// "8:25000000000,16:50000000000", into a map from tx type to its gas price floor.
+// Both fields accept decimal or 0x-prefixed hexadecimal numbers. An empty string means no floor.
+func ParseGasPriceFloors(s string) (map[uint64]*big.Int, error) {
+	floors := make(map[uint64]*big.Int)
+	if strings.TrimSpace(s) == "" {
+		return floors, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		pair := strings.Split(strings.TrimSpace(item), ":")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid gas price floor %q: want txType:gasPrice", item)
+		}
+		txType, err := strconv.ParseUint(pair[0], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx type %q in gas price floor: %v", pair[0], err)
+		}
+		price, ok := new(big.Int).SetString(pair[1], 0)
+		if !ok || price.Sign() < 0 {
+			return nil, fmt.Errorf("invalid gas price %q in gas price floor", pair[1])
+		}
+		if _, exist := floors[txType]; exist {
+			return nil, fmt.Errorf("duplicated gas price floor for tx type %d", txType)
+		}
+		floors[txType] = price
+	}
+	return floors, nil
+}
+
+// Fork is a hardfork scheduled by a ChainConfig.
+type Fork struct {
+	Name  string
+	Block *big.Int // nil if the fork is not scheduled
+}
+
+// Forks returns the hardforks of the config in activation order. Klaytn-specific
+// hardforks are listed here as their activation blocks are added to ChainConfig.
+func (c *ChainConfig) Forks() []Fork {
+	return []Fork{
+		{Name: "gasPriceFloor", Block: c.GasPriceFloorBlock},
+	}
+}
+
+// ForkStatus describes whether a hardfork is active at a block.
+type ForkStatus struct {
+	Name   string   `json:"name"`
+	Block  *big.Int `json:"block"`
+	Active bool     `json:"active"`
+}
+
+// ForkStatuses returns whether each of the given forks is active at the head block.
+func ForkStatuses(forks []Fork, head *big.Int) []ForkStatus {
+	statuses := make([]ForkStatus, 0, len(forks))
+	for _, fork := range forks {
+		statuses = append(statuses, ForkStatus{Name: fork.Name, Block: fork.Block, Active: isForked(fork.Block, head)})
+	}
+	return statuses
+}
+
+// ForkID identifies the hardforks a node has crossed at its head block. It is
+// exchanged during the handshake so that peers left behind on an old fork can be
+// detected.
+type ForkID struct {
+	Passed []uint64 // Activation blocks of the forks active at the head block, in order
+	Next   uint64   // Activation block of the next scheduled fork, 0 if none
+}
+
+// ForkID returns the fork identifier of the config at the head block.
+func (c *ChainConfig) ForkID(head *big.Int) ForkID {
+	var id ForkID
+	for _, block := range c.forkBlocks() {
+		if !isForked(new(big.Int).SetUint64(block), head) {
+			id.Next = block
+			break
+		}
+		id.Passed = append(id.Passed, block)
+	}
+	return id
+}
+
+// StaleFork returns the activation block of a fork crossed by the config at the
+// head block which the given remote fork identifier has neither crossed nor
+// scheduled next. The returned bool is false if the remote is compatible.
+func (c *ChainConfig) StaleFork(head *big.Int, remote ForkID) (uint64, bool) {
+	local := c.ForkID(head)
+	for i, block := range local.Passed {
+		switch {
+		case i < len(remote.Passed):
+			if remote.Passed[i] != block {
+				return block, true
+			}
+		case remote.Next != block:
+			return block, true
+		default:
+			// The remote has scheduled the fork but has not reached it yet.
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// forkBlocks returns the distinct activation blocks of the scheduled forks in
+// ascending order.
+func (c *ChainConfig) forkBlocks() []uint64 {
+	var blocks []uint64
+	for _, fork := range c.Forks() {
+		if fork.Block == nil {
+			continue
+		}
+		blocks = append(blocks, fork.Block.Uint64())
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	var distinct []uint64
+	for i, block := range blocks {
+		if i == 0 || blocks[i-1] != block {
+			distinct = append(distinct, block)
+		}
+	}
+	return distinct
+}
+
 // isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
 // block s2 because head is already past the fork.
 func isForkIncompatible(s1, s2, head *big.Int) bool {
@@ -325,6 +477,7 @@ func (g *GovernanceConfig) Copy() *GovernanceConfig {
 	newConfig.Reward.UseGiniCoeff = g.Reward.UseGiniCoeff
 	newConfig.Reward.DeferredTxFee = g.Reward.DeferredTxFee
 	newConfig.GoverningNode = g.GoverningNode
+	newConfig.GasPriceFloors = g.GasPriceFloors
 
 	return newConfig
 }
@@ -0,0 +1,129 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestForkStatuses(t *testing.T) {
+	forks := []Fork{
+		{Name: "past", Block: big.NewInt(10)},
+		{Name: "head", Block: big.NewInt(100)},
+		{Name: "future", Block: big.NewInt(1000)},
+		{Name: "unscheduled", Block: nil},
+	}
+	want := map[string]bool{"past": true, "head": true, "future": false, "unscheduled": false}
+
+	statuses := ForkStatuses(forks, big.NewInt(100))
+	if len(statuses) != len(forks) {
+		t.Fatalf("fork count mismatch: have %d, want %d", len(statuses), len(forks))
+	}
+	for i, status := range statuses {
+		if status.Name != forks[i].Name || status.Block != forks[i].Block {
+			t.Errorf("fork %d mismatch: have %s at %v, want %s at %v", i, status.Name, status.Block, forks[i].Name, forks[i].Block)
+		}
+		if status.Active != want[status.Name] {
+			t.Errorf("fork %s active mismatch: have %v, want %v", status.Name, status.Active, want[status.Name])
+		}
+	}
+}
+
+func TestChainConfigForks(t *testing.T) {
+	for _, config := range []*ChainConfig{MainnetChainConfig, TestnetChainConfig, TestChainConfig} {
+		if forks := config.Forks(); forks == nil {
+			t.Errorf("forks of %v should not be nil", config)
+		}
+	}
+}
+
+func TestChainConfigStaleFork(t *testing.T) {
+	config := &ChainConfig{GasPriceFloorBlock: big.NewInt(10)}
+	head := big.NewInt(15)
+
+	if id := config.ForkID(big.NewInt(5)); len(id.Passed) != 0 || id.Next != 10 {
+		t.Errorf("fork id before the fork mismatch: %+v", id)
+	}
+	if id := config.ForkID(head); len(id.Passed) != 1 || id.Passed[0] != 10 || id.Next != 0 {
+		t.Errorf("fork id after the fork mismatch: %+v", id)
+	}
+
+	tests := []struct {
+		remote ForkID
+		stale  bool
+	}{
+		{ForkID{}, true},                          // fork not scheduled
+		{ForkID{Next: 10}, false},                 // syncing towards the fork
+		{ForkID{Passed: []uint64{10}}, false},     // crossed the fork
+		{ForkID{Passed: []uint64{12}}, true},      // crossed a fork at another block
+		{ForkID{Passed: []uint64{10, 20}}, false}, // ahead of us
+	}
+	for i, tt := range tests {
+		block, stale := config.StaleFork(head, tt.remote)
+		if stale != tt.stale {
+			t.Errorf("test %d: stale mismatch: have %v, want %v", i, stale, tt.stale)
+		}
+		if stale && block != 10 {
+			t.Errorf("test %d: stale fork block mismatch: have %d, want 10", i, block)
+		}
+	}
+}
+
+func TestParseGasPriceFloors(t *testing.T) {
+	floors, err := ParseGasPriceFloors(" 0:25, 0x8:0x32 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(floors) != 2 || floors[0].Int64() != 25 || floors[8].Int64() != 50 {
+		t.Errorf("unexpected floors: %v", floors)
+	}
+
+	if floors, err := ParseGasPriceFloors(""); err != nil || len(floors) != 0 {
+		t.Errorf("empty floors mismatch: have %v (err %v)", floors, err)
+	}
+	for _, s := range []string{"8", "8:", "x:1", "8:-1", "8:1,8:2", "8:1:2"} {
+		if _, err := ParseGasPriceFloors(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestGasPriceFloorForkGating(t *testing.T) {
+	config := &ChainConfig{
+		GasPriceFloorBlock: big.NewInt(10),
+		Governance:         &GovernanceConfig{GasPriceFloors: "8:25"},
+	}
+	if floors, err := config.GasPriceFloors(big.NewInt(9)); err != nil || floors != nil {
+		t.Errorf("floors before the fork: have %v (err %v), want nil", floors, err)
+	}
+	floors, err := config.GasPriceFloors(big.NewInt(10))
+	if err != nil || floors[8] == nil || floors[8].Int64() != 25 {
+		t.Errorf("floor at the fork: have %v (err %v), want 25", floors[8], err)
+	}
+	if floors[16] != nil {
+		t.Errorf("floor of an unlisted type: have %v, want nil", floors[16])
+	}
+
+	newConfig := &ChainConfig{GasPriceFloorBlock: big.NewInt(20)}
+	if err := config.CheckCompatible(newConfig, 15); err == nil {
+		t.Error("rescheduling an activated fork should be incompatible")
+	}
+	if err := config.CheckCompatible(newConfig, 5); err != nil {
+		t.Errorf("rescheduling a pending fork should be compatible: %v", err)
+	}
+}
@@ -87,6 +87,7 @@ const (
 	ProposerRefreshInterval
 	ConstTxGasHumanReadable
 	CliqueEpoch
+	GasPriceFloors
 )
 
 const (
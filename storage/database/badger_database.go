@@ -19,6 +19,7 @@ package database
 import (
 	"fmt"
 	"github.com/dgraph-io/badger"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/log"
 	"os"
 	"time"
@@ -37,15 +38,20 @@ type badgerDB struct {
 	logger log.Logger // Contextual logger tracking the database path
 }
 
-func getBadgerDBOptions(dbDir string) badger.Options {
+func getBadgerDBOptions(dbDir string, readOnly bool) badger.Options {
 	opts := badger.DefaultOptions
 	opts.Dir = dbDir
 	opts.ValueDir = dbDir
+	opts.ReadOnly = readOnly
 
 	return opts
 }
 
 func NewBadgerDB(dbDir string) (*badgerDB, error) {
+	return newBadgerDB(dbDir, false)
+}
+
+func newBadgerDB(dbDir string, readOnly bool) (*badgerDB, error) {
 	localLogger := logger.NewWith("dbDir", dbDir)
 
 	if fi, err := os.Stat(dbDir); err == nil {
@@ -60,7 +66,7 @@ func NewBadgerDB(dbDir string) (*badgerDB, error) {
 		return nil, fmt.Errorf("failed to make badgerDB while checking dbDir. dbDir: %v, err: %v", dbDir, err)
 	}
 
-	opts := getBadgerDBOptions(dbDir)
+	opts := getBadgerDBOptions(dbDir, readOnly)
 	db, err := badger.Open(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make badgerDB while opening the DB. dbDir: %v, err: %v", dbDir, err)
@@ -74,7 +80,9 @@ func NewBadgerDB(dbDir string) (*badgerDB, error) {
 		closeCh:  make(chan struct{}),
 	}
 
-	go badger.runValueLogGC()
+	if !readOnly {
+		go badger.runValueLogGC()
+	}
 
 	return badger, nil
 }
@@ -180,28 +188,67 @@ func (bg *badgerDB) LDB() *badger.DB {
 }
 
 func (bg *badgerDB) NewBatch() Batch {
-	txn := bg.db.NewTransaction(true)
-	return &badgerBatch{db: bg.db, txn: txn}
+	return &badgerBatch{db: bg.db}
 }
 
 func (bg *badgerDB) Meter(prefix string) {
 	logger.Warn("badgerDB does not support metrics!")
 }
 
+// Compact does nothing as badgerDB reclaims space by its own garbage collection.
+func (bg *badgerDB) Compact(start, limit []byte) error {
+	return nil
+}
+
+// badgerBatch buffers writes in memory until Write, like a LevelDB batch, so
+// nothing reaches the database before Write and Reset drops everything put
+// since the last Reset. Write commits the buffered writes in a transaction and
+// continues in a new one if the transaction grows too big, so that a batch can
+// hold as much data as a LevelDB batch. ValueSize reports the accumulated size
+// of the values put and the keys deleted since the last Reset, the same as the
+// batches of the other backends.
 type badgerBatch struct {
-	db   *badger.DB
-	txn  *badger.Txn
-	size int
+	db     *badger.DB
+	writes []kv
+	size   int
 }
 
 func (b *badgerBatch) Put(key, value []byte) error {
-	err := b.txn.Set(key, value)
+	b.writes = append(b.writes, kv{k: common.CopyBytes(key), v: common.CopyBytes(value)})
 	b.size += len(value)
-	return err
+	return nil
+}
+
+func (b *badgerBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, kv{k: common.CopyBytes(key), del: true})
+	b.size += len(key)
+	return nil
 }
 
 func (b *badgerBatch) Write() error {
-	return b.txn.Commit(nil)
+	txn := b.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+
+	apply := func(w kv) error {
+		if w.del {
+			return txn.Delete(w.k)
+		}
+		return txn.Set(w.k, w.v)
+	}
+	for _, w := range b.writes {
+		err := apply(w)
+		if err == badger.ErrTxnTooBig {
+			if err = txn.Commit(nil); err != nil {
+				return err
+			}
+			txn = b.db.NewTransaction(true)
+			err = apply(w)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return txn.Commit(nil)
 }
 
 func (b *badgerBatch) ValueSize() int {
@@ -209,6 +256,6 @@ func (b *badgerBatch) ValueSize() int {
 }
 
 func (b *badgerBatch) Reset() {
-	b.txn = b.db.NewTransaction(true)
+	b.writes = b.writes[:0]
 	b.size = 0
 }
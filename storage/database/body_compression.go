@@ -0,0 +1,87 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+// BodyCompressionType is the compression applied to block bodies when they are
+// stored. Bodies are always returned as plain RLP regardless of the type.
+type BodyCompressionType uint8
+
+const (
+	NoBodyCompression     BodyCompressionType = iota // Store block bodies as plain RLP
+	SnappyBodyCompression                            // Store block bodies snappy compressed
+)
+
+// bodyHeaderSnappy is prepended to the snappy compressed block bodies. A plain
+// RLP encoded body is a list starting with a byte of at least 0xc0, so a header
+// byte below it tells a compressed body from a legacy plain one. New encodings
+// must take another byte below 0xc0.
+const bodyHeaderSnappy byte = 0x01
+
+// ParseBodyCompressionType parses the name of a block body compression type.
+func ParseBodyCompressionType(name string) (BodyCompressionType, error) {
+	switch name {
+	case "none":
+		return NoBodyCompression, nil
+	case "snappy":
+		return SnappyBodyCompression, nil
+	default:
+		return NoBodyCompression, fmt.Errorf(`unknown body compression %q, want "none" or "snappy"`, name)
+	}
+}
+
+// String implements the stringer interface.
+func (ct BodyCompressionType) String() string {
+	switch ct {
+	case NoBodyCompression:
+		return "none"
+	case SnappyBodyCompression:
+		return "snappy"
+	default:
+		return "unknown"
+	}
+}
+
+// compressBody returns the RLP encoded block body as it is stored with the
+// given compression.
+func compressBody(ct BodyCompressionType, data []byte) []byte {
+	switch ct {
+	case SnappyBodyCompression:
+		return append([]byte{bodyHeaderSnappy}, snappy.Encode(nil, data)...)
+	default:
+		return data
+	}
+}
+
+// decompressBody returns the RLP encoded block body of a stored value, which
+// may be compressed or a plain legacy value.
+func decompressBody(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] >= 0xc0 {
+		return data, nil
+	}
+	switch data[0] {
+	case bodyHeaderSnappy:
+		return snappy.Decode(nil, data[1:])
+	default:
+		return nil, fmt.Errorf("unknown block body header %#x", data[0])
+	}
+}
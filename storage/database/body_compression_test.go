@@ -0,0 +1,125 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+// makeTestBodies returns the RLP encoded bodies of n blocks with a few signed
+// transactions each.
+func makeTestBodies(t testing.TB, n int) []rlp.RawValue {
+	key, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+
+	bodies := make([]rlp.RawValue, n)
+	for i := range bodies {
+		body := &types.Body{}
+		for j := 0; j < 3; j++ {
+			nonce := uint64(3*i + j)
+			tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{byte(j)}, big.NewInt(int64(i)), params.TxGas, big.NewInt(25000000000), nil), signer, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body.Transactions = append(body.Transactions, tx)
+		}
+		data, err := rlp.EncodeToBytes(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies[i] = data
+	}
+	return bodies
+}
+
+func TestBodyCompression(t *testing.T) {
+	bodies := makeTestBodies(t, 4)
+	empty, _ := rlp.EncodeToBytes(&types.Body{})
+	bodies = append(bodies, empty)
+
+	for _, ct := range []BodyCompressionType{NoBodyCompression, SnappyBodyCompression} {
+		stored := compressBody(ct, bodies[0])
+		if ct == NoBodyCompression {
+			assert.Equal(t, []byte(bodies[0]), stored)
+		} else {
+			assert.Equal(t, bodyHeaderSnappy, stored[0])
+		}
+		for i, body := range bodies {
+			decoded, err := decompressBody(compressBody(ct, body))
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(body, decoded), "%v: body %d mismatch", ct, i)
+		}
+	}
+	_, err := decompressBody([]byte{0x7f, 0x00})
+	assert.Error(t, err)
+}
+
+// Tests that bodies written with and without compression are both returned as
+// plain RLP, from the database as well as from the caches.
+func TestDBManager_BodyCompression(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	defer dbm.Close()
+	config := dbm.(*databaseManager).config
+
+	bodies := makeTestBodies(t, 2)
+	legacyHash, compressedHash := common.Hash{1}, common.Hash{2}
+
+	config.BodyCompression = NoBodyCompression
+	dbm.WriteBodyRLP(legacyHash, 1, bodies[0])
+	config.BodyCompression = SnappyBodyCompression
+	dbm.WriteBodyRLP(compressedHash, 2, bodies[1])
+
+	db := dbm.(*databaseManager).getDatabase(BodyDB)
+	stored, _ := db.Get(blockBodyKey(2, compressedHash))
+	assert.Equal(t, bodyHeaderSnappy, stored[0])
+
+	for i := 0; i < 2; i++ { // The second round is served by the cache
+		assert.Equal(t, bodies[0], dbm.ReadBodyRLP(legacyHash, 1))
+		assert.Equal(t, bodies[1], dbm.ReadBodyRLP(compressedHash, 2))
+	}
+	body := dbm.ReadBody(compressedHash, 2)
+	if assert.NotNil(t, body) {
+		assert.Len(t, body.Transactions, 3)
+	}
+}
+
+// BenchmarkBodyCompressionFootprint compares the stored size of the bodies of
+// a 10k-block sample with and without compression.
+func BenchmarkBodyCompressionFootprint(b *testing.B) {
+	bodies := makeTestBodies(b, 10000)
+	for _, ct := range []BodyCompressionType{NoBodyCompression, SnappyBodyCompression} {
+		b.Run(ct.String(), func(b *testing.B) {
+			size := 0
+			for i := 0; i < b.N; i++ {
+				size = 0
+				for _, body := range bodies {
+					size += len(compressBody(ct, body))
+				}
+			}
+			b.Logf("stored size of 10k blocks: %d bytes", size)
+		})
+	}
+}
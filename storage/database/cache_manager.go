@@ -277,6 +277,12 @@ func (cm *cacheManager) writeBlockNumberCache(hash common.Hash, number uint64) {
 	cm.blockNumberCache.Add(hash, number)
 }
 
+// deleteBlockNumberCache evicts the cached headerNumber of headerHash, so that
+// it is read again from the database.
+func (cm *cacheManager) deleteBlockNumberCache(hash common.Hash) {
+	cm.blockNumberCache.Remove(hash)
+}
+
 // readCanonicalHashCache looks for cached canonical hash in canonicalHashCache.
 // It returns empty hash if not found.
 func (cm *cacheManager) readCanonicalHashCache(number uint64) common.Hash {
@@ -175,6 +175,183 @@ func TestMemoryDB_ParallelPutGet(t *testing.T) {
 	testParallelPutGet(NewMemDB(), t)
 }
 
+func TestLDB_BatchValueSize(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testBatchValueSize(db, t)
+}
+
+func TestBadgerDB_BatchValueSize(t *testing.T) {
+	db, remove := newTestBadgerDB()
+	defer remove()
+	testBatchValueSize(db, t)
+}
+
+func TestMemoryDB_BatchValueSize(t *testing.T) {
+	testBatchValueSize(NewMemDB(), t)
+}
+
+// testBatchValueSize checks that a batch reports the accumulated size of its
+// values, even beyond the size of a single badger transaction, and that the
+// size resets on Reset.
+func testBatchValueSize(db Database, t *testing.T) {
+	const n = 10000
+	value := bytes.Repeat([]byte{0xff}, 2048)
+
+	batch := db.NewBatch()
+	for i := 0; i < n; i++ {
+		before := batch.ValueSize()
+		if err := batch.Put([]byte(fmt.Sprintf("key%05d", i)), value[:1024+i%1024]); err != nil {
+			t.Fatalf("put %d failed: %v", i, err)
+		}
+		if size := batch.ValueSize(); size != before+1024+i%1024 {
+			t.Fatalf("value size after put %d: have %d, want %d", i, size, before+1024+i%1024)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	for _, i := range []int{0, n / 2, n - 1} {
+		data, err := db.Get([]byte(fmt.Sprintf("key%05d", i)))
+		if err != nil {
+			t.Fatalf("get %d failed: %v", i, err)
+		}
+		if len(data) != 1024+i%1024 {
+			t.Fatalf("get %d returned wrong length: have %d, want %d", i, len(data), 1024+i%1024)
+		}
+	}
+
+	// A written batch keeps accepting writes until it is reset.
+	before := batch.ValueSize()
+	if err := batch.Put([]byte("extra"), value); err != nil {
+		t.Fatalf("put after write failed: %v", err)
+	}
+	if size := batch.ValueSize(); size != before+len(value) {
+		t.Fatalf("value size after write and put: have %d, want %d", size, before+len(value))
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("second write failed: %v", err)
+	}
+	if _, err := db.Get([]byte("extra")); err != nil {
+		t.Fatalf("get after second write failed: %v", err)
+	}
+
+	batch.Reset()
+	if size := batch.ValueSize(); size != 0 {
+		t.Fatalf("value size after reset: have %d, want 0", size)
+	}
+	if err := batch.Put([]byte("key"), value); err != nil {
+		t.Fatalf("put after reset failed: %v", err)
+	}
+	if size := batch.ValueSize(); size != len(value) {
+		t.Fatalf("value size after reset and put: have %d, want %d", size, len(value))
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("write after reset failed: %v", err)
+	}
+	if _, err := db.Get([]byte("key")); err != nil {
+		t.Fatalf("get after reset failed: %v", err)
+	}
+}
+
+func TestLDB_BatchReset(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testBatchReset(db, t)
+}
+
+func TestBadgerDB_BatchReset(t *testing.T) {
+	db, remove := newTestBadgerDB()
+	defer remove()
+	testBatchReset(db, t)
+}
+
+func TestMemoryDB_BatchReset(t *testing.T) {
+	testBatchReset(NewMemDB(), t)
+}
+
+// testBatchReset checks that nothing put in a batch reaches the database
+// before Write, even beyond the size of a single badger transaction, and that
+// Reset drops the buffered writes.
+func testBatchReset(db Database, t *testing.T) {
+	const n = 10000
+	value := bytes.Repeat([]byte{0xff}, 2048)
+
+	batch := db.NewBatch()
+	for i := 0; i < n; i++ {
+		if err := batch.Put([]byte(fmt.Sprintf("key%05d", i)), value); err != nil {
+			t.Fatalf("put %d failed: %v", i, err)
+		}
+	}
+	for _, i := range []int{0, n / 2, n - 1} {
+		if has, _ := db.Has([]byte(fmt.Sprintf("key%05d", i))); has {
+			t.Fatalf("key %d stored before write", i)
+		}
+	}
+	batch.Reset()
+	if err := batch.Write(); err != nil {
+		t.Fatalf("write after reset failed: %v", err)
+	}
+	for _, i := range []int{0, n / 2, n - 1} {
+		if has, _ := db.Has([]byte(fmt.Sprintf("key%05d", i))); has {
+			t.Fatalf("key %d stored after reset", i)
+		}
+	}
+}
+
+func TestLDB_BatchDelete(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testBatchDelete(db, t)
+}
+
+func TestBadgerDB_BatchDelete(t *testing.T) {
+	db, remove := newTestBadgerDB()
+	defer remove()
+	testBatchDelete(db, t)
+}
+
+func TestMemoryDB_BatchDelete(t *testing.T) {
+	testBatchDelete(NewMemDB(), t)
+}
+
+// testBatchDelete checks that the deletions in a batch are applied in order
+// with the puts only when the batch is written.
+func testBatchDelete(db Database, t *testing.T) {
+	for _, key := range []string{"a", "b"} {
+		if err := db.Put([]byte(key), []byte(key)); err != nil {
+			t.Fatalf("put %q failed: %v", key, err)
+		}
+	}
+
+	batch := db.NewBatch()
+	if err := batch.Delete([]byte("a")); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := batch.Delete([]byte("b")); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := batch.Put([]byte("b"), []byte("new")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if size := batch.ValueSize(); size != 5 {
+		t.Fatalf("value size mismatch: have %d, want 5", size)
+	}
+	if has, _ := db.Has([]byte("a")); !has {
+		t.Fatal("key deleted before write")
+	}
+
+	if err := batch.Write(); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if has, _ := db.Has([]byte("a")); has {
+		t.Fatal("deleted key is still stored")
+	}
+	if value, err := db.Get([]byte("b")); err != nil || !bytes.Equal(value, []byte("new")) {
+		t.Fatalf("value mismatch: have %q (%v), want %q", value, err, "new")
+	}
+}
+
 func TestShardDB(t *testing.T) {
 
 	key := common.Hex2Bytes("0x91d6f7d2537d8a0bd7d487dcc59151ebc00da306")
@@ -269,3 +446,43 @@ func TestDBEntryLengthCheck(t *testing.T) {
 		t.Fatalf("Sum of database configuration ratio should be 100! actual: %v", dbRatioSum)
 	}
 }
+
+func TestParsePartitionRatios(t *testing.T) {
+	ratios, err := ParsePartitionRatios("5, 10, 10, 60, 10, 3, 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{5, 10, 10, 60, 10, 3, 2}; fmt.Sprint(ratios) != fmt.Sprint(want) {
+		t.Fatalf("ratios mismatch: have %v, want %v", ratios, want)
+	}
+
+	for _, s := range []string{
+		"",                     // no ratio
+		"5,10,10,60,10,5",      // too few ratios
+		"5,10,10,60,10,3,1,1",  // too many ratios
+		"5,10,10,60,10,3,3",    // sum is not 100
+		"0,15,10,60,10,3,2",    // zero ratio
+		"5,10,10,sixty,10,3,2", // not a number
+	} {
+		if _, err := ParsePartitionRatios(s); err == nil {
+			t.Errorf("%q should be rejected", s)
+		}
+	}
+}
+
+func TestGetDBEntryConfig_PartitionRatioOverride(t *testing.T) {
+	dbc := &DBConfig{Dir: "chaindata", LevelDBCacheSize: 1000, OpenFilesLimit: 100}
+	if have := getDBEntryConfig(dbc, StateTrieDB).LevelDBCacheSize; have != 1000*dbConfigRatio[StateTrieDB]/100 {
+		t.Errorf("default statetrie cache size mismatch: have %d", have)
+	}
+
+	dbc.PartitionRatioOverride = []int{5, 10, 10, 60, 10, 3, 2}
+	stateTrieDBC := getDBEntryConfig(dbc, StateTrieDB)
+	if stateTrieDBC.LevelDBCacheSize != 600 || stateTrieDBC.OpenFilesLimit != 60 {
+		t.Errorf("statetrie config mismatch: have cache size %d and open files limit %d, want 600 and 60",
+			stateTrieDBC.LevelDBCacheSize, stateTrieDBC.OpenFilesLimit)
+	}
+	if have := getDBEntryConfig(dbc, headerDB).LevelDBCacheSize; have != 50 {
+		t.Errorf("header cache size mismatch: have %d, want 50", have)
+	}
+}
@@ -27,7 +27,7 @@ import (
 func NewLevelDBManagerForTest(dbc *DBConfig, levelDBOption *opt.Options) (DBManager, error) {
 	dbm := newDatabaseManager(dbc)
 
-	checkDBEntryConfigRatio()
+	checkDBEntryConfigRatio(dbc)
 
 	var ldb *levelDB
 	var err error
@@ -38,7 +38,7 @@ func NewLevelDBManagerForTest(dbc *DBConfig, levelDBOption *opt.Options) (DBMana
 			}
 		} else {
 			partitionDir := filepath.Join(dbc.Dir, dbDirs[i])
-			partitionLDBOption := getLevelDBOptionByPartition(levelDBOption, DBEntryType(i))
+			partitionLDBOption := getLevelDBOptionByPartition(dbc, levelDBOption, DBEntryType(i))
 			partitionLDBOption.Compression = getCompressionType(dbc.LevelDBCompression, DBEntryType(i))
 
 			ldb, err = NewLevelDBWithOption(partitionDir, partitionLDBOption)
@@ -59,9 +59,9 @@ func NewLevelDBManagerForTest(dbc *DBConfig, levelDBOption *opt.Options) (DBMana
 // getLevelDBOptionByPartition returns scaled LevelDB option from the given LevelDB option.
 // Some settings are not changed since they are not globally shared resources.
 // e.g., NoSync or CompactionTableSizeMultiplier
-func getLevelDBOptionByPartition(levelDBOption *opt.Options, i DBEntryType) *opt.Options {
+func getLevelDBOptionByPartition(dbc *DBConfig, levelDBOption *opt.Options, i DBEntryType) *opt.Options {
 	copiedLevelDBOption := *levelDBOption
-	ratio := dbConfigRatio[i]
+	ratio := dbEntryConfigRatio(dbc)[i]
 	copiedLevelDBOption.WriteBuffer = levelDBOption.WriteBuffer * ratio / 100
 
 	return &copiedLevelDBOption
@@ -20,23 +20,33 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/ser/rlp"
 	"github.com/pkg/errors"
+	"io"
 	"math/big"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var logger = log.NewModuleLogger(log.StorageDatabase)
 
 type DBManager interface {
 	IsParallelDBWrite() bool
+	IsWriteThroughCaching() bool
+	IsReadOnly() bool
 
 	Close()
+	Compact(start, limit []byte) error
 	NewBatch(dbType DBEntryType) Batch
+	BatchWriteLatency() time.Duration
 	GetMemDB() *MemDB
 
 	// from accessors_chain.go
@@ -45,6 +55,8 @@ type DBManager interface {
 	DeleteCanonicalHash(number uint64)
 
 	ReadHeaderNumber(hash common.Hash) *uint64
+	ReadCachedHeaderNumber(hash common.Hash) *uint64
+	InvalidateHeaderNumberCache(hash common.Hash)
 
 	ReadHeadHeaderHash() common.Hash
 	WriteHeadHeaderHash(hash common.Hash)
@@ -83,6 +95,10 @@ type DBManager interface {
 	WriteReceipts(hash common.Hash, number uint64, receipts types.Receipts)
 	PutReceiptsToBatch(batch Batch, hash common.Hash, number uint64, receipts types.Receipts)
 	DeleteReceipts(hash common.Hash, number uint64)
+	DeleteReceiptsFromBatch(batch Batch, hash common.Hash, number uint64)
+
+	ReadEarliestReceiptsBlockNumber() uint64
+	WriteEarliestReceiptsBlockNumber(blockNum uint64)
 
 	ReadBlock(hash common.Hash, number uint64) *types.Block
 	ReadBlockByHash(hash common.Hash) *types.Block
@@ -93,6 +109,9 @@ type DBManager interface {
 
 	FindCommonAncestor(a, b *types.Header) *types.Header
 
+	ExportBlockRange(from, to uint64, w io.Writer, marshalReceipt ReceiptMarshaller) error
+	ExportReceiptRange(from, to uint64, w io.Writer, marshalReceipt ReceiptMarshaller) error
+
 	ReadIstanbulSnapshot(hash common.Hash) ([]byte, error)
 	WriteIstanbulSnapshot(hash common.Hash, blob []byte) error
 
@@ -103,9 +122,12 @@ type DBManager interface {
 
 	ReadStateTrieNode(key []byte) ([]byte, error)
 	HasStateTrieNode(key []byte) (bool, error)
+	DeleteStateTrieNode(key []byte) error
+	IterateStateTrieKeys(fn func(key []byte) error) error
 
 	// from accessors_indexes.go
 	ReadTxLookupEntry(hash common.Hash) (common.Hash, uint64, uint64)
+	IterateTxLookupEntries(fn func(txHash common.Hash, blockHash common.Hash, blockIndex, index uint64) bool) error
 	WriteTxLookupEntries(block *types.Block)
 	WriteAndCacheTxLookupEntries(block *types.Block) error
 	PutTxLookupEntriesToBatch(batch Batch, block *types.Block)
@@ -117,6 +139,13 @@ type DBManager interface {
 	PutSenderTxHashToTxHashToBatch(batch Batch, senderTxHash, txHash common.Hash) error
 	ReadTxHashFromSenderTxHash(senderTxHash common.Hash) common.Hash
 
+	WriteAccountCreationInfo(addr common.Address, info *AccountCreationInfo) error
+	ReadAccountCreationInfo(addr common.Address) *AccountCreationInfo
+	DeleteAccountCreationInfo(addr common.Address) error
+
+	WriteAccountKeyChange(addr common.Address, change *AccountKeyChange) error
+	ReadAccountKeyHistory(addr common.Address, fromBlock, toBlock uint64) ([]*AccountKeyChange, error)
+
 	ReadReceipt(hash common.Hash) (*types.Receipt, common.Hash, uint64, uint64)
 
 	ReadBloomBits(bloomBitsKey []byte) ([]byte, error)
@@ -133,6 +162,12 @@ type DBManager interface {
 	ReadDatabaseVersion() int
 	WriteDatabaseVersion(version int)
 
+	ReadArchiveMode() (archive bool, stored bool)
+	WriteArchiveMode(archive bool)
+
+	ReadKeyFirstSeen(name string) (addr common.Address, since uint64, ok bool)
+	WriteKeyFirstSeen(name string, addr common.Address, since uint64)
+
 	ReadChainConfig(hash common.Hash) *params.ChainConfig
 	WriteChainConfig(hash common.Hash, cfg *params.ChainConfig)
 
@@ -214,23 +249,67 @@ var dbConfigRatio = [databaseEntryTypeSize]int{
 	5,  // bridgeServiceDB
 }
 
-// checkDBEntryConfigRatio checks if sum of dbConfigRatio is 100.
-// If it isn't, logger.Crit is called.
-func checkDBEntryConfigRatio() {
-	entryConfigRatioSum := 0
-	for i := 0; i < int(databaseEntryTypeSize); i++ {
-		entryConfigRatioSum += dbConfigRatio[i]
+// dbEntryConfigRatio returns the ratios of the partitions given by
+// dbc.PartitionRatioOverride, or dbConfigRatio if it is nil.
+func dbEntryConfigRatio(dbc *DBConfig) []int {
+	if dbc.PartitionRatioOverride != nil {
+		return dbc.PartitionRatioOverride
 	}
-	if entryConfigRatioSum != 100 {
-		logger.Crit("Sum of dbConfigRatio elements should be 100", "actual", entryConfigRatioSum)
+	return dbConfigRatio[:]
+}
+
+// checkDBEntryConfigRatio checks if the partition ratios of dbc are valid.
+// If they aren't, logger.Crit is called.
+func checkDBEntryConfigRatio(dbc *DBConfig) {
+	if err := validatePartitionRatios(dbEntryConfigRatio(dbc)); err != nil {
+		logger.Crit("Invalid partition ratios", "err", err)
 	}
 }
 
+// validatePartitionRatios checks if there is a positive ratio for every
+// DBEntryType and the ratios sum to 100.
+func validatePartitionRatios(ratios []int) error {
+	if len(ratios) != int(databaseEntryTypeSize) {
+		return fmt.Errorf("%d partition ratios are given, want %d (%s)", len(ratios), databaseEntryTypeSize, strings.Join(dbDirs[:], ","))
+	}
+	sum := 0
+	for i, ratio := range ratios {
+		if ratio <= 0 {
+			return fmt.Errorf("ratio of the %s partition should be positive, have %d", dbDirs[i], ratio)
+		}
+		sum += ratio
+	}
+	if sum != 100 {
+		return fmt.Errorf("sum of the partition ratios should be 100, have %d", sum)
+	}
+	return nil
+}
+
+// ParsePartitionRatios parses a comma-separated list of the ratios of the
+// partitions in the order of header, body, receipts, statetrie, txlookup, misc
+// and bridgeservice.
+func ParsePartitionRatios(s string) ([]int, error) {
+	fields := strings.Split(s, ",")
+	ratios := make([]int, len(fields))
+	for i, field := range fields {
+		ratio, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid partition ratio %q", field)
+		}
+		ratios[i] = ratio
+	}
+	if err := validatePartitionRatios(ratios); err != nil {
+		return nil, err
+	}
+	return ratios, nil
+}
+
 // getDBEntryConfig returns a new DBConfig with original DBConfig and DBEntryType.
-// It adjusts configuration according to the ratio specified in dbConfigRatio and dbDirs.
+// It adjusts configuration according to the partition ratio of the DBEntryType
+// and dbDirs.
 func getDBEntryConfig(originalDBC *DBConfig, i DBEntryType) *DBConfig {
 	newDBC := *originalDBC
-	ratio := dbConfigRatio[i]
+	ratio := dbEntryConfigRatio(originalDBC)[i]
 
 	newDBC.LevelDBCacheSize = originalDBC.LevelDBCacheSize * ratio / 100
 	newDBC.OpenFilesLimit = originalDBC.OpenFilesLimit * ratio / 100
@@ -242,18 +321,23 @@ func getDBEntryConfig(originalDBC *DBConfig, i DBEntryType) *DBConfig {
 }
 
 type databaseManager struct {
+	batchWriteLatency int64 // moving average of the batch write latency in nanoseconds, accessed atomically
+
 	config *DBConfig
 	dbs    []Database
 	cm     *cacheManager
+
+	writeThroughCaching bool
 }
 
 func NewMemoryDBManager() DBManager {
 	dbc := &DBConfig{DBType: MemoryDB}
 
 	dbm := databaseManager{
-		config: dbc,
-		dbs:    make([]Database, 1, 1),
-		cm:     newCacheManager(),
+		config:              dbc,
+		dbs:                 make([]Database, 1, 1),
+		cm:                  newCacheManager(),
+		writeThroughCaching: common.WriteThroughCaching,
 	}
 	dbm.dbs[0] = NewMemDB()
 
@@ -274,6 +358,25 @@ type DBConfig struct {
 	LevelDBCacheSize   int // LevelDBCacheSize = BlockCacheCapacity + WriteBuffer
 	LevelDBCompression LevelDBCompressionType
 	LevelDBBufferPool  bool
+
+	// Compression of the block bodies written from now on. Stored bodies are
+	// readable whatever compression they were written with.
+	BodyCompression BodyCompressionType
+
+	// WriteThroughCaching populates the caches of blocks, bodies and receipts
+	// when they are written. common.WriteThroughCaching enables it for every
+	// DBManager regardless of this field; it is deprecated in favor of this field.
+	WriteThroughCaching bool
+
+	// ReadOnly opens every database read-only. Writes fail with ErrReadOnlyDatabase.
+	// LevelDB takes a shared lock instead of an exclusive one in this mode, so
+	// several read-only instances can open the same copy of a database.
+	ReadOnly bool
+
+	// PartitionRatioOverride replaces dbConfigRatio, the split of LevelDBCacheSize
+	// and OpenFilesLimit among the partitions, if it is not nil. It has a ratio
+	// for every DBEntryType and the ratios sum to 100.
+	PartitionRatioOverride []int
 }
 
 const dbMetricPrefix = "klay/db/chaindata/"
@@ -321,12 +424,21 @@ func partitionedDatabaseDBManager(dbc *DBConfig) (DBManager, error) {
 }
 
 // newDatabase returns Database interface with given DBConfig.
+// If dbc.ReadOnly is set, the returned Database rejects every write.
 func newDatabase(dbc *DBConfig, entryType DBEntryType) (Database, error) {
+	db, err := openDatabase(dbc, entryType)
+	if err != nil || !dbc.ReadOnly {
+		return db, err
+	}
+	return newReadOnlyDB(db), nil
+}
+
+func openDatabase(dbc *DBConfig, entryType DBEntryType) (Database, error) {
 	switch dbc.DBType {
 	case LevelDB:
 		return NewLevelDB(dbc, entryType)
 	case BadgerDB:
-		return NewBadgerDB(dbc.Dir)
+		return newBadgerDB(dbc.Dir, dbc.ReadOnly)
 	case MemoryDB:
 		return NewMemDB(), nil
 	default:
@@ -338,10 +450,23 @@ func newDatabase(dbc *DBConfig, entryType DBEntryType) (Database, error) {
 // newDatabaseManager returns the pointer of databaseManager with default configuration.
 func newDatabaseManager(dbc *DBConfig) *databaseManager {
 	return &databaseManager{
-		config: dbc,
-		dbs:    make([]Database, databaseEntryTypeSize),
-		cm:     newCacheManager(),
+		config:              dbc,
+		dbs:                 make([]Database, databaseEntryTypeSize),
+		cm:                  newCacheManager(),
+		writeThroughCaching: dbc.WriteThroughCaching || common.WriteThroughCaching,
+	}
+}
+
+// logWriteError reports a failure of writing to a database. Writing to a
+// read-only database is expected to fail and is only logged as an error, so
+// that a read-only node keeps serving queries. Any other failure is critical.
+func logWriteError(err error, msg string, ctx ...interface{}) {
+	ctx = append(ctx, "err", err)
+	if err == ErrReadOnlyDatabase {
+		logger.Error(msg, ctx...)
+		return
 	}
+	logger.Crit(msg, ctx...)
 }
 
 // NewDBManager returns DBManager interface.
@@ -356,7 +481,7 @@ func NewDBManager(dbc *DBConfig) DBManager {
 			return dbm
 		}
 	} else {
-		checkDBEntryConfigRatio()
+		checkDBEntryConfigRatio(dbc)
 		logger.Info("Partitioned database is used for persistent storage", "DBType", dbc.DBType)
 		if dbm, err := partitionedDatabaseDBManager(dbc); err != nil {
 			logger.Crit("Failed to partitioned database", "DBType", dbc.DBType, "err", err)
@@ -372,8 +497,18 @@ func (dbm *databaseManager) IsParallelDBWrite() bool {
 	return dbm.config.ParallelDBWrite
 }
 
+// IsWriteThroughCaching returns if the caches are populated when data is written.
+func (dbm *databaseManager) IsWriteThroughCaching() bool {
+	return dbm.writeThroughCaching
+}
+
+// IsReadOnly returns if the databases are opened read-only.
+func (dbm *databaseManager) IsReadOnly() bool {
+	return dbm.config.ReadOnly
+}
+
 func (dbm *databaseManager) NewBatch(dbEntryType DBEntryType) Batch {
-	return dbm.getDatabase(dbEntryType).NewBatch()
+	return &timedBatch{Batch: dbm.getDatabase(dbEntryType).NewBatch(), dbm: dbm}
 }
 
 func (dbm *databaseManager) GetMemDB() *MemDB {
@@ -410,6 +545,20 @@ func (dbm *databaseManager) Close() {
 	}
 }
 
+// Compact compacts the given key range of every database, or of the single
+// shared database if the databases are not partitioned.
+func (dbm *databaseManager) Compact(start, limit []byte) error {
+	if !dbm.config.Partitioned {
+		return dbm.dbs[0].Compact(start, limit)
+	}
+	for i, db := range dbm.dbs {
+		if err := db.Compact(start, limit); err != nil {
+			return errors.Wrapf(err, "failed to compact %s database", dbDirs[i])
+		}
+	}
+	return nil
+}
+
 // TODO-Klaytn Some of below need to be invisible outside database package
 // Canonical Hash operations.
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
@@ -433,7 +582,8 @@ func (dbm *databaseManager) ReadCanonicalHash(number uint64) common.Hash {
 func (dbm *databaseManager) WriteCanonicalHash(hash common.Hash, number uint64) {
 	db := dbm.getDatabase(headerDB)
 	if err := db.Put(headerHashKey(number), hash.Bytes()); err != nil {
-		logger.Crit("Failed to store number to hash mapping", "err", err)
+		logWriteError(err, "Failed to store number to hash mapping")
+		return
 	}
 	dbm.cm.writeCanonicalHashCache(number, hash)
 }
@@ -442,7 +592,8 @@ func (dbm *databaseManager) WriteCanonicalHash(hash common.Hash, number uint64)
 func (dbm *databaseManager) DeleteCanonicalHash(number uint64) {
 	db := dbm.getDatabase(headerDB)
 	if err := db.Delete(headerHashKey(number)); err != nil {
-		logger.Crit("Failed to delete number to hash mapping", "err", err)
+		logWriteError(err, "Failed to delete number to hash mapping")
+		return
 	}
 	dbm.cm.writeCanonicalHashCache(number, common.Hash{})
 }
@@ -466,6 +617,18 @@ func (dbm *databaseManager) ReadHeaderNumber(hash common.Hash) *uint64 {
 	return &number
 }
 
+// ReadCachedHeaderNumber returns the header number cached for a hash, without
+// reading the database. It returns nil if the hash is not cached.
+func (dbm *databaseManager) ReadCachedHeaderNumber(hash common.Hash) *uint64 {
+	return dbm.cm.readBlockNumberCache(hash)
+}
+
+// InvalidateHeaderNumberCache evicts the header number cached for a hash, so
+// that the next ReadHeaderNumber reads it from the database.
+func (dbm *databaseManager) InvalidateHeaderNumberCache(hash common.Hash) {
+	dbm.cm.deleteBlockNumberCache(hash)
+}
+
 // Head Header Hash operations.
 // ReadHeadHeaderHash retrieves the hash of the current canonical head header.
 func (dbm *databaseManager) ReadHeadHeaderHash() common.Hash {
@@ -481,7 +644,7 @@ func (dbm *databaseManager) ReadHeadHeaderHash() common.Hash {
 func (dbm *databaseManager) WriteHeadHeaderHash(hash common.Hash) {
 	db := dbm.getDatabase(headerDB)
 	if err := db.Put(headHeaderKey, hash.Bytes()); err != nil {
-		logger.Crit("Failed to store last header's hash", "err", err)
+		logWriteError(err, "Failed to store last header's hash")
 	}
 }
 
@@ -499,7 +662,7 @@ func (dbm *databaseManager) ReadHeadBlockHash() common.Hash {
 func (dbm *databaseManager) WriteHeadBlockHash(hash common.Hash) {
 	db := dbm.getDatabase(headerDB)
 	if err := db.Put(headBlockKey, hash.Bytes()); err != nil {
-		logger.Crit("Failed to store last block's hash", "err", err)
+		logWriteError(err, "Failed to store last block's hash")
 	}
 }
 
@@ -518,7 +681,7 @@ func (dbm *databaseManager) ReadHeadFastBlockHash() common.Hash {
 func (dbm *databaseManager) WriteHeadFastBlockHash(hash common.Hash) {
 	db := dbm.getDatabase(headerDB)
 	if err := db.Put(headFastBlockKey, hash.Bytes()); err != nil {
-		logger.Crit("Failed to store last fast block's hash", "err", err)
+		logWriteError(err, "Failed to store last fast block's hash")
 	}
 }
 
@@ -539,7 +702,7 @@ func (dbm *databaseManager) ReadFastTrieProgress() uint64 {
 func (dbm *databaseManager) WriteFastTrieProgress(count uint64) {
 	db := dbm.getDatabase(MiscDB)
 	if err := db.Put(fastTrieProgressKey, new(big.Int).SetUint64(count).Bytes()); err != nil {
-		logger.Crit("Failed to store fast sync trie progress", "err", err)
+		logWriteError(err, "Failed to store fast sync trie progress")
 	}
 }
 
@@ -597,7 +760,8 @@ func (dbm *databaseManager) WriteHeader(header *types.Header) {
 	)
 	key := headerNumberKey(hash)
 	if err := db.Put(key, encoded); err != nil {
-		logger.Crit("Failed to store hash to number mapping", "err", err)
+		logWriteError(err, "Failed to store hash to number mapping")
+		return
 	}
 	// Write the encoded header
 	data, err := rlp.EncodeToBytes(header)
@@ -606,7 +770,8 @@ func (dbm *databaseManager) WriteHeader(header *types.Header) {
 	}
 	key = headerKey(number, hash)
 	if err := db.Put(key, data); err != nil {
-		logger.Crit("Failed to store header", "err", err)
+		logWriteError(err, "Failed to store header")
+		return
 	}
 
 	// Write to cache at the end of successful write.
@@ -618,10 +783,10 @@ func (dbm *databaseManager) WriteHeader(header *types.Header) {
 func (dbm *databaseManager) DeleteHeader(hash common.Hash, number uint64) {
 	db := dbm.getDatabase(headerDB)
 	if err := db.Delete(headerKey(number, hash)); err != nil {
-		logger.Crit("Failed to delete header", "err", err)
+		logWriteError(err, "Failed to delete header")
 	}
 	if err := db.Delete(headerNumberKey(hash)); err != nil {
-		logger.Crit("Failed to delete hash to number mapping", "err", err)
+		logWriteError(err, "Failed to delete hash to number mapping")
 	}
 
 	// Delete cache at the end of successful delete.
@@ -682,7 +847,7 @@ func (dbm *databaseManager) ReadBodyRLP(hash common.Hash, number uint64) rlp.Raw
 
 	// not found in cache, find body in database
 	db := dbm.getDatabase(BodyDB)
-	data, _ := db.Get(blockBodyKey(number, hash))
+	data := dbm.readStoredBody(db, blockBodyKey(number, hash))
 
 	// Write to cache at the end of successful read.
 	dbm.cm.writeBodyRLPCache(hash, data)
@@ -711,13 +876,24 @@ func (dbm *databaseManager) ReadBodyRLPByHash(hash common.Hash) rlp.RawValue {
 	}
 
 	db := dbm.getDatabase(BodyDB)
-	data, _ := db.Get(blockBodyKey(*number, hash))
+	data := dbm.readStoredBody(db, blockBodyKey(*number, hash))
 
 	// Write to cache at the end of successful read.
 	dbm.cm.writeBodyRLPCache(hash, data)
 	return data
 }
 
+// readStoredBody reads a stored block body and decompresses it to plain RLP.
+func (dbm *databaseManager) readStoredBody(db Database, key []byte) rlp.RawValue {
+	data, _ := db.Get(key)
+	bodyRLP, err := decompressBody(data)
+	if err != nil {
+		logger.Error("Failed to decompress block body", "key", common.Bytes2Hex(key), "err", err)
+		return nil
+	}
+	return bodyRLP
+}
+
 // readBodyRLPInCache retrieves the block body (transactions) in RLP encoding
 // in bodyRLPCache. It only searches cache.
 func (dbm *databaseManager) readBodyRLPInCache(hash common.Hash) rlp.RawValue {
@@ -739,18 +915,19 @@ func (dbm *databaseManager) PutBodyToBatch(batch Batch, hash common.Hash, number
 		logger.Crit("Failed to RLP encode body", "err", err)
 	}
 
-	if err := batch.Put(blockBodyKey(number, hash), data); err != nil {
-		logger.Crit("Failed to store block body", "err", err)
+	if err := batch.Put(blockBodyKey(number, hash), compressBody(dbm.config.BodyCompression, data)); err != nil {
+		logWriteError(err, "Failed to store block body")
 	}
 }
 
 // WriteBodyRLP stores an RLP encoded block body into the database.
 func (dbm *databaseManager) WriteBodyRLP(hash common.Hash, number uint64, rlp rlp.RawValue) {
 	db := dbm.getDatabase(BodyDB)
-	if err := db.Put(blockBodyKey(number, hash), rlp); err != nil {
-		logger.Crit("Failed to store block body", "err", err)
+	if err := db.Put(blockBodyKey(number, hash), compressBody(dbm.config.BodyCompression, rlp)); err != nil {
+		logWriteError(err, "Failed to store block body")
+		return
 	}
-	if common.WriteThroughCaching {
+	if dbm.writeThroughCaching {
 		dbm.cm.writeBodyRLPCache(hash, rlp)
 	}
 }
@@ -759,7 +936,7 @@ func (dbm *databaseManager) WriteBodyRLP(hash common.Hash, number uint64, rlp rl
 func (dbm *databaseManager) DeleteBody(hash common.Hash, number uint64) {
 	db := dbm.getDatabase(BodyDB)
 	if err := db.Delete(blockBodyKey(number, hash)); err != nil {
-		logger.Crit("Failed to delete block body", "err", err)
+		logWriteError(err, "Failed to delete block body")
 	}
 	dbm.cm.deleteBodyCache(hash)
 }
@@ -795,7 +972,8 @@ func (dbm *databaseManager) WriteTd(hash common.Hash, number uint64, td *big.Int
 		logger.Crit("Failed to RLP encode block total blockscore", "err", err)
 	}
 	if err := db.Put(headerTDKey(number, hash), data); err != nil {
-		logger.Crit("Failed to store block total blockscore", "err", err)
+		logWriteError(err, "Failed to store block total blockscore")
+		return
 	}
 
 	// Write to cache at the end of successful write.
@@ -806,7 +984,7 @@ func (dbm *databaseManager) WriteTd(hash common.Hash, number uint64, td *big.Int
 func (dbm *databaseManager) DeleteTd(hash common.Hash, number uint64) {
 	db := dbm.getDatabase(MiscDB)
 	if err := db.Delete(headerTDKey(number, hash)); err != nil {
-		logger.Crit("Failed to delete block total blockscore", "err", err)
+		logWriteError(err, "Failed to delete block total blockscore")
 	}
 	// Delete cache at the end of successful delete.
 	dbm.cm.deleteTdCache(hash)
@@ -851,7 +1029,7 @@ func (dbm *databaseManager) WriteReceipts(hash common.Hash, number uint64, recei
 	db := dbm.getDatabase(ReceiptsDB)
 	// When putReceiptsToPutter is called from WriteReceipts, txReceipt is cached.
 	dbm.putReceiptsToPutter(db, hash, number, receipts, true)
-	if common.WriteThroughCaching {
+	if dbm.writeThroughCaching {
 		// TODO-Klaytn goroutine for performance
 		dbm.cm.writeBlockReceiptsCache(hash, receipts)
 	}
@@ -878,17 +1056,26 @@ func (dbm *databaseManager) putReceiptsToPutter(putter Putter, hash common.Hash,
 	}
 	// Store the flattened receipt slice
 	if err := putter.Put(blockReceiptsKey(number, hash), bytes); err != nil {
-		logger.Crit("Failed to store block receipts", "err", err)
+		logWriteError(err, "Failed to store block receipts")
 	}
 }
 
 // DeleteReceipts removes all receipt data associated with a block hash.
 func (dbm *databaseManager) DeleteReceipts(hash common.Hash, number uint64) {
+	dbm.deleteReceiptsFromDeleter(dbm.getDatabase(ReceiptsDB), hash, number)
+}
+
+// DeleteReceiptsFromBatch deletes the receipts of the given block in the batch.
+// The receipt caches are cleared immediately.
+func (dbm *databaseManager) DeleteReceiptsFromBatch(batch Batch, hash common.Hash, number uint64) {
+	dbm.deleteReceiptsFromDeleter(batch, hash, number)
+}
+
+func (dbm *databaseManager) deleteReceiptsFromDeleter(deleter Deleter, hash common.Hash, number uint64) {
 	receipts := dbm.ReadReceipts(hash, number)
 
-	db := dbm.getDatabase(ReceiptsDB)
-	if err := db.Delete(blockReceiptsKey(number, hash)); err != nil {
-		logger.Crit("Failed to delete block receipts", "err", err)
+	if err := deleter.Delete(blockReceiptsKey(number, hash)); err != nil {
+		logWriteError(err, "Failed to delete block receipts")
 	}
 
 	// Delete blockReceiptsCache and txReceiptCache.
@@ -900,6 +1087,26 @@ func (dbm *databaseManager) DeleteReceipts(hash common.Hash, number uint64) {
 	}
 }
 
+// ReadEarliestReceiptsBlockNumber returns the number of the earliest block whose
+// receipts are available. The receipts of the blocks below it have been pruned.
+func (dbm *databaseManager) ReadEarliestReceiptsBlockNumber() uint64 {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(earliestReceiptsKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteEarliestReceiptsBlockNumber stores the number of the earliest block whose
+// receipts are available.
+func (dbm *databaseManager) WriteEarliestReceiptsBlockNumber(blockNum uint64) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(earliestReceiptsKey, encodeBlockNumber(blockNum)); err != nil {
+		logWriteError(err, "Failed to store the earliest receipts block number", "blockNumber", blockNum)
+	}
+}
+
 // Block operations.
 // ReadBlock retrieves an entire block corresponding to the hash, assembling it
 // back from the stored header and body. If either the header or body could not
@@ -975,7 +1182,7 @@ func (dbm *databaseManager) WriteBlock(block *types.Block) {
 	dbm.WriteBody(block.Hash(), block.NumberU64(), block.Body())
 	dbm.WriteHeader(block.Header())
 
-	if common.WriteThroughCaching {
+	if dbm.writeThroughCaching {
 		dbm.cm.writeBodyCache(block.Hash(), block.Body())
 		dbm.cm.blockCache.Add(block.Hash(), block)
 	}
@@ -990,8 +1197,24 @@ func (dbm *databaseManager) DeleteBlock(hash common.Hash, number uint64) {
 }
 
 // Find Common Ancestor operation
-// FindCommonAncestor returns the last common ancestor of two block headers
+// FindCommonAncestor returns the last common ancestor of two block headers.
+// Headers on the stored canonical chain are resolved through the canonical hash
+// index: if both are canonical, the lower one is the ancestor, and if only one
+// is, the other is walked back until it joins the canonical chain. Otherwise,
+// both headers are walked back until they meet.
 func (dbm *databaseManager) FindCommonAncestor(a, b *types.Header) *types.Header {
+	aCanonical, bCanonical := dbm.isCanonicalHeader(a), dbm.isCanonicalHeader(b)
+	switch {
+	case aCanonical && bCanonical:
+		if a.Number.Cmp(b.Number) <= 0 {
+			return a
+		}
+		return b
+	case aCanonical:
+		return dbm.findCanonicalAncestor(b, a.Number.Uint64())
+	case bCanonical:
+		return dbm.findCanonicalAncestor(a, b.Number.Uint64())
+	}
 	for bn := b.Number.Uint64(); a.Number.Uint64() > bn; {
 		a = dbm.ReadHeader(a.ParentHash, a.Number.Uint64()-1)
 		if a == nil {
@@ -1017,6 +1240,26 @@ func (dbm *databaseManager) FindCommonAncestor(a, b *types.Header) *types.Header
 	return a
 }
 
+// isCanonicalHeader returns whether the given header is on the stored canonical chain.
+func (dbm *databaseManager) isCanonicalHeader(header *types.Header) bool {
+	return dbm.ReadCanonicalHash(header.Number.Uint64()) == header.Hash()
+}
+
+// findCanonicalAncestor walks the given header back to its first ancestor on
+// the stored canonical chain not above the given block number.
+func (dbm *databaseManager) findCanonicalAncestor(header *types.Header, number uint64) *types.Header {
+	for header.Number.Uint64() > number || !dbm.isCanonicalHeader(header) {
+		if header.Number.Sign() == 0 {
+			return nil
+		}
+		header = dbm.ReadHeader(header.ParentHash, header.Number.Uint64()-1)
+		if header == nil {
+			return nil
+		}
+	}
+	return header
+}
+
 // Istanbul Snapshot operations.
 func (dbm *databaseManager) ReadIstanbulSnapshot(hash common.Hash) ([]byte, error) {
 	db := dbm.getDatabase(MiscDB)
@@ -1032,7 +1275,7 @@ func (dbm *databaseManager) WriteIstanbulSnapshot(hash common.Hash, blob []byte)
 func (dbm *databaseManager) WriteMerkleProof(key, value []byte) {
 	db := dbm.getDatabase(MiscDB)
 	if err := db.Put(key, value); err != nil {
-		logger.Crit("Failed to write merkle proof", "err", err)
+		logWriteError(err, "Failed to write merkle proof")
 	}
 }
 
@@ -1062,6 +1305,70 @@ func (dbm *databaseManager) HasStateTrieNode(key []byte) (bool, error) {
 	return true, nil
 }
 
+func (dbm *databaseManager) DeleteStateTrieNode(key []byte) error {
+	db := dbm.getDatabase(StateTrieDB)
+	return db.Delete(key)
+}
+
+// IterateStateTrieKeys calls fn with every key stored in the state trie database.
+// The iteration stops at the first error returned by fn.
+func (dbm *databaseManager) IterateStateTrieKeys(fn func(key []byte) error) error {
+	return iterateKeys(dbm.getDatabase(StateTrieDB), fn)
+}
+
+// iterateKeys calls fn with every key stored in the given database.
+func iterateKeys(db Database, fn func(key []byte) error) error {
+	return iterateEntries(db, nil, false, func(key, _ []byte) error { return fn(key) })
+}
+
+// iterateEntries calls fn with every key stored in the given database which
+// starts with prefix. The value is given only if withValue is true.
+func iterateEntries(db Database, prefix []byte, withValue bool, fn func(key, value []byte) error) error {
+	switch db := db.(type) {
+	case *levelDB:
+		it := db.NewIteratorWithPrefix(prefix)
+		defer it.Release()
+		for it.Next() {
+			var value []byte
+			if withValue {
+				value = common.CopyBytes(it.Value())
+			}
+			if err := fn(common.CopyBytes(it.Key()), value); err != nil {
+				return err
+			}
+		}
+		return it.Error()
+	case *MemDB:
+		for _, key := range db.Keys() {
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+			var value []byte
+			if withValue {
+				var err error
+				if value, err = db.Get(key); err != nil {
+					continue // deleted during the iteration
+				}
+			}
+			if err := fn(key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *readOnlyDB:
+		return iterateEntries(db.Database, prefix, withValue, fn)
+	case *partitionedDB:
+		for _, partition := range db.partitions {
+			if err := iterateEntries(partition, prefix, withValue, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("key iteration is not supported by %v", db.Type())
+	}
+}
+
 // ReadTxLookupEntry retrieves the positional metadata associated with a transaction
 // hash to allow retrieving the transaction or receipt by hash.
 func (dbm *databaseManager) ReadTxLookupEntry(hash common.Hash) (common.Hash, uint64, uint64) {
@@ -1078,6 +1385,34 @@ func (dbm *databaseManager) ReadTxLookupEntry(hash common.Hash) (common.Hash, ui
 	return entry.BlockHash, entry.BlockIndex, entry.Index
 }
 
+// errStopIteration stops an iteration early without reporting an error.
+var errStopIteration = errors.New("iteration stopped")
+
+// IterateTxLookupEntries calls fn with every transaction lookup entry stored in
+// the database, in no particular order. The iteration stops when fn returns false.
+func (dbm *databaseManager) IterateTxLookupEntries(fn func(txHash common.Hash, blockHash common.Hash, blockIndex, index uint64) bool) error {
+	db := dbm.getDatabase(TxLookUpEntryDB)
+	err := iterateEntries(db, txLookupPrefix, true, func(key, value []byte) error {
+		if len(key) != len(txLookupPrefix)+common.HashLength {
+			return nil // another entry sharing the prefix in the shared database
+		}
+		txHash := common.BytesToHash(key[len(txLookupPrefix):])
+		var entry TxLookupEntry
+		if err := rlp.DecodeBytes(value, &entry); err != nil {
+			logger.Error("Invalid transaction lookup entry RLP", "hash", txHash, "err", err)
+			return nil
+		}
+		if !fn(txHash, entry.BlockHash, entry.BlockIndex, entry.Index) {
+			return errStopIteration
+		}
+		return nil
+	})
+	if err == errStopIteration {
+		return nil
+	}
+	return err
+}
+
 // WriteTxLookupEntries stores a positional metadata for every transaction from
 // a block, enabling hash based transaction and receipt lookups.
 func (dbm *databaseManager) WriteTxLookupEntries(block *types.Block) {
@@ -1098,7 +1433,8 @@ func (dbm *databaseManager) WriteAndCacheTxLookupEntries(block *types.Block) err
 			logger.Crit("Failed to encode transaction lookup entry", "err", err)
 		}
 		if err := batch.Put(TxLookupKey(tx.Hash()), data); err != nil {
-			logger.Crit("Failed to store transaction lookup entry", "err", err)
+			logWriteError(err, "Failed to store transaction lookup entry")
+			continue
 		}
 
 		// Write to cache at the end of successful Put.
@@ -1127,7 +1463,7 @@ func putTxLookupEntriesToPutter(putter Putter, block *types.Block) {
 			logger.Crit("Failed to encode transaction lookup entry", "err", err)
 		}
 		if err := putter.Put(TxLookupKey(tx.Hash()), data); err != nil {
-			logger.Crit("Failed to store transaction lookup entry", "err", err)
+			logWriteError(err, "Failed to store transaction lookup entry")
 		}
 	}
 }
@@ -1191,6 +1527,77 @@ func (dbm *databaseManager) ReadTxHashFromSenderTxHash(senderTxHash common.Hash)
 	return txHash
 }
 
+// WriteAccountCreationInfo stores the creation metadata of the given account.
+func (dbm *databaseManager) WriteAccountCreationInfo(addr common.Address, info *AccountCreationInfo) error {
+	data, err := rlp.EncodeToBytes(info)
+	if err != nil {
+		return err
+	}
+	return dbm.getDatabase(MiscDB).Put(accountCreationKey(addr), data)
+}
+
+// ReadAccountCreationInfo retrieves the creation metadata of the given account.
+// It returns nil if the account has no recorded creation metadata.
+func (dbm *databaseManager) ReadAccountCreationInfo(addr common.Address) *AccountCreationInfo {
+	data, _ := dbm.getDatabase(MiscDB).Get(accountCreationKey(addr))
+	if len(data) == 0 {
+		return nil
+	}
+	info := new(AccountCreationInfo)
+	if err := rlp.DecodeBytes(data, info); err != nil {
+		logger.Error("Invalid account creation info RLP", "address", addr, "err", err)
+		return nil
+	}
+	return info
+}
+
+// DeleteAccountCreationInfo removes the creation metadata of the given account.
+func (dbm *databaseManager) DeleteAccountCreationInfo(addr common.Address) error {
+	return dbm.getDatabase(MiscDB).Delete(accountCreationKey(addr))
+}
+
+// WriteAccountKeyChange stores the metadata of an account key change of the given account.
+func (dbm *databaseManager) WriteAccountKeyChange(addr common.Address, change *AccountKeyChange) error {
+	data, err := rlp.EncodeToBytes(change)
+	if err != nil {
+		return err
+	}
+	return dbm.getDatabase(MiscDB).Put(accountKeyHistoryKey(addr, change.BlockNumber, change.TxIndex), data)
+}
+
+// ReadAccountKeyHistory retrieves the account key changes of the given account
+// made between fromBlock and toBlock inclusive, ordered by block number and
+// transaction index.
+func (dbm *databaseManager) ReadAccountKeyHistory(addr common.Address, fromBlock, toBlock uint64) ([]*AccountKeyChange, error) {
+	prefix := append(common.CopyBytes(accountKeyHistoryPrefix), addr.Bytes()...)
+	var history []*AccountKeyChange
+	err := iterateEntries(dbm.getDatabase(MiscDB), prefix, true, func(key, value []byte) error {
+		if len(key) != len(prefix)+16 {
+			return nil
+		}
+		if number := binary.BigEndian.Uint64(key[len(prefix):]); number < fromBlock || number > toBlock {
+			return nil
+		}
+		change := new(AccountKeyChange)
+		if err := rlp.DecodeBytes(value, change); err != nil {
+			return errors.Wrapf(err, "invalid account key change RLP of %x", key)
+		}
+		history = append(history, change)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Memory databases do not iterate in key order.
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].BlockNumber != history[j].BlockNumber {
+			return history[i].BlockNumber < history[j].BlockNumber
+		}
+		return history[i].TxIndex < history[j].TxIndex
+	})
+	return history, nil
+}
+
 // Receipt read operation.
 // Directly copied rawdb operation because it uses two different databases.
 func (dbm *databaseManager) ReadReceipt(hash common.Hash) (*types.Receipt, common.Hash, uint64, uint64) {
@@ -1264,7 +1671,53 @@ func (dbm *databaseManager) WriteDatabaseVersion(version int) {
 	db := dbm.getDatabase(MiscDB)
 	enc, _ := rlp.EncodeToBytes(version)
 	if err := db.Put(databaseVerisionKey, enc); err != nil {
-		logger.Crit("Failed to store the database version", "err", err)
+		logWriteError(err, "Failed to store the database version")
+	}
+}
+
+// ReadArchiveMode retrieves whether the node running on the database was in
+// archive mode. stored is false if it has never been stored.
+func (dbm *databaseManager) ReadArchiveMode() (archive bool, stored bool) {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(archiveModeKey)
+	if len(data) != 1 {
+		return false, false
+	}
+	return data[0] == 1, true
+}
+
+// WriteArchiveMode stores whether the node running on the database is in archive mode.
+func (dbm *databaseManager) WriteArchiveMode(archive bool) {
+	db := dbm.getDatabase(MiscDB)
+	data := []byte{0}
+	if archive {
+		data[0] = 1
+	}
+	if err := db.Put(archiveModeKey, data); err != nil {
+		logWriteError(err, "Failed to store the archive mode", "archive", archive)
+	}
+}
+
+// ReadKeyFirstSeen retrieves the address of the key named name and when the key
+// was first seen in unix seconds. ok is false if it has never been stored.
+func (dbm *databaseManager) ReadKeyFirstSeen(name string) (addr common.Address, since uint64, ok bool) {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(keyFirstSeenKey(name))
+	if len(data) != common.AddressLength+8 {
+		return common.Address{}, 0, false
+	}
+	return common.BytesToAddress(data[:common.AddressLength]), binary.BigEndian.Uint64(data[common.AddressLength:]), true
+}
+
+// WriteKeyFirstSeen stores the address of the key named name and when the key
+// was first seen in unix seconds.
+func (dbm *databaseManager) WriteKeyFirstSeen(name string, addr common.Address, since uint64) {
+	db := dbm.getDatabase(MiscDB)
+	data := make([]byte, common.AddressLength+8)
+	copy(data, addr.Bytes())
+	binary.BigEndian.PutUint64(data[common.AddressLength:], since)
+	if err := db.Put(keyFirstSeenKey(name), data); err != nil {
+		logWriteError(err, "Failed to store the first seen time of a key", "name", name, "addr", addr)
 	}
 }
 
@@ -1293,7 +1746,7 @@ func (dbm *databaseManager) WriteChainConfig(hash common.Hash, cfg *params.Chain
 		logger.Crit("Failed to JSON encode chain config", "err", err)
 	}
 	if err := db.Put(configKey(hash), data); err != nil {
-		logger.Crit("Failed to store chain config", "err", err)
+		logWriteError(err, "Failed to store chain config")
 	}
 }
 
@@ -1310,11 +1763,12 @@ func (dbm *databaseManager) WritePreimages(number uint64, preimages map[common.H
 	batch := dbm.getDatabase(StateTrieDB).NewBatch()
 	for hash, preimage := range preimages {
 		if err := batch.Put(preimageKey(hash), preimage); err != nil {
-			logger.Crit("Failed to store trie preimage", "err", err)
+			logWriteError(err, "Failed to store trie preimage")
 		}
 	}
 	if err := batch.Write(); err != nil {
-		logger.Crit("Failed to batch write trie preimage", "err", err, "blockNumber", number)
+		logWriteError(err, "Failed to batch write trie preimage", "blockNumber", number)
+		return
 	}
 	preimageCounter.Inc(int64(len(preimages)))
 	preimageHitCounter.Inc(int64(len(preimages)))
@@ -1326,7 +1780,7 @@ func (dbm *databaseManager) WriteChildChainTxHash(ccBlockHash common.Hash, ccTxH
 	key := childChainTxHashKey(ccBlockHash)
 	db := dbm.getDatabase(bridgeServiceDB)
 	if err := db.Put(key, ccTxHash.Bytes()); err != nil {
-		logger.Crit("Failed to store ChildChainTxHash", "ccBlockHash", ccBlockHash.String(), "ccTxHash", ccTxHash.String(), "err", err)
+		logWriteError(err, "Failed to store ChildChainTxHash", "ccBlockHash", ccBlockHash.String(), "ccTxHash", ccTxHash.String())
 	}
 }
 
@@ -1347,7 +1801,7 @@ func (dbm *databaseManager) WriteLastIndexedBlockNumber(blockNum uint64) {
 	key := lastIndexedBlockKey
 	db := dbm.getDatabase(bridgeServiceDB)
 	if err := db.Put(key, encodeBlockNumber(blockNum)); err != nil {
-		logger.Crit("Failed to store LastIndexedBlockNumber", "blockNumber", blockNum, "err", err)
+		logWriteError(err, "Failed to store LastIndexedBlockNumber", "blockNumber", blockNum)
 	}
 }
 
@@ -1367,7 +1821,7 @@ func (dbm *databaseManager) WriteAnchoredBlockNumber(blockNum uint64) {
 	key := lastServiceChainTxReceiptKey
 	db := dbm.getDatabase(bridgeServiceDB)
 	if err := db.Put(key, encodeBlockNumber(blockNum)); err != nil {
-		logger.Crit("Failed to store LatestServiceChainBlockNum", "blockNumber", blockNum, "err", err)
+		logWriteError(err, "Failed to store LatestServiceChainBlockNum", "blockNumber", blockNum)
 	}
 }
 
@@ -1388,7 +1842,7 @@ func (dbm *databaseManager) WriteHandleTxHashFromRequestTxHash(rTx, hTx common.H
 	db := dbm.getDatabase(bridgeServiceDB)
 	key := valueTransferTxHashKey(rTx)
 	if err := db.Put(key, hTx.Bytes()); err != nil {
-		logger.Crit("Failed to store handle value transfer tx hash", "request tx hash", rTx.String(), "handle tx hash", hTx.String(), "err", err)
+		logWriteError(err, "Failed to store handle value transfer tx hash", "request tx hash", rTx.String(), "handle tx hash", hTx.String())
 	}
 }
 
@@ -1415,7 +1869,7 @@ func (dbm *databaseManager) WriteReceiptFromParentChain(blockHash common.Hash, r
 	}
 	key := receiptFromParentChainKey(blockHash)
 	if err = db.Put(key, byte); err != nil {
-		logger.Crit("Failed to store receipt received from parent chain", "receipt.TxHash", receipt.TxHash, "err", err)
+		logWriteError(err, "Failed to store receipt received from parent chain", "receipt.TxHash", receipt.TxHash)
 	}
 }
 
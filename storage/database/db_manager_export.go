@@ -0,0 +1,131 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"io"
+)
+
+// ReceiptMarshaller converts a receipt and its transaction into the JSON form
+// of the receipt. api.RpcOutputReceipt is the marshaller of the RPC API.
+type ReceiptMarshaller func(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, receipt *types.Receipt) map[string]interface{}
+
+// exportedBlock is the JSON form of a block written by ExportBlockRange.
+type exportedBlock struct {
+	Number       hexutil.Uint64           `json:"number"`
+	Hash         common.Hash              `json:"hash"`
+	Header       *types.Header            `json:"header"`
+	Transactions types.Transactions       `json:"transactions"`
+	Receipts     []map[string]interface{} `json:"receipts"`
+}
+
+// ExportBlockRange writes the canonical blocks from `from` to `to`, both
+// inclusive, and their receipts into w as newline-delimited JSON, one block per
+// line. Numbers without a canonical block are skipped. The receipts are
+// converted by marshalReceipt and their logs carry the block hash, block
+// number and transaction index.
+func (dbm *databaseManager) ExportBlockRange(from, to uint64, w io.Writer, marshalReceipt ReceiptMarshaller) error {
+	enc := json.NewEncoder(w)
+	return dbm.walkBlockRange(from, to, func(block *types.Block, receipts types.Receipts) error {
+		return enc.Encode(newExportedBlock(block, receipts, marshalReceipt))
+	})
+}
+
+// ExportReceiptRange writes the receipts of the canonical blocks from `from` to
+// `to`, both inclusive, into w as newline-delimited JSON, one receipt per line.
+// The blocks are walked and the receipts are converted as by ExportBlockRange.
+func (dbm *databaseManager) ExportReceiptRange(from, to uint64, w io.Writer, marshalReceipt ReceiptMarshaller) error {
+	enc := json.NewEncoder(w)
+	return dbm.walkBlockRange(from, to, func(block *types.Block, receipts types.Receipts) error {
+		for _, receipt := range newExportedBlock(block, receipts, marshalReceipt).Receipts {
+			if err := enc.Encode(receipt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// walkBlockRange calls fn with the canonical blocks from `from` to `to`, both
+// inclusive, and their receipts in order. Numbers without a canonical block are
+// skipped.
+func (dbm *databaseManager) walkBlockRange(from, to uint64, fn func(block *types.Block, receipts types.Receipts) error) error {
+	if from > to {
+		return fmt.Errorf("invalid block range: %d > %d", from, to)
+	}
+	for number := from; number <= to; number++ {
+		hash := dbm.ReadCanonicalHash(number)
+		if hash == (common.Hash{}) {
+			continue
+		}
+		block := dbm.ReadBlock(hash, number)
+		if block == nil {
+			logger.Warn("Canonical block is missing, skipping", "number", number, "hash", hash)
+			continue
+		}
+		if err := fn(block, dbm.ReadReceipts(hash, number)); err != nil {
+			return err
+		}
+		if number == to { // avoid overflowing when to is the maximum uint64
+			break
+		}
+	}
+	return nil
+}
+
+// newExportedBlock fills the derived log fields of the given block and converts
+// its receipts by marshalReceipt.
+func newExportedBlock(block *types.Block, receipts types.Receipts, marshalReceipt ReceiptMarshaller) *exportedBlock {
+	hash, number := block.Hash(), block.NumberU64()
+	txs := block.Transactions()
+
+	exported := &exportedBlock{
+		Number:       hexutil.Uint64(number),
+		Hash:         hash,
+		Header:       block.Header(),
+		Transactions: txs,
+		Receipts:     make([]map[string]interface{}, 0, len(receipts)),
+	}
+	var logIndex uint
+	for i, receipt := range receipts {
+		if i >= len(txs) {
+			logger.Warn("Receipt without a transaction, skipping", "number", number, "hash", hash, "index", i)
+			break
+		}
+		txHash := txs[i].Hash()
+		logs := make([]*types.Log, len(receipt.Logs))
+		for j, l := range receipt.Logs {
+			derived := *l
+			derived.BlockNumber = number
+			derived.BlockHash = hash
+			derived.TxHash = txHash
+			derived.TxIndex = uint(i)
+			derived.Index = logIndex
+			logIndex++
+			logs[j] = &derived
+		}
+		derived := *receipt
+		derived.Logs = logs
+		exported.Receipts = append(exported.Receipts, marshalReceipt(txs[i], hash, number, uint64(i), &derived))
+	}
+	return exported
+}
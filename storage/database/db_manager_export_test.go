@@ -0,0 +1,150 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBManager_ExportBlockRange(t *testing.T) {
+	managers := map[string]DBManager{
+		"single":      NewMemoryDBManager(),
+		"partitioned": NewDBManager(&DBConfig{DBType: MemoryDB, Partitioned: true, NumStateTriePartitions: 4}),
+	}
+	for name, dbm := range managers {
+		t.Run(name, func(t *testing.T) {
+			defer dbm.Close()
+			testExportBlockRange(t, dbm)
+		})
+	}
+}
+
+func testExportBlockRange(t *testing.T, dbm DBManager) {
+	key, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(big.NewInt(1))
+
+	// Blocks 1 and 3 are canonical, block 2 is a gap.
+	var blocks []*types.Block
+	for _, number := range []uint64{1, 3} {
+		tx, err := types.SignTx(types.NewTransaction(number, common.Address{0x1}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		receipt := &types.Receipt{
+			Status:  types.ReceiptStatusSuccessful,
+			Logs:    []*types.Log{{Address: common.Address{0x2}, Topics: []common.Hash{{0x3}}}},
+			TxHash:  tx.Hash(),
+			GasUsed: 21000,
+		}
+		block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number)}).WithBody([]*types.Transaction{tx})
+		dbm.WriteBlock(block)
+		dbm.WriteCanonicalHash(block.Hash(), number)
+		dbm.WriteReceipts(block.Hash(), number, types.Receipts{receipt})
+		blocks = append(blocks, block)
+	}
+
+	marshalReceipt := func(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, receipt *types.Receipt) map[string]interface{} {
+		return map[string]interface{}{
+			"transactionHash":  tx.Hash(),
+			"blockHash":        blockHash,
+			"blockNumber":      hexutil.Uint64(blockNumber),
+			"transactionIndex": hexutil.Uint(index),
+			"logs":             receipt.Logs,
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, dbm.ExportBlockRange(0, 3, &buf, marshalReceipt))
+	assert.Error(t, dbm.ExportBlockRange(3, 1, &buf, marshalReceipt))
+
+	type exportedLine struct {
+		Number       string             `json:"number"`
+		Hash         common.Hash        `json:"hash"`
+		Header       *types.Header      `json:"header"`
+		Transactions types.Transactions `json:"transactions"`
+		Receipts     []struct {
+			TxHash           common.Hash  `json:"transactionHash"`
+			BlockHash        common.Hash  `json:"blockHash"`
+			BlockNumber      string       `json:"blockNumber"`
+			TransactionIndex string       `json:"transactionIndex"`
+			Logs             []*types.Log `json:"logs"`
+		} `json:"receipts"`
+	}
+	var lines []exportedLine
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line exportedLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+
+	// The genesis and the gap are skipped.
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	for i, block := range blocks {
+		line := lines[i]
+		assert.Equal(t, block.Hash(), line.Hash)
+		assert.Equal(t, block.Hash(), line.Header.Hash())
+		assert.Equal(t, block.Transactions()[0].Hash(), line.Transactions[0].Hash())
+		if !assert.Len(t, line.Receipts, 1) {
+			continue
+		}
+		receipt := line.Receipts[0]
+		assert.Equal(t, block.Transactions()[0].Hash(), receipt.TxHash)
+		assert.Equal(t, block.Hash(), receipt.BlockHash)
+		assert.Equal(t, line.Number, receipt.BlockNumber)
+		assert.Equal(t, "0x0", receipt.TransactionIndex)
+		assert.Equal(t, block.Hash(), receipt.Logs[0].BlockHash)
+		assert.Equal(t, block.NumberU64(), receipt.Logs[0].BlockNumber)
+		assert.Equal(t, block.Transactions()[0].Hash(), receipt.Logs[0].TxHash)
+	}
+
+	// The receipts are exported one per line from the same blocks.
+	buf.Reset()
+	assert.NoError(t, dbm.ExportReceiptRange(0, 3, &buf, marshalReceipt))
+	assert.Error(t, dbm.ExportReceiptRange(3, 1, &buf, marshalReceipt))
+
+	var receipts []map[string]interface{}
+	scanner = bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var receipt map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &receipt); err != nil {
+			t.Fatal(err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	if !assert.Len(t, receipts, len(blocks)) {
+		return
+	}
+	for i, block := range blocks {
+		assert.Equal(t, block.Transactions()[0].Hash().Hex(), receipts[i]["transactionHash"])
+		assert.Equal(t, block.Hash().Hex(), receipts[i]["blockHash"])
+		assert.Equal(t, block.Hash().Hex(), receipts[i]["logs"].([]interface{})[0].(map[string]interface{})["blockHash"])
+	}
+}
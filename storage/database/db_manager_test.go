@@ -0,0 +1,339 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBManager_InvalidateHeaderNumberCache(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	defer dbm.Close()
+
+	header := &types.Header{Number: big.NewInt(7)}
+	hash := header.Hash()
+
+	// Writing the header caches its number.
+	dbm.WriteHeader(header)
+	assert.Equal(t, uint64(7), *dbm.ReadCachedHeaderNumber(hash))
+	assert.Equal(t, uint64(7), *dbm.ReadHeaderNumber(hash))
+
+	// Modify the mapping behind the cache, as a manual DB surgery would do.
+	db := dbm.(*databaseManager).getDatabase(headerDB)
+	if err := db.Put(headerNumberKey(hash), encodeBlockNumber(8)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(7), *dbm.ReadHeaderNumber(hash))
+
+	// After the invalidation, the number is re-derived from the DB.
+	dbm.InvalidateHeaderNumberCache(hash)
+	assert.Nil(t, dbm.ReadCachedHeaderNumber(hash))
+	assert.Equal(t, uint64(8), *dbm.ReadHeaderNumber(hash))
+	assert.Equal(t, uint64(8), *dbm.ReadCachedHeaderNumber(hash))
+}
+
+func TestDBManager_IterateTxLookupEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay_iterate_tx_lookup_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configs := map[string]*DBConfig{
+		"MemoryDB":             {DBType: MemoryDB},
+		"MemoryDB-Partitioned": {DBType: MemoryDB, Partitioned: true, NumStateTriePartitions: 4},
+		"LevelDB":              {Dir: filepath.Join(dir, "single"), DBType: LevelDB, LevelDBCacheSize: 16, OpenFilesLimit: 16},
+		"LevelDB-Partitioned":  {Dir: filepath.Join(dir, "partitioned"), DBType: LevelDB, Partitioned: true, LevelDBCacheSize: 16, OpenFilesLimit: 16, NumStateTriePartitions: 4},
+	}
+	for name, dbc := range configs {
+		t.Run(name, func(t *testing.T) {
+			dbm := NewDBManager(dbc)
+			defer dbm.Close()
+			testIterateTxLookupEntries(t, dbm)
+		})
+	}
+}
+
+func testIterateTxLookupEntries(t *testing.T, dbm DBManager) {
+	type lookup struct {
+		blockHash         common.Hash
+		blockIndex, index uint64
+	}
+	want := make(map[common.Hash]lookup)
+	for number := uint64(1); number <= 3; number++ {
+		var txs types.Transactions
+		for i := uint64(0); i < number; i++ {
+			txs = append(txs, types.NewTransaction(number*10+i, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil))
+		}
+		block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number)}).WithBody(txs)
+		dbm.WriteTxLookupEntries(block)
+		// Other entries of the shared database must not be yielded.
+		dbm.WriteCanonicalHash(block.Hash(), number)
+		dbm.WriteHeader(block.Header())
+		for i, tx := range txs {
+			want[tx.Hash()] = lookup{block.Hash(), number, uint64(i)}
+		}
+	}
+
+	have := make(map[common.Hash]lookup)
+	err := dbm.IterateTxLookupEntries(func(txHash common.Hash, blockHash common.Hash, blockIndex, index uint64) bool {
+		have[txHash] = lookup{blockHash, blockIndex, index}
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, want, have)
+
+	// The iteration stops early when fn returns false.
+	count := 0
+	err = dbm.IterateTxLookupEntries(func(common.Hash, common.Hash, uint64, uint64) bool {
+		count++
+		return count < 2
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+// makeHeaderChain writes a chain of n headers on top of parent, tagged with the
+// given extra data to tell apart the branches, and returns them in order.
+func makeHeaderChain(dbm DBManager, parent *types.Header, n int, extra byte) []*types.Header {
+	headers := make([]*types.Header, n)
+	for i := range headers {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Extra:      []byte{extra},
+		}
+		dbm.WriteHeader(header)
+		headers[i], parent = header, header
+	}
+	return headers
+}
+
+func TestDBManager_FindCommonAncestor(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	defer dbm.Close()
+
+	genesis := &types.Header{Number: big.NewInt(0)}
+	dbm.WriteHeader(genesis)
+	dbm.WriteCanonicalHash(genesis.Hash(), 0)
+
+	canonical := append([]*types.Header{genesis}, makeHeaderChain(dbm, genesis, 1000, 0)...)
+	for _, header := range canonical {
+		dbm.WriteCanonicalHash(header.Hash(), header.Number.Uint64())
+	}
+	side := makeHeaderChain(dbm, canonical[500], 300, 1)
+	other := makeHeaderChain(dbm, canonical[500], 200, 2)
+
+	// Both sides are canonical: resolved without walking the headers in between.
+	for i := 11; i < 1000; i++ {
+		dbm.DeleteHeader(canonical[i].Hash(), canonical[i].Number.Uint64())
+	}
+	assert.Equal(t, canonical[10].Hash(), dbm.FindCommonAncestor(canonical[1000], canonical[10]).Hash())
+	assert.Equal(t, canonical[10].Hash(), dbm.FindCommonAncestor(canonical[10], canonical[1000]).Hash())
+	for i := 11; i < 1000; i++ {
+		dbm.WriteHeader(canonical[i])
+	}
+
+	// One side is canonical: the other is walked back until the canonical chain.
+	assert.Equal(t, canonical[500].Hash(), dbm.FindCommonAncestor(side[299], canonical[1000]).Hash())
+	assert.Equal(t, canonical[500].Hash(), dbm.FindCommonAncestor(canonical[1000], side[299]).Hash())
+	assert.Equal(t, canonical[100].Hash(), dbm.FindCommonAncestor(side[299], canonical[100]).Hash())
+
+	// Neither side is canonical: both are walked back until they meet.
+	assert.Equal(t, canonical[500].Hash(), dbm.FindCommonAncestor(side[299], other[199]).Hash())
+	assert.Equal(t, side[100].Hash(), dbm.FindCommonAncestor(side[299], side[100]).Hash())
+
+	// A missing ancestor can't be resolved.
+	dbm.DeleteHeader(side[50].Hash(), side[50].Number.Uint64())
+	assert.Nil(t, dbm.FindCommonAncestor(side[299], canonical[1000]))
+}
+
+func TestDBManager_Compact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay_compact_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configs := map[string]*DBConfig{
+		"MemoryDB":            {DBType: MemoryDB},
+		"LevelDB":             {Dir: filepath.Join(dir, "single"), DBType: LevelDB, LevelDBCacheSize: 16, OpenFilesLimit: 16},
+		"LevelDB-Partitioned": {Dir: filepath.Join(dir, "partitioned"), DBType: LevelDB, Partitioned: true, LevelDBCacheSize: 16, OpenFilesLimit: 16, NumStateTriePartitions: 4},
+		"BadgerDB":            {Dir: filepath.Join(dir, "badger"), DBType: BadgerDB},
+	}
+	for name, dbc := range configs {
+		t.Run(name, func(t *testing.T) {
+			dbm := NewDBManager(dbc)
+			defer dbm.Close()
+
+			// Write and delete many keys to leave tombstones behind
+			batch := dbm.NewBatch(StateTrieDB)
+			for i := uint64(0); i < 10000; i++ {
+				hash := common.BytesToHash(encodeBlockNumber(i))
+				dbm.WriteCanonicalHash(hash, i)
+				assert.NoError(t, batch.Put(hash[:], hash[:]))
+			}
+			assert.NoError(t, batch.Write())
+			for i := uint64(0); i < 10000; i++ {
+				hash := common.BytesToHash(encodeBlockNumber(i))
+				dbm.DeleteCanonicalHash(i)
+				assert.NoError(t, dbm.DeleteStateTrieNode(hash[:]))
+			}
+			assert.NoError(t, dbm.Compact(nil, nil))
+			assert.NoError(t, dbm.Compact(encodeBlockNumber(100), encodeBlockNumber(200)))
+
+			assert.Equal(t, common.Hash{}, dbm.ReadCanonicalHash(1))
+		})
+	}
+}
+
+func TestDBManager_BatchWriteLatency(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	defer dbm.Close()
+
+	assert.Equal(t, time.Duration(0), dbm.BatchWriteLatency())
+
+	// Only written batches are timed.
+	batch := dbm.NewBatch(MiscDB)
+	assert.NoError(t, batch.Put([]byte("key"), []byte("value")))
+	assert.Equal(t, time.Duration(0), dbm.BatchWriteLatency())
+
+	assert.NoError(t, batch.Write())
+	assert.True(t, dbm.BatchWriteLatency() > 0)
+
+	// The moving average follows the latency of the writes.
+	dbm.(*databaseManager).updateBatchWriteLatency(time.Second)
+	for i := 0; i < 3; i++ {
+		dbm.(*databaseManager).updateBatchWriteLatency(time.Second)
+	}
+	assert.True(t, dbm.BatchWriteLatency() > 500*time.Millisecond)
+}
+
+func TestDBManager_ReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay_readonly_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configs := map[string]DBConfig{
+		"LevelDB":             {Dir: filepath.Join(dir, "single"), DBType: LevelDB, LevelDBCacheSize: 16, OpenFilesLimit: 16},
+		"LevelDB-Partitioned": {Dir: filepath.Join(dir, "partitioned"), DBType: LevelDB, Partitioned: true, LevelDBCacheSize: 16, OpenFilesLimit: 16, NumStateTriePartitions: 4},
+		"BadgerDB":            {Dir: filepath.Join(dir, "badger"), DBType: BadgerDB},
+	}
+	for name, dbc := range configs {
+		t.Run(name, func(t *testing.T) {
+			addr := common.HexToAddress("0x1")
+			hash := common.HexToHash("0x1234")
+
+			dbm := NewDBManager(&dbc)
+			dbm.WriteCanonicalHash(hash, 1)
+			assert.NoError(t, dbm.WriteAccountKeyChange(addr, &AccountKeyChange{BlockNumber: 1}))
+			dbm.Close()
+
+			readOnlyDBC := dbc
+			readOnlyDBC.ReadOnly = true
+			dbm = NewDBManager(&readOnlyDBC)
+			defer dbm.Close()
+
+			// Reads succeed.
+			assert.Equal(t, hash, dbm.ReadCanonicalHash(1))
+			if dbc.DBType == LevelDB {
+				changes, err := dbm.ReadAccountKeyHistory(addr, 0, 1)
+				assert.NoError(t, err)
+				assert.Len(t, changes, 1)
+			}
+
+			// Writes fail without stopping the node.
+			assert.Equal(t, ErrReadOnlyDatabase, dbm.WriteAccountKeyChange(addr, &AccountKeyChange{BlockNumber: 2}))
+			assert.Equal(t, ErrReadOnlyDatabase, dbm.DeleteStateTrieNode(hash[:]))
+			batch := dbm.NewBatch(StateTrieDB)
+			assert.Equal(t, ErrReadOnlyDatabase, batch.Put(hash[:], hash[:]))
+			assert.Equal(t, ErrReadOnlyDatabase, batch.Write())
+			assert.Equal(t, ErrReadOnlyDatabase, errors.Cause(dbm.Compact(nil, nil)))
+
+			dbm.WriteCanonicalHash(common.HexToHash("0x5678"), 1)
+			dbm.DeleteCanonicalHash(1)
+			assert.Equal(t, hash, dbm.ReadCanonicalHash(1))
+		})
+	}
+}
+
+func TestDBManager_WriteThroughCaching(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	bodyRLP, err := rlp.EncodeToBytes(block.Body())
+	assert.NoError(t, err)
+	receipts := types.Receipts{&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: common.HexToHash("0x1")}}
+
+	// Two managers in one process cache differently.
+	dbms := map[bool]*databaseManager{
+		true:  NewDBManager(&DBConfig{DBType: MemoryDB, WriteThroughCaching: true}).(*databaseManager),
+		false: NewDBManager(&DBConfig{DBType: MemoryDB, WriteThroughCaching: false}).(*databaseManager),
+	}
+	for _, dbm := range dbms {
+		dbm.WriteBlock(block)
+		dbm.WriteBodyRLP(block.Hash(), block.NumberU64(), bodyRLP)
+		dbm.WriteReceipts(block.Hash(), block.NumberU64(), receipts)
+	}
+	for writeThrough, dbm := range dbms {
+		assert.Equal(t, writeThrough, dbm.IsWriteThroughCaching())
+		assert.Equal(t, writeThrough, dbm.cm.readBlockCache(block.Hash()) != nil, "write-through %v: block cache", writeThrough)
+		assert.Equal(t, writeThrough, dbm.cm.readBodyCache(block.Hash()) != nil, "write-through %v: body cache", writeThrough)
+		assert.Equal(t, writeThrough, dbm.cm.readBodyRLPCache(block.Hash()) != nil, "write-through %v: body RLP cache", writeThrough)
+		assert.Equal(t, writeThrough, dbm.cm.readBlockReceiptsInCache(block.Hash()) != nil, "write-through %v: receipts cache", writeThrough)
+	}
+}
+
+func TestDBManager_AccountKeyHistory(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	defer dbm.Close()
+
+	addr, other := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	changes := []*AccountKeyChange{
+		{TxHash: common.HexToHash("0x13"), BlockNumber: 30, TxIndex: 0, OldKey: []byte{0x02}, NewKey: []byte{0x03}},
+		{TxHash: common.HexToHash("0x11"), BlockNumber: 10, TxIndex: 2, OldKey: []byte{0x00}, NewKey: []byte{0x01}},
+		{TxHash: common.HexToHash("0x12"), BlockNumber: 20, TxIndex: 1, OldKey: []byte{0x01}, NewKey: []byte{0x02}},
+	}
+	for _, change := range changes {
+		assert.NoError(t, dbm.WriteAccountKeyChange(addr, change))
+	}
+	assert.NoError(t, dbm.WriteAccountKeyChange(other, &AccountKeyChange{BlockNumber: 20}))
+
+	history, err := dbm.ReadAccountKeyHistory(addr, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []*AccountKeyChange{changes[1], changes[2], changes[0]}, history)
+
+	// The block range is inclusive.
+	history, err = dbm.ReadAccountKeyHistory(addr, 20, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []*AccountKeyChange{changes[2], changes[0]}, history)
+
+	history, err = dbm.ReadAccountKeyHistory(common.HexToAddress("0x3"), 0, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
@@ -56,22 +56,33 @@ type Putter interface {
 	Put(key []byte, value []byte) error
 }
 
+// Deleter wraps the database delete operation supported by both batches and regular databases.
+type Deleter interface {
+	Delete(key []byte) error
+}
+
 // Database wraps all database operations. All methods are safe for concurrent use.
 type Database interface {
 	Putter
 	Get(key []byte) ([]byte, error)
 	Has(key []byte) (bool, error)
-	Delete(key []byte) error
+	Deleter
 	Close()
 	NewBatch() Batch
 	Type() DBType
 	Meter(prefix string)
+
+	// Compact flattens the underlying data store for the given key range. A nil
+	// start is treated as a key before all keys and a nil limit as a key after
+	// all keys. Stores without a manual compaction do nothing.
+	Compact(start, limit []byte) error
 }
 
 // Batch is a write-only database that commits changes to its host database
 // when Write is called. Batch cannot be used concurrently.
 type Batch interface {
 	Putter
+	Deleter
 	ValueSize() int // amount of data in the batch
 	Write() error
 	// Reset resets the batch for reuse
@@ -113,6 +113,7 @@ func getLevelDBOptions(dbc *DBConfig) *opt.Options {
 		DisableBufferPool:             !dbc.LevelDBBufferPool,
 		CompactionTableSize:           2 * opt.MiB,
 		CompactionTableSizeMultiplier: 1.0,
+		ReadOnly:                      dbc.ReadOnly,
 	}
 
 	return newOption
@@ -139,7 +140,7 @@ func NewLevelDB(dbc *DBConfig, entryType DBEntryType) (*levelDB, error) {
 
 	// Open the db and recover any potential corruptions
 	db, err := leveldb.OpenFile(dbc.Dir, ldbOpts)
-	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
+	if _, corrupted := err.(*errors.ErrCorrupted); corrupted && !dbc.ReadOnly {
 		db, err = leveldb.RecoverFile(dbc.Dir, nil)
 	}
 	// (Re)check for errors and abort if opening of the db failed
@@ -292,6 +293,12 @@ func (db *levelDB) Close() {
 	}
 }
 
+// Compact flattens the underlying LevelDB for the given key range, discarding
+// deleted and overwritten entries.
+func (db *levelDB) Compact(start, limit []byte) error {
+	return db.db.CompactRange(util.Range{Start: start, Limit: limit})
+}
+
 func (db *levelDB) LDB() *leveldb.DB {
 	return db.db
 }
@@ -417,6 +424,12 @@ func (b *ldbBatch) Put(key, value []byte) error {
 	return nil
 }
 
+func (b *ldbBatch) Delete(key []byte) error {
+	b.b.Delete(key)
+	b.size += len(key)
+	return nil
+}
+
 func (b *ldbBatch) Write() error {
 	return b.db.Write(b.b, nil)
 }
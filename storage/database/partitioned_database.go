@@ -163,6 +163,16 @@ func (pdb *partitionedDB) Type() DBType {
 	return PartitionedDB
 }
 
+// Compact compacts every partition for the given key range.
+func (pdb *partitionedDB) Compact(start, limit []byte) error {
+	for index, partition := range pdb.partitions {
+		if err := partition.Compact(start, limit); err != nil {
+			return fmt.Errorf("failed to compact partition %d: %v", index, err)
+		}
+	}
+	return nil
+}
+
 func (pdb *partitionedDB) Meter(prefix string) {
 	for index, partition := range pdb.partitions {
 		partition.Meter(prefix + strconv.Itoa(index))
@@ -185,6 +195,14 @@ func (pdbBatch *partitionedDBBatch) Put(key []byte, value []byte) error {
 	}
 }
 
+func (pdbBatch *partitionedDBBatch) Delete(key []byte) error {
+	if partitionIndex, err := calcPartition(key, uint(pdbBatch.numBatches)); err != nil {
+		return err
+	} else {
+		return pdbBatch.batches[partitionIndex].Delete(key)
+	}
+}
+
 // ValueSize is called to determine whether to write batches when it exceeds
 // certain limit. partitionedDB returns the largest size of its batches to
 // write all batches at once when one of batch exceeds the limit.
@@ -0,0 +1,62 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import "errors"
+
+// ErrReadOnlyDatabase is returned when writing to a database opened read-only.
+var ErrReadOnlyDatabase = errors.New("database is opened read-only")
+
+// readOnlyDB is a Database rejecting every write with ErrReadOnlyDatabase.
+// It wraps each database opened with DBConfig.ReadOnly.
+type readOnlyDB struct {
+	Database
+}
+
+func newReadOnlyDB(db Database) *readOnlyDB {
+	return &readOnlyDB{Database: db}
+}
+
+func (db *readOnlyDB) Put(key []byte, value []byte) error {
+	return ErrReadOnlyDatabase
+}
+
+func (db *readOnlyDB) Delete(key []byte) error {
+	return ErrReadOnlyDatabase
+}
+
+func (db *readOnlyDB) NewBatch() Batch {
+	return &readOnlyBatch{}
+}
+
+// Compact fails since compacting rewrites the underlying data store.
+func (db *readOnlyDB) Compact(start, limit []byte) error {
+	return ErrReadOnlyDatabase
+}
+
+// readOnlyBatch is a Batch of a readOnlyDB. It fails to write anything.
+type readOnlyBatch struct{}
+
+func (b *readOnlyBatch) Put(key, value []byte) error { return ErrReadOnlyDatabase }
+
+func (b *readOnlyBatch) Delete(key []byte) error { return ErrReadOnlyDatabase }
+
+func (b *readOnlyBatch) Write() error { return ErrReadOnlyDatabase }
+
+func (b *readOnlyBatch) ValueSize() int { return 0 }
+
+func (b *readOnlyBatch) Reset() {}
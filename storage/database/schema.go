@@ -22,6 +22,7 @@ package database
 
 import (
 	"encoding/binary"
+	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/metrics"
 )
@@ -43,6 +44,12 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// archiveModeKey tracks whether the node running on the database was in archive mode.
+	archiveModeKey = []byte("ArchiveMode")
+
+	// earliestReceiptsKey tracks the number of the earliest block whose receipts are not pruned.
+	earliestReceiptsKey = []byte("EarliestReceipts")
+
 	validSectionKey = []byte("count")
 
 	sectionHeadKeyPrefix = []byte("shead")
@@ -81,6 +88,14 @@ var (
 
 	senderTxHashToTxHashPrefix = []byte("SenderTxHash")
 
+	accountCreationPrefix = []byte("AccountCreation") // accountCreationPrefix + address -> account creation metadata
+
+	// accountKeyHistoryPrefix + address + num (uint64 big endian) + tx index (uint64 big endian) -> account key change
+	accountKeyHistoryPrefix = []byte("AccountKeyHistory")
+
+	// keyFirstSeenPrefix + key name -> key address + first seen time (unix seconds, uint64 big endian)
+	keyFirstSeenPrefix = []byte("KeyFirstSeen")
+
 	governancePrefix     = []byte("governance")
 	governanceHistoryKey = []byte("governanceIdxHistory")
 	governanceStateKey   = []byte("governanceState")
@@ -94,6 +109,28 @@ type TxLookupEntry struct {
 	Index      uint64
 }
 
+// AccountCreationInfo is the metadata of an account created by a transaction,
+// either explicitly by an account creation transaction or implicitly by receiving
+// value or being deployed.
+type AccountCreationInfo struct {
+	TxHash        common.Hash
+	BlockHash     common.Hash
+	BlockNumber   uint64
+	TxType        types.TxType
+	HumanReadable bool
+}
+
+// AccountKeyChange is the metadata of an account key changed by an account
+// update transaction. The keys are RLP-encoded account key serializers.
+type AccountKeyChange struct {
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64
+	TxIndex     uint64
+	OldKey      []byte
+	NewKey      []byte
+}
+
 // encodeBlockNumber encodes a block number as big endian uint64
 func encodeBlockNumber(number uint64) []byte {
 	enc := make([]byte, 8)
@@ -140,6 +177,17 @@ func SenderTxHashToTxHashKey(senderTxHash common.Hash) []byte {
 	return append(senderTxHashToTxHashPrefix, senderTxHash.Bytes()...)
 }
 
+// accountCreationKey = accountCreationPrefix + address
+func accountCreationKey(addr common.Address) []byte {
+	return append(accountCreationPrefix, addr.Bytes()...)
+}
+
+// accountKeyHistoryKey = accountKeyHistoryPrefix + address + num (uint64 big endian) + tx index (uint64 big endian)
+func accountKeyHistoryKey(addr common.Address, number, txIndex uint64) []byte {
+	key := append(append(common.CopyBytes(accountKeyHistoryPrefix), addr.Bytes()...), encodeBlockNumber(number)...)
+	return append(key, encodeBlockNumber(txIndex)...)
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -180,6 +228,10 @@ func BloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+func keyFirstSeenKey(name string) []byte {
+	return append(keyFirstSeenPrefix, []byte(name)...)
+}
+
 func governanceKey(num uint64) []byte {
 	b := make([]byte, 8)
 	binary.LittleEndian.PutUint64(b, num)
@@ -0,0 +1,60 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// batchWriteLatencyImpact is the impact that a new batch write latency has on
+// the moving average reported by DBManager.BatchWriteLatency.
+const batchWriteLatencyImpact = 0.25
+
+// timedBatch is a batch created by DBManager.NewBatch which folds the latency
+// of its writes into the moving average of the database manager.
+type timedBatch struct {
+	Batch
+	dbm *databaseManager
+}
+
+func (b *timedBatch) Write() error {
+	start := time.Now()
+	err := b.Batch.Write()
+	if err == nil {
+		b.dbm.updateBatchWriteLatency(time.Since(start))
+	}
+	return err
+}
+
+// updateBatchWriteLatency folds the given latency into the moving average of
+// the batch write latency.
+func (dbm *databaseManager) updateBatchWriteLatency(latency time.Duration) {
+	for {
+		old := atomic.LoadInt64(&dbm.batchWriteLatency)
+		avg := int64(batchWriteLatencyImpact*float64(latency) + (1-batchWriteLatencyImpact)*float64(old))
+		if atomic.CompareAndSwapInt64(&dbm.batchWriteLatency, old, avg) {
+			return
+		}
+	}
+}
+
+// BatchWriteLatency returns the moving average of the latency of the writes of
+// the batches created by NewBatch.
+func (dbm *databaseManager) BatchWriteLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&dbm.batchWriteLatency))
+}
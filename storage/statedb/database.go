@@ -31,6 +31,7 @@ import (
 	"github.com/klaytn/klaytn/storage/database"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -40,6 +41,7 @@ var (
 	memcacheFlushTimeGauge  = metrics.NewRegisteredGauge("trie/memcache/flush/time", nil)
 	memcacheFlushNodesMeter = metrics.NewRegisteredMeter("trie/memcache/flush/nodes", nil)
 	memcacheFlushSizeMeter  = metrics.NewRegisteredMeter("trie/memcache/flush/size", nil)
+	memcacheDirtyFlushMeter = metrics.NewRegisteredMeter("trie/memcache/flush/dirty", nil)
 
 	memcacheGCTimeGauge  = metrics.NewRegisteredGauge("trie/memcache/gc/time", nil)
 	memcacheGCNodesMeter = metrics.NewRegisteredMeter("trie/memcache/gc/nodes", nil)
@@ -49,6 +51,8 @@ var (
 	memcacheCommitNodesMeter = metrics.NewRegisteredMeter("trie/memcache/commit/nodes", nil)
 	memcacheCommitSizeMeter  = metrics.NewRegisteredMeter("trie/memcache/commit/size", nil)
 
+	memcacheCommitBatchSizeHistogram = metrics.NewRegisteredHistogram("trie/memcache/commit/batchsize", nil, metrics.NewExpDecaySample(1028, 0.015))
+
 	memcacheCleanHitMeter   = metrics.NewRegisteredMeter("trie/memcache/clean/hit", nil)
 	memcacheCleanMissMeter  = metrics.NewRegisteredMeter("trie/memcache/clean/miss", nil)
 	memcacheCleanReadMeter  = metrics.NewRegisteredMeter("trie/memcache/clean/read", nil)
@@ -103,6 +107,12 @@ type Database struct {
 	lock sync.RWMutex
 
 	trieNodeCache *bigcache.BigCache // GC friendly memory cache of trie node RLPs
+
+	commitBatchSize int // Maximum size of a batch written while committing a trie
+
+	maxDirtySize common.StorageSize // Budget of the nodes cache forcing an intermediate flush (0 = unlimited)
+	capLock      sync.Mutex         // Serializes flushes of the flush-list
+	flushing     int32              // Set while flushDirty runs, accessed atomically
 }
 
 // rawNode is a simple binary blob used to differentiate between collapsed trie
@@ -318,13 +328,47 @@ func NewDatabaseWithCache(diskDB database.DBManager, cacheSize int) *Database {
 		})
 	}
 	return &Database{
-		diskDB:        diskDB,
-		nodes:         map[common.Hash]*cachedNode{{}: {}},
-		preimages:     make(map[common.Hash][]byte),
-		trieNodeCache: trieNodeCache,
+		diskDB:          diskDB,
+		nodes:           map[common.Hash]*cachedNode{{}: {}},
+		preimages:       make(map[common.Hash][]byte),
+		trieNodeCache:   trieNodeCache,
+		commitBatchSize: database.IdealBatchSize,
 	}
 }
 
+// SetCommitBatchSize sets the maximum size of a batch written while committing a
+// trie. Smaller batches reduce the write latency spikes of large commits. A
+// non-positive size restores the default, database.IdealBatchSize.
+//
+// Splitting a commit into several batches does not break its atomicity: the nodes
+// are written children first and the root last, so an interrupted commit leaves
+// only unreferenced nodes behind and the trie becomes reachable only when its
+// root is written.
+func (db *Database) SetCommitBatchSize(size int) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if size <= 0 {
+		size = database.IdealBatchSize
+	}
+	db.commitBatchSize = size
+}
+
+// SetMaxDirtyBufferSize sets the memory budget of the dirty trie nodes. When
+// the nodes cache grows over the budget while a trie is being committed, the
+// oldest nodes are flushed to disk right away instead of waiting for the next
+// Cap or Commit, bounding the memory used by the commit of a single heavy
+// block. A non-positive size disables the budget.
+func (db *Database) SetMaxDirtyBufferSize(size common.StorageSize) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if size < 0 {
+		size = 0
+	}
+	db.maxDirtySize = size
+}
+
 // DiskDB retrieves the persistent database backing the trie database.
 func (db *Database) DiskDB() database.DBManager {
 	return db.diskDB
@@ -378,6 +422,44 @@ func (db *Database) insert(hash common.Hash, lenEncoded uint16, node node) {
 	db.nodesSize += common.StorageSize(common.HashLength + entry.size)
 }
 
+// overDirtyBudget reports whether the nodes cache exceeds the dirty buffer budget.
+//
+// Note, this method assumes that the database's lock is held!
+func (db *Database) overDirtyBudget() bool {
+	if db.maxDirtySize == 0 {
+		return false
+	}
+	// Count the flush-list metadata as well, the same as Cap and Size do.
+	size := db.nodesSize + common.StorageSize((len(db.nodes)-1)*2*common.HashLength)
+	return size > db.maxDirtySize
+}
+
+// flushDirty flushes the oldest nodes until the nodes cache takes up at most
+// half of the dirty buffer budget. If another flush is already running, it
+// returns immediately since that flush releases the memory anyway.
+func (db *Database) flushDirty() {
+	if !atomic.CompareAndSwapInt32(&db.flushing, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&db.flushing, 0)
+
+	db.capLock.Lock()
+	defer db.capLock.Unlock()
+
+	db.lock.RLock()
+	limit, over := db.maxDirtySize/2, db.overDirtyBudget()
+	db.lock.RUnlock()
+
+	if !over {
+		return
+	}
+	if err := db.cap(limit); err != nil {
+		logger.Error("Failed to flush dirty trie nodes over the budget", "limit", db.maxDirtySize, "err", err)
+		return
+	}
+	memcacheDirtyFlushMeter.Mark(1)
+}
+
 // insertPreimage writes a new trie node pre-image to the memory database if it's
 // yet unknown. The method will make a copy of the slice.
 //
@@ -589,6 +671,14 @@ func (db *Database) dereference(child common.Hash, parent common.Hash) {
 // Cap iteratively flushes old but still referenced trie nodes until the total
 // memory usage goes below the given threshold.
 func (db *Database) Cap(limit common.StorageSize) error {
+	db.capLock.Lock()
+	defer db.capLock.Unlock()
+
+	return db.cap(limit)
+}
+
+// cap is the private version of Cap. It assumes that capLock is held.
+func (db *Database) cap(limit common.StorageSize) error {
 	// Create a database batch to flush persistent data out. It is important that
 	// outside code doesn't see an inconsistent state (referenced data removed from
 	// memory cache during commit but not yet in persistent database). This is ensured
@@ -739,8 +829,8 @@ func (db *Database) writeBatchNodes(node common.Hash) error {
 		if err := batch.Put(result.key, result.val); err != nil {
 			return err
 		}
-		if batch.ValueSize() > database.IdealBatchSize {
-			if err := batch.Write(); err != nil {
+		if batch.ValueSize() > db.commitBatchSize {
+			if err := writeCommitBatch(batch); err != nil {
 				return err
 			}
 			batch.Reset()
@@ -751,7 +841,7 @@ func (db *Database) writeBatchNodes(node common.Hash) error {
 	if err := batch.Put(node[:], enc); err != nil {
 		return err
 	}
-	if err := batch.Write(); err != nil {
+	if err := writeCommitBatch(batch); err != nil {
 		logger.Error("Failed to write trie to disk", "err", err)
 		return err
 	}
@@ -762,6 +852,12 @@ func (db *Database) writeBatchNodes(node common.Hash) error {
 	return nil
 }
 
+// writeCommitBatch writes the given batch of a trie commit, recording its size.
+func writeCommitBatch(batch database.Batch) error {
+	memcacheCommitBatchSizeHistogram.Update(int64(batch.ValueSize()))
+	return batch.Write()
+}
+
 func (db *Database) concurrentCommit(hash common.Hash, resultCh chan<- commitResult, childIndex int) {
 	logger.Trace("concurrentCommit start", "childIndex", childIndex)
 	defer logger.Trace("concurrentCommit end", "childIndex", childIndex)
@@ -774,6 +870,9 @@ func (db *Database) concurrentCommit(hash common.Hash, resultCh chan<- commitRes
 //
 // As a side effect, all pre-images accumulated up to this point are also written.
 func (db *Database) Commit(node common.Hash, report bool) error {
+	db.capLock.Lock()
+	defer db.capLock.Unlock()
+
 	// Create a database batch to flush persistent data out. It is important that
 	// outside code doesn't see an inconsistent state (referenced data removed from
 	// memory cache during commit but not yet in persistent database). This is ensured
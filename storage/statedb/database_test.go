@@ -0,0 +1,145 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// makeCommitTestTrie inserts n entries into a new trie of the given database and
+// hashes it, returning the trie and its root.
+func makeCommitTestTrie(db *Database, n int) (*Trie, common.Hash) {
+	trie, _ := NewTrie(common.Hash{}, db)
+	k := make([]byte, 8)
+	for i := 0; i < n; i++ {
+		binary.BigEndian.PutUint64(k, uint64(i))
+		trie.Update(crypto.Keccak256(k), crypto.Keccak256(crypto.Keccak256(k)))
+	}
+	root, _ := trie.Commit(nil)
+	return trie, root
+}
+
+// Tests that a trie committed with small batches is completely written to disk.
+func TestCommitBatchSize(t *testing.T) {
+	diskDB := database.NewMemoryDBManager()
+	db := NewDatabase(diskDB)
+	db.SetCommitBatchSize(1)
+
+	_, root := makeCommitTestTrie(db, 1000)
+	if err := db.Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+
+	trie, err := NewTrie(root, NewDatabase(diskDB))
+	if err != nil {
+		t.Fatalf("failed to open committed trie: %v", err)
+	}
+	k := make([]byte, 8)
+	for i := 0; i < 1000; i++ {
+		binary.BigEndian.PutUint64(k, uint64(i))
+		if val, err := trie.TryGet(crypto.Keccak256(k)); err != nil || len(val) == 0 {
+			t.Fatalf("entry %d missing after commit: %v", i, err)
+		}
+	}
+}
+
+func TestSetCommitBatchSize(t *testing.T) {
+	db := NewDatabase(database.NewMemoryDBManager())
+	if db.commitBatchSize != database.IdealBatchSize {
+		t.Errorf("default commit batch size mismatch: have %d, want %d", db.commitBatchSize, database.IdealBatchSize)
+	}
+	db.SetCommitBatchSize(1024)
+	if db.commitBatchSize != 1024 {
+		t.Errorf("commit batch size mismatch: have %d, want %d", db.commitBatchSize, 1024)
+	}
+	db.SetCommitBatchSize(0)
+	if db.commitBatchSize != database.IdealBatchSize {
+		t.Errorf("reset commit batch size mismatch: have %d, want %d", db.commitBatchSize, database.IdealBatchSize)
+	}
+}
+
+// BenchmarkCommitBatchSize measures the p99 latency of small writes issued while a
+// state-heavy trie is being committed, with unbounded and bounded commit batches.
+func BenchmarkCommitBatchSize(b *testing.B) {
+	for _, size := range []int{math.MaxInt32, database.IdealBatchSize, 16 * 1024} {
+		name := fmt.Sprintf("%dKiB", size/1024)
+		if size == math.MaxInt32 {
+			name = "unbounded"
+		}
+		b.Run(name, func(b *testing.B) { benchCommitBatchSize(b, size) })
+	}
+}
+
+func benchCommitBatchSize(b *testing.B, size int) {
+	var latencies []time.Duration
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		dir, db := tempDB()
+		db.SetCommitBatchSize(size)
+		_, root := makeCommitTestTrie(db, 50000)
+
+		var (
+			quit = make(chan struct{})
+			wg   sync.WaitGroup
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := make([]byte, 8)
+			for j := uint64(0); ; j++ {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				binary.BigEndian.PutUint64(key, j)
+				batch := db.diskDB.NewBatch(database.StateTrieDB)
+				batch.Put(key, key)
+				start := time.Now()
+				batch.Write()
+				latencies = append(latencies, time.Since(start))
+			}
+		}()
+		b.StartTimer()
+
+		if err := db.Commit(root, false); err != nil {
+			b.Fatalf("failed to commit trie: %v", err)
+		}
+
+		b.StopTimer()
+		close(quit)
+		wg.Wait()
+		db.diskDB.Close()
+		os.RemoveAll(dir)
+		b.StartTimer()
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		b.Logf("p99 write latency: %v", latencies[len(latencies)*99/100])
+	}
+}
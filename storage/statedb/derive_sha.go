@@ -22,9 +22,11 @@ package statedb
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/ser/rlp"
+	"github.com/klaytn/klaytn/storage/database"
 )
 
 type DeriveShaOrig struct{}
@@ -39,3 +41,38 @@ func (d DeriveShaOrig) DeriveSha(list types.DerivableList) common.Hash {
 	}
 	return trie.Hash()
 }
+
+// ProveDerivedSha returns a merkle proof of the element of list at index against
+// the root hash computed by DeriveShaOrig. The proof is the set of RLP encoded trie
+// nodes on the path to the element, whose key is the RLP encoded index.
+func ProveDerivedSha(list types.DerivableList, index int) ([][]byte, error) {
+	if index < 0 || index >= list.Len() {
+		return nil, fmt.Errorf("index %d out of range [0, %d)", index, list.Len())
+	}
+	keybuf := new(bytes.Buffer)
+	trie := new(Trie)
+	for i := 0; i < list.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		trie.Update(keybuf.Bytes(), list.GetRlp(i))
+	}
+	key, err := rlp.EncodeToBytes(uint(index))
+	if err != nil {
+		return nil, err
+	}
+	proofDB := database.NewMemoryDBManager()
+	if err := trie.Prove(key, 0, proofDB); err != nil {
+		return nil, err
+	}
+
+	var proof [][]byte
+	err = proofDB.IterateStateTrieKeys(func(key []byte) error {
+		node, err := proofDB.ReadCachedTrieNode(common.BytesToHash(key))
+		if err != nil {
+			return err
+		}
+		proof = append(proof, node)
+		return nil
+	})
+	return proof, err
+}
@@ -179,6 +179,7 @@ func (h *hasher) store(n node, db *Database, force bool) (node, uint16) {
 
 		db.lock.Lock()
 		db.insert(hash, lenEncoded, n)
+		flush := db.overDirtyBudget()
 		db.lock.Unlock()
 
 		// Track external references from account->storage trie
@@ -196,6 +197,11 @@ func (h *hasher) store(n node, db *Database, force bool) (node, uint16) {
 				}
 			}
 		}
+		// Flush the oldest nodes if the commit grew the cache over its budget.
+		// This is done after tracking the references since they need the node.
+		if flush {
+			db.flushDirty()
+		}
 	}
 	return hash, lenEncoded
 }
@@ -163,7 +163,13 @@ func (s *TrieSync) AddRawEntry(hash common.Hash, depth int, parent common.Hash)
 func (s *TrieSync) Missing(max int) []common.Hash {
 	requests := []common.Hash{}
 	for !s.queue.Empty() && (max == 0 || len(requests) < max) {
-		requests = append(requests, s.queue.PopItem().(common.Hash))
+		hash := s.queue.PopItem().(common.Hash)
+
+		// Skip nodes delivered ahead of their turn, e.g. within a range reply
+		if req, ok := s.requests[hash]; !ok || req.data != nil {
+			continue
+		}
+		requests = append(requests, hash)
 	}
 	return requests
 }
@@ -18,7 +18,6 @@ package tests
 
 import (
 	"crypto/ecdsa"
-	"errors"
 	"fmt"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
@@ -1081,10 +1080,6 @@ func TestInvalidBalanceBlockTx(t *testing.T) {
 		{"FeeDelegatedWithRatioSmartContractExecution", types.TxTypeFeeDelegatedSmartContractExecutionWithRatio},
 		{"FeeDelegatedWithRatioCancel", types.TxTypeFeeDelegatedCancelWithRatio},
 	}
-	// re-declare errors since those errors are private variables in 'blockchain' package.
-	errInsufficientBalanceForGas := errors.New("insufficient balance of the sender to pay for gas")
-	errInsufficientBalanceForGasFeePayer := errors.New("insufficient balance of the fee payer to pay for gas")
-
 	prof := profile.NewProfiler()
 
 	// Initialize blockchain
@@ -1198,7 +1193,7 @@ func TestInvalidBalanceBlockTx(t *testing.T) {
 				} else {
 					valueMap[types.TxValueKeyGasLimit] = gasLimit + (amount / gasPrice.Uint64()) + 1 // requires 1 more gas
 					// The tx will be failed in buyGas() since it cannot buy enough gas
-					expectedErr = errInsufficientBalanceForGasFeePayer
+					expectedErr = blockchain.ErrInsufficientBalanceForGasFeePayer
 				}
 
 				tx, err := types.NewTransactionWithMap(txType, valueMap)
@@ -1287,7 +1282,7 @@ func TestInvalidBalanceBlockTx(t *testing.T) {
 				assert.Equal(t, nil, err)
 
 				receipt, _, err := applyTransaction(t, bcdata, tx)
-				assert.Equal(t, errInsufficientBalanceForGasFeePayer, err)
+				assert.Equal(t, blockchain.ErrInsufficientBalanceForGasFeePayer, err)
 				assert.Equal(t, (*types.Receipt)(nil), receipt)
 			}
 
@@ -1367,7 +1362,7 @@ func TestInvalidBalanceBlockTx(t *testing.T) {
 					// = (gasLimit + (amount / gasPrice.Uint64()) + 1) * 10 * (100 - 90) * 0.01 = gasLimit + (amount / gasPrice.Uint64()) + 1
 					valueMap[types.TxValueKeyGasLimit] = (gasLimit + (amount / gasPrice.Uint64()) + 1) * 10 // requires 1 more gas
 					// The tx will be failed in buyGas() since it cannot buy enough gas
-					expectedErr = errInsufficientBalanceForGas
+					expectedErr = blockchain.ErrInsufficientBalanceForGas
 				}
 
 				tx, err := types.NewTransactionWithMap(txType, valueMap)
@@ -1406,7 +1401,7 @@ func TestInvalidBalanceBlockTx(t *testing.T) {
 				assert.Equal(t, nil, err)
 
 				receipt, _, err := applyTransaction(t, bcdata, tx)
-				assert.Equal(t, errInsufficientBalanceForGasFeePayer, err)
+				assert.Equal(t, blockchain.ErrInsufficientBalanceForGasFeePayer, err)
 				assert.Equal(t, (*types.Receipt)(nil), receipt)
 			}
 
@@ -1556,7 +1551,10 @@ func TestValidationTxSizeAfterRLP(t *testing.T) {
 	}
 
 	// make TxPool to test validation in 'TxPool add' process
-	txpool := blockchain.NewTxPool(blockchain.DefaultTxPoolConfig, bcdata.bc.Config(), bcdata.bc)
+	// The init code limit is raised to the tx size limit to test the size limit of deployment txs.
+	txPoolConfig := blockchain.DefaultTxPoolConfig
+	txPoolConfig.MaxCodeSize = blockchain.MaxTxDataSize
+	txpool := blockchain.NewTxPool(txPoolConfig, bcdata.bc.Config(), bcdata.bc)
 
 	// test for all tx types
 	for _, txType := range testTxTypes {
@@ -1639,6 +1637,211 @@ func TestValidationTxSizeAfterRLP(t *testing.T) {
 	}
 }
 
+// TestValidationTxSizeWithRaisedLimit tests tx size validation during txPool insert process
+// with the tx size limit raised by TxPoolConfig.MaxTxDataSize. Txs over the default limit are
+// accepted and txs over the raised limit are rejected.
+func TestValidationTxSizeWithRaisedLimit(t *testing.T) {
+	var testTxTypes = []types.TxType{
+		types.TxTypeLegacyTransaction,
+		types.TxTypeValueTransferMemo,
+		types.TxTypeSmartContractDeploy,
+		types.TxTypeChainDataAnchoring,
+		types.TxTypeFeeDelegatedValueTransferMemo,
+		types.TxTypeFeeDelegatedSmartContractDeployWithRatio,
+	}
+	const maxTxDataSize = 2 * blockchain.MaxTxDataSize
+
+	// Initialize blockchain
+	bcdata, err := NewBCData(6, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bcdata.Shutdown()
+
+	signer := types.NewEIP155Signer(bcdata.bc.Config().ChainID)
+
+	// reservoir account
+	reservoir := &TestAccountType{
+		Addr:  *bcdata.addrs[0],
+		Keys:  []*ecdsa.PrivateKey{bcdata.privKeys[0]},
+		Nonce: uint64(0),
+	}
+
+	// make TxPool to test validation in 'TxPool add' process
+	// The init code limit is raised as well to test the size limit of deployment txs.
+	txPoolConfig := blockchain.DefaultTxPoolConfig
+	txPoolConfig.MaxTxDataSize = maxTxDataSize
+	txPoolConfig.MaxCodeSize = maxTxDataSize
+	txpool := blockchain.NewTxPool(txPoolConfig, bcdata.bc.Config(), bcdata.bc)
+	defer txpool.Stop()
+
+	newTx := func(txType types.TxType, dataSize int) *types.Transaction {
+		valueMap, _ := genMapForTxTypes(reservoir, reservoir, txType)
+		data := make([]byte, dataSize)
+
+		if valueMap[types.TxValueKeyData] != nil {
+			valueMap[types.TxValueKeyData] = data
+		}
+
+		if valueMap[types.TxValueKeyAnchoredData] != nil {
+			valueMap[types.TxValueKeyAnchoredData] = data
+		}
+
+		tx, err := types.NewTransactionWithMap(txType, valueMap)
+		assert.Equal(t, nil, err)
+
+		err = tx.SignWithKeys(signer, reservoir.Keys)
+		assert.Equal(t, nil, err)
+
+		if txType.IsFeeDelegatedTransaction() {
+			err = tx.SignFeePayerWithKeys(signer, reservoir.Keys)
+			assert.Equal(t, nil, err)
+		}
+		return tx
+	}
+
+	for _, txType := range testTxTypes {
+		// txs over the raised limit are rejected
+		tx := newTx(txType, maxTxDataSize+1)
+		if tx.Size() < maxTxDataSize {
+			t.Fatalf("test data size is smaller than the raised limit")
+		}
+		assert.Equal(t, blockchain.ErrOversizedData, txpool.AddRemote(tx), txType.String())
+
+		// txs over the default limit but within the raised limit are accepted
+		tx = newTx(txType, blockchain.MaxTxDataSize+1024)
+		if tx.Size() <= blockchain.MaxTxDataSize || tx.Size() > maxTxDataSize {
+			t.Fatalf("test data size is not between the default and the raised limit")
+		}
+		assert.Equal(t, nil, txpool.AddRemote(tx), txType.String())
+		reservoir.AddNonce()
+	}
+}
+
+// TestValidationSelfFeeDelegation tests that fee-delegated txs whose fee payer is the sender are
+// rejected with kerrors.ErrSelfFeeDelegation only if TxPoolConfig.RejectSelfFeeDelegation is set.
+func TestValidationSelfFeeDelegation(t *testing.T) {
+	var testTxTypes = []testTxType{
+		{"FeeDelegatedValueTransfer", types.TxTypeFeeDelegatedValueTransfer},
+		{"FeeDelegatedValueTransferWithMemo", types.TxTypeFeeDelegatedValueTransferMemo},
+		{"FeeDelegatedAccountUpdate", types.TxTypeFeeDelegatedAccountUpdate},
+		{"FeeDelegatedSmartContractDeploy", types.TxTypeFeeDelegatedSmartContractDeploy},
+		{"FeeDelegatedSmartContractExecution", types.TxTypeFeeDelegatedSmartContractExecution},
+		{"FeeDelegatedCancel", types.TxTypeFeeDelegatedCancel},
+		{"FeeDelegatedWithRatioValueTransfer", types.TxTypeFeeDelegatedValueTransferWithRatio},
+		{"FeeDelegatedWithRatioValueTransferWithMemo", types.TxTypeFeeDelegatedValueTransferMemoWithRatio},
+		{"FeeDelegatedWithRatioAccountUpdate", types.TxTypeFeeDelegatedAccountUpdateWithRatio},
+		{"FeeDelegatedWithRatioSmartContractDeploy", types.TxTypeFeeDelegatedSmartContractDeployWithRatio},
+		{"FeeDelegatedWithRatioSmartContractExecution", types.TxTypeFeeDelegatedSmartContractExecutionWithRatio},
+		{"FeeDelegatedWithRatioCancel", types.TxTypeFeeDelegatedCancelWithRatio},
+	}
+
+	prof := profile.NewProfiler()
+
+	// Initialize blockchain
+	bcdata, err := NewBCData(6, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bcdata.Shutdown()
+
+	// Initialize address-balance map for verification
+	accountMap := NewAccountMap()
+	if err := accountMap.Initialize(bcdata); err != nil {
+		t.Fatal(err)
+	}
+
+	signer := types.NewEIP155Signer(bcdata.bc.Config().ChainID)
+
+	// reservoir account
+	reservoir := &TestAccountType{
+		Addr:  *bcdata.addrs[0],
+		Keys:  []*ecdsa.PrivateKey{bcdata.privKeys[0]},
+		Nonce: uint64(0),
+	}
+
+	// another funded account paying the fees of the sender
+	feePayer := &TestAccountType{
+		Addr:  *bcdata.addrs[1],
+		Keys:  []*ecdsa.PrivateKey{bcdata.privKeys[1]},
+		Nonce: uint64(0),
+	}
+
+	// for contract execution txs
+	contract, err := createAnonymousAccount("a5c9a50938a089618167c9d67dbebc0deaffc3c76ddc6b40c2777ae59438e989")
+	assert.Equal(t, nil, err)
+
+	// deploy a contract for contract execution tx type
+	{
+		var txs types.Transactions
+
+		values := map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:         reservoir.GetNonce(),
+			types.TxValueKeyFrom:          reservoir.GetAddr(),
+			types.TxValueKeyTo:            (*common.Address)(nil),
+			types.TxValueKeyAmount:        big.NewInt(0),
+			types.TxValueKeyGasLimit:      gasLimit,
+			types.TxValueKeyGasPrice:      big.NewInt(25 * params.Ston),
+			types.TxValueKeyHumanReadable: false,
+			types.TxValueKeyData:          common.FromHex(code),
+			types.TxValueKeyCodeFormat:    params.CodeFormatEVM,
+		}
+
+		tx, err := types.NewTransactionWithMap(types.TxTypeSmartContractDeploy, values)
+		assert.Equal(t, nil, err)
+
+		err = tx.SignWithKeys(signer, reservoir.Keys)
+		assert.Equal(t, nil, err)
+
+		txs = append(txs, tx)
+
+		if err := bcdata.GenABlockWithTransactions(accountMap, txs, prof); err != nil {
+			t.Fatal(err)
+		}
+
+		contract.Addr = crypto.CreateAddress(reservoir.Addr, reservoir.Nonce)
+
+		reservoir.AddNonce()
+	}
+
+	for _, reject := range []bool{false, true} {
+		txPoolConfig := blockchain.DefaultTxPoolConfig
+		txPoolConfig.RejectSelfFeeDelegation = reject
+		txpool := blockchain.NewTxPool(txPoolConfig, bcdata.bc.Config(), bcdata.bc)
+
+		nonce := reservoir.GetNonce()
+		for _, testTxType := range testTxTypes {
+			for _, payer := range []*TestAccountType{reservoir, feePayer} {
+				to := reservoir
+				if toBasicType(testTxType.txType) == types.TxTypeSmartContractExecution {
+					to = contract
+				}
+				valueMap, _ := genMapForTxTypes(reservoir, to, testTxType.txType)
+				valueMap[types.TxValueKeyNonce] = nonce
+				valueMap[types.TxValueKeyFeePayer] = payer.GetAddr()
+
+				tx, err := types.NewTransactionWithMap(testTxType.txType, valueMap)
+				assert.Equal(t, nil, err)
+
+				err = tx.SignWithKeys(signer, reservoir.Keys)
+				assert.Equal(t, nil, err)
+
+				err = tx.SignFeePayerWithKeys(signer, payer.Keys)
+				assert.Equal(t, nil, err)
+
+				err = txpool.AddRemote(tx)
+				if reject && payer == reservoir {
+					assert.Equal(t, kerrors.ErrSelfFeeDelegation, err, testTxType.name)
+					continue
+				}
+				assert.Equal(t, nil, err, testTxType.name)
+				nonce++
+			}
+		}
+		txpool.Stop()
+	}
+}
+
 // TestValidationPoolResetAfterSenderKeyChange puts txs in the pending pool and generates a block only with the first tx.
 // Since the tx changes the sender's account key, all rest txs should drop from the pending pool.
 func TestValidationPoolResetAfterSenderKeyChange(t *testing.T) {
@@ -1968,3 +2171,95 @@ func TestValidationPoolResetAfterFeePayerKeyChange(t *testing.T) {
 	pendingLen, _ = txpool.Stats()
 	assert.Equal(t, 0, pendingLen)
 }
+
+// TestSenderAndFeePayer checks that SenderAndFeePayer returns the sender and the fee
+// payer of all tx types, and an error for a signature which can't be recovered.
+func TestSenderAndFeePayer(t *testing.T) {
+	var testTxTypes = []testTxType{
+		{"LegacyTransaction", types.TxTypeLegacyTransaction},
+		{"ValueTransfer", types.TxTypeValueTransfer},
+		{"ValueTransferWithMemo", types.TxTypeValueTransferMemo},
+		{"AccountUpdate", types.TxTypeAccountUpdate},
+		{"SmartContractDeploy", types.TxTypeSmartContractDeploy},
+		{"SmartContractExecution", types.TxTypeSmartContractExecution},
+		{"Cancel", types.TxTypeCancel},
+		{"ChainDataAnchoring", types.TxTypeChainDataAnchoring},
+		{"FeeDelegatedValueTransfer", types.TxTypeFeeDelegatedValueTransfer},
+		{"FeeDelegatedValueTransferWithMemo", types.TxTypeFeeDelegatedValueTransferMemo},
+		{"FeeDelegatedAccountUpdate", types.TxTypeFeeDelegatedAccountUpdate},
+		{"FeeDelegatedSmartContractDeploy", types.TxTypeFeeDelegatedSmartContractDeploy},
+		{"FeeDelegatedSmartContractExecution", types.TxTypeFeeDelegatedSmartContractExecution},
+		{"FeeDelegatedCancel", types.TxTypeFeeDelegatedCancel},
+		{"FeeDelegatedWithRatioValueTransfer", types.TxTypeFeeDelegatedValueTransferWithRatio},
+		{"FeeDelegatedWithRatioValueTransferWithMemo", types.TxTypeFeeDelegatedValueTransferMemoWithRatio},
+		{"FeeDelegatedWithRatioAccountUpdate", types.TxTypeFeeDelegatedAccountUpdateWithRatio},
+		{"FeeDelegatedWithRatioSmartContractDeploy", types.TxTypeFeeDelegatedSmartContractDeployWithRatio},
+		{"FeeDelegatedWithRatioSmartContractExecution", types.TxTypeFeeDelegatedSmartContractExecutionWithRatio},
+		{"FeeDelegatedWithRatioCancel", types.TxTypeFeeDelegatedCancelWithRatio},
+	}
+
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	sender, err := createDefaultAccount(accountkey.AccountKeyTypeLegacy)
+	assert.Equal(t, nil, err)
+	feePayer, err := createDefaultAccount(accountkey.AccountKeyTypeLegacy)
+	assert.Equal(t, nil, err)
+
+	// invalidate replaces the R and S values of the signatures with zero.
+	invalidate := func(sigs types.TxSignatures) types.TxSignatures {
+		invalid := make(types.TxSignatures, len(sigs))
+		for i, sig := range sigs {
+			invalid[i] = &types.TxSignature{V: sig.V, R: big.NewInt(0), S: big.NewInt(0)}
+		}
+		return invalid
+	}
+
+	for _, testTxType := range testTxTypes {
+		txType := testTxType.txType
+		newTx := func() *types.Transaction {
+			valueMap, _ := genMapForTxTypes(sender, sender, txType)
+			if txType.IsFeeDelegatedTransaction() {
+				valueMap[types.TxValueKeyFeePayer] = feePayer.GetAddr()
+			}
+			tx, err := types.NewTransactionWithMap(txType, valueMap)
+			assert.Equal(t, nil, err)
+
+			err = tx.SignWithKeys(signer, sender.Keys)
+			assert.Equal(t, nil, err)
+
+			if txType.IsFeeDelegatedTransaction() {
+				err = tx.SignFeePayerWithKeys(signer, feePayer.Keys)
+				assert.Equal(t, nil, err)
+			}
+			return tx
+		}
+
+		wantFeePayer := common.Address{}
+		if txType.IsFeeDelegatedTransaction() {
+			wantFeePayer = feePayer.GetAddr()
+		}
+
+		// Both addresses are returned, also from the cache.
+		tx := newTx()
+		for i := 0; i < 2; i++ {
+			from, payer, err := tx.SenderAndFeePayer(signer)
+			assert.Equal(t, nil, err, testTxType.name)
+			assert.Equal(t, sender.GetAddr(), from, testTxType.name)
+			assert.Equal(t, wantFeePayer, payer, testTxType.name)
+		}
+
+		// An unrecoverable sender signature.
+		tx = newTx()
+		tx.SetSignature(invalidate(tx.RawSignatureValues()))
+		_, _, err = tx.SenderAndFeePayer(signer)
+		assert.Equal(t, types.ErrInvalidSigSender, err, testTxType.name)
+
+		// An unrecoverable fee payer signature.
+		if txType.IsFeeDelegatedTransaction() {
+			tx = newTx()
+			feePayerSigs := tx.GetTxInternalData().(types.TxInternalDataFeePayer).GetFeePayerRawSignatureValues()
+			assert.Equal(t, nil, tx.SetFeePayerSignatures(invalidate(feePayerSigs)))
+			_, _, err = tx.SenderAndFeePayer(signer)
+			assert.Equal(t, types.ErrInvalidSigFeePayer, err, testTxType.name)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package work
+
+import (
+	"fmt"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"sync"
+)
+
+// TransactionSet is a set of transactions which is applied in order when a block is assembled.
+type TransactionSet interface {
+	// Peek returns the next transaction to be applied, or nil if there is none.
+	Peek() *types.Transaction
+	// Shift moves to the next transaction after the current one is applied.
+	Shift()
+	// Pop discards the current transaction and the following ones of the same sender.
+	Pop()
+}
+
+// TxOrderer decides the order of the pending transactions in a block.
+// The transactions of each sender must remain in nonce order; otherwise the
+// transactions following a nonce gap of a sender are skipped.
+type TxOrderer interface {
+	Order(signer types.Signer, pending map[common.Address]types.Transactions) types.Transactions
+}
+
+var (
+	txOrderersMu sync.RWMutex
+	txOrderers   = make(map[string]TxOrderer)
+)
+
+// RegisterTxOrderer registers a TxOrderer with the given name so that it can be
+// selected by configuration. It is usually called in init functions of plugins.
+func RegisterTxOrderer(name string, orderer TxOrderer) {
+	txOrderersMu.Lock()
+	defer txOrderersMu.Unlock()
+
+	if _, exist := txOrderers[name]; exist {
+		panic(fmt.Sprintf("tx orderer %q is already registered", name))
+	}
+	txOrderers[name] = orderer
+}
+
+// GetTxOrderer returns the TxOrderer registered with the given name.
+func GetTxOrderer(name string) (TxOrderer, error) {
+	txOrderersMu.RLock()
+	defer txOrderersMu.RUnlock()
+
+	orderer, ok := txOrderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx orderer %q", name)
+	}
+	return orderer, nil
+}
+
+// orderedTransactions is a TransactionSet applying the transactions in the given order.
+type orderedTransactions struct {
+	txs     types.Transactions
+	signer  types.Signer
+	skipped map[common.Address]bool
+}
+
+func newOrderedTransactions(signer types.Signer, txs types.Transactions) *orderedTransactions {
+	return &orderedTransactions{
+		txs:     txs,
+		signer:  signer,
+		skipped: make(map[common.Address]bool),
+	}
+}
+
+// Peek returns the next transaction whose sender is not skipped.
+func (t *orderedTransactions) Peek() *types.Transaction {
+	for len(t.txs) > 0 {
+		from, _ := types.Sender(t.signer, t.txs[0])
+		if !t.skipped[from] {
+			return t.txs[0]
+		}
+		t.txs = t.txs[1:]
+	}
+	return nil
+}
+
+// Shift moves to the next transaction.
+func (t *orderedTransactions) Shift() {
+	t.txs = t.txs[1:]
+}
+
+// Pop skips the current transaction and the remaining ones from the same sender.
+func (t *orderedTransactions) Pop() {
+	from, _ := types.Sender(t.signer, t.txs[0])
+	t.skipped[from] = true
+	t.txs = t.txs[1:]
+}
+
+// newTransactionSet returns the TransactionSet of the pending transactions ordered by
+// the given orderer. If orderer is nil, the transactions are ordered by price and nonce.
+func newTransactionSet(orderer TxOrderer, signer types.Signer, pending map[common.Address]types.Transactions) TransactionSet {
+	if orderer == nil {
+		return types.NewTransactionsByPriceAndNonce(signer, pending)
+	}
+	return newOrderedTransactions(signer, orderer.Order(signer, pending))
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package work
+
+import (
+	"crypto/ecdsa"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
+	"sort"
+	"testing"
+)
+
+// reversePriceOrderer orders the transactions from the lowest price to the highest.
+type reversePriceOrderer struct{}
+
+func (reversePriceOrderer) Order(signer types.Signer, pending map[common.Address]types.Transactions) types.Transactions {
+	var txs types.Transactions
+	for _, accTxs := range pending {
+		txs = append(txs, accTxs...)
+	}
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].GasPrice().Cmp(txs[j].GasPrice()) < 0 })
+	return txs
+}
+
+func TestTxOrderer(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	var (
+		keys  = make([]*ecdsa.PrivateKey, 3)
+		alloc = make(blockchain.GenesisAlloc)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		alloc[crypto.PubkeyToAddress(keys[i].PublicKey)] = blockchain.GenesisAccount{Balance: big.NewInt(params.KLAY)}
+	}
+	db := database.NewMemoryDBManager()
+	genesis := (&blockchain.Genesis{Config: params.TestChainConfig, Alloc: alloc}).MustCommit(db)
+	chain, err := blockchain.NewBlockChain(db, nil, params.TestChainConfig, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	// Each sender sends a transaction with a different price.
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	pending := make(map[common.Address]types.Transactions)
+	for i, key := range keys {
+		tx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(int64(i+1)), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending[crypto.PubkeyToAddress(key.PublicKey)] = types.Transactions{tx}
+	}
+
+	RegisterTxOrderer("reverse-price", reversePriceOrderer{})
+	orderer, err := GetTxOrderer("reverse-price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetTxOrderer("unknown"); err == nil {
+		t.Error("unknown tx orderer should not be found")
+	}
+
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		BlockScore: big.NewInt(1),
+		Time:       big.NewInt(1),
+	}
+	task := NewTask(params.TestChainConfig, signer, statedb, header)
+	task.ApplyTransactions(newTransactionSet(orderer, signer, pending), chain, common.Address{})
+
+	txs := task.Transactions()
+	if len(txs) != len(keys) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(keys))
+	}
+	for i, tx := range txs {
+		if tx.GasPrice().Int64() != int64(i+1) {
+			t.Errorf("transaction %d: price mismatch: have %v, want %d", i, tx.GasPrice(), i+1)
+		}
+	}
+}
+
+func TestOrderedTransactionsPop(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	newTx := func(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		return tx
+	}
+
+	txs := types.Transactions{newTx(0, key1), newTx(0, key2), newTx(1, key1), newTx(1, key2)}
+	set := newOrderedTransactions(signer, txs)
+
+	set.Pop() // Skip the transactions of key1.
+	for _, want := range []*types.Transaction{txs[1], txs[3]} {
+		if tx := set.Peek(); tx != want {
+			t.Fatalf("transaction mismatch: have %v, want %v", tx.Hash(), want.Hash())
+		}
+		set.Shift()
+	}
+	if tx := set.Peek(); tx != nil {
+		t.Errorf("no transaction should remain: have %v", tx.Hash())
+	}
+}
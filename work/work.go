@@ -172,6 +172,12 @@ func (self *Miner) SetExtra(extra []byte) error {
 	return nil
 }
 
+// SetTxOrderer sets the TxOrderer deciding the tx order of the blocks assembled by the miner.
+// If orderer is nil, the transactions are ordered by price and nonce.
+func (self *Miner) SetTxOrderer(orderer TxOrderer) {
+	self.worker.setTxOrderer(orderer)
+}
+
 // Pending returns the currently pending block and associated state.
 func (self *Miner) Pending() (*types.Block, *state.StateDB) {
 	return self.worker.pending()
@@ -185,3 +191,9 @@ func (self *Miner) Pending() (*types.Block, *state.StateDB) {
 func (self *Miner) PendingBlock() *types.Block {
 	return self.worker.pendingBlock()
 }
+
+// MiningBlock returns the work-in-progress block which is being assembled for
+// sealing, or nil if the miner is not mining.
+func (self *Miner) MiningBlock() *types.Block {
+	return self.worker.miningBlock()
+}
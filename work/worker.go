@@ -56,17 +56,18 @@ const (
 
 var (
 	// Metrics for miner
-	timeLimitReachedCounter = metrics.NewRegisteredCounter("miner/timelimitreached", nil)
-	tooLongTxCounter        = metrics.NewRegisteredCounter("miner/toolongtx", nil)
-	ResultChGauge           = metrics.NewRegisteredGauge("miner/resultch", nil)
-	resentTxGauge           = metrics.NewRegisteredGauge("miner/tx/resend/gauge", nil)
-	usedAllTxsCounter       = metrics.NewRegisteredCounter("miner/usedalltxs", nil)
-	checkedTxsGauge         = metrics.NewRegisteredGauge("miner/checkedtxs", nil)
-	tCountGauge             = metrics.NewRegisteredGauge("miner/tcount", nil)
-	nonceTooLowTxsGauge     = metrics.NewRegisteredGauge("miner/nonce/low/txs", nil)
-	nonceTooHighTxsGauge    = metrics.NewRegisteredGauge("miner/nonce/high/txs", nil)
-	gasLimitReachedTxsGauge = metrics.NewRegisteredGauge("miner/limitreached/gas/txs", nil)
-	strangeErrorTxsCounter  = metrics.NewRegisteredCounter("miner/strangeerror/txs", nil)
+	timeLimitReachedCounter  = metrics.NewRegisteredCounter("miner/timelimitreached", nil)
+	tooLongTxCounter         = metrics.NewRegisteredCounter("miner/toolongtx", nil)
+	ResultChGauge            = metrics.NewRegisteredGauge("miner/resultch", nil)
+	resentTxGauge            = metrics.NewRegisteredGauge("miner/tx/resend/gauge", nil)
+	usedAllTxsCounter        = metrics.NewRegisteredCounter("miner/usedalltxs", nil)
+	checkedTxsGauge          = metrics.NewRegisteredGauge("miner/checkedtxs", nil)
+	tCountGauge              = metrics.NewRegisteredGauge("miner/tcount", nil)
+	nonceTooLowTxsGauge      = metrics.NewRegisteredGauge("miner/nonce/low/txs", nil)
+	nonceTooHighTxsGauge     = metrics.NewRegisteredGauge("miner/nonce/high/txs", nil)
+	gasLimitReachedTxsGauge  = metrics.NewRegisteredGauge("miner/limitreached/gas/txs", nil)
+	strangeErrorTxsCounter   = metrics.NewRegisteredCounter("miner/strangeerror/txs", nil)
+	conditionsNotMetTxsGauge = metrics.NewRegisteredGauge("miner/conditions/notmet/txs", nil)
 )
 
 // Agent can register themself with the worker
@@ -129,6 +130,9 @@ type worker struct {
 
 	extra []byte
 
+	ordererMu sync.RWMutex
+	orderer   TxOrderer // orderer decides the tx order of a block; nil means price and nonce order
+
 	currentMu  sync.Mutex
 	current    *Task
 	rewardbase common.Address
@@ -184,6 +188,18 @@ func (self *worker) setExtra(extra []byte) {
 	self.extra = extra
 }
 
+func (self *worker) setTxOrderer(orderer TxOrderer) {
+	self.ordererMu.Lock()
+	defer self.ordererMu.Unlock()
+	self.orderer = orderer
+}
+
+func (self *worker) txOrderer() TxOrderer {
+	self.ordererMu.RLock()
+	defer self.ordererMu.RUnlock()
+	return self.orderer
+}
+
 func (self *worker) pending() (*types.Block, *state.StateDB) {
 	if atomic.LoadInt32(&self.mining) == 0 {
 		// return a snapshot to avoid contention on currentMu mutex
@@ -212,6 +228,18 @@ func (self *worker) pendingBlock() *types.Block {
 	return self.current.Block
 }
 
+// miningBlock returns the block currently assembled for sealing, or nil if
+// the worker is not mining.
+func (self *worker) miningBlock() *types.Block {
+	if atomic.LoadInt32(&self.mining) == 0 {
+		return nil
+	}
+
+	self.currentMu.Lock()
+	defer self.currentMu.Unlock()
+	return self.current.Block
+}
+
 func (self *worker) start() {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -286,7 +314,7 @@ func (self *worker) handleTxsCh(quitByErr chan bool) {
 					}
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(self.current.signer, txs)
+				txset := newTransactionSet(self.txOrderer(), self.current.signer, txs)
 				self.current.commitTransactions(self.mux, txset, self.chain, self.rewardbase)
 				self.updateSnapshot()
 				self.current.stateMu.Unlock()
@@ -539,7 +567,7 @@ func (self *worker) commitNewWork() {
 	// Create the current work task
 	work := self.current
 	if self.nodetype == node.CONSENSUSNODE {
-		txs := types.NewTransactionsByPriceAndNonce(self.current.signer, pending)
+		txs := newTransactionSet(self.txOrderer(), self.current.signer, pending)
 		work.commitTransactions(self.mux, txs, self.chain, self.rewardbase)
 
 		// Create the new block to seal with the consensus engine
@@ -570,7 +598,7 @@ func (self *worker) updateSnapshot() {
 	self.snapshotState = self.current.state.Copy()
 }
 
-func (env *Task) commitTransactions(mux *event.TypeMux, txs *types.TransactionsByPriceAndNonce, bc *blockchain.BlockChain, rewardbase common.Address) {
+func (env *Task) commitTransactions(mux *event.TypeMux, txs TransactionSet, bc *blockchain.BlockChain, rewardbase common.Address) {
 	coalescedLogs := env.ApplyTransactions(txs, bc, rewardbase)
 
 	if len(coalescedLogs) > 0 || env.tcount > 0 {
@@ -593,7 +621,7 @@ func (env *Task) commitTransactions(mux *event.TypeMux, txs *types.TransactionsB
 	}
 }
 
-func (env *Task) ApplyTransactions(txs *types.TransactionsByPriceAndNonce, bc *blockchain.BlockChain, rewardbase common.Address) []*types.Log {
+func (env *Task) ApplyTransactions(txs TransactionSet, bc *blockchain.BlockChain, rewardbase common.Address) []*types.Log {
 	var coalescedLogs []*types.Log
 
 	// Limit the execution time of all transactions in a block
@@ -644,6 +672,7 @@ func (env *Task) ApplyTransactions(txs *types.TransactionsByPriceAndNonce, bc *b
 	var numTxsNonceTooLow int64 = 0
 	var numTxsNonceTooHigh int64 = 0
 	var numTxsGasLimitReached int64 = 0
+	var numTxsConditionsNotMet int64 = 0
 CommitTransactionLoop:
 	for atomic.LoadInt32(&abort) == 0 {
 		// Retrieve the next transaction and abort if all done
@@ -672,6 +701,20 @@ CommitTransactionLoop:
 		//	txs.Pop()
 		//	continue
 		//}
+		// Skip the conditional transaction whose conditions are not met, and the following
+		// ones of the same sender. It is dropped from the pool unless its conditions are
+		// only not met yet, in which case it is checked again in the next block.
+		if conditions := tx.Conditions(); conditions != nil {
+			if err := conditions.Check(env.header, env.state); err != nil {
+				logger.Trace("Skipping transaction with unmet conditions", "sender", from, "hash", tx.Hash(), "err", err)
+				if err != types.ErrTxConditionsNotMetYet {
+					tx.MarkUnexecutable(true)
+				}
+				numTxsConditionsNotMet++
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.Prepare(tx.Hash(), common.Hash{}, env.tcount)
 
@@ -725,6 +768,7 @@ CommitTransactionLoop:
 	nonceTooLowTxsGauge.Update(numTxsNonceTooLow)
 	nonceTooHighTxsGauge.Update(numTxsNonceTooHigh)
 	gasLimitReachedTxsGauge.Update(numTxsGasLimitReached)
+	conditionsNotMetTxsGauge.Update(numTxsConditionsNotMet)
 
 	// Stop the goroutine that has been handling the timer.
 	chDone <- true
@@ -0,0 +1,191 @@
+// Copyright 2019 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package work
+
+import (
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"math/big"
+	"testing"
+)
+
+// testWorkerBackend implements Backend for the worker tests.
+type testWorkerBackend struct {
+	db     database.DBManager
+	chain  *blockchain.BlockChain
+	txPool *blockchain.TxPool
+}
+
+func (b *testWorkerBackend) AccountManager() *accounts.Manager              { return nil }
+func (b *testWorkerBackend) BlockChain() *blockchain.BlockChain             { return b.chain }
+func (b *testWorkerBackend) TxPool() *blockchain.TxPool                     { return b.txPool }
+func (b *testWorkerBackend) ChainDB() database.DBManager                    { return b.db }
+func (b *testWorkerBackend) ReBroadcastTxs(transactions types.Transactions) {}
+
+func TestWorkerMiningBlock(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	config := params.TestChainConfig
+
+	db := database.NewMemoryDBManager()
+	genesis := (&blockchain.Genesis{
+		Config: config,
+		Alloc:  blockchain.GenesisAlloc{addr: {Balance: big.NewInt(params.KLAY)}},
+	}).MustCommit(db)
+	chain, err := blockchain.NewBlockChain(db, nil, config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	poolConfig := blockchain.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+	txPool := blockchain.NewTxPool(poolConfig, config, chain)
+	defer txPool.Stop()
+
+	backend := &testWorkerBackend{db: db, chain: chain, txPool: txPool}
+	w := newWorker(config, gxhash.NewFaker(), common.Address{}, backend, new(event.TypeMux), node.CONSENSUSNODE, false)
+
+	if block := w.miningBlock(); block != nil {
+		t.Fatalf("mining block should be nil when not mining: have %v", block.Number())
+	}
+
+	w.start()
+	defer w.stop()
+
+	signer := types.NewEIP155Signer(config.ChainID)
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1), params.TxGas, txPool.GasPrice(), nil), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txPool.AddLocal(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assemble the next block as if the chain head has been updated. No agent
+	// is registered, so the block is never sealed.
+	w.commitNewWork()
+
+	block := w.miningBlock()
+	if block == nil {
+		t.Fatal("mining block should not be nil while mining")
+	}
+	if block.NumberU64() != genesis.NumberU64()+1 {
+		t.Errorf("block number mismatch: have %d, want %d", block.NumberU64(), genesis.NumberU64()+1)
+	}
+	if txs := block.Transactions(); len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+		t.Fatalf("mining block should contain the pool transaction: have %d txs", len(txs))
+	}
+	if block.GasUsed() != params.TxGas {
+		t.Errorf("gas used mismatch: have %d, want %d", block.GasUsed(), params.TxGas)
+	}
+	if block.Root() == genesis.Root() {
+		t.Error("state root should reflect the applied transaction")
+	}
+}
+
+func TestWorkerConditionalTransactions(t *testing.T) {
+	blockchain.InitDeriveSha(types.ImplDeriveShaOriginal)
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	addr1 := crypto.PubkeyToAddress(key1.PublicKey)
+	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
+	config := params.TestChainConfig
+
+	db := database.NewMemoryDBManager()
+	(&blockchain.Genesis{
+		Config: config,
+		Alloc: blockchain.GenesisAlloc{
+			addr1: {Balance: big.NewInt(params.KLAY)},
+			addr2: {Balance: big.NewInt(params.KLAY)},
+		},
+	}).MustCommit(db)
+	chain, err := blockchain.NewBlockChain(db, nil, config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	poolConfig := blockchain.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+	txPool := blockchain.NewTxPool(poolConfig, config, chain)
+	defer txPool.Stop()
+
+	backend := &testWorkerBackend{db: db, chain: chain, txPool: txPool}
+	w := newWorker(config, gxhash.NewFaker(), common.Address{}, backend, new(event.TypeMux), node.CONSENSUSNODE, false)
+	w.start()
+	defer w.stop()
+
+	signer := types.NewEIP155Signer(config.ChainID)
+	unmetNonce, metNonce := hexutil.Uint64(5), hexutil.Uint64(0)
+
+	// The conditions of failed require the nonce of addr2 to be 5, which does not hold.
+	failed, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1), params.TxGas, txPool.GasPrice(), nil), signer, key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed.SetConditions(&types.TxConditions{
+		KnownAccounts: map[common.Address]types.AccountConditions{addr2: {Nonce: &unmetNonce}},
+	})
+	// The conditions of passed require the nonce of addr1 to be 0, which holds.
+	passed, err := types.SignTx(types.NewTransaction(0, common.Address{2}, big.NewInt(1), params.TxGas, txPool.GasPrice(), nil), signer, key2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passed.SetConditions(&types.TxConditions{
+		KnownAccounts: map[common.Address]types.AccountConditions{addr1: {Nonce: &metNonce}},
+	})
+	// The conditions of early require a block number which is not reached yet.
+	early, err := types.SignTx(types.NewTransaction(1, common.Address{3}, big.NewInt(1), params.TxGas, txPool.GasPrice(), nil), signer, key2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	early.SetConditions(&types.TxConditions{BlockNumberMin: (*hexutil.Big)(big.NewInt(100))})
+	if errs := txPool.AddLocals([]*types.Transaction{failed, passed, early}); errs[0] != nil || errs[1] != nil || errs[2] != nil {
+		t.Fatalf("failed to add transactions: %v", errs)
+	}
+
+	w.commitNewWork()
+
+	block := w.miningBlock()
+	if block == nil {
+		t.Fatal("mining block should not be nil while mining")
+	}
+	if txs := block.Transactions(); len(txs) != 1 || txs[0].Hash() != passed.Hash() {
+		t.Fatalf("mining block should contain only the transaction with met conditions: have %d txs", len(txs))
+	}
+	// The pool drops the transactions marked as unexecutable on its next reset.
+	if !failed.IsMarkedUnexecutable() {
+		t.Error("transaction with unmet conditions should be dropped from the pool")
+	}
+	if early.IsMarkedUnexecutable() || txPool.Get(early.Hash()) == nil {
+		t.Error("transaction with conditions not met yet should stay in the pool")
+	}
+}